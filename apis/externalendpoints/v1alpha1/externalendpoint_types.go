@@ -0,0 +1,67 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExternalEndpointSpec defines the desired state of ExternalEndpoint
+// It gives a name to a group of IPs or subnets located outside of the cluster, such as databases or partner systems.
+// Flows from or to these IPs are labelled with this name, in the `SrcSubnetLabel` and `DstSubnetLabel` fields.
+// These fields can be used as labels in `FlowMetric` resources, and to filter flows in the Console plugin.
+type ExternalEndpointSpec struct {
+	// List of IPs or CIDRs that belong to this endpoint, such as `["192.168.10.0/24", "203.0.113.12/32"]`.
+	// +kubebuilder:validation:MinItems:=1
+	// +required
+	CIDRs []string `json:"cidrs"`
+
+	// Name given to the flows matching this endpoint. When empty, the resource name is used.
+	// +optional
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// ExternalEndpointStatus defines the observed state of ExternalEndpoint
+type ExternalEndpointStatus struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// ExternalEndpoint is the Schema for the externalendpoints API
+type ExternalEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExternalEndpointSpec   `json:"spec,omitempty"`
+	Status ExternalEndpointStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ExternalEndpointList contains a list of ExternalEndpoint
+type ExternalEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExternalEndpoint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExternalEndpoint{}, &ExternalEndpointList{})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-flows-netobserv-io-v1alpha1-externalendpoint,mutating=false,failurePolicy=fail,groups=flows.netobserv.io,resources=externalendpoints,versions=v1alpha1,name=externalendpointvalidationwebhook.netobserv.io,sideEffects=None,admissionReviewVersions=v1
+func (r *ExternalEndpoint) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&externalEndpointValidator{}).
+		Complete()
+}
+
+// externalEndpointValidator rejects the IPs and CIDRs that flowlogs-pipeline would fail to parse
+type externalEndpointValidator struct{}
+
+var _ admission.CustomValidator = &externalEndpointValidator{}
+
+func (v *externalEndpointValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ee, ok := obj.(*ExternalEndpoint)
+	if !ok {
+		return nil, kerr.NewBadRequest(fmt.Sprintf("expected an ExternalEndpoint but got a %T", obj))
+	}
+	return nil, validate(ee)
+}
+
+func (v *externalEndpointValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	ee, ok := newObj.(*ExternalEndpoint)
+	if !ok {
+		return nil, kerr.NewBadRequest(fmt.Sprintf("expected an ExternalEndpoint but got a %T", newObj))
+	}
+	return nil, validate(ee)
+}
+
+func (v *externalEndpointValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validate(ee *ExternalEndpoint) error {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "cidrs")
+	for i, cidr := range ee.Spec.CIDRs {
+		if strings.Contains(cidr, "/") {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				errs = append(errs, field.Invalid(path.Index(i), cidr, "must be a CIDR, such as 192.168.10.0/24"))
+			}
+		} else if net.ParseIP(cidr) == nil {
+			errs = append(errs, field.Invalid(path.Index(i), cidr, "must be an IP address or a CIDR"))
+		}
+	}
+	if len(errs) > 0 {
+		return kerr.NewInvalid(GroupVersion.WithKind("ExternalEndpoint").GroupKind(), ee.Name, errs)
+	}
+	return nil
+}
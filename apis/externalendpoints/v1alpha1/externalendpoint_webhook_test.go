@@ -0,0 +1,33 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateCIDRs(t *testing.T) {
+	assert := assert.New(t)
+	v := externalEndpointValidator{}
+	endpoint := func(cidrs ...string) *ExternalEndpoint {
+		return &ExternalEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: "partner-api"},
+			Spec:       ExternalEndpointSpec{CIDRs: cidrs},
+		}
+	}
+
+	_, err := v.ValidateCreate(context.Background(), endpoint("192.168.10.0/24", "203.0.113.12", "2001:db8::/32", "::1"))
+	assert.NoError(err)
+
+	_, err = v.ValidateCreate(context.Background(), endpoint("192.168.10.0/24", "192.168.10.0/33", "not-an-ip"))
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.cidrs[1]")
+	assert.Contains(err.Error(), "spec.cidrs[2]")
+	assert.NotContains(err.Error(), "spec.cidrs[0]")
+
+	_, err = v.ValidateUpdate(context.Background(), endpoint("10.0.0.0/8"), endpoint("10.0.0.256"))
+	assert.True(kerr.IsInvalid(err))
+}
@@ -51,7 +51,56 @@ func (r *FlowCollector) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.Loki.Monolithic = restored.Spec.Loki.Monolithic
 	dst.Spec.Loki.Microservices = restored.Spec.Loki.Microservices
 	dst.Spec.Loki.Manual = restored.Spec.Loki.Manual
+	dst.Spec.Loki.Migration = restored.Spec.Loki.Migration
+	dst.Spec.Loki.Aggregation = restored.Spec.Loki.Aggregation
+	dst.Spec.CommonLabels = restored.Spec.CommonLabels
+	dst.Spec.CommonAnnotations = restored.Spec.CommonAnnotations
+	dst.Spec.ClusterRBAC = restored.Spec.ClusterRBAC
+	dst.Spec.QueryService = restored.Spec.QueryService
+	dst.Spec.Proxy = restored.Spec.Proxy
+	dst.Spec.Analytics = restored.Spec.Analytics
+	dst.Spec.RetentionPolicy = restored.Spec.RetentionPolicy
+	dst.Spec.Hosted = restored.Spec.Hosted
+	dst.Spec.Diagnostics = restored.Spec.Diagnostics
+	dst.Spec.LoadGenerator = restored.Spec.LoadGenerator
+	dst.Spec.RollbackTo = restored.Spec.RollbackTo
+	dst.Spec.Kafka.Advanced = restored.Spec.Kafka.Advanced
+	dst.Spec.Processor.Anonymization = restored.Spec.Processor.Anonymization
+	dst.Spec.Processor.KafkaSource = restored.Spec.Processor.KafkaSource
+	dst.Spec.Processor.ExternalIngest = restored.Spec.Processor.ExternalIngest
+	dst.Spec.Processor.Virtualization = restored.Spec.Processor.Virtualization
+	dst.Spec.Processor.ServiceMesh = restored.Spec.Processor.ServiceMesh
+	dst.Spec.Processor.DSCPClassification = restored.Spec.Processor.DSCPClassification
+	dst.Spec.Processor.MultiHoming = restored.Spec.Processor.MultiHoming
+	dst.Spec.Processor.EgressClassification = restored.Spec.Processor.EgressClassification
+	dst.Spec.Processor.ClusterNetworkOperatorManaged = restored.Spec.Processor.ClusterNetworkOperatorManaged
+	dst.Spec.Processor.BatchAutoTuning = restored.Spec.Processor.BatchAutoTuning
+	dst.Spec.Processor.KafkaConsumerAutoscaler.Behavior = restored.Spec.Processor.KafkaConsumerAutoscaler.Behavior
+	dst.Spec.Processor.Metrics.RBACProxy = restored.Spec.Processor.Metrics.RBACProxy
+	dst.Spec.Processor.Metrics.Prefix = restored.Spec.Processor.Metrics.Prefix
+	dst.Spec.Processor.Metrics.StaticLabels = restored.Spec.Processor.Metrics.StaticLabels
+	dst.Spec.Processor.Metrics.Routing = restored.Spec.Processor.Metrics.Routing
+	dst.Spec.Processor.Metrics.FlowMetricsQuota = restored.Spec.Processor.Metrics.FlowMetricsQuota
+	dst.Spec.Processor.Metrics.MaxCardinality = restored.Spec.Processor.Metrics.MaxCardinality
+	dst.Spec.Processor.Metrics.CardinalityGuard = restored.Spec.Processor.Metrics.CardinalityGuard
+	dst.Spec.Processor.Metrics.PipelineHealthCheck = restored.Spec.Processor.Metrics.PipelineHealthCheck
+	dst.Spec.ConsolePlugin.AccessMode = restored.Spec.ConsolePlugin.AccessMode
+	dst.Spec.ConsolePlugin.Autoscaler.Behavior = restored.Spec.ConsolePlugin.Autoscaler.Behavior
+	dst.Spec.ConsolePlugin.AutoscalerUsage = restored.Spec.ConsolePlugin.AutoscalerUsage
+	dst.Spec.ConsolePlugin.DeveloperPerspective = restored.Spec.ConsolePlugin.DeveloperPerspective
+	for i := range dst.Spec.Exporters {
+		if i < len(restored.Spec.Exporters) && restored.Spec.Exporters[i] != nil {
+			dst.Spec.Exporters[i].Anonymization = restored.Spec.Exporters[i].Anonymization
+			dst.Spec.Exporters[i].Fields = restored.Spec.Exporters[i].Fields
+			dst.Spec.Exporters[i].Filters = restored.Spec.Exporters[i].Filters
+			dst.Spec.Exporters[i].Kafka.Advanced = restored.Spec.Exporters[i].Kafka.Advanced
+			dst.Spec.Exporters[i].Loki = restored.Spec.Exporters[i].Loki
+			dst.Spec.Exporters[i].ClusterLogForwarder = restored.Spec.Exporters[i].ClusterLogForwarder
+		}
+	}
 
+	dst.Spec.Agent.EBPF.AttachMode = restored.Spec.Agent.EBPF.AttachMode
+	dst.Spec.Agent.OVN = restored.Spec.Agent.OVN
 	if restored.Spec.Agent.EBPF.Advanced != nil {
 		if dst.Spec.Agent.EBPF.Advanced == nil {
 			dst.Spec.Agent.EBPF.Advanced = &v1beta2.AdvancedAgentConfig{}
@@ -65,6 +114,8 @@ func (r *FlowCollector) ConvertTo(dstRaw conversion.Hub) error {
 			dst.Spec.Agent.EBPF.Advanced.Scheduling.Affinity = restored.Spec.Agent.EBPF.Advanced.Scheduling.Affinity
 			dst.Spec.Agent.EBPF.Advanced.Scheduling.PriorityClassName = restored.Spec.Agent.EBPF.Advanced.Scheduling.PriorityClassName
 		}
+		dst.Spec.Agent.EBPF.Advanced.UpdateStrategy = restored.Spec.Agent.EBPF.Advanced.UpdateStrategy
+		dst.Spec.Agent.EBPF.Advanced.GoRuntime = restored.Spec.Agent.EBPF.Advanced.GoRuntime
 	}
 	if restored.Spec.Processor.Advanced != nil {
 		if dst.Spec.Processor.Advanced == nil {
@@ -79,6 +130,11 @@ func (r *FlowCollector) ConvertTo(dstRaw conversion.Hub) error {
 			dst.Spec.Processor.Advanced.Scheduling.Affinity = restored.Spec.Processor.Advanced.Scheduling.Affinity
 			dst.Spec.Processor.Advanced.Scheduling.PriorityClassName = restored.Spec.Processor.Advanced.Scheduling.PriorityClassName
 		}
+		dst.Spec.Processor.Advanced.UpdateStrategy = restored.Spec.Processor.Advanced.UpdateStrategy
+		dst.Spec.Processor.Advanced.DeploymentStrategy = restored.Spec.Processor.Advanced.DeploymentStrategy
+		dst.Spec.Processor.Advanced.OverridesConfigMap = restored.Spec.Processor.Advanced.OverridesConfigMap
+		dst.Spec.Processor.Advanced.Probes = restored.Spec.Processor.Advanced.Probes
+		dst.Spec.Processor.Advanced.GoRuntime = restored.Spec.Processor.Advanced.GoRuntime
 	}
 	if restored.Spec.ConsolePlugin.Advanced != nil {
 		if dst.Spec.ConsolePlugin.Advanced == nil {
@@ -93,6 +149,11 @@ func (r *FlowCollector) ConvertTo(dstRaw conversion.Hub) error {
 			dst.Spec.ConsolePlugin.Advanced.Scheduling.Affinity = restored.Spec.ConsolePlugin.Advanced.Scheduling.Affinity
 			dst.Spec.ConsolePlugin.Advanced.Scheduling.PriorityClassName = restored.Spec.ConsolePlugin.Advanced.Scheduling.PriorityClassName
 		}
+		dst.Spec.ConsolePlugin.Advanced.DeploymentStrategy = restored.Spec.ConsolePlugin.Advanced.DeploymentStrategy
+		dst.Spec.ConsolePlugin.Advanced.OverridesConfigMap = restored.Spec.ConsolePlugin.Advanced.OverridesConfigMap
+		dst.Spec.ConsolePlugin.Advanced.Probes = restored.Spec.ConsolePlugin.Advanced.Probes
+		dst.Spec.ConsolePlugin.Advanced.ServiceAnnotations = restored.Spec.ConsolePlugin.Advanced.ServiceAnnotations
+		dst.Spec.ConsolePlugin.Advanced.ServingCert = restored.Spec.ConsolePlugin.Advanced.ServingCert
 	}
 	ClearDefaultAdvancedConfig(dst)
 
@@ -520,3 +581,17 @@ func Convert_v1beta2_FlowCollectorEBPF_To_v1beta1_FlowCollectorEBPF(in *v1beta2.
 	}
 	return autoConvert_v1beta2_FlowCollectorEBPF_To_v1beta1_FlowCollectorEBPF(in, out, s)
 }
+
+// This function need to be manually created because conversion-gen not able to create it intentionally because
+// we have new defined fields in v1beta2 not in v1beta1
+// nolint:golint,stylecheck,revive
+func Convert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(in *v1beta2.FlowCollectorKafka, out *FlowCollectorKafka, s apiconversion.Scope) error {
+	return autoConvert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(in, out, s)
+}
+
+// This function need to be manually created because conversion-gen not able to create it intentionally because
+// we have new defined fields in v1beta2 not in v1beta1
+// nolint:golint,stylecheck,revive
+func Convert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus(in *v1beta2.FlowCollectorStatus, out *FlowCollectorStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus(in, out, s)
+}
@@ -133,11 +133,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.FlowCollectorKafka)(nil), (*FlowCollectorKafka)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(a.(*v1beta2.FlowCollectorKafka), b.(*FlowCollectorKafka), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*FlowCollectorList)(nil), (*v1beta2.FlowCollectorList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_FlowCollectorList_To_v1beta2_FlowCollectorList(a.(*FlowCollectorList), b.(*v1beta2.FlowCollectorList), scope)
 	}); err != nil {
@@ -153,11 +148,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta2.FlowCollectorStatus)(nil), (*FlowCollectorStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus(a.(*v1beta2.FlowCollectorStatus), b.(*FlowCollectorStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*MetricsServerConfig)(nil), (*v1beta2.MetricsServerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_MetricsServerConfig_To_v1beta2_MetricsServerConfig(a.(*MetricsServerConfig), b.(*v1beta2.MetricsServerConfig), scope)
 	}); err != nil {
@@ -323,6 +313,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.FlowCollectorKafka)(nil), (*FlowCollectorKafka)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(a.(*v1beta2.FlowCollectorKafka), b.(*FlowCollectorKafka), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.FlowCollectorLoki)(nil), (*FlowCollectorLoki)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_FlowCollectorLoki_To_v1beta1_FlowCollectorLoki(a.(*v1beta2.FlowCollectorLoki), b.(*FlowCollectorLoki), scope)
 	}); err != nil {
@@ -333,6 +328,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta2.FlowCollectorStatus)(nil), (*FlowCollectorStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus(a.(*v1beta2.FlowCollectorStatus), b.(*FlowCollectorStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta2.FlowCollector)(nil), (*FlowCollector)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_FlowCollector_To_v1beta1_FlowCollector(a.(*v1beta2.FlowCollector), b.(*FlowCollector), scope)
 	}); err != nil {
@@ -535,7 +535,15 @@ func autoConvert_v1beta2_FLPMetrics_To_v1beta1_FLPMetrics(in *v1beta2.FLPMetrics
 	if err := Convert_v1beta2_MetricsServerConfig_To_v1beta1_MetricsServerConfig(&in.Server, &out.Server, s); err != nil {
 		return err
 	}
+	// WARNING: in.RBACProxy requires manual conversion: does not exist in peer-type
 	out.IncludeList = (*[]FLPMetric)(unsafe.Pointer(in.IncludeList))
+	// WARNING: in.Prefix requires manual conversion: does not exist in peer-type
+	// WARNING: in.StaticLabels requires manual conversion: does not exist in peer-type
+	// WARNING: in.Routing requires manual conversion: does not exist in peer-type
+	// WARNING: in.FlowMetricsQuota requires manual conversion: does not exist in peer-type
+	// WARNING: in.MaxCardinality requires manual conversion: does not exist in peer-type
+	// WARNING: in.CardinalityGuard requires manual conversion: does not exist in peer-type
+	// WARNING: in.PipelineHealthCheck requires manual conversion: does not exist in peer-type
 	out.DisableAlerts = *(*[]FLPAlert)(unsafe.Pointer(&in.DisableAlerts))
 	return nil
 }
@@ -607,6 +615,7 @@ func autoConvert_v1beta2_FlowCollectorAgent_To_v1beta1_FlowCollectorAgent(in *v1
 	if err := Convert_v1beta2_FlowCollectorEBPF_To_v1beta1_FlowCollectorEBPF(&in.EBPF, &out.EBPF, s); err != nil {
 		return err
 	}
+	// WARNING: in.OVN requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -637,10 +646,13 @@ func autoConvert_v1beta2_FlowCollectorConsolePlugin_To_v1beta1_FlowCollectorCons
 	if err := Convert_v1beta2_FlowCollectorHPA_To_v1beta1_FlowCollectorHPA(&in.Autoscaler, &out.Autoscaler, s); err != nil {
 		return err
 	}
+	// WARNING: in.AutoscalerUsage requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta2_ConsolePluginPortConfig_To_v1beta1_ConsolePluginPortConfig(&in.PortNaming, &out.PortNaming, s); err != nil {
 		return err
 	}
 	out.QuickFilters = *(*[]QuickFilter)(unsafe.Pointer(&in.QuickFilters))
+	// WARNING: in.AccessMode requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeveloperPerspective requires manual conversion: does not exist in peer-type
 	// WARNING: in.Advanced requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -676,6 +688,7 @@ func autoConvert_v1beta2_FlowCollectorEBPF_To_v1beta1_FlowCollectorEBPF(in *v1be
 	out.LogLevel = in.LogLevel
 	out.Privileged = in.Privileged
 	out.KafkaBatchSize = in.KafkaBatchSize
+	// WARNING: in.AttachMode requires manual conversion: does not exist in peer-type
 	// WARNING: in.Advanced requires manual conversion: does not exist in peer-type
 	out.Features = *(*[]AgentFeature)(unsafe.Pointer(&in.Features))
 	if err := Convert_v1beta2_EBPFMetrics_To_v1beta1_EBPFMetrics(&in.Metrics, &out.Metrics, s); err != nil {
@@ -704,6 +717,11 @@ func autoConvert_v1beta2_FlowCollectorExporter_To_v1beta1_FlowCollectorExporter(
 	if err := Convert_v1beta2_FlowCollectorIPFIXReceiver_To_v1beta1_FlowCollectorIPFIXReceiver(&in.IPFIX, &out.IPFIX, s); err != nil {
 		return err
 	}
+	// WARNING: in.Loki requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClusterLogForwarder requires manual conversion: does not exist in peer-type
+	// WARNING: in.Anonymization requires manual conversion: does not exist in peer-type
+	// WARNING: in.Fields requires manual conversion: does not exist in peer-type
+	// WARNING: in.Filters requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -752,6 +770,7 @@ func autoConvert_v1beta2_FlowCollectorFLP_To_v1beta1_FlowCollectorFLP(in *v1beta
 	}
 	out.KafkaConsumerQueueCapacity = in.KafkaConsumerQueueCapacity
 	out.KafkaConsumerBatchSize = in.KafkaConsumerBatchSize
+	// WARNING: in.BatchAutoTuning requires manual conversion: does not exist in peer-type
 	out.LogTypes = (*string)(unsafe.Pointer(in.LogTypes))
 	out.ClusterName = in.ClusterName
 	out.MultiClusterDeployment = (*bool)(unsafe.Pointer(in.MultiClusterDeployment))
@@ -759,6 +778,15 @@ func autoConvert_v1beta2_FlowCollectorFLP_To_v1beta1_FlowCollectorFLP(in *v1beta
 	if err := Convert_v1beta2_SubnetLabels_To_v1beta1_SubnetLabels(&in.SubnetLabels, &out.SubnetLabels, s); err != nil {
 		return err
 	}
+	// WARNING: in.ClusterNetworkOperatorManaged requires manual conversion: does not exist in peer-type
+	// WARNING: in.Anonymization requires manual conversion: does not exist in peer-type
+	// WARNING: in.KafkaSource requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExternalIngest requires manual conversion: does not exist in peer-type
+	// WARNING: in.Virtualization requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServiceMesh requires manual conversion: does not exist in peer-type
+	// WARNING: in.DSCPClassification requires manual conversion: does not exist in peer-type
+	// WARNING: in.MultiHoming requires manual conversion: does not exist in peer-type
+	// WARNING: in.EgressClassification requires manual conversion: does not exist in peer-type
 	// WARNING: in.Advanced requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -776,6 +804,7 @@ func autoConvert_v1beta2_FlowCollectorHPA_To_v1beta1_FlowCollectorHPA(in *v1beta
 	out.MinReplicas = (*int32)(unsafe.Pointer(in.MinReplicas))
 	out.MaxReplicas = in.MaxReplicas
 	out.Metrics = *(*[]v2.MetricSpec)(unsafe.Pointer(&in.Metrics))
+	// WARNING: in.Behavior requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -867,14 +896,10 @@ func autoConvert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(in *v1
 	if err := Convert_v1beta2_SASLConfig_To_v1beta1_SASLConfig(&in.SASL, &out.SASL, s); err != nil {
 		return err
 	}
+	// WARNING: in.Advanced requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka is an autogenerated conversion function.
-func Convert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(in *v1beta2.FlowCollectorKafka, out *FlowCollectorKafka, s conversion.Scope) error {
-	return autoConvert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(in, out, s)
-}
-
 func autoConvert_v1beta1_FlowCollectorList_To_v1beta2_FlowCollectorList(in *FlowCollectorList, out *v1beta2.FlowCollectorList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -948,6 +973,8 @@ func autoConvert_v1beta2_FlowCollectorLoki_To_v1beta1_FlowCollectorLoki(in *v1be
 	// WARNING: in.WriteTimeout requires manual conversion: does not exist in peer-type
 	// WARNING: in.WriteBatchWait requires manual conversion: does not exist in peer-type
 	// WARNING: in.WriteBatchSize requires manual conversion: does not exist in peer-type
+	// WARNING: in.Migration requires manual conversion: does not exist in peer-type
+	// WARNING: in.Aggregation requires manual conversion: does not exist in peer-type
 	// WARNING: in.Advanced requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -976,6 +1003,9 @@ func autoConvert_v1beta1_FlowCollectorSpec_To_v1beta2_FlowCollectorSpec(in *Flow
 
 func autoConvert_v1beta2_FlowCollectorSpec_To_v1beta1_FlowCollectorSpec(in *v1beta2.FlowCollectorSpec, out *FlowCollectorSpec, s conversion.Scope) error {
 	out.Namespace = in.Namespace
+	// WARNING: in.CommonLabels requires manual conversion: does not exist in peer-type
+	// WARNING: in.CommonAnnotations requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClusterRBAC requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta2_FlowCollectorAgent_To_v1beta1_FlowCollectorAgent(&in.Agent, &out.Agent, s); err != nil {
 		return err
 	}
@@ -988,11 +1018,19 @@ func autoConvert_v1beta2_FlowCollectorSpec_To_v1beta1_FlowCollectorSpec(in *v1be
 	if err := Convert_v1beta2_FlowCollectorConsolePlugin_To_v1beta1_FlowCollectorConsolePlugin(&in.ConsolePlugin, &out.ConsolePlugin, s); err != nil {
 		return err
 	}
+	// WARNING: in.QueryService requires manual conversion: does not exist in peer-type
 	out.DeploymentModel = string(in.DeploymentModel)
 	if err := Convert_v1beta2_FlowCollectorKafka_To_v1beta1_FlowCollectorKafka(&in.Kafka, &out.Kafka, s); err != nil {
 		return err
 	}
 	// INFO: in.Exporters opted out of conversion generation
+	// WARNING: in.Proxy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Analytics requires manual conversion: does not exist in peer-type
+	// WARNING: in.RetentionPolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Hosted requires manual conversion: does not exist in peer-type
+	// WARNING: in.Diagnostics requires manual conversion: does not exist in peer-type
+	// WARNING: in.LoadGenerator requires manual conversion: does not exist in peer-type
+	// WARNING: in.RollbackTo requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -1009,15 +1047,16 @@ func Convert_v1beta1_FlowCollectorStatus_To_v1beta2_FlowCollectorStatus(in *Flow
 
 func autoConvert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus(in *v1beta2.FlowCollectorStatus, out *FlowCollectorStatus, s conversion.Scope) error {
 	out.Conditions = *(*[]v1.Condition)(unsafe.Pointer(&in.Conditions))
+	// WARNING: in.ObservedGeneration requires manual conversion: does not exist in peer-type
 	out.Namespace = in.Namespace
+	// WARNING: in.Components requires manual conversion: does not exist in peer-type
+	// WARNING: in.Agent requires manual conversion: does not exist in peer-type
+	// WARNING: in.LokiMigration requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClusterNetwork requires manual conversion: does not exist in peer-type
+	// WARNING: in.BatchTuning requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus is an autogenerated conversion function.
-func Convert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus(in *v1beta2.FlowCollectorStatus, out *FlowCollectorStatus, s conversion.Scope) error {
-	return autoConvert_v1beta2_FlowCollectorStatus_To_v1beta1_FlowCollectorStatus(in, out, s)
-}
-
 func autoConvert_v1beta1_MetricsServerConfig_To_v1beta2_MetricsServerConfig(in *MetricsServerConfig, out *v1beta2.MetricsServerConfig, s conversion.Scope) error {
 	out.Port = in.Port
 	if err := Convert_v1beta1_ServerTLS_To_v1beta2_ServerTLS(&in.TLS, &out.TLS, s); err != nil {
@@ -16,8 +16,10 @@ limitations under the License.
 package v1beta2
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	ascv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -51,6 +53,21 @@ type FlowCollectorSpec struct {
 	// +kubebuilder:default:=netobserv
 	Namespace string `json:"namespace,omitempty"`
 
+	// `commonLabels` are labels added to every object managed by the operator, including pods, in addition to the
+	// `app.kubernetes.io` recommended labels. They don't override these recommended labels, nor the labels set by the operator.
+	// +optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// `commonAnnotations` are annotations added to every object managed by the operator, including pods. They don't override
+	// the annotations set by the operator.
+	// +optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+
+	// `clusterRBAC` defines how the cluster-scoped RBAC objects needed by the components, that is ClusterRoles and
+	// ClusterRoleBindings, are managed.
+	// +optional
+	ClusterRBAC FlowCollectorClusterRBAC `json:"clusterRBAC,omitempty"`
+
 	// Agent configuration for flows extraction.
 	Agent FlowCollectorAgent `json:"agent,omitempty"`
 
@@ -64,6 +81,10 @@ type FlowCollectorSpec struct {
 	// `consolePlugin` defines the settings related to the OpenShift Console plugin, when available.
 	ConsolePlugin FlowCollectorConsolePlugin `json:"consolePlugin,omitempty"`
 
+	// `queryService` defines the settings of the flow query service, a REST API for automation, command-line tools and third-party UIs.
+	// +optional
+	QueryService FlowCollectorQueryService `json:"queryService,omitempty"`
+
 	// `deploymentModel` defines the desired type of deployment for flow processing. Possible values are:<br>
 	// - `Direct` (default) to make the flow processor listening directly from the agents.<br>
 	// - `Kafka` to make flows sent to a Kafka pipeline before consumption by the processor.<br>
@@ -81,6 +102,103 @@ type FlowCollectorSpec struct {
 	// +optional
 	// +k8s:conversion-gen=false
 	Exporters []*FlowCollectorExporter `json:"exporters"`
+
+	// `proxy` defines the HTTP(S) proxy settings used by flowlogs-pipeline and the console plugin for their outbound connections,
+	// such as an external Loki or an exporter endpoint.
+	// +optional
+	Proxy FlowCollectorProxy `json:"proxy,omitempty"`
+
+	// `analytics` defines the settings of the flow analytics, which derive insights such as traffic anomalies from the flow metrics.
+	// It requires the Prometheus Operator API (`PrometheusRule`) to be available in the cluster.
+	// +optional
+	Analytics FlowCollectorAnalytics `json:"analytics,omitempty"`
+
+	// `retentionPolicy` defines how flow data is kept over time. Raw flows are stored in Loki, where retention can be kept short,
+	// while long-term trends can be materialized as aggregated Prometheus series, which are much cheaper to keep for months.
+	// It requires the Prometheus Operator API (`PrometheusRule`) to be available in the cluster.
+	// +optional
+	RetentionPolicy FlowCollectorRetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// `hosted` defines a turnkey profile to send flows to a hosted observability service, such as Grafana Cloud,
+	// for clusters that run neither Loki nor the OpenShift Console.
+	// +optional
+	Hosted FlowCollectorHosted `json:"hosted,omitempty"`
+
+	// `diagnostics` defines the settings of the NetObserv must-gather, which collects the resources and logs needed to troubleshoot NetObserv.
+	// +optional
+	Diagnostics FlowCollectorDiagnostics `json:"diagnostics,omitempty"`
+
+	// `loadGenerator` defines a flow generator, for development and QE purposes only, that feeds synthetic flows to a
+	// dedicated flowlogs-pipeline running the same processing as configured in `spec.processor`, in order to plan the
+	// capacity of the stack before enabling the capture cluster-wide. It measures this replica of the pipeline, not the deployed one.
+	// Generated flows are written to Loki with the `app` label `netobserv-load-generator`, in a stream separate from the real flows
+	// that the console plugin doesn't query, and are not sent to the exporters.
+	// +optional
+	LoadGenerator FlowCollectorLoadGenerator `json:"loadGenerator,omitempty"`
+
+	// `rollbackTo` reverts the specification to a previous generation of the FlowCollector. The operator keeps a snapshot of the
+	// last generations that were ready for a few minutes, in the `flowcollector-snapshots` config map of the `spec.namespace` namespace,
+	// with one key per generation, such as `generation-12`. The target snapshot is validated before it replaces the specification,
+	// which also clears this field. When it can't be applied, the error is reported in the `SnapshotsReady` condition.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RollbackTo *int64 `json:"rollbackTo,omitempty"`
+}
+
+type ClusterRBACMode string
+
+const (
+	ClusterRBACManaged ClusterRBACMode = "Managed"
+	ClusterRBACManual  ClusterRBACMode = "Manual"
+)
+
+// `FlowCollectorClusterRBAC` defines how the cluster-scoped RBAC objects are managed.
+type FlowCollectorClusterRBAC struct {
+	// `mode` is `Managed` for the operator to create and update the ClusterRoles and ClusterRoleBindings, or `Manual` for
+	// clusters where the operator is intentionally not allowed to. In `Manual` mode, the operator only writes their manifests
+	// in the `netobserv-cluster-rbac` config map of the `spec.namespace` namespace, for an administrator to apply them.
+	// Until they are applied, the components that need them report a `MissingPermission` failure in their condition.
+	// +kubebuilder:validation:Enum:="Managed";"Manual"
+	// +kubebuilder:default:=Managed
+	// +optional
+	Mode ClusterRBACMode `json:"mode,omitempty"`
+}
+
+type HostedProfile string
+
+const (
+	HostedProfileDisabled     HostedProfile = "Disabled"
+	HostedProfileGrafanaCloud HostedProfile = "GrafanaCloud"
+)
+
+// `FlowCollectorHosted` defines a profile for hosted observability services.
+type FlowCollectorHosted struct {
+	// `profile` is the hosted service to send flows to:<br>
+	// - `Disabled` (default) uses the `loki` and `consolePlugin` settings.<br>
+	// - `GrafanaCloud` writes flows to the Grafana Cloud Logs instance configured in `grafanaCloud`, and disables the console plugin.
+	// The `loki` connection settings are then ignored.<br>
+	// In both cases, metrics are exposed by flowlogs-pipeline for scraping, for example by a Grafana agent that remote-writes them.
+	//+kubebuilder:validation:Enum:="Disabled";"GrafanaCloud"
+	//+kubebuilder:default:="Disabled"
+	// +optional
+	Profile HostedProfile `json:"profile,omitempty"`
+
+	// `grafanaCloud` defines the Grafana Cloud stack to use, when `profile` is `GrafanaCloud`.
+	// +optional
+	GrafanaCloud HostedGrafanaCloud `json:"grafanaCloud,omitempty"`
+}
+
+// `HostedGrafanaCloud` defines the Grafana Cloud Logs instance to write flows to.
+type HostedGrafanaCloud struct {
+	// `logsUrl` is the URL of the Grafana Cloud Logs instance of the stack, for example `https://logs-prod-eu-west-0.grafana.net`.
+	//+kubebuilder:validation:Pattern:=`^https://`
+	LogsURL string `json:"logsUrl,omitempty"`
+
+	// `logsUser` is the user of the Grafana Cloud Logs instance, as shown in the Grafana Cloud portal.
+	LogsUser string `json:"logsUser,omitempty"`
+
+	// `apiKey` is the reference to the file containing a Grafana Cloud access policy token, with the `logs:write` scope.
+	APIKey FileReference `json:"apiKey,omitempty"`
 }
 
 type FlowCollectorAgentType string
@@ -112,6 +230,32 @@ type FlowCollectorAgent struct {
 	// is set to `eBPF`.
 	// +optional
 	EBPF FlowCollectorEBPF `json:"ebpf,omitempty"`
+
+	// `ovn` describes the settings related to the ingestion of OVN-Kubernetes observability samples,
+	// such as network policy verdicts, which are merged into the eBPF flows.
+	// +optional
+	OVN FlowCollectorOVN `json:"ovn,omitempty"`
+}
+
+// `FlowCollectorOVN` defines how the eBPF agent reads the samples emitted by OVN-Kubernetes observability.
+type FlowCollectorOVN struct {
+	// Set `enable` to `true` to read the samples emitted by OVN-Kubernetes observability, such as network policy (ACL) verdicts
+	// and drop reasons, and to add them to the flows as network events. No extra kernel hook is attached for this.
+	// It requires OVN-Kubernetes with the observability feature enabled, and the eBPF agent running in privileged mode
+	// (`spec.agent.ebpf.privileged`).
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	//+kubebuilder:default:="/var/run/openvswitch"
+	// `ovsRunDir` is the host directory containing the Open vSwitch database socket, used to decode the samples.
+	// +optional
+	OVSRunDir string `json:"ovsRunDir,omitempty"`
+
+	//+kubebuilder:default:="/var/run/ovn"
+	// `ovnRunDir` is the host directory containing the OVN northbound database socket, used to resolve the network policies
+	// from the samples. On OpenShift, it is `/var/run/ovn-ic`.
+	// +optional
+	OVNRunDir string `json:"ovnRunDir,omitempty"`
 }
 
 // `FlowCollectorIPFIX` defines a FlowCollector that uses IPFIX on OVN-Kubernetes to collect the
@@ -164,6 +308,16 @@ const (
 	FlowRTT     AgentFeature = "FlowRTT"
 )
 
+// `EBPFAttachMode` is the way the eBPF agent attaches its programs to the network interfaces.
+// +kubebuilder:validation:Enum:="Any";"TC";"TCX"
+type EBPFAttachMode string
+
+const (
+	EBPFAttachAny EBPFAttachMode = "Any"
+	EBPFAttachTC  EBPFAttachMode = "TC"
+	EBPFAttachTCX EBPFAttachMode = "TCX"
+)
+
 // `EBPFMetrics` defines the desired eBPF agent configuration regarding metrics
 type EBPFMetrics struct {
 	// Metrics server endpoint configuration for Prometheus scraper
@@ -295,6 +449,15 @@ type FlowCollectorEBPF struct {
 	// `kafkaBatchSize` limits the maximum size of a request in bytes before being sent to a partition. Ignored when not using Kafka. Default: 1MB.
 	KafkaBatchSize int `json:"kafkaBatchSize"`
 
+	// `attachMode` defines how the eBPF programs are attached to the network interfaces. Possible values are:<br>
+	// - `Any` (default): use TCX when the node kernel supports it (Linux 6.6 and above), otherwise fall back to TC.<br>
+	// - `TCX`: use TCX links, which let several eBPF programs, such as Cilium's, coexist on the same interface. It fails on older kernels.<br>
+	// - `TC`: use legacy TC filters. Other eBPF users attaching TC programs might replace the agent programs, or be replaced by them.<br>
+	// Conflicting eBPF users detected in the cluster are reported in `status.agent.conflicts`.
+	//+kubebuilder:default:=Any
+	// +optional
+	AttachMode EBPFAttachMode `json:"attachMode,omitempty"`
+
 	// `advanced` allows setting some aspects of the internal configuration of the eBPF agent.
 	// This section is aimed mostly for debugging and fine-grained performance optimizations,
 	// such as `GOGC` and `GOMAXPROCS` env vars. Set these values at your own risk.
@@ -338,6 +501,95 @@ type FlowCollectorKafka struct {
 	// SASL authentication configuration. [Unsupported (*)].
 	// +optional
 	SASL SASLConfig `json:"sasl"`
+
+	// `advanced` allows tuning the Kafka producer. This section is aimed at fine-grained performance optimizations,
+	// such as avoiding throughput bottlenecks or broker-side "message too large" errors.
+	// +optional
+	Advanced *AdvancedKafkaConfig `json:"advanced,omitempty"`
+}
+
+type KafkaCompression string
+
+const (
+	KafkaCompressionNone   KafkaCompression = "None"
+	KafkaCompressionGzip   KafkaCompression = "Gzip"
+	KafkaCompressionSnappy KafkaCompression = "Snappy"
+	KafkaCompressionLz4    KafkaCompression = "Lz4"
+	KafkaCompressionZstd   KafkaCompression = "Zstd"
+)
+
+type KafkaBalancer string
+
+const (
+	KafkaBalancerRoundRobin KafkaBalancer = "RoundRobin"
+	KafkaBalancerLeastBytes KafkaBalancer = "LeastBytes"
+	KafkaBalancerHash       KafkaBalancer = "Hash"
+	KafkaBalancerCrc32      KafkaBalancer = "Crc32"
+	KafkaBalancerMurmur2    KafkaBalancer = "Murmur2"
+)
+
+// `AdvancedKafkaConfig` defines the Kafka producer settings. Unset fields keep the producer defaults.
+type AdvancedKafkaConfig struct {
+	// `batchBytes` limits the maximum size of a request in bytes before being sent to a partition. It must not exceed the broker `message.max.bytes`.
+	// When set in `spec.kafka`, it overrides `spec.agent.ebpf.kafkaBatchSize`.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchBytes *int64 `json:"batchBytes,omitempty"`
+
+	// `batchMessages` limits how many messages are buffered before being sent to a partition.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BatchMessages *int32 `json:"batchMessages,omitempty"`
+
+	// `compression` is the codec used to compress messages: `None`, `Gzip`, `Snappy`, `Lz4` or `Zstd`.
+	// It is only applied by the eBPF agent, in `spec.kafka`; it is ignored in `spec.exporters`.
+	// +kubebuilder:validation:Enum:="None";"Gzip";"Snappy";"Lz4";"Zstd"
+	// +optional
+	Compression KafkaCompression `json:"compression,omitempty"`
+
+	// `balancer` is the strategy used to distribute messages across partitions: `RoundRobin`, `LeastBytes`, `Hash`, `Crc32` or `Murmur2`.
+	// It is only applied by flowlogs-pipeline, in `spec.exporters`; it is ignored in `spec.kafka`.
+	// +kubebuilder:validation:Enum:="RoundRobin";"LeastBytes";"Hash";"Crc32";"Murmur2"
+	// +optional
+	Balancer KafkaBalancer `json:"balancer,omitempty"`
+
+	// `writeTimeout` is the timeout of write operations. It is only applied by flowlogs-pipeline, in `spec.exporters`; it is ignored in `spec.kafka`.
+	// +optional
+	WriteTimeout *metav1.Duration `json:"writeTimeout,omitempty"`
+}
+
+type KafkaDecoder string
+
+const (
+	KafkaDecoderProtobuf KafkaDecoder = "Protobuf"
+	KafkaDecoderJSON     KafkaDecoder = "JSON"
+)
+
+// `FlowCollectorKafkaSource` defines an external Kafka topic to consume flows from
+type FlowCollectorKafkaSource struct {
+	// Address of the Kafka server
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Address string `json:"address"`
+
+	// Kafka topic to consume from. It must exist. NetObserv does not create it.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Topic string `json:"topic"`
+
+	// `decoder` is the format of the messages in the topic: `Protobuf` (as produced by the NetObserv eBPF agent) or `JSON`.
+	// +kubebuilder:validation:Enum:="Protobuf";"JSON"
+	// +kubebuilder:default:=Protobuf
+	// +optional
+	Decoder KafkaDecoder `json:"decoder,omitempty"`
+
+	// TLS client configuration. When using TLS, verify that the address matches the Kafka port used for TLS, generally 9093.
+	// +optional
+	TLS ClientTLS `json:"tls"`
+
+	// SASL authentication configuration. [Unsupported (*)].
+	// +optional
+	SASL SASLConfig `json:"sasl"`
 }
 
 type FlowCollectorIPFIXReceiver struct {
@@ -403,20 +655,62 @@ type MetricsServerConfig struct {
 	TLS ServerTLS `json:"tls"`
 }
 
+// `MetricsRBACProxy` configures kube-rbac-proxy in front of a metrics endpoint.
+type MetricsRBACProxy struct {
+	// Set `enable` to `true` to serve the metrics through a kube-rbac-proxy sidecar. Scraping then requires a bearer token
+	// allowed to `get` the `/metrics` non-resource URL, such as the one of the cluster monitoring Prometheus.
+	// The metrics server TLS must be enabled (`server.tls.type` set to `Auto` or `Provided`): its certificate is served by the proxy,
+	// while the metrics server only listens on localhost.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type FlowMetricsTier string
+
+const (
+	FlowMetricsPlatform     FlowMetricsTier = "Platform"
+	FlowMetricsUserWorkload FlowMetricsTier = "UserWorkload"
+	FlowMetricsExternal     FlowMetricsTier = "External"
+)
+
+// `MetricsRouting` defines which Prometheus scrapes the flow metrics, apart from the health metrics of NetObserv.
+type MetricsRouting struct {
+	// `flowMetrics` defines which Prometheus scrapes the flow metrics, that are the metrics derived from flows, including the ones defined by `FlowMetric` resources.
+	// The health metrics of flowlogs-pipeline remain scraped by the platform Prometheus. Possible values are:<br>
+	// - `Platform` (default) to scrape flow metrics and health metrics with a single `ServiceMonitor`.<br>
+	// - `UserWorkload` to scrape flow metrics with the OpenShift user-workload monitoring. The FlowCollector namespace is then no longer labelled
+	// for the platform monitoring, and health metrics are scraped from a `ServiceMonitor` installed in the privileged namespace.<br>
+	// - `External` to scrape flow metrics with another Prometheus, that selects the flow metrics `ServiceMonitor` by its labels.<br>
+	// +kubebuilder:validation:Enum:="Platform";"UserWorkload";"External"
+	// +kubebuilder:default:="Platform"
+	// +optional
+	FlowMetrics FlowMetricsTier `json:"flowMetrics,omitempty"`
+
+	// `serviceMonitorLabels` are additional labels set on the flow metrics `ServiceMonitor` when `flowMetrics` is not `Platform`,
+	// for instance to match the `serviceMonitorSelector` of an external Prometheus.
+	// +optional
+	ServiceMonitorLabels map[string]string `json:"serviceMonitorLabels,omitempty"`
+}
+
 // Name of a processor alert.
 // Possible values are:<br>
 // - `NetObservNoFlows`, which is triggered when no flows are being observed for a certain period.<br>
 // - `NetObservLokiError`, which is triggered when flows are being dropped due to Loki errors.<br>
-// +kubebuilder:validation:Enum:="NetObservNoFlows";"NetObservLokiError"
+// - `NetObservPipelineErrors`, which is triggered when the error ratio of a flowlogs-pipeline stage exceeds 5%.<br>
+// - `NetObservCardinalityBudgetExceeded`, which is triggered when the NetObserv metrics exceed `spec.processor.metrics.maxCardinality` series.<br>
+// +kubebuilder:validation:Enum:="NetObservNoFlows";"NetObservLokiError";"NetObservPipelineErrors";"NetObservCardinalityBudgetExceeded"
 type FLPAlert string
 
 const (
-	AlertNoFlows   FLPAlert = "NetObservNoFlows"
-	AlertLokiError FLPAlert = "NetObservLokiError"
+	AlertNoFlows        FLPAlert = "NetObservNoFlows"
+	AlertLokiError      FLPAlert = "NetObservLokiError"
+	AlertPipelineErrors FLPAlert = "NetObservPipelineErrors"
+	AlertCardinality    FLPAlert = "NetObservCardinalityBudgetExceeded"
 )
 
 // Metric name. More information in https://github.com/netobserv/network-observability-operator/blob/main/docs/Metrics.md.
-// +kubebuilder:validation:Enum:="namespace_egress_bytes_total";"namespace_egress_packets_total";"namespace_ingress_bytes_total";"namespace_ingress_packets_total";"namespace_flows_total";"node_egress_bytes_total";"node_egress_packets_total";"node_ingress_bytes_total";"node_ingress_packets_total";"node_flows_total";"workload_egress_bytes_total";"workload_egress_packets_total";"workload_ingress_bytes_total";"workload_ingress_packets_total";"workload_flows_total";"namespace_drop_bytes_total";"namespace_drop_packets_total";"node_drop_bytes_total";"node_drop_packets_total";"workload_drop_bytes_total";"workload_drop_packets_total";"namespace_rtt_seconds";"node_rtt_seconds";"workload_rtt_seconds";"namespace_dns_latency_seconds";"node_dns_latency_seconds";"workload_dns_latency_seconds"
+// +kubebuilder:validation:Enum:="namespace_egress_bytes_total";"namespace_egress_packets_total";"namespace_ingress_bytes_total";"namespace_ingress_packets_total";"namespace_flows_total";"node_egress_bytes_total";"node_egress_packets_total";"node_ingress_bytes_total";"node_ingress_packets_total";"node_flows_total";"workload_egress_bytes_total";"workload_egress_packets_total";"workload_ingress_bytes_total";"workload_ingress_packets_total";"workload_flows_total";"namespace_drop_bytes_total";"namespace_drop_packets_total";"node_drop_bytes_total";"node_drop_packets_total";"workload_drop_bytes_total";"workload_drop_packets_total";"namespace_rtt_seconds";"node_rtt_seconds";"workload_rtt_seconds";"namespace_dns_latency_seconds";"node_dns_latency_seconds";"workload_dns_latency_seconds";"namespace_conversations_total";"namespace_conversation_bytes";"node_conversations_total";"node_conversation_bytes";"workload_conversations_total";"workload_conversation_bytes";"vm_egress_bytes_total";"vm_egress_packets_total";"vm_ingress_bytes_total";"vm_ingress_packets_total";"vm_flows_total";"mesh_service_ingress_bytes_total";"mesh_sidecar_ingress_bytes_total";"namespace_dscp_egress_bytes_total";"namespace_dscp_egress_packets_total";"namespace_dscp_ingress_bytes_total";"namespace_dscp_ingress_packets_total";"node_dscp_egress_bytes_total";"node_dscp_egress_packets_total";"node_dscp_ingress_bytes_total";"node_dscp_ingress_packets_total";"workload_dscp_egress_bytes_total";"workload_dscp_egress_packets_total";"workload_dscp_ingress_bytes_total";"workload_dscp_ingress_packets_total";"namespace_icmp_error_packets_total";"node_icmp_error_packets_total";"workload_icmp_error_packets_total";"namespace_sctp_egress_bytes_total";"namespace_sctp_egress_packets_total";"namespace_sctp_ingress_bytes_total";"namespace_sctp_ingress_packets_total";"node_sctp_egress_bytes_total";"node_sctp_egress_packets_total";"node_sctp_ingress_bytes_total";"node_sctp_ingress_packets_total";"workload_sctp_egress_bytes_total";"workload_sctp_egress_packets_total";"workload_sctp_ingress_bytes_total";"workload_sctp_ingress_packets_total";"namespace_egress_class_bytes_total";"node_egress_class_bytes_total";"workload_egress_class_bytes_total"
 type FLPMetric string
 
 // `FLPMetrics` define the desired FLP configuration regarding metrics
@@ -425,6 +719,10 @@ type FLPMetrics struct {
 	// +optional
 	Server MetricsServerConfig `json:"server,omitempty"`
 
+	// `rbacProxy` configures kube-rbac-proxy in front of the metrics endpoint, to restrict scraping to authorized clients.
+	// +optional
+	RBACProxy MetricsRBACProxy `json:"rbacProxy,omitempty"`
+
 	// `includeList` is a list of metric names to specify which ones to generate.
 	// The names correspond to the names in Prometheus without the prefix. For example,
 	// `namespace_egress_packets_total` shows up as `netobserv_namespace_egress_packets_total` in Prometheus.
@@ -436,14 +734,168 @@ type FLPMetrics struct {
 	// +optional
 	IncludeList *[]FLPMetric `json:"includeList,omitempty"`
 
+	// `prefix` is prepended to the name of every flow metric generated by flowlogs-pipeline, including the ones defined by `FlowMetric` resources.
+	// Change it to avoid collisions when several clusters are federated into a single Prometheus.
+	// Dashboards and analytics rules managed by the operator follow this prefix.
+	//+kubebuilder:validation:Pattern:=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	//+kubebuilder:default:="netobserv_"
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+
+	// `staticLabels` is a map of common labels to set on every flow metric generated by flowlogs-pipeline,
+	// for instance `cluster: prod-eu`, so that metrics from several clusters can be told apart without any relabeling configuration.
+	// Keys must be valid Prometheus label names.
+	// +optional
+	StaticLabels map[string]string `json:"staticLabels,omitempty"`
+
+	// `routing` sends the flow metrics to a different Prometheus than the health metrics, for instance to the user-workload monitoring,
+	// to separate platform and tenant observability.
+	// +optional
+	Routing MetricsRouting `json:"routing,omitempty"`
+
+	// `flowMetricsQuota` limits the `FlowMetric` resources that can be created in each namespace, to protect Prometheus from
+	// accidental cardinality explosions when several teams define their own metrics. It is enforced on creation and update of `FlowMetric` resources,
+	// by a webhook that is ignored when the operator is unavailable: this keeps `FlowMetric` resources editable during an operator outage or upgrade,
+	// at the cost of not enforcing the quota meanwhile.
+	// +optional
+	FlowMetricsQuota *FlowMetricsQuota `json:"flowMetricsQuota,omitempty"`
+
+	//+kubebuilder:validation:Minimum=0
+	// `maxCardinality` is the budget of Prometheus series for all the NetObserv metrics, including the ones defined by `FlowMetric` resources.
+	// When set, the operator periodically counts these series in Prometheus, reports a warning in the FlowCollector status when the budget is exceeded,
+	// and applies the action configured in `cardinalityGuard`. The `NetObservCardinalityBudgetExceeded` alert is also triggered. Zero or unset means no budget.
+	// +optional
+	MaxCardinality int32 `json:"maxCardinality,omitempty"`
+
+	// `cardinalityGuard` configures how the operator checks and enforces `maxCardinality`.
+	// +optional
+	CardinalityGuard CardinalityGuard `json:"cardinalityGuard,omitempty"`
+
+	// `pipelineHealthCheck` allows the operator to check the error ratio of each flowlogs-pipeline stage in Prometheus,
+	// and to report a degraded pipeline in the FlowCollector status.
+	// +optional
+	PipelineHealthCheck PipelineHealthCheck `json:"pipelineHealthCheck,omitempty"`
+
 	// `disableAlerts` is a list of alerts that should be disabled.
 	// Possible values are:<br>
 	// `NetObservNoFlows`, which is triggered when no flows are being observed for a certain period.<br>
 	// `NetObservLokiError`, which is triggered when flows are being dropped due to Loki errors.<br>
+	// `NetObservPipelineErrors`, which is triggered when the error ratio of a flowlogs-pipeline stage exceeds 5%.<br>
+	// `NetObservCardinalityBudgetExceeded`, which is triggered when the NetObserv metrics exceed `maxCardinality` series.<br>
 	// +optional
 	DisableAlerts []FLPAlert `json:"disableAlerts"`
 }
 
+type CardinalityGuardAction string
+
+const (
+	CardinalityGuardAlert             CardinalityGuardAction = "Alert"
+	CardinalityGuardDisableFlowMetric CardinalityGuardAction = "DisableFlowMetric"
+)
+
+// `CardinalityGuard` defines how the operator checks the cardinality of the NetObserv metrics
+type CardinalityGuard struct {
+	// `action` to take when `maxCardinality` is exceeded:<br>
+	// - `Alert` (default) only reports it, as a warning in the FlowCollector status and with an alert.<br>
+	// - `DisableFlowMetric` additionally disables the `FlowMetric` generating the most series, with a `Disabled` condition in its status and an event.
+	// At most one `FlowMetric` is disabled per `interval`, and no more than every 5 minutes, so that the series of the previous one expire. A disabled `FlowMetric` is enabled again when its spec is modified.<br>
+	//+kubebuilder:validation:Enum:="Alert";"DisableFlowMetric"
+	//+kubebuilder:default:="Alert"
+	// +optional
+	Action CardinalityGuardAction `json:"action,omitempty"`
+
+	// `url` of the Prometheus API queried to count the series. The operator authenticates with its service account token.
+	//+kubebuilder:default:="https://thanos-querier.openshift-monitoring.svc:9091/"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// `insecureSkipVerify` allows skipping the verification of the Prometheus server certificate.
+	// Otherwise, it is verified with the system certificates and the service CA of the cluster.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// `interval` between two checks of the cardinality.
+	//+kubebuilder:default:="5m"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// `PipelineHealthCheck` defines how the operator checks the error ratio of the flowlogs-pipeline stages
+type PipelineHealthCheck struct {
+	// `enable` the pipeline health check. The operator periodically queries the error ratio of each flowlogs-pipeline stage
+	// in Prometheus, and reports a `PipelineDegraded` warning in the FlowCollector status while a stage fails to process
+	// more than 5% of the flows, the threshold of the `NetObservPipelineErrors` alert.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// `url` of the Prometheus API queried for the error ratio. The operator authenticates with its service account token.
+	//+kubebuilder:default:="https://thanos-querier.openshift-monitoring.svc:9091/"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// `insecureSkipVerify` allows skipping the verification of the Prometheus server certificate.
+	// Otherwise, it is verified with the system certificates and the service CA of the cluster.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// `interval` between two checks of the error ratio. Values under one minute are raised to one minute.
+	//+kubebuilder:default:="5m"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+type BatchProfile string
+
+const (
+	BatchProfileLowTraffic  BatchProfile = "LowTraffic"
+	BatchProfileDefault     BatchProfile = "Default"
+	BatchProfileHighTraffic BatchProfile = "HighTraffic"
+)
+
+// `FLPBatchAutoTuning` defines how the operator adjusts the batch sizes of flowlogs-pipeline to the observed flow rate
+type FLPBatchAutoTuning struct {
+	// `enable` batch auto-tuning. The operator periodically queries the rate of processed flows in Prometheus, and picks
+	// one of the following profiles, with bounded values:<br>
+	// - `LowTraffic`, under 1000 flows per second: Loki batches of 100KiB sent every 500ms, Kafka consumer batches of 1MiB.<br>
+	// - `Default`: Loki batches of 100KiB sent every second, Kafka consumer batches of 10MiB.<br>
+	// - `HighTraffic`, above 20000 flows per second: Loki batches of 1MiB sent every 2s, Kafka consumer batches of 50MiB.<br>
+	// The profile changes by one step at a time, and only when the flow rate is 20% beyond the threshold, to avoid
+	// restarting flowlogs-pipeline on small variations.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// `url` of the Prometheus API queried for the flow rate. The operator authenticates with its service account token.
+	//+kubebuilder:default:="https://thanos-querier.openshift-monitoring.svc:9091/"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// `insecureSkipVerify` allows skipping the verification of the Prometheus server certificate.
+	// Otherwise, it is verified with the system certificates and the service CA of the cluster.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+
+	// `interval` between two checks of the flow rate. Values under one minute are raised to one minute.
+	//+kubebuilder:default:="5m"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// `FlowMetricsQuota` defines the limits applied per namespace to the `FlowMetric` resources
+type FlowMetricsQuota struct {
+	//+kubebuilder:validation:Minimum=0
+	// `maxMetrics` is the maximum number of `FlowMetric` resources in a namespace. Zero or unset means no limit.
+	// +optional
+	MaxMetrics int32 `json:"maxMetrics,omitempty"`
+
+	//+kubebuilder:validation:Minimum=0
+	// `maxLabels` is the maximum number of labels, summed over all the `FlowMetric` resources of a namespace. Zero or unset means no limit.
+	// Each label is a dimension of the generated metric, multiplying its cardinality.
+	// +optional
+	MaxLabels int32 `json:"maxLabels,omitempty"`
+}
+
 type FLPLogTypes string
 
 const (
@@ -497,6 +949,13 @@ type FlowCollectorFLP struct {
 	// `kafkaConsumerBatchSize` indicates to the broker the maximum batch size, in bytes, that the consumer accepts. Ignored when not using Kafka. Default: 10MB.
 	KafkaConsumerBatchSize int `json:"kafkaConsumerBatchSize"`
 
+	// `batchAutoTuning` allows the operator to adjust the batch sizes of flowlogs-pipeline to the observed flow rate,
+	// to keep latency low at low traffic and throughput high at peak. When enabled, the tuned values replace
+	// `spec.loki.writeBatchSize`, `spec.loki.writeBatchWait` and `spec.processor.kafkaConsumerBatchSize`.
+	// The values in use are reported in `status.batchTuning`.
+	// +optional
+	BatchAutoTuning FLPBatchAutoTuning `json:"batchAutoTuning,omitempty"`
+
 	// `logTypes` defines the desired record types to generate. Possible values are:<br>
 	// - `Flows` (default) to export regular network flows<br>
 	// - `Conversations` to generate events for started conversations, ended conversations as well as periodic "tick" updates<br>
@@ -526,6 +985,57 @@ type FlowCollectorFLP struct {
 	// When a subnet matches the source or destination IP of a flow, a corresponding field is added: `SrcSubnetLabel` or `DstSubnetLabel`.
 	SubnetLabels SubnetLabels `json:"subnetLabels,omitempty"`
 
+	// `clusterNetworkOperatorManaged` allows, when set to `true`, to read the cluster network configuration (pods and services CIDRs,
+	// MTU and network type) from the OpenShift Cluster Network Operator, and to update the processor configuration when it changes.
+	// The pods and services CIDRs classify flow destinations in `egressClassification`, even when `subnetLabels.openShiftAutoDetect`
+	// is disabled, and the network type is checked for the features depending on OVN-Kubernetes. The configuration read is reported
+	// in `status.clusterNetwork`. Enabled by default on OpenShift.
+	// +optional
+	ClusterNetworkOperatorManaged *bool `json:"clusterNetworkOperatorManaged,omitempty"`
+
+	// `anonymization` allows to redact sensitive information from flows before they are stored in Loki, sent to exporters
+	// or turned into metrics, such as in privacy-sensitive environments. It can be overridden per exporter.
+	// +optional
+	Anonymization *Anonymization `json:"anonymization,omitempty"`
+
+	// `kafkaSource` allows `flowlogs-pipeline-transformer` to consume flows from an existing Kafka topic, produced by another tool
+	// such as a NetObserv instance running on another cluster, or a custom producer.
+	// It is ignored when `spec.deploymentModel` is `Kafka`, in which case flows are consumed from `spec.kafka`.
+	// +optional
+	KafkaSource *FlowCollectorKafkaSource `json:"kafkaSource,omitempty"`
+
+	// `externalIngest` exposes the flow ingest endpoint outside of the cluster, so that agents running on VMs or bare metal hosts
+	// can send flows into the same pipeline. Senders use the `pbflow.Collector/Send` gRPC method of the eBPF agent protobuf schema,
+	// authenticated by a service account token or a client certificate.
+	// It is only available when `spec.deploymentModel` is `Direct`.
+	// +optional
+	ExternalIngest FLPExternalIngest `json:"externalIngest,omitempty"`
+
+	// `virtualization` allows attributing flows to KubeVirt virtual machines, such as in OpenShift Virtualization,
+	// instead of their `virt-launcher` pods.
+	// +optional
+	Virtualization FLPVirtualization `json:"virtualization,omitempty"`
+
+	// `serviceMesh` allows attributing flows to the services and revisions of an Istio service mesh, such as OpenShift Service Mesh,
+	// in metrics.
+	// +optional
+	ServiceMesh FLPServiceMesh `json:"serviceMesh,omitempty"`
+
+	// `dscpClassification` allows classifying flows in the service classes of their Differentiated Services Code Point (DSCP),
+	// such as to verify the QoS marking of traffic end-to-end.
+	// +optional
+	DSCPClassification FLPDSCPClassification `json:"dscpClassification,omitempty"`
+
+	// `multiHoming` allows attributing the flows on the secondary network interfaces of pods, such as Multus interfaces
+	// used by multi-homed SCTP associations, to their pods.
+	// +optional
+	MultiHoming FLPMultiHoming `json:"multiHoming,omitempty"`
+
+	// `egressClassification` allows classifying the destinations of flows as in-cluster, node, external private or internet,
+	// such as to estimate the cost of the egress traffic.
+	// +optional
+	EgressClassification FLPEgressClassification `json:"egressClassification,omitempty"`
+
 	// `advanced` allows setting some aspects of the internal configuration of the flow processor.
 	// This section is aimed mostly for debugging and fine-grained performance optimizations,
 	// such as `GOGC` and `GOMAXPROCS` env vars. Set these values at your own risk.
@@ -533,59 +1043,186 @@ type FlowCollectorFLP struct {
 	Advanced *AdvancedProcessorConfig `json:"advanced,omitempty"`
 }
 
-type HPAStatus string
+type ExternalIngestAuthType string
 
 const (
-	HPAStatusDisabled HPAStatus = "Disabled"
-	HPAStatusEnabled  HPAStatus = "Enabled"
+	ExternalIngestAuthToken ExternalIngestAuthType = "Token"
+	ExternalIngestAuthMTLS  ExternalIngestAuthType = "MTLS"
 )
 
-type FlowCollectorHPA struct {
-	// +kubebuilder:validation:Enum:=Disabled;Enabled
-	// +kubebuilder:default:=Disabled
-	// `status` describes the desired status regarding deploying an horizontal pod autoscaler.<br>
-	// - `Disabled` does not deploy an horizontal pod autoscaler.<br>
-	// - `Enabled` deploys an horizontal pod autoscaler.<br>
-	Status HPAStatus `json:"status,omitempty"`
+// `FLPExternalIngest` defines the endpoint accepting flows from senders running outside of the cluster.
+type FLPExternalIngest struct {
+	// Set `enable` to `true` to expose the ingest endpoint.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
 
-	// `minReplicas` is the lower limit for the number of replicas to which the autoscaler
-	// can scale down. It defaults to 1 pod. minReplicas is allowed to be 0 if the
-	// alpha feature gate HPAScaleToZero is enabled and at least one Object or External
-	// metric is configured. Scaling is active as long as at least one metric value is
-	// available.
+	// `port` is the TLS port of the endpoint, served by kube-rbac-proxy in front of the flowlogs-pipeline gRPC ingest.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=65535
+	//+kubebuilder:default:=9443
 	// +optional
-	MinReplicas *int32 `json:"minReplicas,omitempty" protobuf:"varint,2,opt,name=minReplicas"`
+	Port int32 `json:"port,omitempty"`
 
-	// `maxReplicas` is the upper limit for the number of pods that can be set by the autoscaler; cannot be smaller than MinReplicas.
-	// +kubebuilder:default:=3
+	// `serviceType` is the type of the `Service` exposing the endpoint: `LoadBalancer` (default), `NodePort` or `ClusterIP`.
+	//+kubebuilder:validation:Enum:="ClusterIP";"NodePort";"LoadBalancer"
+	//+kubebuilder:default:=LoadBalancer
 	// +optional
-	MaxReplicas int32 `json:"maxReplicas" protobuf:"varint,3,opt,name=maxReplicas"`
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
 
-	// Metrics used by the pod autoscaler. For documentation, refer to https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/horizontal-pod-autoscaler-v2/
+	// `authentication` defines how senders authenticate:<br>
+	// - `Token` (default) for a bearer token, such as a service account token, reviewed by the Kubernetes API.<br>
+	// - `MTLS` to also accept client certificates signed by `clientCA`, the certificate common name being the user name.<br>
+	// In both cases, senders must be authorized to `create` on the `/pbflow.Collector/Send` non-resource URL,
+	// for instance by binding them to the `flowlogs-pipeline-ingest-sender` cluster role.
+	//+kubebuilder:validation:Enum:="Token";"MTLS"
+	//+kubebuilder:default:=Token
 	// +optional
-	Metrics []ascv2.MetricSpec `json:"metrics"`
-}
+	Authentication ExternalIngestAuthType `json:"authentication,omitempty"`
 
-type LokiAuthToken string
+	// `serverCert` references the certificate and key served by the endpoint. When not set, a certificate is generated
+	// by the OpenShift service CA, which is only trusted inside of the cluster.
+	// +optional
+	ServerCert *CertificateReference `json:"serverCert,omitempty"`
 
-const (
-	LokiAuthDisabled         LokiAuthToken = "Disabled"
-	LokiAuthUseHostToken     LokiAuthToken = "Host"
-	LokiAuthForwardUserToken LokiAuthToken = "Forward"
-)
+	// `clientCA` references the CA used to verify the client certificates, when `authentication` is `MTLS`.
+	// +optional
+	ClientCA *FileReference `json:"clientCA,omitempty"`
+}
 
-// `LokiManualParams` defines the full connection parameters to Loki.
-type LokiManualParams struct {
-	//+kubebuilder:default:="http://loki:3100/"
-	// `ingesterUrl` is the address of an existing Loki ingester service to push the flows to. When using the Loki Operator,
-	// set it to the Loki gateway service with the `network` tenant set in path, for example
-	// https://loki-gateway-http.netobserv.svc:8080/api/logs/v1/network.
-	IngesterURL string `json:"ingesterUrl,omitempty"`
+// `FLPVirtualization` defines the enrichment of flows with KubeVirt virtual machines.
+type FLPVirtualization struct {
+	// Set `enable` to `true` to add the `SrcK8S_VirtualMachine` and `DstK8S_VirtualMachine` fields to flows, and enable the `vm_*` metrics.
+	// The IP addresses of the virtual machines are read from their `VirtualMachineInstance` network interfaces, including
+	// secondary interfaces: flows on these interfaces also get the namespace and owner of the virtual machine.
+	// It requires KubeVirt or OpenShift Virtualization to be installed. Note that flowlogs-pipeline is restarted
+	// when the IP addresses of the virtual machines change.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+}
 
-	//+kubebuilder:default:="http://loki:3100/"
-	// `querierUrl` specifies the address of the Loki querier service.
-	// When using the Loki Operator, set it to the Loki gateway service with the `network` tenant set in path, for example
-	// https://loki-gateway-http.netobserv.svc:8080/api/logs/v1/network.
+// `FLPServiceMesh` defines the enrichment of flows with the Istio service registry data.
+type FLPServiceMesh struct {
+	// Set `enable` to `true` to add the Istio canonical service, canonical revision and sidecar TLS mode of pods to flows,
+	// as the `*K8S_MeshService`, `*K8S_MeshRevision` and `*K8S_MeshTLSMode` fields. They are read from the labels set by
+	// the sidecar injector. It enables the `mesh_*` metrics and the "NetObserv / Service mesh" dashboard.
+	// These fields are only available to metrics, including `FlowMetric` resources: they are not stored in Loki nor exported.
+	// It requires Istio or OpenShift Service Mesh to be installed, in sidecar mode.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+}
+
+// `FLPDSCPClassification` defines the classification of flows from their DSCP value.
+type FLPDSCPClassification struct {
+	// Set `enable` to `true` to add the `DscpClass` field to flows, naming the service class of their DSCP value as defined in RFC 4594,
+	// such as `Telephony`, `Multimedia Streaming` or `Standard`. DSCP values that are not part of these classes are named `Other`.
+	// It enables the `*_dscp_*` metrics.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+}
+
+// `FLPMultiHoming` defines the enrichment of flows with the secondary network addresses of pods.
+type FLPMultiHoming struct {
+	// Set `enable` to `true` to read the addresses of the secondary networks of pods from the `k8s.v1.cni.cncf.io/network-status`
+	// annotation set by Multus, and to set the Kubernetes fields of flows on these addresses, such as `SrcK8S_Name`, `SrcK8S_Namespace`
+	// and `SrcK8S_OwnerName`. All the paths of a multi-homed SCTP association are then attributed to the same pods.
+	// Note that flowlogs-pipeline is restarted when the secondary addresses of pods change.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+}
+
+// `FLPEgressClassification` defines the classification of flows from their destination address.
+type FLPEgressClassification struct {
+	// Set `enable` to `true` to add the `DstEgressClass` field to flows, naming the class of their destination: `InCluster` for pods
+	// and services, `Node` for nodes, `ExternalPrivate` for the private ranges outside of the cluster and `Internet` for everything else.
+	// On OpenShift, the pods, services and machines networks are detected automatically.
+	// It enables the `*_egress_class_*` metrics and the egress cost dashboard.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// `clusterCIDRs` are additional ranges classified as `InCluster`, such as the pods and services networks when they cannot be detected.
+	// +optional
+	ClusterCIDRs []string `json:"clusterCIDRs,omitempty"`
+
+	// `privateCIDRs` are the ranges classified as `ExternalPrivate` when they are not part of the cluster, such as peered networks
+	// or on-premise datacenters. When empty, the IPv4 private and shared address spaces (RFC 1918 and RFC 6598), and the IPv6 unique
+	// local addresses (RFC 4193) are used.
+	// +optional
+	PrivateCIDRs []string `json:"privateCIDRs,omitempty"`
+
+	// `costPerGB` is the price of the egress traffic to the internet, in US dollars per gigabyte, used to estimate the egress cost
+	// in the dashboard. Flows are scaled by the sampling ratio in this estimation.
+	// +kubebuilder:validation:Pattern:=`^[0-9]+(\.[0-9]+)?$`
+	//+kubebuilder:default:="0.09"
+	// +optional
+	CostPerGB string `json:"costPerGB,omitempty"`
+}
+
+type HPAStatus string
+
+const (
+	HPAStatusDisabled HPAStatus = "Disabled"
+	HPAStatusEnabled  HPAStatus = "Enabled"
+)
+
+type FlowCollectorHPA struct {
+	// +kubebuilder:validation:Enum:=Disabled;Enabled
+	// +kubebuilder:default:=Disabled
+	// `status` describes the desired status regarding deploying an horizontal pod autoscaler.<br>
+	// - `Disabled` does not deploy an horizontal pod autoscaler.<br>
+	// - `Enabled` deploys an horizontal pod autoscaler.<br>
+	Status HPAStatus `json:"status,omitempty"`
+
+	// `minReplicas` is the lower limit for the number of replicas to which the autoscaler
+	// can scale down. It defaults to 1 pod. minReplicas is allowed to be 0 if the
+	// alpha feature gate HPAScaleToZero is enabled and at least one Object or External
+	// metric is configured. Scaling is active as long as at least one metric value is
+	// available.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty" protobuf:"varint,2,opt,name=minReplicas"`
+
+	// `maxReplicas` is the upper limit for the number of pods that can be set by the autoscaler; cannot be smaller than MinReplicas.
+	// +kubebuilder:default:=3
+	// +optional
+	MaxReplicas int32 `json:"maxReplicas" protobuf:"varint,3,opt,name=maxReplicas"`
+
+	// Metrics used by the pod autoscaler. For documentation, refer to https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/horizontal-pod-autoscaler-v2/
+	// +optional
+	Metrics []ascv2.MetricSpec `json:"metrics"`
+
+	// `behavior` configures the scaling behavior of the autoscaler in both up and down directions, such as stabilization windows
+	// and scaling policies. For instance, a longer `scaleDown.stabilizationWindowSeconds` avoids flapping under bursty load.
+	// When omitted, the Kubernetes defaults are used. For documentation, refer to https://kubernetes.io/docs/reference/kubernetes-api/workload-resources/horizontal-pod-autoscaler-v2/
+	// +optional
+	Behavior *ascv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+type LokiAuthToken string
+
+const (
+	LokiAuthDisabled         LokiAuthToken = "Disabled"
+	LokiAuthUseHostToken     LokiAuthToken = "Host"
+	LokiAuthForwardUserToken LokiAuthToken = "Forward"
+	LokiAuthSecretToken      LokiAuthToken = "Secret"
+)
+
+// `LokiManualParams` defines the full connection parameters to Loki.
+type LokiManualParams struct {
+	//+kubebuilder:default:="http://loki:3100/"
+	// `ingesterUrl` is the address of an existing Loki ingester service to push the flows to. When using the Loki Operator,
+	// set it to the Loki gateway service with the `network` tenant set in path, for example
+	// https://loki-gateway-http.netobserv.svc:8080/api/logs/v1/network.
+	IngesterURL string `json:"ingesterUrl,omitempty"`
+
+	//+kubebuilder:default:="http://loki:3100/"
+	// `querierUrl` specifies the address of the Loki querier service.
+	// When using the Loki Operator, set it to the Loki gateway service with the `network` tenant set in path, for example
+	// https://loki-gateway-http.netobserv.svc:8080/api/logs/v1/network.
 	QuerierURL string `json:"querierUrl,omitempty"`
 
 	//+kubebuilder:validation:optional
@@ -602,15 +1239,27 @@ type LokiManualParams struct {
 	// When using the Loki Operator, set it to `network`, which corresponds to a special tenant mode.
 	TenantID string `json:"tenantID,omitempty"`
 
-	//+kubebuilder:validation:Enum:="Disabled";"Host";"Forward"
+	//+kubebuilder:validation:Enum:="Disabled";"Host";"Forward";"Secret"
 	//+kubebuilder:default:="Disabled"
 	// `authToken` describes the way to get a token to authenticate to Loki.<br>
 	// - `Disabled` does not send any token with the request.<br>
 	// - `Forward` forwards the user token for authorization.<br>
 	// - `Host` [deprecated (*)] - uses the local pod service account to authenticate to Loki.<br>
+	// - `Secret` sends the bearer token read from `authTokenSecret`, for example a hosted Loki API token.<br>
 	// When using the Loki Operator, this must be set to `Forward`.
 	AuthToken LokiAuthToken `json:"authToken,omitempty"`
 
+	// `authTokenSecret` is the reference to the file containing the bearer token, when `authToken` is `Secret`.
+	// The token is read again on each request, so that it can be rotated without restarting flowlogs-pipeline.
+	// +optional
+	AuthTokenSecret *FileReference `json:"authTokenSecret,omitempty"`
+
+	// `basicAuth` configures the HTTP basic authentication to Loki, as used by most hosted Loki services.
+	// For Grafana Cloud, set the Loki instance user as `username`, and an API key as `password`.
+	// It cannot be combined with `authToken`, which must be `Disabled`.
+	// +optional
+	BasicAuth *LokiBasicAuth `json:"basicAuth,omitempty"`
+
 	// TLS client configuration for Loki URL.
 	// +optional
 	TLS ClientTLS `json:"tls"`
@@ -620,6 +1269,16 @@ type LokiManualParams struct {
 	StatusTLS ClientTLS `json:"statusTls"`
 }
 
+// `LokiBasicAuth` defines the HTTP basic authentication to Loki.
+type LokiBasicAuth struct {
+	// `username` is the basic authentication user.
+	Username string `json:"username"`
+
+	// `password` is the reference to the file containing the basic authentication password.
+	// The password is read again on each request, so that it can be rotated without restarting flowlogs-pipeline.
+	Password FileReference `json:"password"`
+}
+
 // LokiMicroservicesParams is the configuration for microservices Loki (https://grafana.com/docs/loki/latest/fundamentals/architecture/deployment-modes/#microservices-mode)
 type LokiMicroservicesParams struct {
 	//+kubebuilder:default:="http://loki-distributor:3100/"
@@ -654,6 +1313,50 @@ type LokiMonolithParams struct {
 	TLS ClientTLS `json:"tls"`
 }
 
+type LokiMigrationReadFrom string
+
+const (
+	LokiMigrationReadFromCurrent LokiMigrationReadFrom = "Current"
+	LokiMigrationReadFromTarget  LokiMigrationReadFrom = "Target"
+)
+
+// LokiMigration defines a blue/green migration from the current Loki to a new one
+type LokiMigration struct {
+	// `target` is the configuration of the new Loki. During the migration, flows are written both to the current Loki,
+	// configured in `spec.loki`, and to the target Loki.
+	// +kubebuilder:validation:Required
+	Target LokiManualParams `json:"target"`
+
+	//+kubebuilder:validation:Enum:="Current";"Target"
+	//+kubebuilder:default:="Current"
+	// `readFrom` selects the Loki queried by the console plugin:<br>
+	// - `Current` reads from the Loki configured in `spec.loki`.<br>
+	// - `Target` reads from the target Loki, which should be set once the target holds enough history.
+	ReadFrom LokiMigrationReadFrom `json:"readFrom,omitempty"`
+
+	//+kubebuilder:default:="24h"
+	// `overlapPeriod` is how long flows must be written to both Loki before the target is considered to hold enough history.
+	// It is typically the retention period of the current Loki.
+	OverlapPeriod *metav1.Duration `json:"overlapPeriod,omitempty"` // Warning: keep as pointer, else default is ignored
+}
+
+// LokiAggregation defines how flows are rolled up before they are written to Loki
+type LokiAggregation struct {
+	//+kubebuilder:default:=false
+	// Set `enable` to `true` to write aggregated records to Loki, instead of every flow.
+	Enable *bool `json:"enable,omitempty"`
+
+	//+kubebuilder:default:="15s"
+	// `window` is the interval at which a record is written for each active key set, with the totals since the key set became active.
+	// A last record, with `_RecordType` set to `endConnection`, is written once no flow matched the key set during `window`.
+	Window *metav1.Duration `json:"window,omitempty"` // Warning: keep as pointer, else default is ignored
+
+	// `groupBy` is the list of flow fields, in addition to the Loki labels, that identify a key set, such as `SrcK8S_Name` and `DstK8S_Name`
+	// to aggregate per pod pair. By default, flows are aggregated by Loki labels only: per owner pair, flow layer and direction.
+	// +optional
+	GroupBy []string `json:"groupBy,omitempty"`
+}
+
 // LokiStackRef defines the name and namespace of the LokiStack instance
 type LokiStackRef struct {
 	// Name of an existing LokiStack resource to use.
@@ -735,6 +1438,17 @@ type FlowCollectorLoki struct {
 	// `writeBatchSize` is the maximum batch size (in bytes) of Loki logs to accumulate before sending.
 	WriteBatchSize int64 `json:"writeBatchSize,omitempty"`
 
+	// `migration` allows switching to a new Loki without losing visibility: flows are written to both Loki,
+	// while the console plugin reads from the one selected in `readFrom`. The migration progress is reported in `status.lokiMigration`.
+	// +optional
+	Migration *LokiMigration `json:"migration,omitempty"`
+
+	// `aggregation` rolls up the flows written to Loki per key set, such as per owner pair, to reduce the storage volume
+	// by an order of magnitude when per-flow granularity isn't needed. Metrics and exporters still receive every flow.
+	// It requires `spec.processor.logTypes` to be `Flows`.
+	// +optional
+	Aggregation *LokiAggregation `json:"aggregation,omitempty"`
+
 	// `advanced` allows setting some aspects of the internal configuration of the Loki clients.
 	// This section is aimed mostly for debugging and fine-grained performance optimizations.
 	// +optional
@@ -775,6 +1489,14 @@ type FlowCollectorConsolePlugin struct {
 	// +optional
 	Autoscaler FlowCollectorHPA `json:"autoscaler,omitempty"`
 
+	// `autoscalerUsage` scales the plugin on its own usage: the rate or the latency of the HTTP requests served by each pod,
+	// which follow the console usage more closely than CPU. The operator records them per pod in Prometheus, as
+	// `netobserv:plugin_http_requests:rate1m` and `netobserv:plugin_http_request_duration_seconds:avg1m`, which must be served
+	// as pods metrics by a custom metrics adapter, such as prometheus-adapter. When `autoscaler.status` is `Enabled`, they are added
+	// to the `autoscaler` metrics. With KEDA, keep the `autoscaler` disabled and use the recorded series in a Prometheus trigger.
+	// +optional
+	AutoscalerUsage *PluginAutoscalerUsage `json:"autoscalerUsage,omitempty"`
+
 	//+kubebuilder:default:={enable:true}
 	// `portNaming` defines the configuration of the port-to-service name translation
 	PortNaming ConsolePluginPortConfig `json:"portNaming,omitempty"`
@@ -784,6 +1506,21 @@ type FlowCollectorConsolePlugin struct {
 	// `quickFilters` configures quick filter presets for the Console plugin
 	QuickFilters []QuickFilter `json:"quickFilters"`
 
+	// `accessMode` defines which console users are allowed to see flows in the plugin.<br>
+	// - `Auto` (default) lets Loki decide when it receives the user token (`LokiStack` mode or `Forward` authentication), and otherwise only allows cluster administrators.<br>
+	// - `Admin` only allows cluster administrators.<br>
+	// - `NamespaceRestricted` allows any authenticated user, who only sees the flows of the namespaces they have access to.
+	// This requires Loki to receive the user token, so that the Loki multi-tenancy filters flows by namespace, as the `network` tenant of `LokiStack` does.
+	// When the user token is not forwarded to Loki, the plugin falls back to `Admin`.<br>
+	// +kubebuilder:validation:Enum:="Auto";"Admin";"NamespaceRestricted"
+	//+kubebuilder:default:="Auto"
+	// +optional
+	AccessMode ConsolePluginAccessMode `json:"accessMode,omitempty"`
+
+	// `developerPerspective` defines the integration of the plugin in the console Developer perspective.
+	// +optional
+	DeveloperPerspective ConsolePluginDeveloperPerspective `json:"developerPerspective,omitempty"`
+
 	// `advanced` allows setting some aspects of the internal configuration of the console plugin.
 	// This section is aimed mostly for debugging and fine-grained performance optimizations,
 	// such as `GOGC` and `GOMAXPROCS` env vars. Set these values at your own risk.
@@ -791,6 +1528,47 @@ type FlowCollectorConsolePlugin struct {
 	Advanced *AdvancedPluginConfig `json:"advanced,omitempty"`
 }
 
+// `FlowCollectorQueryService` defines the desired state of the flow query service
+type FlowCollectorQueryService struct {
+	// Set `enable` to `true` to deploy the query service: it serves the same flow and metric queries as the console plugin backend,
+	// under the `/api` path of the `netobserv-query` service, without depending on the OpenShift Console.
+	// Requests must provide a bearer token, that is checked like the console users, following `spec.consolePlugin.accessMode`.
+	// `spec.loki.enable` must also be `true`.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:default:=1
+	// `replicas` defines the number of replicas (pods) to start.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	//+kubebuilder:default:={requests:{memory:"50Mi",cpu:"100m"},limits:{memory:"100Mi"}}
+	// `resources`, in terms of compute resources, required by this container.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+type ConsolePluginAccessMode string
+
+const (
+	ConsolePluginAccessAuto                ConsolePluginAccessMode = "Auto"
+	ConsolePluginAccessAdmin               ConsolePluginAccessMode = "Admin"
+	ConsolePluginAccessNamespaceRestricted ConsolePluginAccessMode = "NamespaceRestricted"
+)
+
+// `ConsolePluginDeveloperPerspective` defines the integration of the plugin in the console Developer perspective.
+// Developers only see flows when they are allowed to query them: set `accessMode` to `NamespaceRestricted`,
+// and, in `LokiStack` mode, bind them the `netobserv-reader` cluster role.
+type ConsolePluginDeveloperPerspective struct {
+	// Set `enable` to `true` to show the Network Traffic tab in the Developer perspective, scoped to the selected project.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+}
+
 // Configuration of the port to service name translation feature of the console plugin
 type ConsolePluginPortConfig struct {
 	//+kubebuilder:default:=true
@@ -971,6 +1749,15 @@ type AdvancedAgentConfig struct {
 	// scheduling controls whether the pod will be scheduled or not.
 	// +optional
 	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`
+
+	// `updateStrategy` defines how the agent pods are replaced when the DaemonSet is updated,
+	// for instance to roll fewer nodes at once on large clusters (`maxUnavailable`), or to only update on pod deletion (`OnDelete`).
+	// +optional
+	UpdateStrategy *appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// `goRuntime` tunes the memory management of the Go runtime of the agent, to prevent OOM kills at high flow rates.
+	// +optional
+	GoRuntime *GoRuntimeConfig `json:"goRuntime,omitempty"`
 }
 
 // `AdvancedProcessorConfig` allows tweaking some aspects of the internal configuration of the processor.
@@ -1035,6 +1822,31 @@ type AdvancedProcessorConfig struct {
 	// scheduling controls whether the pod will be scheduled or not.
 	// +optional
 	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`
+
+	// `updateStrategy` defines how the processor pods are replaced when the DaemonSet is updated, with `spec.deploymentModel` `Direct`.
+	// +optional
+	UpdateStrategy *appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// `deploymentStrategy` defines how the processor pods are replaced when the Deployment is updated, with `spec.deploymentModel` `Kafka`.
+	// +optional
+	DeploymentStrategy *appsv1.DeploymentStrategy `json:"deploymentStrategy,omitempty"`
+
+	// `probes` tunes the timing of the liveness, readiness and startup probes of the processor pods, when `enableKubeProbes` is `true`.
+	// For instance, increase `probes.startup.failureThreshold` when the pods take long to start, such as when certificates are slow to mount.
+	// +optional
+	Probes *ProbesConfig `json:"probes,omitempty"`
+
+	// `goRuntime` tunes the memory management of the Go runtime of the processor, to prevent OOM kills at high flow rates.
+	// +optional
+	GoRuntime *GoRuntimeConfig `json:"goRuntime,omitempty"`
+
+	// `overridesConfigMap` is the name of a ConfigMap, in the FlowCollector namespace, whose keys override values of the generated
+	// flowlogs-pipeline configuration. It is a break-glass mechanism, meant to work around an issue until it is fixed in a release,
+	// usually on the advice of support. Each key is a dot-separated path in the configuration, where array elements are selected
+	// by name or index, such as `parameters.loki.write.loki.batchSize`, and each value is parsed as YAML.
+	// Overrides aren't validated, and a warning is reported in the FlowCollector status as long as they are applied.
+	// +optional
+	OverridesConfigMap string `json:"overridesConfigMap,omitempty"`
 }
 
 // `AdvancedLokiConfig` allows tweaking some aspects of the Loki clients.
@@ -1093,9 +1905,142 @@ type AdvancedPluginConfig struct {
 	// `port` is the plugin service port. Do not use 9002, which is reserved for metrics.
 	Port *int32 `json:"port,omitempty"`
 
+	// `serviceAnnotations` are additional annotations set on the plugin service, for instance to configure
+	// a load balancer or an ingress controller.
+	//+optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// `servingCert` references the certificate and private key served by the plugin, for instance when it is issued
+	// by cert-manager. When omitted, the certificate is generated by the OpenShift service CA.
+	//+optional
+	ServingCert *CertificateReference `json:"servingCert,omitempty"`
+
 	// scheduling controls whether the pod will be scheduled or not.
 	// +optional
 	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`
+
+	// `deploymentStrategy` defines how the plugin pods are replaced when the Deployment is updated.
+	// +optional
+	DeploymentStrategy *appsv1.DeploymentStrategy `json:"deploymentStrategy,omitempty"`
+
+	// `probes` tunes the timing of the liveness, readiness and startup probes of the plugin pods.
+	// For instance, increase `probes.startup.failureThreshold` when the pods take long to start, such as when certificates are slow to mount.
+	// +optional
+	Probes *ProbesConfig `json:"probes,omitempty"`
+
+	// `overridesConfigMap` is the name of a ConfigMap, in the FlowCollector namespace, whose keys override values of the generated
+	// console plugin configuration. It is a break-glass mechanism, meant to work around an issue until it is fixed in a release,
+	// usually on the advice of support. Each key is a dot-separated path in the configuration, where array elements are selected
+	// by name, id or index, such as `loki.timeout` or `frontend.columns.SrcK8S_Name.default`, and each value is parsed as YAML.
+	// Overrides aren't validated, and a warning is reported in the FlowCollector status as long as they are applied.
+	// +optional
+	OverridesConfigMap string `json:"overridesConfigMap,omitempty"`
+}
+
+// `PluginAutoscalerUsage` defines the usage targets of the plugin autoscaler. Each target that is set adds a pods metric to the autoscaler.
+type PluginAutoscalerUsage struct {
+	// `requestsPerSecond` is the target average rate of HTTP requests per plugin pod, such as `5` or `500m`.
+	// +optional
+	RequestsPerSecond *resource.Quantity `json:"requestsPerSecond,omitempty"`
+
+	// `latency` is the target average latency of the HTTP requests served by each plugin pod, such as `2s`.
+	// +optional
+	Latency *metav1.Duration `json:"latency,omitempty"`
+}
+
+// `GoRuntimeConfig` tunes the memory management of the Go runtime of a component.
+// Variables set explicitly in `env` take precedence over these settings.
+type GoRuntimeConfig struct {
+	//+kubebuilder:validation:Minimum=0
+	//+kubebuilder:validation:Maximum=100
+	//+optional
+	// `memoryLimitPercent` sets the Go soft memory limit (`GOMEMLIMIT`) to this percentage of the container memory limit,
+	// so that the garbage collector runs more often when the limit is close, instead of the container being OOM killed.
+	// The remaining headroom is for the memory that the Go runtime is unaware of. It has no effect when the container has no memory limit.
+	// When unset, it defaults to 90. Set `0` to not set `GOMEMLIMIT`.
+	MemoryLimitPercent *int32 `json:"memoryLimitPercent,omitempty"`
+
+	//+kubebuilder:validation:Minimum=1
+	//+optional
+	// `gcPercent` sets the garbage collection target percentage of the Go runtime (`GOGC`). Lower values collect more often,
+	// trading CPU for memory. When unset, the Go runtime default (100) applies.
+	GCPercent *int32 `json:"gcPercent,omitempty"`
+}
+
+// `ProbesConfig` tunes the Kubernetes probes of a component. Unset values keep the operator defaults.
+type ProbesConfig struct {
+	// `startup` tunes the probe that holds the liveness and readiness probes until the component has started.
+	// +optional
+	Startup *ProbeConfig `json:"startup,omitempty"`
+
+	// `liveness` tunes the probe that restarts the component when it stops responding.
+	// +optional
+	Liveness *ProbeConfig `json:"liveness,omitempty"`
+
+	// `readiness` tunes the probe that removes the component from its service endpoints when it is not ready.
+	// +optional
+	Readiness *ProbeConfig `json:"readiness,omitempty"`
+}
+
+// `ProbeConfig` holds the timing of a Kubernetes probe.
+type ProbeConfig struct {
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	// `initialDelaySeconds` is the number of seconds after the container has started before the probe is initiated.
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	//+kubebuilder:validation:Minimum=1
+	//+optional
+	// `periodSeconds` is how often, in seconds, to perform the probe.
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	//+kubebuilder:validation:Minimum=1
+	//+optional
+	// `timeoutSeconds` is the number of seconds after which the probe times out.
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	//+kubebuilder:validation:Minimum=1
+	//+optional
+	// `failureThreshold` is the number of consecutive failures for the probe to be considered failed.
+	// With the startup probe, the component has `failureThreshold` times `periodSeconds` to start.
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+type AnonymizationIPMode string
+
+const (
+	AnonymizationIPKeep     AnonymizationIPMode = "Keep"
+	AnonymizationIPTruncate AnonymizationIPMode = "Truncate"
+	AnonymizationIPDrop     AnonymizationIPMode = "Drop"
+)
+
+// `Anonymization` defines how sensitive information is redacted from flows.
+type Anonymization struct {
+	// `ips` defines how the source and destination IP addresses are anonymized. Possible values are:<br>
+	// - `Keep` (default) to leave IP addresses untouched.<br>
+	// - `Truncate` to replace IP addresses with their network prefix, as defined in `truncatePrefixLength`.<br>
+	// - `Drop` to remove IP addresses from flows.<br>
+	// Kubernetes enrichment, subnet labels and conversation tracking are processed before anonymization, so they are not affected.
+	// +kubebuilder:validation:Enum:="Keep";"Truncate";"Drop"
+	// +kubebuilder:default:=Keep
+	IPs AnonymizationIPMode `json:"ips,omitempty"`
+
+	// `truncatePrefixLength` is the network prefix length used when `ips` is `Truncate`, applied to both IPv4 and IPv6 addresses,
+	// hence it must not exceed 32.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=32
+	// +kubebuilder:default:=24
+	// +optional
+	TruncatePrefixLength *int32 `json:"truncatePrefixLength,omitempty"`
+
+	// `dropMACs`, when set to `true`, removes the source and destination MAC addresses from flows.
+	// +optional
+	DropMACs bool `json:"dropMACs,omitempty"`
+
+	// `dropPodNames`, when set to `true`, removes the source and destination resource names (such as pod names) from flows,
+	// along with the names of their owners (such as deployments) and the names and IPs of their nodes. Only their kinds and namespaces are kept.
+	// +optional
+	DropPodNames bool `json:"dropPodNames,omitempty"`
 }
 
 // `SubnetLabels` allows to define custom labels on subnets and IPs or to enable automatic labelling of recognized subnets in OpenShift.
@@ -1128,13 +2073,16 @@ type ExporterType string
 const (
 	KafkaExporter ExporterType = "Kafka"
 	IpfixExporter ExporterType = "IPFIX"
+	LokiExporter  ExporterType = "Loki"
+
+	ClusterLogForwarderExporter ExporterType = "ClusterLogForwarder"
 )
 
 // `FlowCollectorExporter` defines an additional exporter to send enriched flows to.
 type FlowCollectorExporter struct {
-	// `type` selects the type of exporters. The available options are `Kafka` and `IPFIX`.
+	// `type` selects the type of exporters. The available options are `Kafka`, `IPFIX`, `Loki` and `ClusterLogForwarder`.
 	// +unionDiscriminator
-	// +kubebuilder:validation:Enum:="Kafka";"IPFIX"
+	// +kubebuilder:validation:Enum:="Kafka";"IPFIX";"Loki";"ClusterLogForwarder"
 	// +kubebuilder:validation:Required
 	Type ExporterType `json:"type"`
 
@@ -1145,6 +2093,325 @@ type FlowCollectorExporter struct {
 	// IPFIX configuration, such as the IP address and port to send enriched IPFIX flows to.
 	// +optional
 	IPFIX FlowCollectorIPFIXReceiver `json:"ipfix,omitempty"`
+
+	// Loki configuration, such as the URL and credentials, to write enriched flows to an additional Loki instance,
+	// for instance a central Loki with a longer retention.
+	// +optional
+	Loki FlowCollectorLokiExporter `json:"loki,omitempty"`
+
+	// `clusterLogForwarder` configuration, to hand flows to an existing `ClusterLogForwarder` of the Red Hat OpenShift Logging Operator,
+	// which sends them to its outputs.
+	// +optional
+	ClusterLogForwarder FlowCollectorLogForwarderExporter `json:"clusterLogForwarder,omitempty"`
+
+	// `anonymization` overrides `spec.processor.anonymization` for this exporter. When omitted, the processor settings apply.
+	// +optional
+	Anonymization *Anonymization `json:"anonymization,omitempty"`
+
+	// `fields` selects which flow fields are sent to this exporter. When omitted, full records are exported.
+	// +optional
+	Fields *ExporterFields `json:"fields,omitempty"`
+
+	// `filters` is a list of conditions that flows must all match to be sent to this exporter.
+	// When omitted, all flows are exported.
+	// +optional
+	Filters []ExporterFilter `json:"filters,omitempty"`
+}
+
+// `FlowCollectorLokiExporter` defines an additional Loki instance to write flows to, in parallel with `spec.loki`
+type FlowCollectorLokiExporter struct {
+	// `url` is the address of the Loki push API, such as `https://loki-distributor.central:3100/`.
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// `tenantID` is the Loki `X-Scope-OrgID` header that identifies the tenant for each request.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	//+kubebuilder:validation:Enum:="Disabled";"Host";"Secret"
+	//+kubebuilder:default:="Disabled"
+	// `authToken` describes the way to get a token to authenticate to Loki:<br>
+	// - `Disabled` does not send any token with the request.<br>
+	// - `Host` uses the local pod service account to authenticate to Loki.<br>
+	// - `Secret` reads the token from `authTokenSecret`.
+	AuthToken LokiAuthToken `json:"authToken,omitempty"`
+
+	// `authTokenSecret` references the file containing the bearer token, when `authToken` is `Secret`.
+	// +optional
+	AuthTokenSecret *FileReference `json:"authTokenSecret,omitempty"`
+
+	// `basicAuth` configures basic authentication. It can't be combined with `authToken`.
+	// +optional
+	BasicAuth *LokiBasicAuth `json:"basicAuth,omitempty"`
+
+	// TLS client configuration for Loki URL.
+	// +optional
+	TLS ClientTLS `json:"tls"`
+
+	//+kubebuilder:default:="1s"
+	// `writeBatchWait` is the maximum time to wait before sending a Loki batch.
+	WriteBatchWait *metav1.Duration `json:"writeBatchWait,omitempty"` // Warning: keep as pointer, else default is ignored
+
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default:=102400
+	// `writeBatchSize` is the maximum batch size (in bytes) of Loki logs to accumulate before sending.
+	WriteBatchSize int64 `json:"writeBatchSize,omitempty"`
+
+	//+kubebuilder:default:="10s"
+	// `writeTimeout` is the maximum Loki time connection / request limit.
+	// A timeout of zero means no timeout.
+	WriteTimeout *metav1.Duration `json:"writeTimeout,omitempty"` // Warning: keep as pointer, else default is ignored
+}
+
+// `FlowCollectorLogForwarderExporter` defines the `ClusterLogForwarder` that flows are handed to. Flows are written
+// as JSON lines to the flowlogs-pipeline standard output, and the operator adds to the `ClusterLogForwarder` an input
+// collecting them, along with a pipeline to the selected outputs. The `ClusterLogForwarder` must be allowed to collect
+// application logs.
+type FlowCollectorLogForwarderExporter struct {
+	// `name` of an existing `ClusterLogForwarder` resource, from the `observability.openshift.io/v1` API.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	//+kubebuilder:default:="openshift-logging"
+	// `namespace` of the `ClusterLogForwarder`.
+	Namespace string `json:"namespace,omitempty"`
+
+	// `outputs` are the names of the `ClusterLogForwarder` outputs to send flows to.
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	Outputs []string `json:"outputs"`
+}
+
+// `ExporterFields` defines the list of flow fields sent to an exporter
+type ExporterFields struct {
+	// `include` is the list of fields to keep; any other field is removed. When empty, all fields are kept.
+	// Refer to the documentation for the list of available fields: https://docs.openshift.com/container-platform/latest/network_observability/json-flows-format-reference.html.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// `exclude` is the list of fields to remove. It is applied after `include`.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+type ExporterFilterMatchType string
+
+const (
+	ExporterFilterEqual    ExporterFilterMatchType = "Equal"
+	ExporterFilterNotEqual ExporterFilterMatchType = "NotEqual"
+	ExporterFilterPresence ExporterFilterMatchType = "Presence"
+	ExporterFilterAbsence  ExporterFilterMatchType = "Absence"
+)
+
+// `ExporterFilter` defines a condition that flows must match to be exported
+type ExporterFilter struct {
+	// Name of the field to filter on, for instance `K8S_FlowLayer` or `DstK8S_Namespace`.
+	// +required
+	Field string `json:"field"`
+
+	// Value to filter on. When `matchType` is `Equal` or `NotEqual`, only string fields can be compared.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Type of matching to apply
+	// +kubebuilder:validation:Enum:="Equal";"NotEqual";"Presence";"Absence"
+	// +kubebuilder:default:="Equal"
+	MatchType ExporterFilterMatchType `json:"matchType"`
+}
+
+type ProxyMode string
+
+const (
+	ProxyModeAuto     ProxyMode = "Auto"
+	ProxyModeManual   ProxyMode = "Manual"
+	ProxyModeDisabled ProxyMode = "Disabled"
+)
+
+// `FlowCollectorProxy` defines the HTTP(S) proxy settings of FlowCollector
+type FlowCollectorProxy struct {
+	// `mode` defines how the proxy settings are resolved. Possible values are:<br>
+	// - `Auto` (default) to use the OpenShift cluster-wide `Proxy` resource named `cluster`, when available. Any field explicitly set below overrides the cluster-wide value.<br>
+	// - `Manual` to only use the fields set below.<br>
+	// - `Disabled` to not configure any proxy, even when a cluster-wide proxy is defined.
+	// +kubebuilder:validation:Enum:="Auto";"Manual";"Disabled"
+	// +kubebuilder:default:=Auto
+	Mode ProxyMode `json:"mode,omitempty"`
+
+	// `httpProxy` is the URL of the proxy for HTTP requests, injected as `HTTP_PROXY` in the containers.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// `httpsProxy` is the URL of the proxy for HTTPS requests, injected as `HTTPS_PROXY` in the containers.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// `noProxy` is a comma-separated list of hostnames, domains and CIDRs for which the proxy must not be used, injected as `NO_PROXY` in the containers.
+	// In `Manual` mode, make sure it includes in-cluster destinations, such as the Kubernetes API server and services (for example `.svc,.cluster.local`).
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+
+	// `injectTrustedCABundle`, when enabled, mounts the OpenShift cluster trusted CA bundle in flowlogs-pipeline and the console plugin,
+	// so that they trust the same certificate authorities as the cluster, for instance when reaching an external Loki or exporter endpoint.
+	// When unset, it is enabled if the cluster-wide proxy defines a trusted CA. Set it to `false` to disable it.
+	// The bundle is injected by OpenShift in a ConfigMap created by the operator: the components are not deployed until it is injected.
+	// This setting has no effect on other platforms.
+	// +optional
+	InjectTrustedCABundle *bool `json:"injectTrustedCABundle,omitempty"`
+}
+
+// `FlowCollectorAnalytics` defines the flow analytics settings of FlowCollector
+type FlowCollectorAnalytics struct {
+	// `anomalyDetection` learns per-workload traffic baselines and reports deviations from them.
+	// +optional
+	AnomalyDetection AnomalyDetection `json:"anomalyDetection,omitempty"`
+
+	// `security` enables built-in heuristics detecting suspicious traffic, such as port scans and SYN floods.
+	// +optional
+	Security SecurityAnalytics `json:"security,omitempty"`
+
+	// `egressBudgets` defines per-namespace budgets of the bandwidth leaving the cluster. A `NetObservEgressBudgetExceeded` alert
+	// is raised for each namespace exceeding its budget.
+	// +optional
+	EgressBudgets []EgressBudget `json:"egressBudgets,omitempty"`
+}
+
+// `AnomalyDetection` defines the settings of the traffic anomaly detection.
+// The baseline is learned by Prometheus from the `workload_ingress_bytes_total` metric, which must be listed in `spec.processor.metrics.includeList`.
+// A `netobserv_anomaly_score` metric is recorded per workload, as the number of standard deviations between the current traffic and the baseline,
+// and a `NetObservTrafficAnomaly` alert is raised when it exceeds the configured threshold.
+type AnomalyDetection struct {
+	// Set `enable` to `true` to enable the traffic anomaly detection.
+	//+kubebuilder:default:=false
+	Enable *bool `json:"enable,omitempty"`
+
+	// `baselineWindow` is the period over which the traffic baseline is learned.
+	//+kubebuilder:default:="24h"
+	// +optional
+	BaselineWindow *metav1.Duration `json:"baselineWindow,omitempty"`
+
+	// `threshold` is the anomaly score, in number of standard deviations from the baseline, above which an alert is raised.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default:=3
+	// +optional
+	Threshold *int32 `json:"threshold,omitempty"`
+}
+
+// `SecurityAnalytics` defines the settings of the security heuristics.
+// They rely on a `netobserv_security_syn_only_flows_total` metric, counting TCP flows where the handshake is not completed.
+// It is labelled with the source and destination workloads only, so that its cardinality doesn't grow with the ports scanned.
+// Thresholds apply to sampled flows: adjust them according to `spec.agent.ebpf.sampling`.
+type SecurityAnalytics struct {
+	// Set `enable` to `true` to enable the security heuristics.
+	//+kubebuilder:default:=false
+	Enable *bool `json:"enable,omitempty"`
+
+	// `portScanThreshold` is the number of connection attempts that a single workload makes within 5 minutes,
+	// without completing the TCP handshake, above which a port scan is reported. Each probed port is a distinct flow.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default:=100
+	// +optional
+	PortScanThreshold *int32 `json:"portScanThreshold,omitempty"`
+
+	// `synFloodThreshold` is the rate, in flows per second, of TCP connection attempts that do not complete the handshake
+	// toward a single workload, above which a SYN flood is reported.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default:=100
+	// +optional
+	SynFloodThreshold *int32 `json:"synFloodThreshold,omitempty"`
+
+	// Set `alerts` to `false` to only produce the metrics, without the `NetObservPortScan` and `NetObservSYNFlood` alerts.
+	//+kubebuilder:default:=true
+	// +optional
+	Alerts *bool `json:"alerts,omitempty"`
+}
+
+// `EgressBudget` defines the bandwidth that the workloads of a namespace can send outside of the cluster, such as to the internet.
+// It relies on a `netobserv_namespace_external_egress_bytes_total` metric, counting the bytes sent by the budgeted namespaces to
+// destinations that are not Kubernetes resources. The bandwidth is measured on sampled flows, and extrapolated according to
+// `spec.agent.ebpf.sampling`.
+type EgressBudget struct {
+	// `namespace` is the namespace whose egress bandwidth is budgeted.
+	// +kubebuilder:validation:Pattern:=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +required
+	Namespace string `json:"namespace"`
+
+	// `maxMbps` is the egress bandwidth, in megabits per second averaged over 5 minutes, above which an alert is raised.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	MaxMbps int32 `json:"maxMbps"`
+
+	// `severity` is the severity label of the alert.
+	// +kubebuilder:validation:Enum:="info";"warning";"critical"
+	// +kubebuilder:default:="warning"
+	// +optional
+	Severity string `json:"severity,omitempty"`
+}
+
+// `FlowCollectorRetentionPolicy` defines the flow retention tiering settings.
+// When `longTermAggregates` is enabled, the processor generates the `namespace_flows_total`, `namespace_ingress_bytes_total` and `namespace_ingress_packets_total` metrics,
+// and their rates are recorded per pair of namespaces, for example as `netobserv:namespace_ingress_bytes:rate1h`.
+// Those series are meant to be kept in the long-term storage of Prometheus, while the retention of raw flows is configured in Loki,
+// for instance with `spec.limits.global.retention` in a `LokiStack`.
+type FlowCollectorRetentionPolicy struct {
+	// Set `longTermAggregates` to `true` to record the aggregated traffic series meant for long-term retention.
+	//+kubebuilder:default:=false
+	// +optional
+	LongTermAggregates *bool `json:"longTermAggregates,omitempty"`
+
+	// `aggregationWindow` is the period over which the recorded rates are averaged. It also appears in the name of the recorded series.
+	//+kubebuilder:default:="1h"
+	// +optional
+	AggregationWindow *metav1.Duration `json:"aggregationWindow,omitempty"`
+}
+
+// `FlowCollectorDiagnostics` defines the settings of the NetObserv must-gather, run with `oc adm must-gather --image=<NetObserv must-gather image>`.
+type FlowCollectorDiagnostics struct {
+	// `extraGatherScripts` references a `ConfigMap` holding extra scripts that the must-gather runs after collecting the NetObserv data,
+	// so that vendors embedding NetObserv can collect their own artifacts in the same bundle.
+	// Each key of the `ConfigMap` is a script, run with `bash`. Its output directory is passed as the `GATHER_OUTPUT_DIR` environment variable,
+	// and its standard output and error are collected in that directory.
+	// +optional
+	ExtraGatherScripts *FlowCollectorGatherScripts `json:"extraGatherScripts,omitempty"`
+}
+
+// `FlowCollectorGatherScripts` references a `ConfigMap` of must-gather scripts.
+type FlowCollectorGatherScripts struct {
+	// `name` of the `ConfigMap`, in the namespace of NetObserv.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Name string `json:"name"`
+
+	// `timeout` of each script.
+	//+kubebuilder:default:="5m"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// `FlowCollectorLoadGenerator` defines the settings of the flow generator used for performance tests.
+type FlowCollectorLoadGenerator struct {
+	// Set `enable` to `true` to deploy the flow generator.
+	//+kubebuilder:default:=false
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+
+	// `flowsPerSecond` is the number of synthetic flows generated per second.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default:=1000
+	// +optional
+	FlowsPerSecond *int32 `json:"flowsPerSecond,omitempty"`
+
+	// `connections` is the number of distinct connections that the generated flows are spread on, which drives the
+	// cardinality of the generated metrics and of the connection tracking.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default:=100
+	// +optional
+	Connections *int32 `json:"connections,omitempty"`
+
+	//+kubebuilder:default:={requests:{memory:"100Mi",cpu:"100m"},limits:{memory:"800Mi"}}
+	// `resources` are the compute resources required by the flow generator.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // `FlowCollectorStatus` defines the observed state of FlowCollector
@@ -1154,9 +2421,211 @@ type FlowCollectorStatus struct {
 	// `conditions` represent the latest available observations of an object's state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// `observedGeneration` is the FlowCollector generation last reconciled by the operator. The `Ready` condition
+	// applies to this generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Namespace where console plugin and flowlogs-pipeline have been deployed.
 	// Deprecated: annotations are used instead
 	Namespace string `json:"namespace,omitempty"`
+
+	// `components` lists the objects currently deployed by the operator, with their state.
+	// +optional
+	Components []FlowCollectorComponentObject `json:"components,omitempty"`
+
+	// `agent` summarizes the state of the eBPF agent pods across nodes.
+	// +optional
+	Agent *FlowCollectorAgentStatus `json:"agent,omitempty"`
+
+	// `lokiMigration` reports the progress of the Loki migration configured in `spec.loki.migration`.
+	// +optional
+	LokiMigration *LokiMigrationStatus `json:"lokiMigration,omitempty"`
+
+	// `clusterNetwork` is the cluster network configuration read from the Cluster Network Operator, when
+	// `spec.processor.clusterNetworkOperatorManaged` is enabled.
+	// +optional
+	ClusterNetwork *ClusterNetworkStatus `json:"clusterNetwork,omitempty"`
+
+	// `batchTuning` reports the batch sizes picked by the operator, when `spec.processor.batchAutoTuning` is enabled.
+	// +optional
+	BatchTuning *BatchTuningStatus `json:"batchTuning,omitempty"`
+}
+
+// `BatchTuningStatus` reports the batch sizes picked from the observed flow rate.
+type BatchTuningStatus struct {
+	// `profile` in use: `LowTraffic`, `Default` or `HighTraffic`.
+	Profile BatchProfile `json:"profile"`
+
+	// `flowsPerSecond` is the flow rate observed during the last check.
+	// +optional
+	FlowsPerSecond int64 `json:"flowsPerSecond,omitempty"`
+
+	// `lokiWriteBatchSize` is the maximum size, in bytes, of the Loki batches.
+	// +optional
+	LokiWriteBatchSize int64 `json:"lokiWriteBatchSize,omitempty"`
+
+	// `lokiWriteBatchWait` is the maximum time to wait before sending a Loki batch.
+	// +optional
+	LokiWriteBatchWait *metav1.Duration `json:"lokiWriteBatchWait,omitempty"`
+
+	// `kafkaConsumerBatchSize` is the maximum size, in bytes, of the batches consumed from Kafka.
+	// +optional
+	KafkaConsumerBatchSize int `json:"kafkaConsumerBatchSize,omitempty"`
+
+	// `lastCheckTime` is when the flow rate was last checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// `lastTransitionTime` is when the profile last changed.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// `ClusterNetworkStatus` is the cluster network configuration read from the Cluster Network Operator.
+type ClusterNetworkStatus struct {
+	// `networkType` is the cluster network plugin, such as `OVNKubernetes`.
+	// +optional
+	NetworkType string `json:"networkType,omitempty"`
+
+	// `mtu` is the MTU of the cluster network.
+	// +optional
+	MTU int `json:"mtu,omitempty"`
+
+	// `podCIDRs` are the pods subnets.
+	// +optional
+	PodCIDRs []string `json:"podCIDRs,omitempty"`
+
+	// `serviceCIDRs` are the services subnets.
+	// +optional
+	ServiceCIDRs []string `json:"serviceCIDRs,omitempty"`
+}
+
+// `LokiMigrationStatus` is the checklist of a Loki migration.
+type LokiMigrationStatus struct {
+	// `target` is the ingester URL of the target Loki. Changing the target restarts the migration.
+	Target string `json:"target"`
+
+	// `startTime` is when flows started to be written to the target Loki, unset until flowlogs-pipeline is rolled out with the target.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// `steps` lists the migration steps, in order.
+	Steps []LokiMigrationStep `json:"steps"`
+}
+
+// `LokiMigrationStep` is a step of a Loki migration.
+type LokiMigrationStep struct {
+	// `name` of the step, such as `DualWrite`.
+	Name string `json:"name"`
+
+	// `done` is `true` when the step is complete.
+	Done bool `json:"done"`
+
+	// `message` describes the state of the step, or what to do to complete it.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// `FlowCollectorAgentStatus` summarizes the state of the eBPF agent.
+type FlowCollectorAgentStatus struct {
+	// `nodes` summarizes the state of the agent pods per node.
+	Nodes AgentNodesStatus `json:"nodes"`
+
+	// `conflicts` lists other eBPF users detected in the cluster, which might compete with the agent for the same kernel hooks.
+	// +optional
+	Conflicts []AgentConflict `json:"conflicts,omitempty"`
+
+	// `attachModes` counts the nodes per eBPF attach mode, as selected from `spec.agent.ebpf.attachMode` and the node kernel version.
+	// +optional
+	AttachModes []AgentAttachModeCount `json:"attachModes,omitempty"`
+}
+
+// `AgentAttachModeCount` counts the nodes where the agent uses an attach mode.
+type AgentAttachModeCount struct {
+	// `mode` is the attach mode used on these nodes, `TC` or `TCX`.
+	Mode EBPFAttachMode `json:"mode"`
+
+	// `nodes` is the number of nodes using this mode.
+	Nodes int32 `json:"nodes"`
+
+	// `unsupported` lists up to 10 nodes where this mode is forced but is not supported by the kernel.
+	// +optional
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// `AgentConflict` describes another eBPF user running in the cluster.
+type AgentConflict struct {
+	// `name` of the conflicting product, such as `Cilium`.
+	Name string `json:"name"`
+
+	// `namespace` of the DaemonSet running it.
+	Namespace string `json:"namespace"`
+
+	// `daemonSet` is the name of the DaemonSet running it.
+	DaemonSet string `json:"daemonSet"`
+
+	// `message` explains the impact on the agent and how to mitigate it.
+	Message string `json:"message"`
+}
+
+// `AgentNodesStatus` counts the nodes per agent state, and lists the nodes where the agent is the least healthy.
+type AgentNodesStatus struct {
+	// `desired` is the number of nodes that should run the agent.
+	Desired int32 `json:"desired"`
+
+	// `ready` is the number of nodes where the agent is running and ready.
+	Ready int32 `json:"ready"`
+
+	// `outdated` is the number of nodes where the agent does not run the latest configuration yet.
+	Outdated int32 `json:"outdated"`
+
+	// `unhealthy` lists up to 10 nodes where the agent is not ready or has restarted, the not ready ones and the most restarted ones first.
+	// +optional
+	Unhealthy []AgentNodeState `json:"unhealthy,omitempty"`
+}
+
+// `AgentNodeState` describes the agent pod running on a node.
+type AgentNodeState struct {
+	// `node` is the name of the node.
+	Node string `json:"node"`
+
+	// `pod` is the name of the agent pod.
+	Pod string `json:"pod"`
+
+	// `ready` is `true` when the agent pod is ready.
+	Ready bool `json:"ready"`
+
+	// `restarts` is the number of restarts of the agent container.
+	Restarts int32 `json:"restarts"`
+
+	// `reason` explains why the agent container is not running, such as `CrashLoopBackOff`.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// `image` is the image run by the agent container, which tells its version.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// `FlowCollectorComponentObject` describes an object deployed by the operator.
+type FlowCollectorComponentObject struct {
+	// `kind` of the object, such as `Deployment` or `ConfigMap`.
+	Kind string `json:"kind"`
+
+	// `namespace` of the object. It is empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// `name` of the object.
+	Name string `json:"name"`
+
+	// `revision` is the `metadata.generation` of the object, when it is versioned.
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+
+	// `ready` is `true` when the object exists and, for workloads, when all their pods are updated and available.
+	Ready bool `json:"ready"`
 }
 
 // +kubebuilder:object:root=true
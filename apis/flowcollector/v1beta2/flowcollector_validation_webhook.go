@@ -0,0 +1,282 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var promLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// GatedFields returns a message for each field of the spec that is ignored because of the operator feature gates
+type GatedFields func(spec *FlowCollectorSpec) []string
+
+// flowCollectorValidator rejects the configurations that the components would fail to apply
+type flowCollectorValidator struct {
+	gated GatedFields
+}
+
+var _ admission.CustomValidator = &flowCollectorValidator{}
+
+func (v *flowCollectorValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	fc, ok := obj.(*FlowCollector)
+	if !ok {
+		return nil, kerr.NewBadRequest(fmt.Sprintf("expected a FlowCollector but got a %T", obj))
+	}
+	return v.warnings(fc), v.validate(fc)
+}
+
+func (v *flowCollectorValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	fc, ok := newObj.(*FlowCollector)
+	if !ok {
+		return nil, kerr.NewBadRequest(fmt.Sprintf("expected a FlowCollector but got a %T", newObj))
+	}
+	return v.warnings(fc), v.validate(fc)
+}
+
+func (v *flowCollectorValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *flowCollectorValidator) warnings(fc *FlowCollector) admission.Warnings {
+	if v.gated == nil {
+		return nil
+	}
+	return v.gated(&fc.Spec)
+}
+
+func (v *flowCollectorValidator) validate(fc *FlowCollector) error {
+	errs := validateFlowFilter(fc.Spec.Agent.EBPF.FlowFilter, field.NewPath("spec", "agent", "ebpf", "flowFilter"))
+	errs = append(errs, validateExporters(fc.Spec.Exporters, field.NewPath("spec", "exporters"))...)
+	errs = append(errs, validateLokiAggregation(&fc.Spec, field.NewPath("spec", "loki", "aggregation"))...)
+	errs = append(errs, validateStaticLabels(fc.Spec.Processor.Metrics.StaticLabels, field.NewPath("spec", "processor", "metrics", "staticLabels"))...)
+	errs = append(errs, validateAnonymization(fc.Spec.Processor.Anonymization, field.NewPath("spec", "processor", "anonymization"))...)
+	errs = append(errs, validateHosted(&fc.Spec.Hosted, field.NewPath("spec", "hosted"))...)
+	if fc.Spec.Loki.Mode == LokiModeManual {
+		manual := &fc.Spec.Loki.Manual
+		errs = append(errs, validateLokiAuth(manual.AuthToken, manual.AuthTokenSecret, manual.BasicAuth, field.NewPath("spec", "loki", "manual"))...)
+	}
+	if fc.Spec.Loki.Migration != nil {
+		target := &fc.Spec.Loki.Migration.Target
+		errs = append(errs, validateLokiAuth(target.AuthToken, target.AuthTokenSecret, target.BasicAuth, field.NewPath("spec", "loki", "migration", "target"))...)
+	}
+	for i, exporter := range fc.Spec.Exporters {
+		if exporter != nil {
+			errs = append(errs, validateAnonymization(exporter.Anonymization, field.NewPath("spec", "exporters").Index(i).Child("anonymization"))...)
+			if exporter.Type == LokiExporter {
+				loki := &exporter.Loki
+				errs = append(errs, validateLokiAuth(loki.AuthToken, loki.AuthTokenSecret, loki.BasicAuth, field.NewPath("spec", "exporters").Index(i).Child("loki"))...)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return kerr.NewInvalid(GroupVersion.WithKind("FlowCollector").GroupKind(), fc.Name, errs)
+	}
+	return nil
+}
+
+// validateFlowFilter checks the eBPF flow filter when enabled, as the agent doesn't start with an invalid filter
+func validateFlowFilter(filter *EBPFFlowFilter, path *field.Path) field.ErrorList {
+	if filter == nil || filter.Enable == nil || !*filter.Enable {
+		return nil
+	}
+	var errs field.ErrorList
+	if filter.CIDR != "" {
+		if _, _, err := net.ParseCIDR(filter.CIDR); err != nil {
+			errs = append(errs, field.Invalid(path.Child("cidr"), filter.CIDR, "must be a CIDR, such as 10.10.10.0/24"))
+		}
+	}
+	if filter.PeerIP != "" && net.ParseIP(filter.PeerIP) == nil {
+		errs = append(errs, field.Invalid(path.Child("peerIP"), filter.PeerIP, "must be an IP address"))
+	}
+
+	hasPorts := false
+	for name, ports := range map[string]intstr.IntOrString{"sourcePorts": filter.SourcePorts, "destPorts": filter.DestPorts, "ports": filter.Ports} {
+		if isPortsSet(ports) {
+			hasPorts = true
+			if err := validatePorts(ports); err != nil {
+				errs = append(errs, field.Invalid(path.Child(name), ports.String(), err.Error()))
+			}
+		}
+	}
+	if isPortsSet(filter.Ports) && (isPortsSet(filter.SourcePorts) || isPortsSet(filter.DestPorts)) {
+		errs = append(errs, field.Forbidden(path.Child("ports"), "can't be combined with sourcePorts or destPorts"))
+	}
+	if hasPorts && filter.Protocol != "TCP" && filter.Protocol != "UDP" && filter.Protocol != "SCTP" {
+		errs = append(errs, field.Invalid(path.Child("protocol"), filter.Protocol, "must be TCP, UDP or SCTP to filter on ports"))
+	}
+
+	hasICMP := false
+	for name, value := range map[string]*int{"icmpType": filter.ICMPType, "icmpCode": filter.ICMPCode} {
+		if value != nil {
+			hasICMP = true
+			if *value < 0 || *value > 255 {
+				errs = append(errs, field.Invalid(path.Child(name), *value, "must be between 0 and 255"))
+			}
+		}
+	}
+	if hasICMP && filter.Protocol != "ICMP" && filter.Protocol != "ICMPv6" {
+		errs = append(errs, field.Invalid(path.Child("protocol"), filter.Protocol, "must be ICMP or ICMPv6 to filter on ICMP type or code"))
+	}
+	return errs
+}
+
+// validateExporters checks that flows are handed to a single ClusterLogForwarder, as they are all collected from the
+// flowlogs-pipeline output
+func validateExporters(exporters []*FlowCollectorExporter, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	found := false
+	for i, exporter := range exporters {
+		if exporter == nil || exporter.Type != ClusterLogForwarderExporter {
+			continue
+		}
+		if found {
+			errs = append(errs, field.Forbidden(path.Index(i), "only one ClusterLogForwarder exporter is allowed"))
+		}
+		found = true
+	}
+	return errs
+}
+
+// validateLokiAggregation checks that the aggregation receives flows rather than conversations, which are already aggregated
+func validateLokiAggregation(spec *FlowCollectorSpec, path *field.Path) field.ErrorList {
+	agg := spec.Loki.Aggregation
+	if agg == nil || agg.Enable == nil || !*agg.Enable {
+		return nil
+	}
+	if spec.Processor.LogTypes != nil && *spec.Processor.LogTypes != LogTypeFlows {
+		return field.ErrorList{field.Forbidden(path.Child("enable"), "requires spec.processor.logTypes to be Flows")}
+	}
+	return nil
+}
+
+// validateStaticLabels checks that the static labels are valid Prometheus label names, which are not reserved for internal use
+func validateStaticLabels(labels map[string]string, path *field.Path) field.ErrorList {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs field.ErrorList
+	for _, name := range names {
+		if !promLabelName.MatchString(name) || strings.HasPrefix(name, "__") {
+			errs = append(errs, field.Invalid(path.Key(name), name, "must be a Prometheus label name, matching [a-zA-Z_][a-zA-Z0-9_]* and not starting with __"))
+		}
+	}
+	return errs
+}
+
+// validateAnonymization checks that the truncated prefix length is valid for IPv4 addresses, as it applies to both IPv4 and IPv6
+func validateAnonymization(anon *Anonymization, path *field.Path) field.ErrorList {
+	if anon == nil || anon.IPs != AnonymizationIPTruncate || anon.TruncatePrefixLength == nil {
+		return nil
+	}
+	if *anon.TruncatePrefixLength > 32 {
+		return field.ErrorList{field.Invalid(path.Child("truncatePrefixLength"), *anon.TruncatePrefixLength, "must not exceed 32, as it applies to IPv4 addresses")}
+	}
+	return nil
+}
+
+// validateHosted checks that the Grafana Cloud stack is fully configured, as flows would otherwise be written nowhere
+func validateHosted(hosted *FlowCollectorHosted, path *field.Path) field.ErrorList {
+	if hosted.Profile != HostedProfileGrafanaCloud {
+		return nil
+	}
+	gc := &hosted.GrafanaCloud
+	path = path.Child("grafanaCloud")
+	var errs field.ErrorList
+	if gc.LogsURL == "" {
+		errs = append(errs, field.Required(path.Child("logsUrl"), "must be set when profile is GrafanaCloud"))
+	} else if u, err := url.Parse(gc.LogsURL); err != nil || u.Scheme != "https" || u.Host == "" {
+		errs = append(errs, field.Invalid(path.Child("logsUrl"), gc.LogsURL, "must be an HTTPS URL, such as https://logs-prod-eu-west-0.grafana.net"))
+	}
+	if gc.LogsUser == "" {
+		errs = append(errs, field.Required(path.Child("logsUser"), "must be set when profile is GrafanaCloud"))
+	}
+	if gc.APIKey.Name == "" {
+		errs = append(errs, field.Required(path.Child("apiKey", "name"), "must be set when profile is GrafanaCloud"))
+	}
+	if gc.APIKey.File == "" {
+		errs = append(errs, field.Required(path.Child("apiKey", "file"), "must be set when profile is GrafanaCloud"))
+	}
+	return errs
+}
+
+// validateLokiAuth checks that the token Secret is set when used, as no credentials would be sent otherwise, and that a single authentication is used
+func validateLokiAuth(authToken LokiAuthToken, secret *FileReference, basicAuth *LokiBasicAuth, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if authToken == LokiAuthSecretToken && secret == nil {
+		errs = append(errs, field.Required(path.Child("authTokenSecret"), "must be set when authToken is Secret"))
+	}
+	if basicAuth != nil && authToken != "" && authToken != LokiAuthDisabled {
+		errs = append(errs, field.Forbidden(path.Child("basicAuth"), fmt.Sprintf("can't be combined with authToken %s", authToken)))
+	}
+	return errs
+}
+
+func isPortsSet(ports intstr.IntOrString) bool {
+	return (ports.Type == intstr.Int && ports.IntVal != 0) || (ports.Type == intstr.String && ports.StrVal != "")
+}
+
+// validatePorts checks a single port or a "start-end" range
+func validatePorts(ports intstr.IntOrString) error {
+	if ports.Type == intstr.Int {
+		return validatePort(int(ports.IntVal))
+	}
+	start, end, found := strings.Cut(ports.StrVal, "-")
+	if !found {
+		return fmt.Errorf("must be a port number, or a range such as 80-100")
+	}
+	startPort, err := strconv.Atoi(strings.TrimSpace(start))
+	if err != nil {
+		return fmt.Errorf("invalid range start: %w", err)
+	}
+	endPort, err := strconv.Atoi(strings.TrimSpace(end))
+	if err != nil {
+		return fmt.Errorf("invalid range end: %w", err)
+	}
+	if err := validatePort(startPort); err != nil {
+		return err
+	}
+	if err := validatePort(endPort); err != nil {
+		return err
+	}
+	if startPort >= endPort {
+		return fmt.Errorf("range start must be lower than its end")
+	}
+	return nil
+}
+
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port %d must be between 1 and 65535", port)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package v1beta2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func flowCollectorWithFilter(filter EBPFFlowFilter) *FlowCollector {
+	filter.Enable = ptr.To(true)
+	return &FlowCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       FlowCollectorSpec{Agent: FlowCollectorAgent{EBPF: FlowCollectorEBPF{FlowFilter: &filter}}},
+	}
+}
+
+func TestValidateFlowFilter(t *testing.T) {
+	assert := assert.New(t)
+	v := flowCollectorValidator{}
+
+	// valid filters
+	assert.NoError(v.validate(&FlowCollector{}))
+	assert.NoError(v.validate(flowCollectorWithFilter(EBPFFlowFilter{
+		CIDR:        "10.10.10.0/24",
+		Action:      "Accept",
+		Protocol:    "TCP",
+		SourcePorts: intstr.FromInt32(443),
+		DestPorts:   intstr.FromString("8000-8080"),
+		PeerIP:      "10.10.10.10",
+	})))
+	assert.NoError(v.validate(flowCollectorWithFilter(EBPFFlowFilter{
+		CIDR:     "100:100:100:100::/64",
+		Protocol: "ICMPv6",
+		ICMPType: ptr.To(128),
+	})))
+
+	// disabled filters are not validated
+	disabled := flowCollectorWithFilter(EBPFFlowFilter{CIDR: "foo"})
+	disabled.Spec.Agent.EBPF.FlowFilter.Enable = ptr.To(false)
+	assert.NoError(v.validate(disabled))
+
+	err := v.validate(flowCollectorWithFilter(EBPFFlowFilter{CIDR: "10.10.10.0", PeerIP: "10.10.10"}))
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.cidr: Invalid value: \"10.10.10.0\": must be a CIDR")
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.peerIP: Invalid value: \"10.10.10\": must be an IP address")
+
+	err = v.validate(flowCollectorWithFilter(EBPFFlowFilter{Protocol: "TCP", DestPorts: intstr.FromString("100-80")}))
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.destPorts: Invalid value: \"100-80\": range start must be lower than its end")
+
+	err = v.validate(flowCollectorWithFilter(EBPFFlowFilter{Protocol: "UDP", Ports: intstr.FromInt32(70000)}))
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.ports: Invalid value: \"70000\": port 70000 must be between 1 and 65535")
+
+	err = v.validate(flowCollectorWithFilter(EBPFFlowFilter{Protocol: "TCP", Ports: intstr.FromInt32(80), SourcePorts: intstr.FromInt32(443)}))
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.ports: Forbidden: can't be combined with sourcePorts or destPorts")
+
+	err = v.validate(flowCollectorWithFilter(EBPFFlowFilter{Protocol: "ICMP", Ports: intstr.FromInt32(80)}))
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.protocol: Invalid value: \"ICMP\": must be TCP, UDP or SCTP to filter on ports")
+
+	err = v.validate(flowCollectorWithFilter(EBPFFlowFilter{ICMPCode: ptr.To(300)}))
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.icmpCode: Invalid value: 300: must be between 0 and 255")
+	assert.Contains(err.Error(), "spec.agent.ebpf.flowFilter.protocol: Invalid value: \"\": must be ICMP or ICMPv6 to filter on ICMP type or code")
+}
+
+func TestValidateExporters(t *testing.T) {
+	assert := assert.New(t)
+	v := flowCollectorValidator{}
+
+	clf := func(name string) *FlowCollectorExporter {
+		return &FlowCollectorExporter{
+			Type:                ClusterLogForwarderExporter,
+			ClusterLogForwarder: FlowCollectorLogForwarderExporter{Name: name, Outputs: []string{"splunk"}},
+		}
+	}
+	fc := &FlowCollector{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	fc.Spec.Exporters = []*FlowCollectorExporter{{Type: KafkaExporter}, clf("a")}
+	assert.NoError(v.validate(fc))
+
+	fc.Spec.Exporters = append(fc.Spec.Exporters, clf("b"))
+	err := v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.exporters[2]: Forbidden: only one ClusterLogForwarder exporter is allowed")
+}
+
+func TestValidateLokiAggregation(t *testing.T) {
+	assert := assert.New(t)
+	v := flowCollectorValidator{}
+
+	fc := &FlowCollector{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	fc.Spec.Loki.Aggregation = &LokiAggregation{Enable: ptr.To(true)}
+	assert.NoError(v.validate(fc))
+	fc.Spec.Processor.LogTypes = ptr.To(LogTypeFlows)
+	assert.NoError(v.validate(fc))
+
+	fc.Spec.Processor.LogTypes = ptr.To(LogTypeConversations)
+	err := v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.loki.aggregation.enable: Forbidden: requires spec.processor.logTypes to be Flows")
+
+	fc.Spec.Loki.Aggregation.Enable = ptr.To(false)
+	assert.NoError(v.validate(fc))
+}
+
+func TestValidateStaticLabels(t *testing.T) {
+	assert := assert.New(t)
+	v := flowCollectorValidator{}
+
+	fc := &FlowCollector{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	fc.Spec.Processor.Metrics.StaticLabels = map[string]string{"cluster": "prod-eu", "_region": "eu"}
+	assert.NoError(v.validate(fc))
+
+	fc.Spec.Processor.Metrics.StaticLabels = map[string]string{"cluster-name": "prod-eu", "__name__": "foo"}
+	err := v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), `spec.processor.metrics.staticLabels[__name__]: Invalid value: "__name__": must be a Prometheus label name`)
+	assert.Contains(err.Error(), `spec.processor.metrics.staticLabels[cluster-name]: Invalid value: "cluster-name": must be a Prometheus label name`)
+}
+
+func TestValidateAnonymization(t *testing.T) {
+	assert := assert.New(t)
+	v := flowCollectorValidator{}
+
+	fc := &FlowCollector{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	fc.Spec.Processor.Anonymization = &Anonymization{IPs: AnonymizationIPTruncate, TruncatePrefixLength: ptr.To(int32(32))}
+	assert.NoError(v.validate(fc))
+
+	// not applicable to IPv4
+	fc.Spec.Processor.Anonymization.TruncatePrefixLength = ptr.To(int32(64))
+	err := v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.processor.anonymization.truncatePrefixLength: Invalid value: 64: must not exceed 32")
+
+	// ignored when not truncating
+	fc.Spec.Processor.Anonymization.IPs = AnonymizationIPDrop
+	assert.NoError(v.validate(fc))
+
+	// exporter override
+	fc.Spec.Exporters = []*FlowCollectorExporter{{Type: KafkaExporter, Anonymization: &Anonymization{IPs: AnonymizationIPTruncate, TruncatePrefixLength: ptr.To(int32(48))}}}
+	err = v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.exporters[0].anonymization.truncatePrefixLength: Invalid value: 48: must not exceed 32")
+}
+
+func TestValidateLokiAuth(t *testing.T) {
+	assert := assert.New(t)
+	v := flowCollectorValidator{}
+
+	fc := &FlowCollector{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	fc.Spec.Loki.Mode = LokiModeManual
+	fc.Spec.Loki.Manual.AuthToken = LokiAuthSecretToken
+	err := v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.loki.manual.authTokenSecret: Required value: must be set when authToken is Secret")
+
+	fc.Spec.Loki.Manual.AuthTokenSecret = &FileReference{Type: RefTypeSecret, Name: "loki-token", File: "token"}
+	assert.NoError(v.validate(fc))
+
+	// ignored when not in manual mode
+	fc.Spec.Loki.Mode = LokiModeLokiStack
+	fc.Spec.Loki.Manual.AuthTokenSecret = nil
+	assert.NoError(v.validate(fc))
+
+	// basic authentication can't be combined with a token
+	fc.Spec.Loki.Migration = &LokiMigration{Target: LokiManualParams{
+		AuthToken: LokiAuthForwardUserToken,
+		BasicAuth: &LokiBasicAuth{Username: "user"},
+	}}
+	err = v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.loki.migration.target.basicAuth: Forbidden: can't be combined with authToken Forward")
+
+	// Loki exporter
+	fc.Spec.Loki.Migration = nil
+	fc.Spec.Exporters = []*FlowCollectorExporter{{Type: LokiExporter, Loki: FlowCollectorLokiExporter{URL: "http://loki:3100", AuthToken: LokiAuthSecretToken}}}
+	err = v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.exporters[0].loki.authTokenSecret: Required value")
+}
+
+func TestValidateHosted(t *testing.T) {
+	assert := assert.New(t)
+	v := flowCollectorValidator{}
+
+	fc := &FlowCollector{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	fc.Spec.Hosted.Profile = HostedProfileGrafanaCloud
+	err := v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.hosted.grafanaCloud.logsUrl: Required value")
+	assert.Contains(err.Error(), "spec.hosted.grafanaCloud.logsUser: Required value")
+	assert.Contains(err.Error(), "spec.hosted.grafanaCloud.apiKey.name: Required value")
+	assert.Contains(err.Error(), "spec.hosted.grafanaCloud.apiKey.file: Required value")
+
+	fc.Spec.Hosted.GrafanaCloud = HostedGrafanaCloud{
+		LogsURL:  "https://logs-prod-eu-west-0.grafana.net",
+		LogsUser: "123456",
+		APIKey:   FileReference{Type: RefTypeSecret, Name: "grafana-cloud", File: "api-key"},
+	}
+	assert.NoError(v.validate(fc))
+
+	fc.Spec.Hosted.GrafanaCloud.LogsURL = "http://logs-prod-eu-west-0.grafana.net"
+	err = v.validate(fc)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), "spec.hosted.grafanaCloud.logsUrl: Invalid value")
+
+	// ignored when disabled
+	fc.Spec.Hosted.Profile = HostedProfileDisabled
+	assert.NoError(v.validate(fc))
+}
+
+func TestGatedFieldsWarnings(t *testing.T) {
+	assert := assert.New(t)
+	fc := &FlowCollector{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+	warnings, err := (&flowCollectorValidator{}).ValidateCreate(context.Background(), fc)
+	assert.NoError(err)
+	assert.Empty(warnings)
+
+	v := flowCollectorValidator{gated: func(spec *FlowCollectorSpec) []string {
+		if spec.Processor.BatchAutoTuning.Enable != nil && *spec.Processor.BatchAutoTuning.Enable {
+			return []string{"spec.processor.batchAutoTuning.enable is ignored"}
+		}
+		return nil
+	}}
+	fc.Spec.Processor.BatchAutoTuning.Enable = ptr.To(true)
+	warnings, err = v.ValidateUpdate(context.Background(), fc, fc)
+	assert.NoError(err)
+	assert.Equal(admission.Warnings{"spec.processor.batchAutoTuning.enable is ignored"}, warnings)
+}
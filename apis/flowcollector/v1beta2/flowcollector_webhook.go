@@ -18,10 +18,12 @@ package v1beta2
 
 import ctrl "sigs.k8s.io/controller-runtime"
 
-// +kubebuilder:webhook:verbs=create;update,path=/validate-netobserv-io-v1beta2-flowcollector,mutating=false,failurePolicy=fail,groups=netobserv.io,resources=flowcollectors,versions=v1beta2,name=flowcollectorconversionwebhook.netobserv.io,sideEffects=None,admissionReviewVersions=v1
-func (r *FlowCollector) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// +kubebuilder:webhook:verbs=create;update,path=/validate-flows-netobserv-io-v1beta2-flowcollector,mutating=false,failurePolicy=fail,groups=flows.netobserv.io,resources=flowcollectors,versions=v1beta2,name=flowcollectorvalidationwebhook.netobserv.io,sideEffects=None,admissionReviewVersions=v1
+// When gated is set, the fields that the operator ignores because of its feature gates are reported as warnings.
+func (r *FlowCollector) SetupWebhookWithManager(mgr ctrl.Manager, gated GatedFields) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&flowCollectorValidator{gated: gated}).
 		Complete()
 }
 
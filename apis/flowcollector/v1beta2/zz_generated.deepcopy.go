@@ -21,6 +21,7 @@ limitations under the License.
 package v1beta2
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,6 +43,16 @@ func (in *AdvancedAgentConfig) DeepCopyInto(out *AdvancedAgentConfig) {
 		*out = new(SchedulingConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.DaemonSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoRuntime != nil {
+		in, out := &in.GoRuntime, &out.GoRuntime
+		*out = new(GoRuntimeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvancedAgentConfig.
@@ -54,6 +65,36 @@ func (in *AdvancedAgentConfig) DeepCopy() *AdvancedAgentConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdvancedKafkaConfig) DeepCopyInto(out *AdvancedKafkaConfig) {
+	*out = *in
+	if in.BatchBytes != nil {
+		in, out := &in.BatchBytes, &out.BatchBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BatchMessages != nil {
+		in, out := &in.BatchMessages, &out.BatchMessages
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WriteTimeout != nil {
+		in, out := &in.WriteTimeout, &out.WriteTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvancedKafkaConfig.
+func (in *AdvancedKafkaConfig) DeepCopy() *AdvancedKafkaConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AdvancedKafkaConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AdvancedLokiConfig) DeepCopyInto(out *AdvancedLokiConfig) {
 	*out = *in
@@ -116,11 +157,33 @@ func (in *AdvancedPluginConfig) DeepCopyInto(out *AdvancedPluginConfig) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ServingCert != nil {
+		in, out := &in.ServingCert, &out.ServingCert
+		*out = new(CertificateReference)
+		**out = **in
+	}
 	if in.Scheduling != nil {
 		in, out := &in.Scheduling, &out.Scheduling
 		*out = new(SchedulingConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeploymentStrategy != nil {
+		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvancedPluginConfig.
@@ -188,6 +251,26 @@ func (in *AdvancedProcessorConfig) DeepCopyInto(out *AdvancedProcessorConfig) {
 		*out = new(SchedulingConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.DaemonSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentStrategy != nil {
+		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
+		*out = new(appsv1.DeploymentStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoRuntime != nil {
+		in, out := &in.GoRuntime, &out.GoRuntime
+		*out = new(GoRuntimeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdvancedProcessorConfig.
@@ -200,6 +283,176 @@ func (in *AdvancedProcessorConfig) DeepCopy() *AdvancedProcessorConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentAttachModeCount) DeepCopyInto(out *AgentAttachModeCount) {
+	*out = *in
+	if in.Unsupported != nil {
+		in, out := &in.Unsupported, &out.Unsupported
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentAttachModeCount.
+func (in *AgentAttachModeCount) DeepCopy() *AgentAttachModeCount {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentAttachModeCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentConflict) DeepCopyInto(out *AgentConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentConflict.
+func (in *AgentConflict) DeepCopy() *AgentConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentNodeState) DeepCopyInto(out *AgentNodeState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentNodeState.
+func (in *AgentNodeState) DeepCopy() *AgentNodeState {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentNodeState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentNodesStatus) DeepCopyInto(out *AgentNodesStatus) {
+	*out = *in
+	if in.Unhealthy != nil {
+		in, out := &in.Unhealthy, &out.Unhealthy
+		*out = make([]AgentNodeState, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentNodesStatus.
+func (in *AgentNodesStatus) DeepCopy() *AgentNodesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentNodesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnomalyDetection) DeepCopyInto(out *AnomalyDetection) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BaselineWindow != nil {
+		in, out := &in.BaselineWindow, &out.BaselineWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnomalyDetection.
+func (in *AnomalyDetection) DeepCopy() *AnomalyDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(AnomalyDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Anonymization) DeepCopyInto(out *Anonymization) {
+	*out = *in
+	if in.TruncatePrefixLength != nil {
+		in, out := &in.TruncatePrefixLength, &out.TruncatePrefixLength
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Anonymization.
+func (in *Anonymization) DeepCopy() *Anonymization {
+	if in == nil {
+		return nil
+	}
+	out := new(Anonymization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchTuningStatus) DeepCopyInto(out *BatchTuningStatus) {
+	*out = *in
+	if in.LokiWriteBatchWait != nil {
+		in, out := &in.LokiWriteBatchWait, &out.LokiWriteBatchWait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = new(v1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = new(v1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchTuningStatus.
+func (in *BatchTuningStatus) DeepCopy() *BatchTuningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchTuningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CardinalityGuard) DeepCopyInto(out *CardinalityGuard) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CardinalityGuard.
+func (in *CardinalityGuard) DeepCopy() *CardinalityGuard {
+	if in == nil {
+		return nil
+	}
+	out := new(CardinalityGuard)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateReference) DeepCopyInto(out *CertificateReference) {
 	*out = *in
@@ -247,6 +500,51 @@ func (in *ClusterNetworkOperatorConfig) DeepCopy() *ClusterNetworkOperatorConfig
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterNetworkStatus) DeepCopyInto(out *ClusterNetworkStatus) {
+	*out = *in
+	if in.PodCIDRs != nil {
+		in, out := &in.PodCIDRs, &out.PodCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceCIDRs != nil {
+		in, out := &in.ServiceCIDRs, &out.ServiceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNetworkStatus.
+func (in *ClusterNetworkStatus) DeepCopy() *ClusterNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsolePluginDeveloperPerspective) DeepCopyInto(out *ConsolePluginDeveloperPerspective) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsolePluginDeveloperPerspective.
+func (in *ConsolePluginDeveloperPerspective) DeepCopy() *ConsolePluginDeveloperPerspective {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsolePluginDeveloperPerspective)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConsolePluginPortConfig) DeepCopyInto(out *ConsolePluginPortConfig) {
 	*out = *in
@@ -329,538 +627,1557 @@ func (in *EBPFMetrics) DeepCopy() *EBPFMetrics {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FLPMetrics) DeepCopyInto(out *FLPMetrics) {
+func (in *EgressBudget) DeepCopyInto(out *EgressBudget) {
 	*out = *in
-	in.Server.DeepCopyInto(&out.Server)
-	if in.IncludeList != nil {
-		in, out := &in.IncludeList, &out.IncludeList
-		*out = new([]FLPMetric)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]FLPMetric, len(*in))
-			copy(*out, *in)
-		}
-	}
-	if in.DisableAlerts != nil {
-		in, out := &in.DisableAlerts, &out.DisableAlerts
-		*out = make([]FLPAlert, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPMetrics.
-func (in *FLPMetrics) DeepCopy() *FLPMetrics {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressBudget.
+func (in *EgressBudget) DeepCopy() *EgressBudget {
 	if in == nil {
 		return nil
 	}
-	out := new(FLPMetrics)
+	out := new(EgressBudget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FileReference) DeepCopyInto(out *FileReference) {
+func (in *ExporterFields) DeepCopyInto(out *ExporterFields) {
 	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileReference.
-func (in *FileReference) DeepCopy() *FileReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterFields.
+func (in *ExporterFields) DeepCopy() *ExporterFields {
 	if in == nil {
 		return nil
 	}
-	out := new(FileReference)
+	out := new(ExporterFields)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollector) DeepCopyInto(out *FlowCollector) {
+func (in *ExporterFilter) DeepCopyInto(out *ExporterFilter) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollector.
-func (in *FlowCollector) DeepCopy() *FlowCollector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExporterFilter.
+func (in *ExporterFilter) DeepCopy() *ExporterFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollector)
+	out := new(ExporterFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FlowCollector) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorAgent) DeepCopyInto(out *FlowCollectorAgent) {
+func (in *FLPBatchAutoTuning) DeepCopyInto(out *FLPBatchAutoTuning) {
 	*out = *in
-	out.IPFIX = in.IPFIX
-	in.EBPF.DeepCopyInto(&out.EBPF)
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorAgent.
-func (in *FlowCollectorAgent) DeepCopy() *FlowCollectorAgent {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPBatchAutoTuning.
+func (in *FLPBatchAutoTuning) DeepCopy() *FLPBatchAutoTuning {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorAgent)
+	out := new(FLPBatchAutoTuning)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorConsolePlugin) DeepCopyInto(out *FlowCollectorConsolePlugin) {
+func (in *FLPDSCPClassification) DeepCopyInto(out *FLPDSCPClassification) {
 	*out = *in
 	if in.Enable != nil {
 		in, out := &in.Enable, &out.Enable
 		*out = new(bool)
 		**out = **in
 	}
-	if in.Replicas != nil {
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPDSCPClassification.
+func (in *FLPDSCPClassification) DeepCopy() *FLPDSCPClassification {
+	if in == nil {
+		return nil
+	}
+	out := new(FLPDSCPClassification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FLPEgressClassification) DeepCopyInto(out *FLPEgressClassification) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ClusterCIDRs != nil {
+		in, out := &in.ClusterCIDRs, &out.ClusterCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrivateCIDRs != nil {
+		in, out := &in.PrivateCIDRs, &out.PrivateCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPEgressClassification.
+func (in *FLPEgressClassification) DeepCopy() *FLPEgressClassification {
+	if in == nil {
+		return nil
+	}
+	out := new(FLPEgressClassification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FLPExternalIngest) DeepCopyInto(out *FLPExternalIngest) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServerCert != nil {
+		in, out := &in.ServerCert, &out.ServerCert
+		*out = new(CertificateReference)
+		**out = **in
+	}
+	if in.ClientCA != nil {
+		in, out := &in.ClientCA, &out.ClientCA
+		*out = new(FileReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPExternalIngest.
+func (in *FLPExternalIngest) DeepCopy() *FLPExternalIngest {
+	if in == nil {
+		return nil
+	}
+	out := new(FLPExternalIngest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FLPMetrics) DeepCopyInto(out *FLPMetrics) {
+	*out = *in
+	in.Server.DeepCopyInto(&out.Server)
+	in.RBACProxy.DeepCopyInto(&out.RBACProxy)
+	if in.IncludeList != nil {
+		in, out := &in.IncludeList, &out.IncludeList
+		*out = new([]FLPMetric)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]FLPMetric, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.StaticLabels != nil {
+		in, out := &in.StaticLabels, &out.StaticLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Routing.DeepCopyInto(&out.Routing)
+	if in.FlowMetricsQuota != nil {
+		in, out := &in.FlowMetricsQuota, &out.FlowMetricsQuota
+		*out = new(FlowMetricsQuota)
+		**out = **in
+	}
+	in.CardinalityGuard.DeepCopyInto(&out.CardinalityGuard)
+	in.PipelineHealthCheck.DeepCopyInto(&out.PipelineHealthCheck)
+	if in.DisableAlerts != nil {
+		in, out := &in.DisableAlerts, &out.DisableAlerts
+		*out = make([]FLPAlert, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPMetrics.
+func (in *FLPMetrics) DeepCopy() *FLPMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(FLPMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FLPMultiHoming) DeepCopyInto(out *FLPMultiHoming) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPMultiHoming.
+func (in *FLPMultiHoming) DeepCopy() *FLPMultiHoming {
+	if in == nil {
+		return nil
+	}
+	out := new(FLPMultiHoming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FLPServiceMesh) DeepCopyInto(out *FLPServiceMesh) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPServiceMesh.
+func (in *FLPServiceMesh) DeepCopy() *FLPServiceMesh {
+	if in == nil {
+		return nil
+	}
+	out := new(FLPServiceMesh)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FLPVirtualization) DeepCopyInto(out *FLPVirtualization) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FLPVirtualization.
+func (in *FLPVirtualization) DeepCopy() *FLPVirtualization {
+	if in == nil {
+		return nil
+	}
+	out := new(FLPVirtualization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileReference) DeepCopyInto(out *FileReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileReference.
+func (in *FileReference) DeepCopy() *FileReference {
+	if in == nil {
+		return nil
+	}
+	out := new(FileReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollector) DeepCopyInto(out *FlowCollector) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollector.
+func (in *FlowCollector) DeepCopy() *FlowCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlowCollector) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorAgent) DeepCopyInto(out *FlowCollectorAgent) {
+	*out = *in
+	out.IPFIX = in.IPFIX
+	in.EBPF.DeepCopyInto(&out.EBPF)
+	in.OVN.DeepCopyInto(&out.OVN)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorAgent.
+func (in *FlowCollectorAgent) DeepCopy() *FlowCollectorAgent {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorAgent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorAgentStatus) DeepCopyInto(out *FlowCollectorAgentStatus) {
+	*out = *in
+	in.Nodes.DeepCopyInto(&out.Nodes)
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]AgentConflict, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttachModes != nil {
+		in, out := &in.AttachModes, &out.AttachModes
+		*out = make([]AgentAttachModeCount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorAgentStatus.
+func (in *FlowCollectorAgentStatus) DeepCopy() *FlowCollectorAgentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorAgentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorAnalytics) DeepCopyInto(out *FlowCollectorAnalytics) {
+	*out = *in
+	in.AnomalyDetection.DeepCopyInto(&out.AnomalyDetection)
+	in.Security.DeepCopyInto(&out.Security)
+	if in.EgressBudgets != nil {
+		in, out := &in.EgressBudgets, &out.EgressBudgets
+		*out = make([]EgressBudget, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorAnalytics.
+func (in *FlowCollectorAnalytics) DeepCopy() *FlowCollectorAnalytics {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorAnalytics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorClusterRBAC) DeepCopyInto(out *FlowCollectorClusterRBAC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorClusterRBAC.
+func (in *FlowCollectorClusterRBAC) DeepCopy() *FlowCollectorClusterRBAC {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorClusterRBAC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorComponentObject) DeepCopyInto(out *FlowCollectorComponentObject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorComponentObject.
+func (in *FlowCollectorComponentObject) DeepCopy() *FlowCollectorComponentObject {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorComponentObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorConsolePlugin) DeepCopyInto(out *FlowCollectorConsolePlugin) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
 		**out = **in
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	in.Autoscaler.DeepCopyInto(&out.Autoscaler)
-	in.PortNaming.DeepCopyInto(&out.PortNaming)
-	if in.QuickFilters != nil {
-		in, out := &in.QuickFilters, &out.QuickFilters
-		*out = make([]QuickFilter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Autoscaler.DeepCopyInto(&out.Autoscaler)
+	if in.AutoscalerUsage != nil {
+		in, out := &in.AutoscalerUsage, &out.AutoscalerUsage
+		*out = new(PluginAutoscalerUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	in.PortNaming.DeepCopyInto(&out.PortNaming)
+	if in.QuickFilters != nil {
+		in, out := &in.QuickFilters, &out.QuickFilters
+		*out = make([]QuickFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.DeveloperPerspective.DeepCopyInto(&out.DeveloperPerspective)
+	if in.Advanced != nil {
+		in, out := &in.Advanced, &out.Advanced
+		*out = new(AdvancedPluginConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorConsolePlugin.
+func (in *FlowCollectorConsolePlugin) DeepCopy() *FlowCollectorConsolePlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorConsolePlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorDiagnostics) DeepCopyInto(out *FlowCollectorDiagnostics) {
+	*out = *in
+	if in.ExtraGatherScripts != nil {
+		in, out := &in.ExtraGatherScripts, &out.ExtraGatherScripts
+		*out = new(FlowCollectorGatherScripts)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorDiagnostics.
+func (in *FlowCollectorDiagnostics) DeepCopy() *FlowCollectorDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorEBPF) DeepCopyInto(out *FlowCollectorEBPF) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Sampling != nil {
+		in, out := &in.Sampling, &out.Sampling
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeInterfaces != nil {
+		in, out := &in.ExcludeInterfaces, &out.ExcludeInterfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Advanced != nil {
+		in, out := &in.Advanced, &out.Advanced
+		*out = new(AdvancedAgentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]AgentFeature, len(*in))
+		copy(*out, *in)
+	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	if in.FlowFilter != nil {
+		in, out := &in.FlowFilter, &out.FlowFilter
+		*out = new(EBPFFlowFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorEBPF.
+func (in *FlowCollectorEBPF) DeepCopy() *FlowCollectorEBPF {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorEBPF)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorExporter) DeepCopyInto(out *FlowCollectorExporter) {
+	*out = *in
+	in.Kafka.DeepCopyInto(&out.Kafka)
+	out.IPFIX = in.IPFIX
+	in.Loki.DeepCopyInto(&out.Loki)
+	in.ClusterLogForwarder.DeepCopyInto(&out.ClusterLogForwarder)
+	if in.Anonymization != nil {
+		in, out := &in.Anonymization, &out.Anonymization
+		*out = new(Anonymization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = new(ExporterFields)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]ExporterFilter, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorExporter.
+func (in *FlowCollectorExporter) DeepCopy() *FlowCollectorExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorFLP) DeepCopyInto(out *FlowCollectorFLP) {
+	*out = *in
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.KafkaConsumerReplicas != nil {
+		in, out := &in.KafkaConsumerReplicas, &out.KafkaConsumerReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.KafkaConsumerAutoscaler.DeepCopyInto(&out.KafkaConsumerAutoscaler)
+	in.BatchAutoTuning.DeepCopyInto(&out.BatchAutoTuning)
+	if in.LogTypes != nil {
+		in, out := &in.LogTypes, &out.LogTypes
+		*out = new(FLPLogTypes)
+		**out = **in
+	}
+	if in.MultiClusterDeployment != nil {
+		in, out := &in.MultiClusterDeployment, &out.MultiClusterDeployment
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AddZone != nil {
+		in, out := &in.AddZone, &out.AddZone
+		*out = new(bool)
+		**out = **in
+	}
+	in.SubnetLabels.DeepCopyInto(&out.SubnetLabels)
+	if in.ClusterNetworkOperatorManaged != nil {
+		in, out := &in.ClusterNetworkOperatorManaged, &out.ClusterNetworkOperatorManaged
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Anonymization != nil {
+		in, out := &in.Anonymization, &out.Anonymization
+		*out = new(Anonymization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KafkaSource != nil {
+		in, out := &in.KafkaSource, &out.KafkaSource
+		*out = new(FlowCollectorKafkaSource)
+		**out = **in
+	}
+	in.ExternalIngest.DeepCopyInto(&out.ExternalIngest)
+	in.Virtualization.DeepCopyInto(&out.Virtualization)
+	in.DSCPClassification.DeepCopyInto(&out.DSCPClassification)
+	in.MultiHoming.DeepCopyInto(&out.MultiHoming)
+	in.EgressClassification.DeepCopyInto(&out.EgressClassification)
+	in.ServiceMesh.DeepCopyInto(&out.ServiceMesh)
+	if in.Advanced != nil {
+		in, out := &in.Advanced, &out.Advanced
+		*out = new(AdvancedProcessorConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorFLP.
+func (in *FlowCollectorFLP) DeepCopy() *FlowCollectorFLP {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorFLP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorGatherScripts) DeepCopyInto(out *FlowCollectorGatherScripts) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorGatherScripts.
+func (in *FlowCollectorGatherScripts) DeepCopy() *FlowCollectorGatherScripts {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorGatherScripts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorHPA) DeepCopyInto(out *FlowCollectorHPA) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]v2.MetricSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Behavior != nil {
+		in, out := &in.Behavior, &out.Behavior
+		*out = new(v2.HorizontalPodAutoscalerBehavior)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorHPA.
+func (in *FlowCollectorHPA) DeepCopy() *FlowCollectorHPA {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorHPA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorHosted) DeepCopyInto(out *FlowCollectorHosted) {
+	*out = *in
+	out.GrafanaCloud = in.GrafanaCloud
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorHosted.
+func (in *FlowCollectorHosted) DeepCopy() *FlowCollectorHosted {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorHosted)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorIPFIX) DeepCopyInto(out *FlowCollectorIPFIX) {
+	*out = *in
+	out.ClusterNetworkOperator = in.ClusterNetworkOperator
+	out.OVNKubernetes = in.OVNKubernetes
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorIPFIX.
+func (in *FlowCollectorIPFIX) DeepCopy() *FlowCollectorIPFIX {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorIPFIX)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorIPFIXReceiver) DeepCopyInto(out *FlowCollectorIPFIXReceiver) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorIPFIXReceiver.
+func (in *FlowCollectorIPFIXReceiver) DeepCopy() *FlowCollectorIPFIXReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorIPFIXReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorKafka) DeepCopyInto(out *FlowCollectorKafka) {
+	*out = *in
+	out.TLS = in.TLS
+	out.SASL = in.SASL
+	if in.Advanced != nil {
+		in, out := &in.Advanced, &out.Advanced
+		*out = new(AdvancedKafkaConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorKafka.
+func (in *FlowCollectorKafka) DeepCopy() *FlowCollectorKafka {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorKafka)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorKafkaSource) DeepCopyInto(out *FlowCollectorKafkaSource) {
+	*out = *in
+	out.TLS = in.TLS
+	out.SASL = in.SASL
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorKafkaSource.
+func (in *FlowCollectorKafkaSource) DeepCopy() *FlowCollectorKafkaSource {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorKafkaSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorList) DeepCopyInto(out *FlowCollectorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FlowCollector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorList.
+func (in *FlowCollectorList) DeepCopy() *FlowCollectorList {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FlowCollectorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorLoadGenerator) DeepCopyInto(out *FlowCollectorLoadGenerator) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FlowsPerSecond != nil {
+		in, out := &in.FlowsPerSecond, &out.FlowsPerSecond
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorLoadGenerator.
+func (in *FlowCollectorLoadGenerator) DeepCopy() *FlowCollectorLoadGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorLoadGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorLogForwarderExporter) DeepCopyInto(out *FlowCollectorLogForwarderExporter) {
+	*out = *in
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorLogForwarderExporter.
+func (in *FlowCollectorLogForwarderExporter) DeepCopy() *FlowCollectorLogForwarderExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorLogForwarderExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorLoki) DeepCopyInto(out *FlowCollectorLoki) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	in.Manual.DeepCopyInto(&out.Manual)
+	out.Microservices = in.Microservices
+	out.Monolithic = in.Monolithic
+	out.LokiStack = in.LokiStack
+	if in.ReadTimeout != nil {
+		in, out := &in.ReadTimeout, &out.ReadTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WriteTimeout != nil {
+		in, out := &in.WriteTimeout, &out.WriteTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WriteBatchWait != nil {
+		in, out := &in.WriteBatchWait, &out.WriteBatchWait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(LokiMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Aggregation != nil {
+		in, out := &in.Aggregation, &out.Aggregation
+		*out = new(LokiAggregation)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Advanced != nil {
 		in, out := &in.Advanced, &out.Advanced
-		*out = new(AdvancedPluginConfig)
+		*out = new(AdvancedLokiConfig)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorConsolePlugin.
-func (in *FlowCollectorConsolePlugin) DeepCopy() *FlowCollectorConsolePlugin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorLoki.
+func (in *FlowCollectorLoki) DeepCopy() *FlowCollectorLoki {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorConsolePlugin)
+	out := new(FlowCollectorLoki)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorEBPF) DeepCopyInto(out *FlowCollectorEBPF) {
+func (in *FlowCollectorLokiExporter) DeepCopyInto(out *FlowCollectorLokiExporter) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Sampling != nil {
-		in, out := &in.Sampling, &out.Sampling
+	if in.AuthTokenSecret != nil {
+		in, out := &in.AuthTokenSecret, &out.AuthTokenSecret
+		*out = new(FileReference)
+		**out = **in
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(LokiBasicAuth)
+		**out = **in
+	}
+	out.TLS = in.TLS
+	if in.WriteBatchWait != nil {
+		in, out := &in.WriteBatchWait, &out.WriteBatchWait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WriteTimeout != nil {
+		in, out := &in.WriteTimeout, &out.WriteTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorLokiExporter.
+func (in *FlowCollectorLokiExporter) DeepCopy() *FlowCollectorLokiExporter {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorLokiExporter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorOVN) DeepCopyInto(out *FlowCollectorOVN) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorOVN.
+func (in *FlowCollectorOVN) DeepCopy() *FlowCollectorOVN {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorOVN)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorProxy) DeepCopyInto(out *FlowCollectorProxy) {
+	*out = *in
+	if in.InjectTrustedCABundle != nil {
+		in, out := &in.InjectTrustedCABundle, &out.InjectTrustedCABundle
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorProxy.
+func (in *FlowCollectorProxy) DeepCopy() *FlowCollectorProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorQueryService) DeepCopyInto(out *FlowCollectorQueryService) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
 		**out = **in
 	}
-	if in.Interfaces != nil {
-		in, out := &in.Interfaces, &out.Interfaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorQueryService.
+func (in *FlowCollectorQueryService) DeepCopy() *FlowCollectorQueryService {
+	if in == nil {
+		return nil
 	}
-	if in.ExcludeInterfaces != nil {
-		in, out := &in.ExcludeInterfaces, &out.ExcludeInterfaces
-		*out = make([]string, len(*in))
+	out := new(FlowCollectorQueryService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorRetentionPolicy) DeepCopyInto(out *FlowCollectorRetentionPolicy) {
+	*out = *in
+	if in.LongTermAggregates != nil {
+		in, out := &in.LongTermAggregates, &out.LongTermAggregates
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AggregationWindow != nil {
+		in, out := &in.AggregationWindow, &out.AggregationWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorRetentionPolicy.
+func (in *FlowCollectorRetentionPolicy) DeepCopy() *FlowCollectorRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorSpec) DeepCopyInto(out *FlowCollectorSpec) {
+	*out = *in
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.ClusterRBAC = in.ClusterRBAC
+	in.Agent.DeepCopyInto(&out.Agent)
+	in.Processor.DeepCopyInto(&out.Processor)
+	in.Loki.DeepCopyInto(&out.Loki)
+	in.ConsolePlugin.DeepCopyInto(&out.ConsolePlugin)
+	in.QueryService.DeepCopyInto(&out.QueryService)
+	in.Kafka.DeepCopyInto(&out.Kafka)
+	if in.Exporters != nil {
+		in, out := &in.Exporters, &out.Exporters
+		*out = make([]*FlowCollectorExporter, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(FlowCollectorExporter)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	in.Proxy.DeepCopyInto(&out.Proxy)
+	in.Analytics.DeepCopyInto(&out.Analytics)
+	in.RetentionPolicy.DeepCopyInto(&out.RetentionPolicy)
+	out.Hosted = in.Hosted
+	in.Diagnostics.DeepCopyInto(&out.Diagnostics)
+	in.LoadGenerator.DeepCopyInto(&out.LoadGenerator)
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorSpec.
+func (in *FlowCollectorSpec) DeepCopy() *FlowCollectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FlowCollectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FlowCollectorStatus) DeepCopyInto(out *FlowCollectorStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]FlowCollectorComponentObject, len(*in))
 		copy(*out, *in)
 	}
-	if in.Advanced != nil {
-		in, out := &in.Advanced, &out.Advanced
-		*out = new(AdvancedAgentConfig)
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(FlowCollectorAgentStatus)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Features != nil {
-		in, out := &in.Features, &out.Features
-		*out = make([]AgentFeature, len(*in))
-		copy(*out, *in)
+	if in.LokiMigration != nil {
+		in, out := &in.LokiMigration, &out.LokiMigration
+		*out = new(LokiMigrationStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	in.Metrics.DeepCopyInto(&out.Metrics)
-	if in.FlowFilter != nil {
-		in, out := &in.FlowFilter, &out.FlowFilter
-		*out = new(EBPFFlowFilter)
+	if in.ClusterNetwork != nil {
+		in, out := &in.ClusterNetwork, &out.ClusterNetwork
+		*out = new(ClusterNetworkStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BatchTuning != nil {
+		in, out := &in.BatchTuning, &out.BatchTuning
+		*out = new(BatchTuningStatus)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorEBPF.
-func (in *FlowCollectorEBPF) DeepCopy() *FlowCollectorEBPF {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorStatus.
+func (in *FlowCollectorStatus) DeepCopy() *FlowCollectorStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorEBPF)
+	out := new(FlowCollectorStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorExporter) DeepCopyInto(out *FlowCollectorExporter) {
+func (in *FlowMetricsQuota) DeepCopyInto(out *FlowMetricsQuota) {
 	*out = *in
-	out.Kafka = in.Kafka
-	out.IPFIX = in.IPFIX
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorExporter.
-func (in *FlowCollectorExporter) DeepCopy() *FlowCollectorExporter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowMetricsQuota.
+func (in *FlowMetricsQuota) DeepCopy() *FlowMetricsQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorExporter)
+	out := new(FlowMetricsQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorFLP) DeepCopyInto(out *FlowCollectorFLP) {
+func (in *GoRuntimeConfig) DeepCopyInto(out *GoRuntimeConfig) {
 	*out = *in
-	in.Metrics.DeepCopyInto(&out.Metrics)
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.KafkaConsumerReplicas != nil {
-		in, out := &in.KafkaConsumerReplicas, &out.KafkaConsumerReplicas
+	if in.MemoryLimitPercent != nil {
+		in, out := &in.MemoryLimitPercent, &out.MemoryLimitPercent
 		*out = new(int32)
 		**out = **in
 	}
-	in.KafkaConsumerAutoscaler.DeepCopyInto(&out.KafkaConsumerAutoscaler)
-	if in.LogTypes != nil {
-		in, out := &in.LogTypes, &out.LogTypes
-		*out = new(FLPLogTypes)
+	if in.GCPercent != nil {
+		in, out := &in.GCPercent, &out.GCPercent
+		*out = new(int32)
 		**out = **in
 	}
-	if in.MultiClusterDeployment != nil {
-		in, out := &in.MultiClusterDeployment, &out.MultiClusterDeployment
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoRuntimeConfig.
+func (in *GoRuntimeConfig) DeepCopy() *GoRuntimeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GoRuntimeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostedGrafanaCloud) DeepCopyInto(out *HostedGrafanaCloud) {
+	*out = *in
+	out.APIKey = in.APIKey
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostedGrafanaCloud.
+func (in *HostedGrafanaCloud) DeepCopy() *HostedGrafanaCloud {
+	if in == nil {
+		return nil
+	}
+	out := new(HostedGrafanaCloud)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiAggregation) DeepCopyInto(out *LokiAggregation) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
 		*out = new(bool)
 		**out = **in
 	}
-	if in.AddZone != nil {
-		in, out := &in.AddZone, &out.AddZone
-		*out = new(bool)
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
 		**out = **in
 	}
-	in.SubnetLabels.DeepCopyInto(&out.SubnetLabels)
-	if in.Advanced != nil {
-		in, out := &in.Advanced, &out.Advanced
-		*out = new(AdvancedProcessorConfig)
-		(*in).DeepCopyInto(*out)
+	if in.GroupBy != nil {
+		in, out := &in.GroupBy, &out.GroupBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorFLP.
-func (in *FlowCollectorFLP) DeepCopy() *FlowCollectorFLP {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiAggregation.
+func (in *LokiAggregation) DeepCopy() *LokiAggregation {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorFLP)
+	out := new(LokiAggregation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorHPA) DeepCopyInto(out *FlowCollectorHPA) {
+func (in *LokiBasicAuth) DeepCopyInto(out *LokiBasicAuth) {
 	*out = *in
-	if in.MinReplicas != nil {
-		in, out := &in.MinReplicas, &out.MinReplicas
-		*out = new(int32)
+	out.Password = in.Password
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiBasicAuth.
+func (in *LokiBasicAuth) DeepCopy() *LokiBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LokiBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiManualParams) DeepCopyInto(out *LokiManualParams) {
+	*out = *in
+	if in.AuthTokenSecret != nil {
+		in, out := &in.AuthTokenSecret, &out.AuthTokenSecret
+		*out = new(FileReference)
 		**out = **in
 	}
-	if in.Metrics != nil {
-		in, out := &in.Metrics, &out.Metrics
-		*out = make([]v2.MetricSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(LokiBasicAuth)
+		**out = **in
 	}
+	out.TLS = in.TLS
+	out.StatusTLS = in.StatusTLS
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorHPA.
-func (in *FlowCollectorHPA) DeepCopy() *FlowCollectorHPA {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiManualParams.
+func (in *LokiManualParams) DeepCopy() *LokiManualParams {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorHPA)
+	out := new(LokiManualParams)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorIPFIX) DeepCopyInto(out *FlowCollectorIPFIX) {
+func (in *LokiMicroservicesParams) DeepCopyInto(out *LokiMicroservicesParams) {
 	*out = *in
-	out.ClusterNetworkOperator = in.ClusterNetworkOperator
-	out.OVNKubernetes = in.OVNKubernetes
+	out.TLS = in.TLS
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorIPFIX.
-func (in *FlowCollectorIPFIX) DeepCopy() *FlowCollectorIPFIX {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiMicroservicesParams.
+func (in *LokiMicroservicesParams) DeepCopy() *LokiMicroservicesParams {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorIPFIX)
+	out := new(LokiMicroservicesParams)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorIPFIXReceiver) DeepCopyInto(out *FlowCollectorIPFIXReceiver) {
+func (in *LokiMigration) DeepCopyInto(out *LokiMigration) {
 	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	if in.OverlapPeriod != nil {
+		in, out := &in.OverlapPeriod, &out.OverlapPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorIPFIXReceiver.
-func (in *FlowCollectorIPFIXReceiver) DeepCopy() *FlowCollectorIPFIXReceiver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiMigration.
+func (in *LokiMigration) DeepCopy() *LokiMigration {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorIPFIXReceiver)
+	out := new(LokiMigration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorKafka) DeepCopyInto(out *FlowCollectorKafka) {
+func (in *LokiMigrationStatus) DeepCopyInto(out *LokiMigrationStatus) {
 	*out = *in
-	out.TLS = in.TLS
-	out.SASL = in.SASL
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]LokiMigrationStep, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorKafka.
-func (in *FlowCollectorKafka) DeepCopy() *FlowCollectorKafka {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiMigrationStatus.
+func (in *LokiMigrationStatus) DeepCopy() *LokiMigrationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorKafka)
+	out := new(LokiMigrationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorList) DeepCopyInto(out *FlowCollectorList) {
+func (in *LokiMigrationStep) DeepCopyInto(out *LokiMigrationStep) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]FlowCollector, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorList.
-func (in *FlowCollectorList) DeepCopy() *FlowCollectorList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiMigrationStep.
+func (in *LokiMigrationStep) DeepCopy() *LokiMigrationStep {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorList)
+	out := new(LokiMigrationStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FlowCollectorList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LokiMonolithParams) DeepCopyInto(out *LokiMonolithParams) {
+	*out = *in
+	out.TLS = in.TLS
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiMonolithParams.
+func (in *LokiMonolithParams) DeepCopy() *LokiMonolithParams {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(LokiMonolithParams)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorLoki) DeepCopyInto(out *FlowCollectorLoki) {
+func (in *LokiStackRef) DeepCopyInto(out *LokiStackRef) {
 	*out = *in
-	if in.Enable != nil {
-		in, out := &in.Enable, &out.Enable
-		*out = new(bool)
-		**out = **in
-	}
-	out.Manual = in.Manual
-	out.Microservices = in.Microservices
-	out.Monolithic = in.Monolithic
-	out.LokiStack = in.LokiStack
-	if in.ReadTimeout != nil {
-		in, out := &in.ReadTimeout, &out.ReadTimeout
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.WriteTimeout != nil {
-		in, out := &in.WriteTimeout, &out.WriteTimeout
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.WriteBatchWait != nil {
-		in, out := &in.WriteBatchWait, &out.WriteBatchWait
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Advanced != nil {
-		in, out := &in.Advanced, &out.Advanced
-		*out = new(AdvancedLokiConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorLoki.
-func (in *FlowCollectorLoki) DeepCopy() *FlowCollectorLoki {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiStackRef.
+func (in *LokiStackRef) DeepCopy() *LokiStackRef {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorLoki)
+	out := new(LokiStackRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorSpec) DeepCopyInto(out *FlowCollectorSpec) {
+func (in *MetricsRBACProxy) DeepCopyInto(out *MetricsRBACProxy) {
 	*out = *in
-	in.Agent.DeepCopyInto(&out.Agent)
-	in.Processor.DeepCopyInto(&out.Processor)
-	in.Loki.DeepCopyInto(&out.Loki)
-	in.ConsolePlugin.DeepCopyInto(&out.ConsolePlugin)
-	out.Kafka = in.Kafka
-	if in.Exporters != nil {
-		in, out := &in.Exporters, &out.Exporters
-		*out = make([]*FlowCollectorExporter, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(FlowCollectorExporter)
-				**out = **in
-			}
-		}
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorSpec.
-func (in *FlowCollectorSpec) DeepCopy() *FlowCollectorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsRBACProxy.
+func (in *MetricsRBACProxy) DeepCopy() *MetricsRBACProxy {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorSpec)
+	out := new(MetricsRBACProxy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FlowCollectorStatus) DeepCopyInto(out *FlowCollectorStatus) {
+func (in *MetricsRouting) DeepCopyInto(out *MetricsRouting) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	if in.ServiceMonitorLabels != nil {
+		in, out := &in.ServiceMonitorLabels, &out.ServiceMonitorLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowCollectorStatus.
-func (in *FlowCollectorStatus) DeepCopy() *FlowCollectorStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsRouting.
+func (in *MetricsRouting) DeepCopy() *MetricsRouting {
 	if in == nil {
 		return nil
 	}
-	out := new(FlowCollectorStatus)
+	out := new(MetricsRouting)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LokiManualParams) DeepCopyInto(out *LokiManualParams) {
+func (in *MetricsServerConfig) DeepCopyInto(out *MetricsServerConfig) {
 	*out = *in
-	out.TLS = in.TLS
-	out.StatusTLS = in.StatusTLS
+	in.TLS.DeepCopyInto(&out.TLS)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiManualParams.
-func (in *LokiManualParams) DeepCopy() *LokiManualParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsServerConfig.
+func (in *MetricsServerConfig) DeepCopy() *MetricsServerConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(LokiManualParams)
+	out := new(MetricsServerConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LokiMicroservicesParams) DeepCopyInto(out *LokiMicroservicesParams) {
+func (in *OVNKubernetesConfig) DeepCopyInto(out *OVNKubernetesConfig) {
 	*out = *in
-	out.TLS = in.TLS
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiMicroservicesParams.
-func (in *LokiMicroservicesParams) DeepCopy() *LokiMicroservicesParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNKubernetesConfig.
+func (in *OVNKubernetesConfig) DeepCopy() *OVNKubernetesConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(LokiMicroservicesParams)
+	out := new(OVNKubernetesConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LokiMonolithParams) DeepCopyInto(out *LokiMonolithParams) {
+func (in *PipelineHealthCheck) DeepCopyInto(out *PipelineHealthCheck) {
 	*out = *in
-	out.TLS = in.TLS
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiMonolithParams.
-func (in *LokiMonolithParams) DeepCopy() *LokiMonolithParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineHealthCheck.
+func (in *PipelineHealthCheck) DeepCopy() *PipelineHealthCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(LokiMonolithParams)
+	out := new(PipelineHealthCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LokiStackRef) DeepCopyInto(out *LokiStackRef) {
+func (in *PluginAutoscalerUsage) DeepCopyInto(out *PluginAutoscalerUsage) {
 	*out = *in
+	if in.RequestsPerSecond != nil {
+		in, out := &in.RequestsPerSecond, &out.RequestsPerSecond
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LokiStackRef.
-func (in *LokiStackRef) DeepCopy() *LokiStackRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginAutoscalerUsage.
+func (in *PluginAutoscalerUsage) DeepCopy() *PluginAutoscalerUsage {
 	if in == nil {
 		return nil
 	}
-	out := new(LokiStackRef)
+	out := new(PluginAutoscalerUsage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricsServerConfig) DeepCopyInto(out *MetricsServerConfig) {
+func (in *ProbeConfig) DeepCopyInto(out *ProbeConfig) {
 	*out = *in
-	in.TLS.DeepCopyInto(&out.TLS)
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsServerConfig.
-func (in *MetricsServerConfig) DeepCopy() *MetricsServerConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeConfig.
+func (in *ProbeConfig) DeepCopy() *ProbeConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricsServerConfig)
+	out := new(ProbeConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OVNKubernetesConfig) DeepCopyInto(out *OVNKubernetesConfig) {
+func (in *ProbesConfig) DeepCopyInto(out *ProbesConfig) {
 	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(ProbeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(ProbeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ProbeConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OVNKubernetesConfig.
-func (in *OVNKubernetesConfig) DeepCopy() *OVNKubernetesConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfig.
+func (in *ProbesConfig) DeepCopy() *ProbesConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(OVNKubernetesConfig)
+	out := new(ProbesConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -938,6 +2255,41 @@ func (in *SchedulingConfig) DeepCopy() *SchedulingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityAnalytics) DeepCopyInto(out *SecurityAnalytics) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PortScanThreshold != nil {
+		in, out := &in.PortScanThreshold, &out.PortScanThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SynFloodThreshold != nil {
+		in, out := &in.SynFloodThreshold, &out.SynFloodThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityAnalytics.
+func (in *SecurityAnalytics) DeepCopy() *SecurityAnalytics {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityAnalytics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerTLS) DeepCopyInto(out *ServerTLS) {
 	*out = *in
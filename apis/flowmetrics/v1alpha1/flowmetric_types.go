@@ -61,7 +61,7 @@ type MetricFilter struct {
 // usage of Prometheus workloads as this could potentially have a high impact. Cf https://rhobs-handbook.netlify.app/products/openshiftmonitoring/telemetry.md/#what-is-the-cardinality-of-a-metric<br>
 // To check the cardinality of all NetObserv metrics, run as `promql`: `count({__name__=~"netobserv.*"}) by (__name__)`.
 type FlowMetricSpec struct {
-	// Name of the metric in Prometheus. It will be automatically prefixed with "netobserv_".
+	// Name of the metric in Prometheus. It will be automatically prefixed with the FlowCollector `spec.processor.metrics.prefix`, "netobserv_" by default.
 	// +required
 	MetricName string `json:"metricName"`
 
@@ -109,16 +109,46 @@ type FlowMetricSpec struct {
 	// A list of buckets to use when `type` is "Histogram". The list must be parseable as floats. Prometheus default buckets will be used if unset.
 	// +optional
 	Buckets []string `json:"buckets,omitempty"`
+
+	// `recordingRules` is a list of Prometheus recording rules generated alongside the metric, precomputing its rate aggregated by a subset of its labels,
+	// so that dashboards can query cheap precomputed series instead of computing `rate()` over the raw high-cardinality series.
+	// Each rule is recorded as `netobserv:<metricName>:rate<window>`, or `netobserv:<metricName>_by_<labels>:rate<window>` when `labels` are set,
+	// where `<labels>` are joined with `_`. For a "Histogram", the rate of the `_bucket` series is recorded, also aggregated by `le`.
+	// It requires the Prometheus Operator API (`PrometheusRule`) to be available in the cluster.
+	// +optional
+	RecordingRules []RecordingRule `json:"recordingRules,omitempty"`
+}
+
+// `RecordingRule` defines a recording rule aggregating the rate of a metric.
+type RecordingRule struct {
+	// `labels` is the list of labels to aggregate the rate by. They must be part of the metric `labels`.
+	// Leave empty to aggregate the whole cluster.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// `window` is the period over which the rate is computed.
+	// +kubebuilder:default:="5m"
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
 }
 
 // FlowMetricStatus defines the observed state of FlowMetric
 type FlowMetricStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// `conditions` represent the latest available observations of the FlowMetric, such as the `Disabled` condition set when the operator
+	// disables it because NetObserv metrics exceed their cardinality budget
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ConditionDisabled is set to true when the FlowMetric is disabled by the operator. It is only effective for the generation
+	// it was observed on, so that modifying the FlowMetric enables it again.
+	ConditionDisabled = "Disabled"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:deprecatedversion
 
 // FlowMetric is the Schema for the flowmetrics API
 type FlowMetric struct {
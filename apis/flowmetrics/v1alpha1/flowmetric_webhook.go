@@ -0,0 +1,126 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	utilconversion "github.com/netobserv/network-observability-operator/pkg/conversion"
+)
+
+// ConvertTo converts this v1alpha1 FlowMetric to its v1beta1 equivalent (the conversion Hub)
+// https://book.kubebuilder.io/multiversion-tutorial/conversion.html
+func (r *FlowMetric) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.FlowMetric)
+
+	dst.ObjectMeta = r.ObjectMeta
+	convertSpecToV1beta1(&r.Spec, &dst.Spec)
+	dst.Status.Conditions = make([]v1.Condition, len(r.Status.Conditions))
+	copy(dst.Status.Conditions, r.Status.Conditions)
+
+	// Manually restore data.
+	restored := &v1beta1.FlowMetric{}
+	if ok, err := utilconversion.UnmarshalData(r, restored); err != nil || !ok {
+		return err
+	}
+	// Restore elements that don't exist in v1alpha1
+	dst.Spec.Divider = restored.Spec.Divider
+	dst.Spec.Remap = restored.Spec.Remap
+	dst.Spec.Charts = restored.Spec.Charts
+	return nil
+}
+
+// ConvertFrom converts the hub version v1beta1 FlowMetric object to v1alpha1
+func (r *FlowMetric) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.FlowMetric)
+
+	r.ObjectMeta = src.ObjectMeta
+	convertSpecFromV1beta1(&src.Spec, &r.Spec)
+	r.Status.Conditions = make([]v1.Condition, len(src.Status.Conditions))
+	copy(r.Status.Conditions, src.Status.Conditions)
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, r)
+}
+
+func (r *FlowMetricList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1beta1.FlowMetricList)
+	dst.ListMeta = r.ListMeta
+	dst.Items = make([]v1beta1.FlowMetric, len(r.Items))
+	for i := range r.Items {
+		if err := r.Items[i].ConvertTo(&dst.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *FlowMetricList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1beta1.FlowMetricList)
+	r.ListMeta = src.ListMeta
+	r.Items = make([]FlowMetric, len(src.Items))
+	for i := range src.Items {
+		if err := r.Items[i].ConvertFrom(&src.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertSpecToV1beta1(in *FlowMetricSpec, out *v1beta1.FlowMetricSpec) {
+	out.MetricName = in.MetricName
+	out.Type = v1beta1.MetricType(in.Type)
+	out.ValueField = in.ValueField
+	out.Filters = nil
+	if in.Filters != nil {
+		out.Filters = make([]v1beta1.MetricFilter, len(in.Filters))
+		for i, f := range in.Filters {
+			out.Filters[i] = v1beta1.MetricFilter{Field: f.Field, Value: f.Value, MatchType: v1beta1.FilterMatchType(f.MatchType)}
+		}
+	}
+	out.Labels = in.Labels
+	out.IncludeDuplicates = in.IncludeDuplicates
+	out.Direction = v1beta1.FlowDirection(in.Direction)
+	out.Buckets = in.Buckets
+	out.RecordingRules = nil
+	for _, rr := range in.RecordingRules {
+		out.RecordingRules = append(out.RecordingRules, v1beta1.RecordingRule{Labels: rr.Labels, Window: rr.Window})
+	}
+}
+
+func convertSpecFromV1beta1(in *v1beta1.FlowMetricSpec, out *FlowMetricSpec) {
+	out.MetricName = in.MetricName
+	out.Type = MetricType(in.Type)
+	out.ValueField = in.ValueField
+	out.Filters = nil
+	if in.Filters != nil {
+		out.Filters = make([]MetricFilter, len(in.Filters))
+		for i, f := range in.Filters {
+			out.Filters[i] = MetricFilter{Field: f.Field, Value: f.Value, MatchType: FilterMatchType(f.MatchType)}
+		}
+	}
+	out.Labels = in.Labels
+	out.IncludeDuplicates = in.IncludeDuplicates
+	out.Direction = FlowDirection(in.Direction)
+	out.Buckets = in.Buckets
+	out.RecordingRules = nil
+	for _, rr := range in.RecordingRules {
+		out.RecordingRules = append(out.RecordingRules, RecordingRule{Labels: rr.Labels, Window: rr.Window})
+	}
+}
@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+)
+
+func TestConversionRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	// v1alpha1 -> v1beta1 -> v1alpha1
+	alpha := FlowMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: "m1", Namespace: "team-a"},
+		Spec: FlowMetricSpec{
+			MetricName:        "team_a_bytes_total",
+			Type:              CounterMetric,
+			ValueField:        "Bytes",
+			Filters:           []MetricFilter{{Field: "SrcK8S_Namespace", Value: "team-a", MatchType: MatchEqual}},
+			Labels:            []string{"DstK8S_Namespace"},
+			IncludeDuplicates: true,
+			Direction:         Egress,
+			RecordingRules:    []RecordingRule{{Labels: []string{"DstK8S_Namespace"}}},
+		},
+		Status: FlowMetricStatus{Conditions: []metav1.Condition{{Type: ConditionDisabled, Status: metav1.ConditionTrue}}},
+	}
+	hub := v1beta1.FlowMetric{}
+	assert.NoError(alpha.DeepCopy().ConvertTo(&hub))
+	assert.Equal("team_a_bytes_total", hub.Spec.MetricName)
+	assert.Equal(v1beta1.Egress, hub.Spec.Direction)
+	assert.True(hub.Spec.IncludeDuplicates)
+	assert.Equal([]string{"spec.includeDuplicates"}, hub.Spec.DeprecatedFields())
+	assert.Len(hub.Status.Conditions, 1)
+
+	back := FlowMetric{}
+	assert.NoError(back.ConvertFrom(&hub))
+	delete(back.Annotations, constants.ConversionAnnotation)
+	assert.Equal(alpha.Spec, back.Spec)
+	assert.Equal(alpha.Status, back.Status)
+
+	// v1beta1 -> v1alpha1 -> v1beta1: the fields that don't exist in v1alpha1 are restored
+	hub.Spec.Divider = "1000"
+	hub.Spec.Remap = map[string]string{"DstK8S_Namespace": "namespace"}
+	hub.Spec.Charts = []v1beta1.Chart{{
+		DashboardName: "Main",
+		Title:         "Bytes",
+		Type:          v1beta1.ChartTypeLine,
+		Queries:       []v1beta1.Query{{PromQL: "sum(rate($METRIC[2m])) by (namespace)", Legend: "{{ namespace }}", Top: 7}},
+	}}
+	alpha = FlowMetric{}
+	assert.NoError(alpha.ConvertFrom(hub.DeepCopy()))
+	assert.Contains(alpha.Annotations, constants.ConversionAnnotation)
+	restored := v1beta1.FlowMetric{}
+	assert.NoError(alpha.ConvertTo(&restored))
+	assert.Equal(hub.Spec, restored.Spec)
+	assert.NotContains(restored.Annotations, constants.ConversionAnnotation)
+}
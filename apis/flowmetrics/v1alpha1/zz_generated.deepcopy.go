@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,7 +31,7 @@ func (in *FlowMetric) DeepCopyInto(out *FlowMetric) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowMetric.
@@ -101,6 +102,13 @@ func (in *FlowMetricSpec) DeepCopyInto(out *FlowMetricSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RecordingRules != nil {
+		in, out := &in.RecordingRules, &out.RecordingRules
+		*out = make([]RecordingRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowMetricSpec.
@@ -116,6 +124,13 @@ func (in *FlowMetricSpec) DeepCopy() *FlowMetricSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FlowMetricStatus) DeepCopyInto(out *FlowMetricStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowMetricStatus.
@@ -142,3 +157,28 @@ func (in *MetricFilter) DeepCopy() *MetricFilter {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingRule) DeepCopyInto(out *RecordingRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingRule.
+func (in *RecordingRule) DeepCopy() *RecordingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingRule)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,194 @@
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/metrics"
+)
+
+const (
+	previewPeriod  = 5 * time.Minute
+	previewFlows   = 1000
+	previewSamples = 5
+	// previewTimeout keeps the preview below the default webhook timeout of 10s
+	previewTimeout = 5 * time.Second
+	// defaultBuckets is the number of Prometheus default histogram buckets
+	defaultBuckets = 11
+)
+
+// FlowsReader returns the flows written to Loki during the last period, up to limit
+type FlowsReader func(ctx context.Context, fc *flowslatest.FlowCollector, period time.Duration, limit int) ([]map[string]any, error)
+
+// preview evaluates a FlowMetric against recent flows, and reports the sample series and the estimated cardinality
+// as warnings. It runs on dry-run requests only, so that a definition can be checked before being created.
+func (v *quotaValidator) preview(ctx context.Context, fc *flowslatest.FlowCollector, fm *FlowMetric) []string {
+	if fc.Spec.Loki.Enable != nil && !*fc.Spec.Loki.Enable {
+		return []string{"preview unavailable: Loki is disabled in FlowCollector"}
+	}
+	ctx, cancel := context.WithTimeout(ctx, previewTimeout)
+	defer cancel()
+	flows, err := v.flows(ctx, fc, previewPeriod, previewFlows)
+	if err != nil {
+		return []string{fmt.Sprintf("preview unavailable: %v", err)}
+	}
+	prefix := metrics.GetPrefix(&fc.Spec)
+	p, err := previewMetric(&fm.Spec, flows)
+	if err != nil {
+		return []string{fmt.Sprintf("preview unavailable: %v", err)}
+	}
+	warnings := []string{fmt.Sprintf(
+		"preview: %d of the last %d flows (%s) match, for an estimated cardinality of %d series",
+		p.matched, len(flows), previewPeriod, p.cardinality(&fm.Spec),
+	)}
+	for _, s := range p.samples(previewSamples) {
+		warnings = append(warnings, fmt.Sprintf("preview: %s%s%s %s", prefix, fm.Spec.MetricName, s.labels, strconv.FormatFloat(s.value, 'f', -1, 64)))
+	}
+	return warnings
+}
+
+type series struct {
+	labels string
+	value  float64
+}
+
+type metricPreview struct {
+	matched int
+	series  map[string]float64
+}
+
+// previewMetric applies the filters and labels of a FlowMetric to flows, the same way as flowlogs-pipeline does
+func previewMetric(spec *FlowMetricSpec, flows []map[string]any) (*metricPreview, error) {
+	filters := slices.Clone(spec.Filters)
+	if !spec.IncludeDuplicates {
+		filters = append(filters, MetricFilter{Field: "Duplicate", Value: "true", MatchType: MatchNotEqual})
+	}
+	switch spec.Direction {
+	case Egress:
+		filters = append(filters, MetricFilter{Field: "FlowDirection", Value: "1|2", MatchType: MatchRegex})
+	case Ingress:
+		filters = append(filters, MetricFilter{Field: "FlowDirection", Value: "0|2", MatchType: MatchRegex})
+	}
+	regexes := map[string]*regexp.Regexp{}
+	for _, f := range filters {
+		if f.MatchType == MatchRegex || f.MatchType == MatchNotRegex {
+			r, err := regexp.Compile(f.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q for field %s: %w", f.Value, f.Field, err)
+			}
+			regexes[f.Value] = r
+		}
+	}
+	p := metricPreview{series: map[string]float64{}}
+	for _, flow := range flows {
+		if !matchFilters(flow, filters, regexes) {
+			continue
+		}
+		value := 1.0
+		if spec.ValueField != "" {
+			str, ok := fieldValue(flow, spec.ValueField)
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				continue
+			}
+			value = v
+		}
+		p.matched++
+		p.series[seriesLabels(flow, spec.Labels)] += value
+	}
+	return &p, nil
+}
+
+func matchFilters(flow map[string]any, filters []MetricFilter, regexes map[string]*regexp.Regexp) bool {
+	for _, f := range filters {
+		value, ok := fieldValue(flow, f.Field)
+		var match bool
+		switch f.MatchType {
+		case MatchEqual:
+			match = ok && value == f.Value
+		case MatchNotEqual:
+			match = !ok || value != f.Value
+		case MatchPresence:
+			match = ok
+		case MatchAbsence:
+			match = !ok
+		case MatchRegex:
+			match = ok && regexes[f.Value].MatchString(value)
+		case MatchNotRegex:
+			match = !ok || !regexes[f.Value].MatchString(value)
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValue returns a flow field formatted as a metric label value
+func fieldValue(flow map[string]any, field string) (string, bool) {
+	v, ok := flow[field]
+	if !ok || v == nil {
+		return "", false
+	}
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	default:
+		return fmt.Sprint(val), true
+	}
+}
+
+func seriesLabels(flow map[string]any, labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		value, _ := fieldValue(flow, l)
+		pairs = append(pairs, fmt.Sprintf("%s=%q", l, value))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// cardinality estimates the number of series created from the previewed flows: a histogram has a series per
+// bucket, plus the +Inf bucket, the sum and the count
+func (p *metricPreview) cardinality(spec *FlowMetricSpec) int {
+	if spec.Type != HistogramMetric {
+		return len(p.series)
+	}
+	buckets := defaultBuckets
+	if len(spec.Buckets) > 0 {
+		buckets = len(spec.Buckets)
+	}
+	return len(p.series) * (buckets + 3)
+}
+
+// samples returns the series having the highest values
+func (p *metricPreview) samples(n int) []series {
+	all := make([]series, 0, len(p.series))
+	for labels, value := range p.series {
+		all = append(all, series{labels: labels, value: value})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].value != all[j].value {
+			return all[i].value > all[j].value
+		}
+		return all[i].labels < all[j].labels
+	})
+	if len(all) > n {
+		all = all[:n]
+	}
+	return all
+}
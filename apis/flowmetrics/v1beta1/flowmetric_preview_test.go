@@ -0,0 +1,94 @@
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+var previewedFlows = []map[string]any{
+	{"SrcK8S_Namespace": "team-a", "DstK8S_Namespace": "team-b", "Bytes": float64(1000), "FlowDirection": "1"},
+	{"SrcK8S_Namespace": "team-a", "DstK8S_Namespace": "team-b", "Bytes": float64(500), "FlowDirection": "0"},
+	{"SrcK8S_Namespace": "team-a", "DstK8S_Namespace": "team-c", "Bytes": float64(2000000), "FlowDirection": "2"},
+	{"SrcK8S_Namespace": "team-a", "Bytes": float64(100), "FlowDirection": "1"},
+	{"SrcK8S_Namespace": "team-a", "DstK8S_Namespace": "team-b", "Bytes": float64(1000), "FlowDirection": "1", "Duplicate": true},
+	{"SrcK8S_Namespace": "team-d", "DstK8S_Namespace": "team-b", "Bytes": float64(10), "FlowDirection": "1"},
+}
+
+func TestPreviewMetric(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := FlowMetricSpec{
+		Type:       CounterMetric,
+		ValueField: "Bytes",
+		Labels:     []string{"DstK8S_Namespace"},
+		Filters:    []MetricFilter{{Field: "SrcK8S_Namespace", Value: "team-a", MatchType: MatchEqual}},
+		Direction:  Egress,
+	}
+	p, err := previewMetric(&spec, previewedFlows)
+	assert.NoError(err)
+	assert.Equal(3, p.matched)
+	assert.Equal(map[string]float64{
+		`{DstK8S_Namespace="team-b"}`: 1000,
+		`{DstK8S_Namespace="team-c"}`: 2000000,
+		`{DstK8S_Namespace=""}`:       100,
+	}, p.series)
+	assert.Equal(3, p.cardinality(&spec))
+	assert.Equal([]series{{labels: `{DstK8S_Namespace="team-c"}`, value: 2000000}}, p.samples(1))
+	assert.Len(spec.Filters, 1, "the spec filters must not be modified")
+
+	// duplicates, absence, histogram
+	spec = FlowMetricSpec{
+		Type:              HistogramMetric,
+		Labels:            []string{"SrcK8S_Namespace"},
+		Filters:           []MetricFilter{{Field: "DstK8S_Namespace", MatchType: MatchPresence}},
+		IncludeDuplicates: true,
+		Buckets:           []string{"1", "10"},
+	}
+	p, err = previewMetric(&spec, previewedFlows)
+	assert.NoError(err)
+	assert.Equal(5, p.matched)
+	assert.Equal(10, p.cardinality(&spec))
+
+	spec.Filters = []MetricFilter{{Field: "DstK8S_Namespace", Value: "(", MatchType: MatchRegex}}
+	_, err = previewMetric(&spec, previewedFlows)
+	assert.ErrorContains(err, `invalid regex "(" for field DstK8S_Namespace`)
+}
+
+func TestPreviewWarnings(t *testing.T) {
+	assert := assert.New(t)
+
+	fm := flowMetric("m1", "DstK8S_Namespace")
+	fm.Spec.MetricName = "team_a_flows_total"
+	fm.Spec.Filters = []MetricFilter{{Field: "SrcK8S_Namespace", Value: "team-a", MatchType: MatchEqual}}
+	fc := flowslatest.FlowCollector{}
+	v := quotaValidator{flows: func(_ context.Context, _ *flowslatest.FlowCollector, period time.Duration, limit int) ([]map[string]any, error) {
+		assert.Equal(previewPeriod, period)
+		assert.Equal(previewFlows, limit)
+		return previewedFlows, nil
+	}}
+	assert.Equal([]string{
+		"preview: 4 of the last 6 flows (5m0s) match, for an estimated cardinality of 3 series",
+		`preview: netobserv_team_a_flows_total{DstK8S_Namespace="team-b"} 2`,
+		`preview: netobserv_team_a_flows_total{DstK8S_Namespace=""} 1`,
+		`preview: netobserv_team_a_flows_total{DstK8S_Namespace="team-c"} 1`,
+	}, v.preview(context.Background(), &fc, fm))
+
+	// custom prefix
+	fc.Spec.Processor.Metrics.Prefix = ptr.To("prod_")
+	assert.Contains(v.preview(context.Background(), &fc, fm), `preview: prod_team_a_flows_total{DstK8S_Namespace="team-b"} 2`)
+
+	v.flows = func(context.Context, *flowslatest.FlowCollector, time.Duration, int) ([]map[string]any, error) {
+		return nil, errors.New("could not query Loki: connection refused")
+	}
+	assert.Equal([]string{"preview unavailable: could not query Loki: connection refused"}, v.preview(context.Background(), &fc, fm))
+
+	fc.Spec.Loki.Enable = ptr.To(false)
+	assert.Equal([]string{"preview unavailable: Loki is disabled in FlowCollector"}, v.preview(context.Background(), &fc, fm))
+}
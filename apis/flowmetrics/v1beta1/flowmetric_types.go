@@ -0,0 +1,261 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type MetricType string
+type FilterMatchType string
+type FlowDirection string
+
+const (
+	CounterMetric   MetricType = "Counter"
+	HistogramMetric MetricType = "Histogram"
+	// Note: we don't expose gauge on purpose to avoid configuration mistake related to gauge limitation.
+	// 99% of times, "counter" or "histogram" should be the ones to use. We can eventually revisit later.
+	MatchEqual    FilterMatchType = "Equal"
+	MatchNotEqual FilterMatchType = "NotEqual"
+	MatchPresence FilterMatchType = "Presence"
+	MatchAbsence  FilterMatchType = "Absence"
+	MatchRegex    FilterMatchType = "MatchRegex"
+	MatchNotRegex FilterMatchType = "NotMatchRegex"
+	Egress        FlowDirection   = "Egress"
+	Ingress       FlowDirection   = "Ingress"
+	AnyDirection  FlowDirection   = "Any"
+)
+
+type MetricFilter struct {
+	// Name of the field to filter on
+	// +required
+	Field string `json:"field"`
+
+	// Value to filter on
+	// +optional
+	Value string `json:"value"`
+
+	// Type of matching to apply
+	// +kubebuilder:validation:Enum:="Equal";"NotEqual";"Presence";"Absence";"MatchRegex";"NotMatchRegex"
+	// +kubebuilder:default:="Equal"
+	MatchType FilterMatchType `json:"matchType"`
+}
+
+// FlowMetricSpec defines the desired state of FlowMetric
+// The provided API allows you to customize these metrics according to your needs.<br>
+// When adding new metrics or modifying existing labels, you must carefully monitor the memory
+// usage of Prometheus workloads as this could potentially have a high impact. Cf https://rhobs-handbook.netlify.app/products/openshiftmonitoring/telemetry.md/#what-is-the-cardinality-of-a-metric<br>
+// To check the cardinality of all NetObserv metrics, run as `promql`: `count({__name__=~"netobserv.*"}) by (__name__)`.
+type FlowMetricSpec struct {
+	// Name of the metric in Prometheus. It will be automatically prefixed with the FlowCollector `spec.processor.metrics.prefix`, "netobserv_" by default.
+	// +required
+	MetricName string `json:"metricName"`
+
+	// Metric type: "Counter" or "Histogram".
+	// Use "Counter" for any value that increases over time and on which you can compute a rate, such as Bytes or Packets.
+	// Use "Histogram" for any value that must be sampled independently, such as latencies.
+	// +kubebuilder:validation:Enum:="Counter";"Histogram"
+	// +required
+	Type MetricType `json:"type"`
+
+	// `valueField` is the flow field that must be used as a value for this metric. This field must hold numeric values.
+	// Leave empty to count flows rather than a specific value per flow.
+	// Refer to the documentation for the list of available fields: https://docs.openshift.com/container-platform/latest/networking/network_observability/json-flows-format-reference.html.
+	// +optional
+	ValueField string `json:"valueField,omitempty"`
+
+	// `filters` is a list of fields and values used to restrict which flows are taken into account. Oftentimes, these filters must
+	// be used to eliminate duplicates: `Duplicate != "true"` and `FlowDirection = "0"`.
+	// Refer to the documentation for the list of available fields: https://docs.openshift.com/container-platform/latest/networking/network_observability/json-flows-format-reference.html.
+	// +optional
+	Filters []MetricFilter `json:"filters"`
+
+	// `labels` is a list of fields that should be used as Prometheus labels, also known as dimensions.
+	// From choosing labels results the level of granularity of this metric, as well as the available aggregations at query time.
+	// It must be done carefully as it impacts the metric cardinality (cf https://rhobs-handbook.netlify.app/products/openshiftmonitoring/telemetry.md/#what-is-the-cardinality-of-a-metric).
+	// In general, avoid setting very high cardinality labels such as IP or MAC addresses.
+	// "SrcK8S_OwnerName" or "DstK8S_OwnerName" should be preferred over "SrcK8S_Name" or "DstK8S_Name" as much as possible.
+	// Refer to the documentation for the list of available fields: https://docs.openshift.com/container-platform/latest/network_observability/json-flows-format-reference.html.
+	// +optional
+	Labels []string `json:"labels"`
+
+	// `includeDuplicates` [deprecated (*)]: when set to `true`, flows duplicated across several interfaces will add up in the generated metrics.
+	// When set to `false` (default), it is equivalent to adding the exact filter on `Duplicate` != `true`.
+	// Duplicated flows are merged by the eBPF agent by default, so this setting only matters when the agent is configured to mark them.
+	// It is reported by the `Deprecated` condition.
+	// +optional
+	IncludeDuplicates bool `json:"includeDuplicates,omitempty"`
+
+	// Filter for ingress, egress or any direction flows.
+	// When set to `Ingress`, it is equivalent to adding the regex filter on `FlowDirection`: `0|2`.
+	// When set to `Egress`, it is equivalent to adding the regex filter on `FlowDirection`: `1|2`.
+	// +kubebuilder:validation:Enum:="Any";"Egress";"Ingress"
+	// +kubebuilder:default:="Any"
+	// +optional
+	Direction FlowDirection `json:"direction,omitempty"`
+
+	// A list of buckets to use when `type` is "Histogram". The list must be parseable as floats. Prometheus default buckets will be used if unset.
+	// +optional
+	Buckets []string `json:"buckets,omitempty"`
+
+	// When nonzero, scale factor (divider) of the value. Metric value = Flow value / Divider.
+	// For instance, use "1000000000" to convert a time in nanoseconds into seconds.
+	// +kubebuilder:validation:Pattern:=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	Divider string `json:"divider,omitempty"`
+
+	// Set the `remap` property to use different names for the generated metric labels than the flow fields.
+	// Use the origin flow fields as keys, and the desired label names as values.
+	// The flow fields used as keys must be part of `labels`.
+	// +optional
+	Remap map[string]string `json:"remap,omitempty"`
+
+	// Charts configuration, for the OpenShift Console in the administrator view, Dashboards menu.
+	// +optional
+	Charts []Chart `json:"charts,omitempty"`
+
+	// `recordingRules` is a list of Prometheus recording rules generated alongside the metric, precomputing its rate aggregated by a subset of its labels,
+	// so that dashboards can query cheap precomputed series instead of computing `rate()` over the raw high-cardinality series.
+	// Each rule is recorded as `netobserv:<metricName>:rate<window>`, or `netobserv:<metricName>_by_<labels>:rate<window>` when `labels` are set,
+	// where `<labels>` are joined with `_`. For a "Histogram", the rate of the `_bucket` series is recorded, also aggregated by `le`.
+	// It requires the Prometheus Operator API (`PrometheusRule`) to be available in the cluster.
+	// +optional
+	RecordingRules []RecordingRule `json:"recordingRules,omitempty"`
+}
+
+// `RecordingRule` defines a recording rule aggregating the rate of a metric.
+type RecordingRule struct {
+	// `labels` is the list of labels to aggregate the rate by. They must be part of the metric `labels`, and are renamed as set in `remap`.
+	// Leave empty to aggregate the whole cluster.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// `window` is the period over which the rate is computed.
+	// +kubebuilder:default:="5m"
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+type Unit string
+type ChartType string
+
+const (
+	UnitBytes           Unit      = "bytes"
+	UnitSeconds         Unit      = "seconds"
+	UnitBPS             Unit      = "Bps"
+	UnitPPS             Unit      = "pps"
+	UnitPercent         Unit      = "percent"
+	UnitNone            Unit      = ""
+	ChartTypeSingleStat ChartType = "SingleStat"
+	ChartTypeLine       ChartType = "Line"
+	ChartTypeStackArea  ChartType = "StackArea"
+)
+
+// Configures charts / dashboard generation associated to a metric
+type Chart struct {
+	// Name of the containing dashboard. If this name does not refer to an existing dashboard, a new dashboard is created.
+	// +kubebuilder:default:="Main"
+	DashboardName string `json:"dashboardName"`
+
+	// Name of the containing dashboard section. If this name does not refer to an existing section, a new section is created.
+	// If `sectionName` is omitted or empty, the chart is placed in the global top section.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+
+	// Title of the chart.
+	// +required
+	Title string `json:"title"`
+
+	// Unit of this chart. Only a few units are currently supported. Leave empty to use generic number.
+	// +kubebuilder:validation:Enum:="bytes";"seconds";"Bps";"pps";"percent";""
+	// +optional
+	Unit Unit `json:"unit,omitempty"`
+
+	// Type of the chart.
+	// +kubebuilder:validation:Enum:="SingleStat";"Line";"StackArea"
+	// +required
+	Type ChartType `json:"type"`
+
+	// List of queries to be displayed on this chart. If `type` is `SingleStat` and multiple queries are provided,
+	// this chart is automatically expanded in several panels (one per query).
+	// +required
+	Queries []Query `json:"queries"`
+}
+
+// Configures PromQL queries
+type Query struct {
+	// The `promQL` query to be run against Prometheus. If the chart `type` is `SingleStat`, this query should only return
+	// a single timeseries. For other types, a top 7 is displayed.
+	// You can use `$METRIC` to refer to the metric defined in this resource. For example: `sum(rate($METRIC[2m]))`.
+	// To learn more about `promQL`, refer to the Prometheus documentation: https://prometheus.io/docs/prometheus/latest/querying/basics/
+	// +required
+	PromQL string `json:"promQL"`
+
+	// The query legend that applies to each timeseries represented in this chart. When multiple timeseries are displayed, you should set a legend
+	// that distinguishes each of them. It can be done with the following format: `{{ Label }}`. For example, if the `promQL` groups timeseries per
+	// label such as: `sum(rate($METRIC[2m])) by (Label1, Label2)`, you may write as the legend: `Label1={{ Label1 }}, Label2={{ Label2 }}`.
+	// +required
+	Legend string `json:"legend"`
+
+	// Top N series to display per timestamp. Does not apply to `SingleStat` chart type.
+	// +kubebuilder:default:=7
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	Top int `json:"top"`
+}
+
+// FlowMetricStatus defines the observed state of FlowMetric
+type FlowMetricStatus struct {
+	// `conditions` represent the latest available observations of the FlowMetric, such as the `Disabled` condition set when the operator
+	// disables it because NetObserv metrics exceed their cardinality budget
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionDisabled is set to true when the FlowMetric is disabled by the operator. It is only effective for the generation
+	// it was observed on, so that modifying the FlowMetric enables it again.
+	ConditionDisabled = "Disabled"
+	// ConditionDeprecated is set to true when the FlowMetric uses deprecated fields, which are listed in its message
+	ConditionDeprecated = "Deprecated"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// FlowMetric is the Schema for the flowmetrics API
+type FlowMetric struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FlowMetricSpec   `json:"spec,omitempty"`
+	Status FlowMetricStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FlowMetricList contains a list of FlowMetric
+type FlowMetricList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FlowMetric `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FlowMetric{}, &FlowMetricList{})
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/flowschema"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-flows-netobserv-io-v1beta1-flowmetric,mutating=false,failurePolicy=ignore,groups=flows.netobserv.io,resources=flowmetrics,versions=v1beta1,name=flowmetricvalidationwebhook.netobserv.io,sideEffects=None,admissionReviewVersions=v1
+// The failure policy is ignore, so that FlowMetrics can still be edited when the operator is unavailable; the quota is then not enforced.
+// When flows is set, dry-run requests preview the FlowMetric against the recent flows.
+func (r *FlowMetric) SetupWebhookWithManager(mgr ctrl.Manager, flows FlowsReader) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&quotaValidator{reader: mgr.GetAPIReader(), flows: flows}).
+		Complete()
+}
+
+// Hub marks this version as a conversion hub.
+// All the other version need to provide converters from/to this version.
+// https://book.kubebuilder.io/multiversion-tutorial/conversion-concepts.html
+func (*FlowMetric) Hub()     {}
+func (*FlowMetricList) Hub() {}
+
+// DeprecatedFields returns the paths of the deprecated fields set in this FlowMetric
+func (s *FlowMetricSpec) DeprecatedFields() []string {
+	var fields []string
+	if s.IncludeDuplicates {
+		fields = append(fields, "spec.includeDuplicates")
+	}
+	return fields
+}
+
+// LabelName returns the name of the metric label generated from a flow field, as set in `remap`
+func (s *FlowMetricSpec) LabelName(field string) string {
+	if label, ok := s.Remap[field]; ok {
+		return label
+	}
+	return field
+}
+
+// quotaValidator enforces the FlowMetric quota configured in FlowCollector
+type quotaValidator struct {
+	reader client.Reader
+	flows  FlowsReader
+}
+
+var _ admission.CustomValidator = &quotaValidator{}
+
+func (v *quotaValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	fm, ok := obj.(*FlowMetric)
+	if !ok {
+		return nil, kerr.NewBadRequest(fmt.Sprintf("expected a FlowMetric but got a %T", obj))
+	}
+	return v.validate(ctx, nil, fm)
+}
+
+func (v *quotaValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldFM, ok := oldObj.(*FlowMetric)
+	if !ok {
+		return nil, kerr.NewBadRequest(fmt.Sprintf("expected a FlowMetric but got a %T", oldObj))
+	}
+	fm, ok := newObj.(*FlowMetric)
+	if !ok {
+		return nil, kerr.NewBadRequest(fmt.Sprintf("expected a FlowMetric but got a %T", newObj))
+	}
+	return v.validate(ctx, oldFM, fm)
+}
+
+func (v *quotaValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *quotaValidator) validate(ctx context.Context, oldFM, fm *FlowMetric) (admission.Warnings, error) {
+	// the FlowMetrics of the namespace share the metrics pipeline, hence the remapped labels and the quota
+	var existing *FlowMetricList
+	listExisting := func() ([]FlowMetric, error) {
+		if existing == nil {
+			list := FlowMetricList{}
+			if err := v.reader.List(ctx, &list, &client.ListOptions{Namespace: fm.Namespace}); err != nil {
+				return nil, fmt.Errorf("could not list FlowMetrics: %w", err)
+			}
+			existing = &list
+		}
+		return existing.Items, nil
+	}
+	if len(fm.Spec.Remap) > 0 {
+		items, err := listExisting()
+		if err != nil {
+			return nil, err
+		}
+		fields, err := flowschema.FieldNames()
+		if err != nil {
+			return nil, err
+		}
+		if err := validateRemap(fm, items, fields); err != nil {
+			return nil, err
+		}
+	}
+	var warnings admission.Warnings
+	for _, f := range fm.Spec.DeprecatedFields() {
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated", f))
+	}
+	fc := flowslatest.FlowCollector{}
+	if err := v.reader.Get(ctx, types.NamespacedName{Name: "cluster"}, &fc); err != nil {
+		if kerr.IsNotFound(err) {
+			// No FlowCollector, hence no quota
+			return warnings, nil
+		}
+		return nil, fmt.Errorf("could not get FlowCollector to check the FlowMetric quota: %w", err)
+	}
+	if quota := fc.Spec.Processor.Metrics.FlowMetricsQuota; quota != nil && (quota.MaxMetrics > 0 || quota.MaxLabels > 0) {
+		items, err := listExisting()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkQuota(quota, oldFM, fm, items); err != nil {
+			return nil, err
+		}
+	}
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.DryRun != nil && *req.DryRun && v.flows != nil {
+		warnings = append(warnings, v.preview(ctx, &fc, fm)...)
+	}
+	return warnings, nil
+}
+
+// validateRemap checks that the remapped fields are labels of the metric, and that the label names don't collide, as the
+// remapped fields are copied in a pipeline stage shared by all FlowMetrics: a label must not be named after a flow field,
+// nor after a label remapped from another field, in this FlowMetric or in the other FlowMetrics of the namespace
+func validateRemap(fm *FlowMetric, existing []FlowMetric, fields []string) error {
+	var errs field.ErrorList
+	// remapped label name => field remapped to it by the other FlowMetrics
+	remapped := map[string]string{}
+	for i := range existing {
+		if existing[i].Name == fm.Name {
+			continue
+		}
+		for from, to := range existing[i].Spec.Remap {
+			remapped[to] = from
+		}
+	}
+	for _, k := range helper.KeySorted(fm.Spec.Remap) {
+		path := field.NewPath("spec", "remap").Key(k[0])
+		switch {
+		case !slices.Contains(fm.Spec.Labels, k[0]):
+			errs = append(errs, field.Invalid(path, k[1], "the remapped field must be part of spec.labels"))
+		case slices.Contains(fields, k[1]):
+			errs = append(errs, field.Invalid(path, k[1], "the label must not be named after a flow field"))
+		case remapped[k[1]] != "" && remapped[k[1]] != k[0]:
+			errs = append(errs, field.Invalid(path, k[1], fmt.Sprintf("the label is already remapped from %s, in this or another FlowMetric", remapped[k[1]])))
+		}
+		remapped[k[1]] = k[0]
+	}
+	if len(errs) > 0 {
+		return kerr.NewInvalid(GroupVersion.WithKind("FlowMetric").GroupKind(), fm.Name, errs)
+	}
+	return nil
+}
+
+// checkQuota checks that creating or updating a FlowMetric doesn't exceed the quota of its namespace, given the existing FlowMetrics.
+// Updates that don't increase the usage are always allowed, so that lowering the quota doesn't lock existing resources.
+func checkQuota(quota *flowslatest.FlowMetricsQuota, oldFM, fm *FlowMetric, existing []FlowMetric) error {
+	metrics := 1
+	labels := len(fm.Spec.Labels)
+	for i := range existing {
+		if existing[i].Name == fm.Name {
+			continue
+		}
+		metrics++
+		labels += len(existing[i].Spec.Labels)
+	}
+	if oldFM == nil && quota.MaxMetrics > 0 && metrics > int(quota.MaxMetrics) {
+		return kerr.NewForbidden(
+			GroupVersion.WithResource("flowmetrics").GroupResource(),
+			fm.Name,
+			fmt.Errorf("namespace %s would have %d FlowMetrics, exceeding the quota of %d set in FlowCollector (spec.processor.metrics.flowMetricsQuota.maxMetrics)", fm.Namespace, metrics, quota.MaxMetrics),
+		)
+	}
+	increased := oldFM == nil || len(fm.Spec.Labels) > len(oldFM.Spec.Labels)
+	if increased && quota.MaxLabels > 0 && labels > int(quota.MaxLabels) {
+		return kerr.NewForbidden(
+			GroupVersion.WithResource("flowmetrics").GroupResource(),
+			fm.Name,
+			fmt.Errorf("namespace %s would have %d labels across its FlowMetrics (%d in %s), exceeding the quota of %d set in FlowCollector (spec.processor.metrics.flowMetricsQuota.maxLabels)", fm.Namespace, labels, len(fm.Spec.Labels), fm.Name, quota.MaxLabels),
+		)
+	}
+	return nil
+}
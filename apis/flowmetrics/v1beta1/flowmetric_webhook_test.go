@@ -0,0 +1,108 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+func flowMetric(name string, labels ...string) *FlowMetric {
+	return &FlowMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+		Spec:       FlowMetricSpec{Labels: labels},
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	assert := assert.New(t)
+
+	existing := []FlowMetric{
+		*flowMetric("m1", "SrcK8S_Namespace", "DstK8S_Namespace"),
+		*flowMetric("m2", "SrcK8S_Name"),
+	}
+
+	// No limit
+	err := checkQuota(&flowslatest.FlowMetricsQuota{}, nil, flowMetric("m3", "DstK8S_Name"), existing)
+	assert.NoError(err)
+
+	// Too many metrics on create
+	quota := flowslatest.FlowMetricsQuota{MaxMetrics: 2}
+	err = checkQuota(&quota, nil, flowMetric("m3"), existing)
+	assert.True(kerr.IsForbidden(err))
+	assert.Contains(err.Error(), "namespace team-a would have 3 FlowMetrics, exceeding the quota of 2")
+
+	// Updating an existing metric doesn't count it twice
+	err = checkQuota(&quota, existing[1].DeepCopy(), flowMetric("m2", "SrcK8S_Name", "DstK8S_Name"), existing)
+	assert.NoError(err)
+
+	// Too many labels
+	quota = flowslatest.FlowMetricsQuota{MaxLabels: 4}
+	err = checkQuota(&quota, nil, flowMetric("m3", "DstK8S_Name"), existing)
+	assert.NoError(err)
+	err = checkQuota(&quota, nil, flowMetric("m3", "DstK8S_Name", "SrcK8S_HostName"), existing)
+	assert.True(kerr.IsForbidden(err))
+	assert.Contains(err.Error(), "namespace team-a would have 5 labels across its FlowMetrics (2 in m3), exceeding the quota of 4")
+
+	// Updates that don't add labels are allowed even when the quota is already exceeded
+	quota = flowslatest.FlowMetricsQuota{MaxLabels: 2}
+	err = checkQuota(&quota, existing[0].DeepCopy(), flowMetric("m1", "SrcK8S_Namespace"), existing)
+	assert.NoError(err)
+	err = checkQuota(&quota, existing[1].DeepCopy(), flowMetric("m2", "SrcK8S_Name", "DstK8S_Name"), existing)
+	assert.True(kerr.IsForbidden(err))
+}
+
+func TestValidateRemap(t *testing.T) {
+	assert := assert.New(t)
+
+	fields := []string{"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_Name"}
+	fm := flowMetric("m1", "SrcK8S_Namespace", "DstK8S_Namespace")
+	fm.Spec.Remap = map[string]string{"SrcK8S_Namespace": "from", "DstK8S_Namespace": "to"}
+	assert.NoError(validateRemap(fm, nil, fields))
+	assert.Equal("from", fm.Spec.LabelName("SrcK8S_Namespace"))
+	assert.Equal("SrcK8S_Name", fm.Spec.LabelName("SrcK8S_Name"))
+
+	fm.Spec.Remap["SrcK8S_Name"] = "pod"
+	err := validateRemap(fm, nil, fields)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), `spec.remap[SrcK8S_Name]: Invalid value: "pod": the remapped field must be part of spec.labels`)
+
+	// labels named after a flow field, or after another remapped field
+	fm.Spec.Remap = map[string]string{"SrcK8S_Namespace": "SrcK8S_Name", "DstK8S_Namespace": "to"}
+	err = validateRemap(fm, nil, fields)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), `spec.remap[SrcK8S_Namespace]: Invalid value: "SrcK8S_Name": the label must not be named after a flow field`)
+	fm.Spec.Remap = map[string]string{"SrcK8S_Namespace": "namespace", "DstK8S_Namespace": "namespace"}
+	err = validateRemap(fm, nil, fields)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), `spec.remap[SrcK8S_Namespace]: Invalid value: "namespace": the label is already remapped from DstK8S_Namespace`)
+}
+
+func TestValidateRemapAcrossFlowMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	fields := []string{"SrcK8S_Namespace", "DstK8S_Namespace"}
+	m1 := flowMetric("m1", "SrcK8S_Namespace")
+	m1.Spec.Remap = map[string]string{"SrcK8S_Namespace": "namespace"}
+	existing := []FlowMetric{*m1}
+
+	// the same field can be remapped to the same label
+	m2 := flowMetric("m2", "SrcK8S_Namespace")
+	m2.Spec.Remap = map[string]string{"SrcK8S_Namespace": "namespace"}
+	assert.NoError(validateRemap(m2, existing, fields))
+
+	// but another field can't, as the shared remap stage would overwrite the label of m1
+	m2 = flowMetric("m2", "DstK8S_Namespace")
+	m2.Spec.Remap = map[string]string{"DstK8S_Namespace": "namespace"}
+	err := validateRemap(m2, existing, fields)
+	assert.True(kerr.IsInvalid(err))
+	assert.Contains(err.Error(), `spec.remap[DstK8S_Namespace]: Invalid value: "namespace": the label is already remapped from SrcK8S_Namespace`)
+
+	// updating m1 itself is not a collision
+	m1.Spec.Labels = []string{"DstK8S_Namespace"}
+	m1.Spec.Remap = map[string]string{"DstK8S_Namespace": "namespace"}
+	assert.NoError(validateRemap(m1, existing, fields))
+}
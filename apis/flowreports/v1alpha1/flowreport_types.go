@@ -0,0 +1,218 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type FlowReportAggregation string
+
+const (
+	AggregationTopTalkers      FlowReportAggregation = "TopTalkers"
+	AggregationNamespaceEgress FlowReportAggregation = "NamespaceEgress"
+)
+
+type FlowReportOutputType string
+
+const (
+	OutputConfigMap FlowReportOutputType = "ConfigMap"
+	OutputS3        FlowReportOutputType = "S3"
+	OutputEmail     FlowReportOutputType = "Email"
+)
+
+// FlowReportSpec defines the desired state of FlowReport
+// It describes a traffic report that the operator periodically generates in a `CronJob`, from the metrics of NetObserv stored in Prometheus,
+// for audiences who don't use the Console plugin.
+// Only the `FlowReport` resources created in the namespace of NetObserv (such as `netobserv`) are taken into account.
+type FlowReportSpec struct {
+	// `schedule` of the report, in Cron format, such as `0 6 * * 1` to run it every Monday at 6:00.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Schedule string `json:"schedule"`
+
+	// Set `suspend` to `true` to stop generating the report, without deleting it.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// `aggregation` defines the content of the report:<br>
+	// - `TopTalkers` (default) lists the pairs of workloads that exchanged the most bytes.<br>
+	// - `NamespaceEgress` lists the bytes sent by each namespace.<br>
+	// Both are computed from the `workload_ingress_bytes_total` metric, which must be listed in `spec.processor.metrics.includeList`
+	// of the `FlowCollector`, as it is by default.
+	// +kubebuilder:validation:Enum:="TopTalkers";"NamespaceEgress"
+	// +kubebuilder:default:="TopTalkers"
+	// +optional
+	Aggregation FlowReportAggregation `json:"aggregation,omitempty"`
+
+	// `filters` restricts the traffic covered by the report. Keys are labels of the metric, such as `SrcK8S_Namespace` or `DstK8S_OwnerName`,
+	// and values are regular expressions, for example, `filters: {"DstK8S_Namespace": "openshift-.*"}`.
+	// +optional
+	Filters map[string]string `json:"filters,omitempty"`
+
+	// `timeRange` is the period of time covered by the report, ending when the report is generated.
+	// +kubebuilder:default:="24h"
+	// +optional
+	TimeRange *metav1.Duration `json:"timeRange,omitempty"`
+
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:validation:Maximum=1000
+	// `limit` is the maximum number of rows in the report.
+	//+kubebuilder:default:=10
+	// +optional
+	Limit int32 `json:"limit,omitempty"`
+
+	// `prometheus` defines the Prometheus API queried to generate the report.
+	// +optional
+	Prometheus FlowReportPrometheus `json:"prometheus,omitempty"`
+
+	// `output` defines where the report is sent.
+	// +required
+	Output FlowReportOutput `json:"output"`
+}
+
+// `FlowReportPrometheus` defines the Prometheus API queried to generate the report
+type FlowReportPrometheus struct {
+	// `url` of the Prometheus API. The report job authenticates with its service account token.
+	//+kubebuilder:default:="https://thanos-querier.openshift-monitoring.svc:9091/"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// `insecureSkipVerify` allows skipping the verification of the Prometheus server certificate.
+	// Otherwise, it is verified with the system certificates and the service CA of the cluster.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// `FlowReportOutput` defines where the report is sent
+type FlowReportOutput struct {
+	// `type` of output:<br>
+	// - `ConfigMap` (default) keeps the last report in a `ConfigMap`, as CSV and text.<br>
+	// - `S3` uploads each report as a CSV file to an S3 bucket.<br>
+	// - `Email` sends each report as a text table by email.<br>
+	// +kubebuilder:validation:Enum:="ConfigMap";"S3";"Email"
+	// +kubebuilder:default:="ConfigMap"
+	// +optional
+	Type FlowReportOutputType `json:"type,omitempty"`
+
+	// `configMap` configuration, when `type` is `ConfigMap`.
+	// +optional
+	ConfigMap FlowReportConfigMap `json:"configMap,omitempty"`
+
+	// `s3` configuration, required when `type` is `S3`.
+	// +optional
+	S3 *FlowReportS3 `json:"s3,omitempty"`
+
+	// `email` configuration, required when `type` is `Email`.
+	// +optional
+	Email *FlowReportEmail `json:"email,omitempty"`
+}
+
+// `FlowReportConfigMap` defines the `ConfigMap` holding the last report
+type FlowReportConfigMap struct {
+	// `name` of the `ConfigMap`, in the namespace of the `FlowReport`. When empty, the `FlowReport` name is used.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// `FlowReportS3` defines the S3 bucket where reports are uploaded
+type FlowReportS3 struct {
+	// `endpoint` of the S3 API, such as `https://s3.us-east-1.amazonaws.com`. Objects are addressed in path style.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Endpoint string `json:"endpoint"`
+
+	// `bucket` where reports are uploaded.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	Bucket string `json:"bucket"`
+
+	// `region` of the bucket, used to sign the requests.
+	//+kubebuilder:default:="us-east-1"
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// `prefix` of the uploaded objects. Each report is uploaded as `<prefix><FlowReport name>-<timestamp>.csv`.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// `credentialsSecret` is the name of a `Secret`, in the namespace of the `FlowReport`, holding the
+	// `AWS_ACCESS_KEY_ID` and `AWS_SECRET_ACCESS_KEY` keys.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// `FlowReportEmail` defines how reports are sent by email
+type FlowReportEmail struct {
+	// `smtpAddress` of the mail server, as `host:port`.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	SMTPAddress string `json:"smtpAddress"`
+
+	// `from` is the sender address.
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	From string `json:"from"`
+
+	// `to` is the list of recipient addresses.
+	// +kubebuilder:validation:MinItems:=1
+	// +required
+	To []string `json:"to"`
+
+	// `credentialsSecret` is the name of a `Secret`, in the namespace of the `FlowReport`, holding the `username` and `password` keys
+	// used to authenticate to the mail server. When empty, no authentication is used.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// FlowReportStatus defines the observed state of FlowReport
+type FlowReportStatus struct {
+	// `conditions` represent the latest available observations of the report `CronJob`
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionReady is true when the CronJob generating the report is up-to-date
+	ConditionReady = "Ready"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// FlowReport is the Schema for the flowreports API
+type FlowReport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FlowReportSpec   `json:"spec,omitempty"`
+	Status FlowReportStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FlowReportList contains a list of FlowReport
+type FlowReportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FlowReport `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FlowReport{}, &FlowReportList{})
+}
@@ -1,10 +1,17 @@
 package config
 
 import (
+	_ "embed"
+
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 )
 
+// StaticFrontendConfig is the static part of the frontend configuration, such as the columns and the flow fields
+//
+//go:embed static-frontend-config.yaml
+var StaticFrontendConfig []byte
+
 type ServerConfig struct {
 	Port        int    `yaml:"port,omitempty" json:"port,omitempty"`
 	MetricsPort int    `yaml:"metricsPort,omitempty" json:"metricsPort,omitempty"`
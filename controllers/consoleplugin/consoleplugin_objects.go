@@ -1,7 +1,6 @@
 package consoleplugin
 
 import (
-	_ "embed"
 	"fmt"
 	"hash/fnv"
 	"path/filepath"
@@ -14,6 +13,7 @@ import (
 	ascv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -23,10 +23,13 @@ import (
 	"github.com/netobserv/network-observability-operator/controllers/ebpf"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
 	"github.com/netobserv/network-observability-operator/pkg/loki"
+	"github.com/netobserv/network-observability-operator/pkg/overrides"
 	"github.com/netobserv/network-observability-operator/pkg/volumes"
+	"github.com/netobserv/network-observability-operator/pkg/watchers"
 )
 
 const secretName = "console-serving-cert"
+const servingCertVolume = "serving-cert"
 const displayName = "NetObserv plugin"
 const proxyAlias = "backend"
 
@@ -37,6 +40,16 @@ const configPath = "/opt/app-root/"
 const metricsSvcName = constants.PluginName + "-metrics"
 const metricsPort = 9002
 const metricsPortName = "metrics"
+const probeTimeoutSeconds = 5
+const probePeriodSeconds = 10
+const probeFailureThreshold = 3
+
+// startupFailureThreshold leaves 5 minutes to the plugin to start, as mounting certificates can be slow
+const startupFailureThreshold = 30
+
+// Series recorded per plugin pod, to scale the plugin on its usage
+const requestRateRecord = "netobserv:plugin_http_requests:rate1m"
+const requestLatencyRecord = "netobserv:plugin_http_request_duration_seconds:avg1m"
 
 type builder struct {
 	namespace string
@@ -47,13 +60,32 @@ type builder struct {
 	imageName string
 	volumes   volumes.Builder
 	loki      *helper.LokiConfig
+	proxy     *helper.ProxyConfig
+
+	// name of the deployment, and of its service, service account and RBAC
+	name           string
+	configMapName  string
+	certSecretName string
+	// queryService is set when building the query service rather than the console plugin
+	queryService bool
+	// trustedCADigest is set when the cluster trusted CA bundle is mounted, to restart pods on changes
+	trustedCADigest string
+	// lokiTokenDigest is set when the Loki token is read from a secret, to restart pods on rotation
+	lokiTokenDigest string
+	// servingCertDigest is set when a custom serving certificate is used, to restart pods on rotation
+	servingCertDigest string
+	// overrides are the break-glass overrides of the generated configuration
+	overrides map[string]string
 }
 
-func newBuilder(ns, imageName string, desired *flowslatest.FlowCollectorSpec, loki *helper.LokiConfig) builder {
+func newBuilder(ns, imageName string, desired *flowslatest.FlowCollectorSpec, loki *helper.LokiConfig, proxy *helper.ProxyConfig) builder {
 	version := helper.ExtractVersion(imageName)
 	advanced := helper.GetAdvancedPluginConfig(desired.ConsolePlugin.Advanced)
 	return builder{
-		namespace: ns,
+		name:           constants.PluginName,
+		configMapName:  configMapName,
+		certSecretName: secretName,
+		namespace:      ns,
 		labels: map[string]string{
 			"app":     constants.PluginName,
 			"version": helper.MaxLabelLength(version),
@@ -65,6 +97,7 @@ func newBuilder(ns, imageName string, desired *flowslatest.FlowCollectorSpec, lo
 		advanced:  &advanced,
 		imageName: imageName,
 		loki:      loki,
+		proxy:     proxy,
 	}
 }
 
@@ -95,9 +128,17 @@ func (b *builder) consolePlugin() *osv1alpha1.ConsolePlugin {
 	}
 }
 
+// servingCertPaths returns the paths of the certificate and key served by the plugin
+func (b *builder) servingCertPaths() (certPath, keyPath string) {
+	if b.advanced.ServingCert != nil {
+		return b.volumes.AddCertificate(b.advanced.ServingCert, servingCertVolume)
+	}
+	return "/var/serving-cert/tls.crt", "/var/serving-cert/tls.key"
+}
+
 func (b *builder) serviceMonitor() *monitoringv1.ServiceMonitor {
 	serverName := fmt.Sprintf("%s.%s.svc", constants.PluginName, b.namespace)
-	return &monitoringv1.ServiceMonitor{
+	sm := monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      constants.PluginName,
 			Namespace: b.namespace,
@@ -149,12 +190,22 @@ func (b *builder) serviceMonitor() *monitoringv1.ServiceMonitor {
 			},
 		},
 	}
+	if b.advanced.ServingCert != nil {
+		// the CA of a custom serving certificate isn't known, and the service CA certificates aren't generated
+		sm.Spec.Endpoints[0].TLSConfig = &monitoringv1.TLSConfig{
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{
+				ServerName:         serverName,
+				InsecureSkipVerify: true,
+			},
+		}
+	}
+	return &sm
 }
 
 func (b *builder) deployment(cmDigest string) *appsv1.Deployment {
-	return &appsv1.Deployment{
+	dep := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      constants.PluginName,
+			Name:      b.name,
 			Namespace: b.namespace,
 			Labels:    b.labels,
 		},
@@ -166,23 +217,20 @@ func (b *builder) deployment(cmDigest string) *appsv1.Deployment {
 			Template: *b.podTemplate(cmDigest),
 		},
 	}
+	if b.advanced.DeploymentStrategy != nil {
+		dep.Spec.Strategy = *b.advanced.DeploymentStrategy
+	}
+	return &dep
 }
 
 func (b *builder) podTemplate(cmDigest string) *corev1.PodTemplateSpec {
 	volumes := []corev1.Volume{
 		{
-			Name: secretName,
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: secretName,
-				},
-			},
-		}, {
 			Name: configVolume,
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: configMapName,
+						Name: b.configMapName,
 					},
 				},
 			},
@@ -191,15 +239,33 @@ func (b *builder) podTemplate(cmDigest string) *corev1.PodTemplateSpec {
 
 	volumeMounts := []corev1.VolumeMount{
 		{
-			Name:      secretName,
-			MountPath: "/var/serving-cert",
-			ReadOnly:  true,
-		}, {
 			Name:      configVolume,
 			MountPath: configPath,
 			ReadOnly:  true,
 		},
 	}
+	annotations := map[string]string{
+		constants.PodConfigurationDigest: cmDigest,
+	}
+
+	if b.advanced.ServingCert != nil {
+		b.servingCertPaths()
+		annotations[watchers.Annotation("serving-cert")] = b.servingCertDigest
+	} else {
+		volumes = append([]corev1.Volume{{
+			Name: b.certSecretName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: b.certSecretName,
+				},
+			},
+		}}, volumes...)
+		volumeMounts = append([]corev1.VolumeMount{{
+			Name:      b.certSecretName,
+			MountPath: "/var/serving-cert",
+			ReadOnly:  true,
+		}}, volumeMounts...)
+	}
 
 	// ensure volumes are up to date
 	if b.loki.TLS.Enable && !b.loki.TLS.InsecureSkipVerify {
@@ -209,33 +275,46 @@ func (b *builder) podTemplate(cmDigest string) *corev1.PodTemplateSpec {
 		b.volumes.AddMutualTLSCertificates(&b.loki.StatusTLS, "loki-status-certs")
 	}
 	if b.loki.UseHostToken() {
-		b.volumes.AddToken(constants.PluginName)
+		b.volumes.AddToken(b.name)
+	}
+	if b.loki.UseSecretToken() {
+		b.volumes.AddVolume(b.loki.AuthTokenSecret, "loki-token")
+		annotations[watchers.Annotation("loki-token")] = b.lokiTokenDigest
+	}
+	if b.proxy.UseTrustedCABundle() {
+		b.volumes.AddTrustedCABundle()
+		annotations[watchers.Annotation("trusted-ca")] = b.trustedCADigest
 	}
 
+	probes := helper.GetProbesConfig(b.advanced.Probes)
+	startupProbe := b.probe()
+	startupProbe.FailureThreshold = startupFailureThreshold
+
 	return &corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels: b.labels,
-			Annotations: map[string]string{
-				constants.PodConfigurationDigest: cmDigest,
-			},
+			Labels:      b.labels,
+			Annotations: annotations,
 		},
 		Spec: corev1.PodSpec{
 			Containers: []corev1.Container{{
-				Name:            constants.PluginName,
+				Name:            b.name,
 				Image:           b.imageName,
 				ImagePullPolicy: corev1.PullPolicy(b.desired.ConsolePlugin.ImagePullPolicy),
 				Resources:       *b.desired.ConsolePlugin.Resources.DeepCopy(),
 				VolumeMounts:    b.volumes.AppendMounts(volumeMounts),
-				Env:             []corev1.EnvVar{constants.EnvNoHTTP2},
+				Env:             append([]corev1.EnvVar{constants.EnvNoHTTP2}, b.proxy.EnvVars()...),
 				Args: []string{
 
 					"-loglevel", b.desired.ConsolePlugin.LogLevel,
 					"-config", filepath.Join(configPath, configFile),
 				},
 				SecurityContext: helper.ContainerDefaultSecurityContext(),
+				LivenessProbe:   helper.WithProbeConfig(b.probe(), probes.Liveness),
+				ReadinessProbe:  helper.WithProbeConfig(b.probe(), probes.Readiness),
+				StartupProbe:    helper.WithProbeConfig(startupProbe, probes.Startup),
 			}},
 			Volumes:            b.volumes.AppendVolumes(volumes),
-			ServiceAccountName: constants.PluginName,
+			ServiceAccountName: b.name,
 			NodeSelector:       b.advanced.Scheduling.NodeSelector,
 			Tolerations:        b.advanced.Scheduling.Tolerations,
 			Affinity:           b.advanced.Scheduling.Affinity,
@@ -244,7 +323,80 @@ func (b *builder) podTemplate(cmDigest string) *corev1.PodTemplateSpec {
 	}
 }
 
+// probe checks that the plugin accepts connections, as its HTTPS server starts once the configuration and certificates are loaded
+func (b *builder) probe() *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt32(*b.advanced.Port),
+			},
+		},
+		TimeoutSeconds:   probeTimeoutSeconds,
+		PeriodSeconds:    probePeriodSeconds,
+		FailureThreshold: probeFailureThreshold,
+	}
+}
+
+// autoscalerSpec returns the autoscaler settings, with the usage targets added to the metrics
+func (b *builder) autoscalerSpec() *flowslatest.FlowCollectorHPA {
+	hpa := b.desired.ConsolePlugin.Autoscaler.DeepCopy()
+	usage := b.desired.ConsolePlugin.AutoscalerUsage
+	if usage == nil {
+		return hpa
+	}
+	if usage.RequestsPerSecond != nil {
+		hpa.Metrics = append(hpa.Metrics, podsMetric(requestRateRecord, *usage.RequestsPerSecond))
+	}
+	if usage.Latency != nil {
+		hpa.Metrics = append(hpa.Metrics, podsMetric(requestLatencyRecord, *resource.NewMilliQuantity(usage.Latency.Milliseconds(), resource.DecimalSI)))
+	}
+	return hpa
+}
+
+func podsMetric(name string, target resource.Quantity) ascv2.MetricSpec {
+	return ascv2.MetricSpec{
+		Type: ascv2.PodsMetricSourceType,
+		Pods: &ascv2.PodsMetricSource{
+			Metric: ascv2.MetricIdentifier{Name: name},
+			Target: ascv2.MetricTarget{
+				Type:         ascv2.AverageValueMetricType,
+				AverageValue: &target,
+			},
+		},
+	}
+}
+
+// prometheusRule records the plugin usage per pod, for autoscaling. It returns nil when the usage isn't used.
+func (b *builder) prometheusRule() *monitoringv1.PrometheusRule {
+	if b.desired.ConsolePlugin.AutoscalerUsage == nil {
+		return nil
+	}
+	series := func(suffix string) string {
+		return fmt.Sprintf(`sum by (namespace, pod) (rate(netobserv_plugin_http_request_duration_seconds_%s{namespace="%s"}[1m]))`, suffix, b.namespace)
+	}
+	return &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.PluginName,
+			Namespace: b.namespace,
+			Labels:    b.labels,
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{{
+				Name: "NetobservConsolePlugin",
+				Rules: []monitoringv1.Rule{{
+					Record: requestRateRecord,
+					Expr:   intstr.FromString(series("count")),
+				}, {
+					Record: requestLatencyRecord,
+					Expr:   intstr.FromString(series("sum") + " / " + series("count")),
+				}},
+			}},
+		},
+	}
+}
+
 func (b *builder) autoScaler() *ascv2.HorizontalPodAutoscaler {
+	hpa := b.autoscalerSpec()
 	return &ascv2.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      constants.PluginName,
@@ -257,22 +409,28 @@ func (b *builder) autoScaler() *ascv2.HorizontalPodAutoscaler {
 				Kind:       "Deployment",
 				Name:       constants.PluginName,
 			},
-			MinReplicas: b.desired.ConsolePlugin.Autoscaler.MinReplicas,
-			MaxReplicas: b.desired.ConsolePlugin.Autoscaler.MaxReplicas,
-			Metrics:     b.desired.ConsolePlugin.Autoscaler.Metrics,
+			MinReplicas: hpa.MinReplicas,
+			MaxReplicas: hpa.MaxReplicas,
+			Metrics:     hpa.Metrics,
+			Behavior:    hpa.Behavior,
 		},
 	}
 }
 
 func (b *builder) mainService() *corev1.Service {
+	annotations := map[string]string{}
+	for k, v := range b.advanced.ServiceAnnotations {
+		annotations[k] = v
+	}
+	if b.advanced.ServingCert == nil {
+		annotations[constants.OpenShiftCertificateAnnotation] = b.certSecretName
+	}
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      constants.PluginName,
-			Namespace: b.namespace,
-			Labels:    b.labels,
-			Annotations: map[string]string{
-				constants.OpenShiftCertificateAnnotation: "console-serving-cert",
-			},
+			Name:        b.name,
+			Namespace:   b.namespace,
+			Labels:      b.labels,
+			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: b.selector,
@@ -310,6 +468,29 @@ func (b *builder) metricsService() *corev1.Service {
 	}
 }
 
+// authCheck returns the plugin authorization check matching the configured access mode
+func (b *builder) authCheck() string {
+	switch b.desired.ConsolePlugin.AccessMode {
+	case flowslatest.ConsolePluginAccessAdmin:
+		return "admin"
+	case flowslatest.ConsolePluginAccessNamespaceRestricted:
+		// Without the user token, Loki can't filter flows per namespace: only allow admins
+		if b.loki.UseForwardToken() {
+			return "auth"
+		}
+		return "admin"
+	}
+	if b.queryService {
+		// "auto" leaves the authentication to the console proxy: outside the console, tokens must be checked. Without the user token,
+		// Loki is queried with the service credentials and can't filter flows per namespace: only allow admins
+		if b.loki.UseForwardToken() {
+			return "auth"
+		}
+		return "admin"
+	}
+	return "auto"
+}
+
 func (b *builder) setLokiConfig(lconf *config.LokiConfig) {
 	lconf.URL = b.loki.QuerierURL
 	statusURL := b.loki.StatusURL
@@ -324,6 +505,7 @@ func (b *builder) setLokiConfig(lconf *config.LokiConfig) {
 	}
 	lconf.TenantID = b.loki.TenantID
 	lconf.ForwardUserToken = b.loki.UseForwardToken()
+	lconf.AuthCheck = b.authCheck()
 	if b.loki.TLS.Enable {
 		if b.loki.TLS.InsecureSkipVerify {
 			lconf.SkipTLS = true
@@ -349,7 +531,9 @@ func (b *builder) setLokiConfig(lconf *config.LokiConfig) {
 		}
 	}
 	if b.loki.UseHostToken() {
-		lconf.TokenPath = b.volumes.AddToken(constants.PluginName)
+		lconf.TokenPath = b.volumes.AddToken(b.name)
+	} else if b.loki.UseSecretToken() {
+		lconf.TokenPath = b.volumes.AddVolume(b.loki.AuthTokenSecret, "loki-token")
 	}
 }
 
@@ -375,6 +559,10 @@ func (b *builder) setFrontendConfig(fconf *config.FrontendConfig) error {
 		fconf.Features = append(fconf.Features, "flowRTT")
 	}
 
+	if helper.IsOVNObservabilityEnabled(&b.desired.Agent) {
+		fconf.Features = append(fconf.Features, "networkEvents")
+	}
+
 	if b.desired.Agent.EBPF.Advanced != nil {
 		if v, ok := b.desired.Agent.EBPF.Advanced.Env[ebpf.EnvDedupeJustMark]; ok {
 			dedupJustMark, err = strconv.ParseBool(v)
@@ -390,7 +578,7 @@ func (b *builder) setFrontendConfig(fconf *config.FrontendConfig) error {
 			}
 		}
 	}
-	fconf.RecordTypes = helper.GetRecordTypes(&b.desired.Processor)
+	fconf.RecordTypes = helper.GetLokiRecordTypes(b.desired)
 	fconf.PortNaming = b.desired.ConsolePlugin.PortNaming
 	fconf.QuickFilters = b.desired.ConsolePlugin.QuickFilters
 	fconf.AlertNamespaces = []string{b.namespace}
@@ -408,20 +596,31 @@ func (b *builder) setFrontendConfig(fconf *config.FrontendConfig) error {
 	if helper.IsSubnetLabelsEnabled(&b.desired.Processor) {
 		fconf.Features = append(fconf.Features, "subnetLabels")
 	}
+	if helper.IsVirtualizationEnabled(&b.desired.Processor) {
+		fconf.Features = append(fconf.Features, "virtualization")
+	}
+	if helper.IsDSCPClassificationEnabled(&b.desired.Processor) {
+		fconf.Features = append(fconf.Features, "dscpClassification")
+	}
+	if helper.IsEgressClassificationEnabled(&b.desired.Processor) {
+		fconf.Features = append(fconf.Features, "egressClassification")
+	}
+	if helper.IsDeveloperPerspectiveEnabled(&b.desired.ConsolePlugin) {
+		fconf.Features = append(fconf.Features, "developerPerspective")
+	}
 	return nil
 }
 
-//go:embed config/static-frontend-config.yaml
-var staticFrontendConfig []byte
+var staticFrontendConfig = config.StaticFrontendConfig
 
 // returns a configmap with a digest of its configuration contents, which will be used to
 // detect any configuration change
 func (b *builder) configMap() (*corev1.ConfigMap, string, error) {
 	config := config.PluginConfig{}
 	// configure server
-	config.Server.CertPath = "/var/serving-cert/tls.crt"
-	config.Server.KeyPath = "/var/serving-cert/tls.key"
+	config.Server.CertPath, config.Server.KeyPath = b.servingCertPaths()
 	config.Server.Port = int(*b.advanced.Port)
+	config.Server.MetricsPort = metricsPort
 
 	// configure loki
 	b.setLokiConfig(&config.Loki)
@@ -438,15 +637,19 @@ func (b *builder) configMap() (*corev1.ConfigMap, string, error) {
 
 	var configStr string
 	bs, err := yaml.Marshal(config)
-	if err == nil {
-		configStr = string(bs)
-	} else {
+	if err != nil {
+		return nil, "", err
+	}
+	// break-glass overrides, reported in status
+	bs, err = overrides.ApplyYAML(bs, b.overrides)
+	if err != nil {
 		return nil, "", err
 	}
+	configStr = string(bs)
 
 	configMap := corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      configMapName,
+			Name:      b.configMapName,
 			Namespace: b.namespace,
 			Labels:    b.labels,
 		},
@@ -466,10 +669,10 @@ func (b *builder) configMap() (*corev1.ConfigMap, string, error) {
 func (b *builder) serviceAccount() *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      constants.PluginName,
+			Name:      b.name,
 			Namespace: b.namespace,
 			Labels: map[string]string{
-				"app": constants.PluginName,
+				"app": b.name,
 			},
 		},
 	}
@@ -477,9 +680,10 @@ func (b *builder) serviceAccount() *corev1.ServiceAccount {
 
 // The operator needs to have at least the same permissions as flowlogs-pipeline in order to grant them
 //+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
-func buildClusterRole() *rbacv1.ClusterRole {
-	return &rbacv1.ClusterRole{
+func buildClusterRole(desired *pluginSpec) *rbacv1.ClusterRole {
+	cr := rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: constants.PluginName,
 		},
@@ -489,24 +693,33 @@ func buildClusterRole() *rbacv1.ClusterRole {
 			Resources: []string{"tokenreviews"},
 		}},
 	}
+	if helper.IsDeveloperPerspectiveEnabled(desired) {
+		// Checks whether developers have access to the selected project
+		cr.Rules = append(cr.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{"authorization.k8s.io"},
+			Verbs:     []string{"create"},
+			Resources: []string{"subjectaccessreviews"},
+		})
+	}
+	return &cr
 }
 
 func (b *builder) clusterRoleBinding() *rbacv1.ClusterRoleBinding {
 	return &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: constants.PluginName,
+			Name: b.name,
 			Labels: map[string]string{
-				"app": constants.PluginName,
+				"app": b.name,
 			},
 		},
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: "rbac.authorization.k8s.io",
 			Kind:     "ClusterRole",
-			Name:     constants.PluginName,
+			Name:     b.name,
 		},
 		Subjects: []rbacv1.Subject{{
 			Kind:      "ServiceAccount",
-			Name:      constants.PluginName,
+			Name:      b.name,
 			Namespace: b.namespace,
 		}},
 	}
@@ -2,7 +2,9 @@ package consoleplugin
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 
 	osv1alpha1 "github.com/openshift/api/console/v1alpha1"
 	operatorsv1 "github.com/openshift/api/operator/v1"
@@ -10,6 +12,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	ascv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -18,6 +21,8 @@ import (
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/loki"
+	"github.com/netobserv/network-observability-operator/pkg/overrides"
 )
 
 // Type alias
@@ -33,6 +38,7 @@ type CPReconciler struct {
 	serviceAccount *corev1.ServiceAccount
 	configMap      *corev1.ConfigMap
 	serviceMonitor *monitoringv1.ServiceMonitor
+	prometheusRule *monitoringv1.PrometheusRule
 }
 
 func NewReconciler(cmn *reconcilers.Instance) CPReconciler {
@@ -48,6 +54,9 @@ func NewReconciler(cmn *reconcilers.Instance) CPReconciler {
 	if cmn.AvailableAPIs.HasSvcMonitor() {
 		rec.serviceMonitor = cmn.Managed.NewServiceMonitor(constants.PluginName)
 	}
+	if cmn.AvailableAPIs.HasPromRule() {
+		rec.prometheusRule = cmn.Managed.NewPrometheusRule(constants.PluginName)
+	}
 	return rec
 }
 
@@ -65,49 +74,95 @@ func (r *CPReconciler) Reconcile(ctx context.Context, desired *flowslatest.FlowC
 	// Retrieve current owned objects
 	err := r.Managed.FetchAll(ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("fetching current console plugin objects: %w", err)
 	}
 
 	if err = r.checkAutoPatch(ctx, desired); err != nil {
-		return err
+		return fmt.Errorf("registering the console plugin: %w", err)
 	}
 
 	if helper.UseConsolePlugin(&desired.Spec) {
 		// Create object builder
-		builder := newBuilder(ns, r.Instance.Image, &desired.Spec, r.Loki)
+		builder := newBuilder(ns, r.Instance.Image, &desired.Spec, r.Loki, r.Proxy)
+
+		// Break-glass overrides of the generated configuration, reported in status
+		var overridesWarning string
+		if name := builder.advanced.OverridesConfigMap; name != "" {
+			ovr, err := overrides.Read(ctx, r.Client, r.Watcher, name, ns)
+			if err != nil {
+				l.Error(err, "overrides not applied")
+				overridesWarning = err.Error()
+			} else if len(ovr) > 0 {
+				l.Info("Overriding the console plugin configuration", "configMap", name, "keys", len(ovr))
+				builder.overrides = ovr
+				overridesWarning = overrides.Warning(name, ovr)
+			}
+		}
 
 		if err := r.reconcilePermissions(ctx, &builder); err != nil {
-			return err
+			return fmt.Errorf("reconciling permissions: %w", err)
 		}
 
-		if err = r.reconcilePlugin(ctx, &builder, &desired.Spec); err != nil {
-			return err
+		if r.Namespaced {
+			// the ConsolePlugin is cluster-scoped: it must be created by an administrator
+			l.Info("The operator only watches some namespaces: ConsolePlugin not reconciled", "name", constants.PluginName)
+		} else if err = r.reconcilePlugin(ctx, &builder, &desired.Spec); err != nil {
+			return fmt.Errorf("reconciling ConsolePlugin: %w", err)
 		}
 
 		cmDigest, err := r.reconcileConfigMap(ctx, &builder)
 		if err != nil {
-			return err
+			return fmt.Errorf("reconciling configuration: %w", err)
+		}
+
+		// Watch for trusted CA bundle injection; need to restart pods as the system bundle is only read at startup
+		if r.Proxy.UseTrustedCABundle() {
+			if builder.trustedCADigest, err = r.ReconcileTrustedCABundle(ctx); err != nil || builder.trustedCADigest == "" {
+				// not deployed until the bundle is injected
+				return err
+			}
+		}
+
+		// Watch for Loki credentials; need to restart pods on token rotation
+		if builder.lokiTokenDigest, err = r.ReconcileLokiCredentials(ctx); err != nil {
+			return fmt.Errorf("reading Loki credentials: %w", err)
+		}
+
+		// Watch for the custom serving certificate; need to restart pods on rotation
+		if builder.advanced.ServingCert != nil {
+			if builder.servingCertDigest, err = r.Watcher.ProcessCertRef(ctx, r.Client, builder.advanced.ServingCert, r.Namespace); err != nil {
+				return fmt.Errorf("reading serving certificate: %w", err)
+			}
 		}
 
 		if err = r.reconcileDeployment(ctx, &builder, &desired.Spec, cmDigest); err != nil {
-			return err
+			return fmt.Errorf("reconciling deployment: %w", err)
 		}
 
 		if err = r.reconcileServices(ctx, &builder); err != nil {
-			return err
+			return fmt.Errorf("reconciling services: %w", err)
 		}
 
-		if err = r.reconcileHPA(ctx, &builder, &desired.Spec); err != nil {
-			return err
+		if err = r.reconcileHPA(ctx, &builder); err != nil {
+			return fmt.Errorf("reconciling autoscaler: %w", err)
+		}
+
+		warnings := r.checkQueryLimits(ctx, &desired.Spec)
+		if overridesWarning != "" {
+			r.Status.SetWarning(overrides.ReasonConfigOverridden, strings.Join(append([]string{overridesWarning}, warnings...), "; "))
+		} else if len(warnings) > 0 {
+			r.Status.SetWarning("LokiQueryLimitsExceeded", strings.Join(warnings, "; "))
+		} else {
+			r.Status.ClearWarning()
 		}
 
 		// Watch for Loki certificates if necessary; we'll ignore in that case the returned digest, as we don't need to restart pods on cert rotation
 		// because certificate is always reloaded from file
 		if _, err = r.Watcher.ProcessCACert(ctx, r.Client, &r.Loki.TLS, r.Namespace); err != nil {
-			return err
+			return fmt.Errorf("reading Loki certificates: %w", err)
 		}
 		if _, _, err = r.Watcher.ProcessMTLSCerts(ctx, r.Client, &r.Loki.StatusTLS, r.Namespace); err != nil {
-			return err
+			return fmt.Errorf("reading Loki status certificates: %w", err)
 		}
 	} else {
 		// delete any existing owned object
@@ -118,6 +173,10 @@ func (r *CPReconciler) Reconcile(ctx context.Context, desired *flowslatest.FlowC
 }
 
 func (r *CPReconciler) checkAutoPatch(ctx context.Context, desired *flowslatest.FlowCollector) error {
+	if r.Namespaced {
+		// the Console operator configuration is cluster-scoped: the plugin must be registered by an administrator
+		return nil
+	}
 	console := operatorsv1.Console{}
 	advancedConfig := helper.GetAdvancedPluginConfig(desired.Spec.ConsolePlugin.Advanced)
 	reg := helper.UseConsolePlugin(&desired.Spec) && *advancedConfig.Register
@@ -136,12 +195,25 @@ func (r *CPReconciler) checkAutoPatch(ctx context.Context, desired *flowslatest.
 	return nil
 }
 
+// checkQueryLimits returns warnings when the plugin query limits exceed the limits of the LokiStack, which would make Loki reject queries
+func (r *CPReconciler) checkQueryLimits(ctx context.Context, desired *flowslatest.FlowCollectorSpec) []string {
+	if !r.AvailableAPIs.HasLokiStack() {
+		return nil
+	}
+	warnings, err := loki.CheckQueryLimits(ctx, r.Client, desired)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "can't check Loki query limits")
+		return nil
+	}
+	return warnings
+}
+
 func (r *CPReconciler) reconcilePermissions(ctx context.Context, builder *builder) error {
 	if !r.Managed.Exists(r.serviceAccount) {
 		return r.CreateOwned(ctx, builder.serviceAccount())
 	} // update not needed for now
 
-	cr := buildClusterRole()
+	cr := buildClusterRole(&builder.desired.ConsolePlugin)
 	if err := r.ReconcileClusterRole(ctx, cr); err != nil {
 		return err
 	}
@@ -169,10 +241,19 @@ func (r *CPReconciler) reconcilePlugin(ctx context.Context, builder *builder, de
 		if err := r.CreateOwned(ctx, consolePlugin); err != nil {
 			return err
 		}
-	} else if pluginNeedsUpdate(&oldPlg, &desired.ConsolePlugin) {
+	} else if pluginNeedsUpdate(&oldPlg, consolePlugin) {
 		if err := r.UpdateIfOwned(ctx, &oldPlg, consolePlugin); err != nil {
 			return err
 		}
+		if oldPlg.Spec.Service.Namespace != builder.namespace {
+			// The console keeps proxying to the previous namespace until it reloads the plugin
+			if !helper.IsOwned(&oldPlg) {
+				r.Status.SetFailure("ConsolePluginNotOwned", fmt.Sprintf("ConsolePlugin %s is not owned by the operator and still points to namespace %s", oldPlg.Name, oldPlg.Spec.Service.Namespace))
+			} else {
+				log.FromContext(ctx).Info("ConsolePlugin moved to the new namespace", "old", oldPlg.Spec.Service.Namespace, "new", builder.namespace)
+				r.Status.SetInProgress("ConsolePluginMoving", fmt.Sprintf("ConsolePlugin %s moved to namespace %s, waiting for the console to load it", oldPlg.Name, builder.namespace))
+			}
+		}
 	}
 	return nil
 }
@@ -226,10 +307,17 @@ func (r *CPReconciler) reconcileServices(ctx context.Context, builder *builder)
 			return err
 		}
 	}
+	if r.AvailableAPIs.HasPromRule() {
+		if promRule := builder.prometheusRule(); promRule == nil {
+			r.Managed.TryDelete(ctx, r.prometheusRule)
+		} else if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.prometheusRule, promRule, &report, helper.PrometheusRuleChanged); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (r *CPReconciler) reconcileHPA(ctx context.Context, builder *builder, desired *flowslatest.FlowCollectorSpec) error {
+func (r *CPReconciler) reconcileHPA(ctx context.Context, builder *builder) error {
 	report := helper.NewChangeReport("Console autoscaler")
 	defer report.LogIfNeeded(ctx)
 
@@ -238,12 +326,13 @@ func (r *CPReconciler) reconcileHPA(ctx context.Context, builder *builder, desir
 		r.Instance,
 		r.hpa,
 		builder.autoScaler(),
-		&desired.ConsolePlugin.Autoscaler,
+		builder.autoscalerSpec(),
 		&report,
 	)
 }
 
-func pluginNeedsUpdate(plg *osv1alpha1.ConsolePlugin, desired *pluginSpec) bool {
-	advancedConfig := helper.GetAdvancedPluginConfig(desired.Advanced)
-	return plg.Spec.Service.Port != *advancedConfig.Port
+func pluginNeedsUpdate(plg, desired *osv1alpha1.ConsolePlugin) bool {
+	// namespaces must be compared too, as the plugin service and proxy move along with the FlowCollector namespace
+	return !equality.Semantic.DeepDerivative(desired.Spec.Service, plg.Spec.Service) ||
+		!equality.Semantic.DeepDerivative(desired.Spec.Proxy, plg.Spec.Proxy)
 }
@@ -2,8 +2,10 @@ package consoleplugin
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	promConfig "github.com/prometheus/common/config"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
 
@@ -19,6 +22,7 @@ import (
 	config "github.com/netobserv/network-observability-operator/controllers/consoleplugin/config"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/watchers"
 )
 
 const testImage = "quay.io/netobserv/network-observability-console-plugin:dev"
@@ -115,7 +119,7 @@ func TestContainerUpdateCheck(t *testing.T) {
 		LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://loki:3100/", TenantID: "netobserv"},
 	}
 	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}
-	builder := newBuilder(testNamespace, testImage, &spec, &loki)
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	old := builder.deployment("digest")
 	nEw := builder.deployment("digest")
 	report := helper.NewChangeReport("")
@@ -167,7 +171,7 @@ func TestContainerUpdateCheck(t *testing.T) {
 				CertFile: "ca.crt",
 			},
 		}}}
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw = builder.deployment("digest")
 	report = helper.NewChangeReport("")
 	assert.True(helper.PodChanged(&old.Spec.Template, &nEw.Spec.Template, constants.PluginName, &report))
@@ -176,7 +180,7 @@ func TestContainerUpdateCheck(t *testing.T) {
 
 	//new loki cert name
 	loki.LokiManualParams.TLS.CACert.Name = "cm-name-2"
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw = builder.deployment("digest")
 	report = helper.NewChangeReport("")
 	assert.True(helper.PodChanged(&old.Spec.Template, &nEw.Spec.Template, constants.PluginName, &report))
@@ -185,11 +189,36 @@ func TestContainerUpdateCheck(t *testing.T) {
 
 	//test again no change
 	loki.LokiManualParams.TLS.CACert.Name = "cm-name-2"
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw = builder.deployment("digest")
 	report = helper.NewChangeReport("")
 	assert.False(helper.PodChanged(&old.Spec.Template, &nEw.Spec.Template, constants.PluginName, &report))
 	assert.Contains(report.String(), "no change")
+	old = nEw
+
+	//new proxy
+	proxy := helper.ProxyConfig{HTTPSProxy: "http://proxy:3128", NoProxy: ".svc"}
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, &proxy)
+	nEw = builder.deployment("digest")
+	report = helper.NewChangeReport("")
+	assert.True(helper.PodChanged(&old.Spec.Template, &nEw.Spec.Template, constants.PluginName, &report))
+	assert.Contains(report.String(), "Env changed")
+	assert.Contains(nEw.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"})
+	old = nEw
+
+	//trusted CA bundle
+	proxy.TrustedCABundle = true
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, &proxy)
+	builder.trustedCADigest = "ca-digest"
+	nEw = builder.deployment("digest")
+	report = helper.NewChangeReport("")
+	assert.True(helper.PodChanged(&old.Spec.Template, &nEw.Spec.Template, constants.PluginName, &report))
+	assert.Equal("ca-digest", nEw.Spec.Template.Annotations["flows.netobserv.io/watched-trusted-ca"])
+	assert.Contains(nEw.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      constants.TrustedCABundleName,
+		ReadOnly:  true,
+		MountPath: "/etc/pki/ca-trust/extracted/pem",
+	})
 }
 
 func TestConfigMapUpdateCheck(t *testing.T) {
@@ -201,7 +230,7 @@ func TestConfigMapUpdateCheck(t *testing.T) {
 		LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://loki:3100/", TenantID: "netobserv"},
 	}
 	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}
-	builder := newBuilder(testNamespace, testImage, &spec, &loki)
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	old, _, _ := builder.configMap()
 	nEw, _, _ := builder.configMap()
 	assert.Equal(old.Data, nEw.Data)
@@ -217,7 +246,7 @@ func TestConfigMapUpdateCheck(t *testing.T) {
 			},
 		}},
 	}
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw, _, _ = builder.configMap()
 	assert.NotEqual(old.Data, nEw.Data)
 	old = nEw
@@ -225,7 +254,7 @@ func TestConfigMapUpdateCheck(t *testing.T) {
 	//set status url and enable default tls
 	loki.LokiManualParams.StatusURL = "http://loki.status:3100/"
 	loki.LokiManualParams.StatusTLS.Enable = true
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw, _, _ = builder.configMap()
 	assert.NotEqual(old.Data, nEw.Data)
 	old = nEw
@@ -236,7 +265,7 @@ func TestConfigMapUpdateCheck(t *testing.T) {
 		Name:     "status-cm-name",
 		CertFile: "status-ca.crt",
 	}
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw, _, _ = builder.configMap()
 	assert.NotEqual(old.Data, nEw.Data)
 	old = nEw
@@ -248,7 +277,7 @@ func TestConfigMapUpdateCheck(t *testing.T) {
 		CertFile: "tls.crt",
 		CertKey:  "tls.key",
 	}
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw, _, _ = builder.configMap()
 	assert.NotEqual(old.Data, nEw.Data)
 }
@@ -264,7 +293,7 @@ func TestConfigMapUpdateWithLokistackMode(t *testing.T) {
 	}
 	loki := helper.NewLokiConfig(&lokiSpec, "any")
 	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: plugin, Loki: lokiSpec}
-	builder := newBuilder(testNamespace, testImage, &spec, &loki)
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	old, _, _ := builder.configMap()
 	nEw, _, _ := builder.configMap()
 	assert.Equal(old.Data, nEw.Data)
@@ -274,7 +303,7 @@ func TestConfigMapUpdateWithLokistackMode(t *testing.T) {
 	loki = helper.NewLokiConfig(&lokiSpec, "any")
 
 	spec = flowslatest.FlowCollectorSpec{ConsolePlugin: plugin, Loki: lokiSpec}
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw, _, _ = builder.configMap()
 	assert.NotEqual(old.Data, nEw.Data)
 	old = nEw
@@ -284,7 +313,7 @@ func TestConfigMapUpdateWithLokistackMode(t *testing.T) {
 	loki = helper.NewLokiConfig(&lokiSpec, "any")
 
 	spec = flowslatest.FlowCollectorSpec{ConsolePlugin: plugin, Loki: lokiSpec}
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	nEw, _, _ = builder.configMap()
 	assert.NotEqual(old.Data, nEw.Data)
 }
@@ -309,7 +338,7 @@ func TestConfigMapContent(t *testing.T) {
 		Loki:          lokiSpec,
 		Processor:     flowslatest.FlowCollectorFLP{SubnetLabels: flowslatest.SubnetLabels{OpenShiftAutoDetect: ptr.To(false)}},
 	}
-	builder := newBuilder(testNamespace, testImage, &spec, &loki)
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	cm, _, err := builder.configMap()
 	assert.NotNil(cm)
 	assert.Nil(err)
@@ -333,6 +362,409 @@ func TestConfigMapContent(t *testing.T) {
 	assert.Equal(config.Frontend.Deduper.Merge, true)
 }
 
+func TestConfigMapOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	loki := helper.NewLokiConfig(&spec.Loki, "any")
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	_, digest, err := builder.configMap()
+	assert.NoError(err)
+
+	builder.overrides = map[string]string{
+		"loki.timeout":                         "2m",
+		"frontend.columns.SrcK8S_Name.default": "false",
+	}
+	cm, overriddenDigest, err := builder.configMap()
+	assert.NoError(err)
+	assert.NotEqual(digest, overriddenDigest)
+
+	var config config.PluginConfig
+	err = yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config)
+	assert.NoError(err)
+	assert.Equal("2m", config.Loki.Timeout)
+	for _, col := range config.Frontend.Columns {
+		if col.ID == "SrcK8S_Name" {
+			assert.False(col.Default)
+		}
+	}
+
+	// invalid overrides fail the configuration
+	builder.overrides = map[string]string{"frontend.columns.Unknown.default": "false"}
+	_, _, err = builder.configMap()
+	assert.EqualError(err, `override frontend.columns.Unknown.default: no element "Unknown"`)
+}
+
+func TestConfigMapAccessMode(t *testing.T) {
+	assert := assert.New(t)
+
+	authCheck := func(lokiSpec flowslatest.FlowCollectorLoki, mode flowslatest.ConsolePluginAccessMode) string {
+		loki := helper.NewLokiConfig(&lokiSpec, "any")
+		spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig(), Loki: lokiSpec}
+		spec.ConsolePlugin.AccessMode = mode
+		builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+		cm, _, err := builder.configMap()
+		assert.NoError(err)
+		var config config.PluginConfig
+		assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config))
+		return config.Loki.AuthCheck
+	}
+
+	lokiStack := flowslatest.FlowCollectorLoki{
+		Mode:      flowslatest.LokiModeLokiStack,
+		LokiStack: flowslatest.LokiStackRef{Name: "lokistack", Namespace: "ls-namespace"},
+	}
+	assert.Equal("auto", authCheck(lokiStack, ""))
+	assert.Equal("admin", authCheck(lokiStack, flowslatest.ConsolePluginAccessAdmin))
+	assert.Equal("auth", authCheck(lokiStack, flowslatest.ConsolePluginAccessNamespaceRestricted))
+
+	// User token not forwarded: fall back to admin
+	monolithic := flowslatest.FlowCollectorLoki{
+		Mode:       flowslatest.LokiModeMonolithic,
+		Monolithic: flowslatest.LokiMonolithParams{URL: "http://loki:3100/"},
+	}
+	assert.Equal("admin", authCheck(monolithic, flowslatest.ConsolePluginAccessNamespaceRestricted))
+}
+
+func TestQueryService(t *testing.T) {
+	assert := assert.New(t)
+
+	lokiSpec := flowslatest.FlowCollectorLoki{
+		Mode:      flowslatest.LokiModeLokiStack,
+		LokiStack: flowslatest.LokiStackRef{Name: "lokistack", Namespace: "ls-namespace"},
+	}
+	loki := helper.NewLokiConfig(&lokiSpec, "any")
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig(), Loki: lokiSpec}
+	assert.False(helper.UseQueryService(&spec))
+
+	spec.ConsolePlugin.Enable = ptr.To(false)
+	spec.ConsolePlugin.Advanced = &flowslatest.AdvancedPluginConfig{
+		ServingCert: &flowslatest.CertificateReference{Type: flowslatest.RefTypeSecret, Name: "plugin-cert", CertFile: "cert.pem", CertKey: "key.pem"},
+	}
+	spec.QueryService = flowslatest.FlowCollectorQueryService{
+		Enable:    ptr.To(true),
+		Replicas:  ptr.To(int32(2)),
+		Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")}},
+	}
+	// the query service doesn't depend on the console plugin
+	assert.True(helper.UseQueryService(&spec))
+
+	builder := newQueryBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, digest, err := builder.configMap()
+	assert.NoError(err)
+	assert.Equal("query-service-config", cm.Name)
+	var cfg config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	// tokens are always checked
+	assert.Equal("auth", cfg.Loki.AuthCheck)
+	// the plugin custom serving certificate doesn't apply to the query service
+	assert.Equal("/var/serving-cert/tls.crt", cfg.Server.CertPath)
+
+	dep := builder.queryDeployment(digest)
+	assert.Equal("netobserv-query", dep.Name)
+	assert.Equal(ptr.To(int32(2)), dep.Spec.Replicas)
+	assert.Equal(map[string]string{"app": "netobserv-query"}, dep.Spec.Selector.MatchLabels)
+	pod := dep.Spec.Template.Spec
+	assert.Equal("netobserv-query", pod.ServiceAccountName)
+	assert.Equal("200Mi", pod.Containers[0].Resources.Limits.Memory().String())
+	assert.Equal("query-serving-cert", pod.Volumes[0].Secret.SecretName)
+	assert.Equal("query-service-config", pod.Volumes[1].ConfigMap.Name)
+
+	svc := builder.mainService()
+	assert.Equal("netobserv-query", svc.Name)
+	assert.Equal(map[string]string{constants.OpenShiftCertificateAnnotation: "query-serving-cert"}, svc.Annotations)
+
+	cr := builder.queryClusterRole()
+	binding := builder.clusterRoleBinding()
+	assert.Equal("netobserv-query", cr.Name)
+	assert.Equal(cr.Name, binding.RoleRef.Name)
+	assert.Equal("netobserv-query", binding.Subjects[0].Name)
+
+	// the plugin builder is unchanged
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	assert.Equal("netobserv-plugin", builder.deployment(digest).Spec.Template.Spec.ServiceAccountName)
+	assert.Equal("netobserv-plugin", builder.clusterRoleBinding().RoleRef.Name)
+}
+
+func TestQueryServiceAccessMode(t *testing.T) {
+	assert := assert.New(t)
+
+	authCheck := func(lokiSpec flowslatest.FlowCollectorLoki, mode flowslatest.ConsolePluginAccessMode) string {
+		loki := helper.NewLokiConfig(&lokiSpec, "any")
+		spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig(), Loki: lokiSpec}
+		spec.ConsolePlugin.AccessMode = mode
+		builder := newQueryBuilder(testNamespace, testImage, &spec, &loki, nil)
+		cm, _, err := builder.configMap()
+		assert.NoError(err)
+		var cfg config.PluginConfig
+		assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+		return cfg.Loki.AuthCheck
+	}
+
+	// the user token is forwarded to Loki, which filters flows per namespace
+	lokiStack := flowslatest.FlowCollectorLoki{
+		Mode:      flowslatest.LokiModeLokiStack,
+		LokiStack: flowslatest.LokiStackRef{Name: "lokistack", Namespace: "ls-namespace"},
+	}
+	assert.Equal("auth", authCheck(lokiStack, ""))
+	assert.Equal("admin", authCheck(lokiStack, flowslatest.ConsolePluginAccessAdmin))
+
+	// User token not forwarded: any authenticated identity would read all the flows, only allow admins
+	monolithic := flowslatest.FlowCollectorLoki{
+		Mode:       flowslatest.LokiModeMonolithic,
+		Monolithic: flowslatest.LokiMonolithParams{URL: "http://loki:3100/"},
+	}
+	assert.Equal("admin", authCheck(monolithic, ""))
+	assert.Equal("admin", authCheck(monolithic, flowslatest.ConsolePluginAccessNamespaceRestricted))
+}
+
+func TestNetworkEventsFeature(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	spec.Agent.OVN.Enable = ptr.To(true)
+
+	// OVN observability requires the privileged mode
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err := builder.configMap()
+	assert.NoError(err)
+	var cfg config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.NotContains(cfg.Frontend.Features, "networkEvents")
+
+	spec.Agent.EBPF.Privileged = true
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err = builder.configMap()
+	assert.NoError(err)
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.Contains(cfg.Frontend.Features, "networkEvents")
+	// policy verdicts can be filtered
+	assert.True(slices.ContainsFunc(cfg.Frontend.Filters, func(f config.FilterConfig) bool { return f.ID == "network_events" }))
+}
+
+func TestVirtualizationFeature(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err := builder.configMap()
+	assert.NoError(err)
+	var cfg config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.NotContains(cfg.Frontend.Features, "virtualization")
+
+	spec.Processor.Virtualization.Enable = ptr.To(true)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err = builder.configMap()
+	assert.NoError(err)
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.Contains(cfg.Frontend.Features, "virtualization")
+}
+
+func TestDSCPClassificationFeature(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	spec.Processor.DSCPClassification.Enable = ptr.To(true)
+
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err := builder.configMap()
+	assert.NoError(err)
+	var cfg config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.Contains(cfg.Frontend.Features, "dscpClassification")
+}
+
+func TestEgressClassificationFeature(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	spec.Processor.EgressClassification.Enable = ptr.To(true)
+
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err := builder.configMap()
+	assert.NoError(err)
+	var cfg config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.Contains(cfg.Frontend.Features, "egressClassification")
+}
+
+func TestMetricsConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err := builder.configMap()
+	assert.NoError(err)
+	var cfg config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.Equal(metricsPort, cfg.Server.MetricsPort)
+
+	// metrics service targets the configured port
+	svc := builder.metricsService()
+	assert.Equal(int32(metricsPort), svc.Spec.Ports[0].TargetPort.IntVal)
+}
+
+func TestLokiSecretToken(t *testing.T) {
+	assert := assert.New(t)
+
+	lokiSpec := flowslatest.FlowCollectorLoki{
+		Mode: flowslatest.LokiModeManual,
+		Manual: flowslatest.LokiManualParams{
+			QuerierURL:      "https://logs.example.com/",
+			AuthToken:       flowslatest.LokiAuthSecretToken,
+			AuthTokenSecret: &flowslatest.FileReference{Type: flowslatest.RefTypeSecret, Name: "loki-creds", File: "token"},
+		},
+	}
+	loki := helper.NewLokiConfig(&lokiSpec, "any")
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig(), Loki: lokiSpec}
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	builder.lokiTokenDigest = "token-digest"
+
+	cm, digest, err := builder.configMap()
+	assert.NoError(err)
+	var config config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config))
+	assert.Equal("/var/loki-token/token", config.Loki.TokenPath)
+	assert.False(config.Loki.ForwardUserToken)
+
+	pod := builder.podTemplate(digest)
+	assert.Equal("token-digest", pod.Annotations[watchers.Annotation("loki-token")])
+	assert.Contains(pod.Spec.Volumes, corev1.Volume{
+		Name:         "loki-token",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "loki-creds"}},
+	})
+}
+
+func TestServingCertAndServiceAnnotations(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+
+	// default: service CA
+	svc := builder.mainService()
+	assert.Equal(map[string]string{constants.OpenShiftCertificateAnnotation: "console-serving-cert"}, svc.Annotations)
+	cm, digest, err := builder.configMap()
+	assert.NoError(err)
+	var cfg config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.Equal("/var/serving-cert/tls.crt", cfg.Server.CertPath)
+	pod := builder.podTemplate(digest)
+	assert.Equal("console-serving-cert", pod.Spec.Volumes[0].Secret.SecretName)
+
+	// custom serving cert and annotations
+	spec.ConsolePlugin.Advanced = &flowslatest.AdvancedPluginConfig{
+		ServiceAnnotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+		ServingCert: &flowslatest.CertificateReference{
+			Type:     flowslatest.RefTypeSecret,
+			Name:     "plugin-cert",
+			CertFile: "cert.pem",
+			CertKey:  "key.pem",
+		},
+	}
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	builder.servingCertDigest = "cert-digest"
+	svc = builder.mainService()
+	assert.Equal(map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}, svc.Annotations)
+	cm, digest, err = builder.configMap()
+	assert.NoError(err)
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &cfg))
+	assert.Equal("/var/serving-cert/cert.pem", cfg.Server.CertPath)
+	assert.Equal("/var/serving-cert/key.pem", cfg.Server.KeyPath)
+	pod = builder.podTemplate(digest)
+	assert.Equal("cert-digest", pod.Annotations[watchers.Annotation("serving-cert")])
+	assert.Contains(pod.Spec.Volumes, corev1.Volume{
+		Name:         "serving-cert",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "plugin-cert"}},
+	})
+	for _, v := range pod.Spec.Volumes {
+		assert.NotEqual("console-serving-cert", v.Name)
+	}
+	assert.True(builder.serviceMonitor().Spec.Endpoints[0].TLSConfig.InsecureSkipVerify)
+}
+
+func TestProbes(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	first := builder.podTemplate("digest")
+	container := first.Spec.Containers[0]
+	assert.Equal(intstr.FromInt32(*builder.advanced.Port), container.LivenessProbe.TCPSocket.Port)
+	assert.Equal(intstr.FromInt32(*builder.advanced.Port), container.ReadinessProbe.TCPSocket.Port)
+	assert.Equal(int32(startupFailureThreshold), container.StartupProbe.FailureThreshold)
+
+	spec.ConsolePlugin.Advanced = &flowslatest.AdvancedPluginConfig{
+		Probes: &flowslatest.ProbesConfig{
+			Liveness: &flowslatest.ProbeConfig{PeriodSeconds: ptr.To(int32(30)), TimeoutSeconds: ptr.To(int32(10))},
+		},
+	}
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	second := builder.podTemplate("digest")
+	container = second.Spec.Containers[0]
+	assert.Equal(int32(30), container.LivenessProbe.PeriodSeconds)
+	assert.Equal(int32(10), container.LivenessProbe.TimeoutSeconds)
+	assert.Equal(int32(probeFailureThreshold), container.LivenessProbe.FailureThreshold)
+	assert.Equal(first.Spec.Containers[0].ReadinessProbe, container.ReadinessProbe)
+
+	report := helper.NewChangeReport("")
+	assert.True(helper.PodChanged(first, second, constants.PluginName, &report))
+	assert.Contains(report.String(), "Liveness probe changed")
+}
+
+func TestPluginNeedsUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: getPluginConfig()}
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	current := builder.consolePlugin()
+	assert.False(pluginNeedsUpdate(current, builder.consolePlugin()))
+
+	// Port changed
+	spec.ConsolePlugin.Advanced = &flowslatest.AdvancedPluginConfig{Port: ptr.To(int32(9099))}
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	assert.True(pluginNeedsUpdate(current, builder.consolePlugin()))
+
+	// Namespace changed
+	spec.ConsolePlugin.Advanced = nil
+	builder = newBuilder("other-ns", testImage, &spec, &loki, nil)
+	desired := builder.consolePlugin()
+	assert.True(pluginNeedsUpdate(current, desired))
+	assert.Equal("other-ns", desired.Spec.Service.Namespace)
+	assert.Equal("other-ns", desired.Spec.Proxy[0].Service.Namespace)
+}
+
+func TestDeveloperPerspective(t *testing.T) {
+	assert := assert.New(t)
+
+	plugin := getPluginConfig()
+	assert.Len(buildClusterRole(&plugin).Rules, 1)
+
+	plugin.DeveloperPerspective.Enable = ptr.To(true)
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}
+	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	cm, _, err := builder.configMap()
+	assert.NoError(err)
+	var config config.PluginConfig
+	assert.NoError(yaml.Unmarshal([]byte(cm.Data["config.yaml"]), &config))
+	assert.Contains(config.Frontend.Features, "developerPerspective")
+
+	cr := buildClusterRole(&spec.ConsolePlugin)
+	assert.Len(cr.Rules, 2)
+	assert.Equal([]string{"subjectaccessreviews"}, cr.Rules[1].Resources)
+}
+
 func TestConfigMapError(t *testing.T) {
 	assert := assert.New(t)
 
@@ -356,7 +788,7 @@ func TestConfigMapError(t *testing.T) {
 		ConsolePlugin: getPluginConfig(),
 		Loki:          lokiSpec,
 	}
-	builder := newBuilder(testNamespace, testImage, &spec, &loki)
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	cm, _, err := builder.configMap()
 	assert.Nil(cm)
 	assert.NotNil(err)
@@ -371,7 +803,7 @@ func TestConfigMapError(t *testing.T) {
 		ConsolePlugin: getPluginConfig(),
 		Loki:          lokiSpec,
 	}
-	builder = newBuilder(testNamespace, testImage, &spec, &loki)
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	cm, _, err = builder.configMap()
 	assert.NotNil(cm)
 	assert.Nil(err)
@@ -416,7 +848,7 @@ func TestBuiltService(t *testing.T) {
 	plugin := getPluginConfig()
 	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
 	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}
-	builder := newBuilder(testNamespace, testImage, &spec, &loki)
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
 	old := builder.mainService()
 	nEw := builder.mainService()
 	report := helper.NewChangeReport("")
@@ -430,7 +862,7 @@ func TestLabels(t *testing.T) {
 	plugin := getPluginConfig()
 	loki := helper.LokiConfig{LokiManualParams: flowslatest.LokiManualParams{IngesterURL: "http://foo:1234"}}
 	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}
-	builder := newBuilder(testNamespace, testImage, &spec, &loki)
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
 
 	// Deployment
 	depl := builder.deployment("digest")
@@ -476,6 +908,70 @@ func TestAutoScalerUpdateCheck(t *testing.T) {
 	report = helper.NewChangeReport("")
 	assert.Equal(helper.AutoScalerChanged(&autoScalerSpec, plugin.Autoscaler, &report), true)
 	assert.Contains(report.String(), "Metrics changed")
+
+	//behavior set, with the API server defaults on the current autoscaler
+	autoScalerSpec, plugin = getAutoScalerSpecs()
+	plugin.Autoscaler.Behavior = &ascv2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &ascv2.HPAScalingRules{StabilizationWindowSeconds: ptr.To(int32(600))},
+	}
+	builder := newBuilder(testNamespace, testImage, &flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}, &helper.LokiConfig{}, nil)
+	assert.Equal(plugin.Autoscaler.Behavior, builder.autoScaler().Spec.Behavior)
+	report = helper.NewChangeReport("")
+	assert.Equal(helper.AutoScalerChanged(&autoScalerSpec, plugin.Autoscaler, &report), true)
+	assert.Contains(report.String(), "Behavior changed")
+
+	autoScalerSpec.Spec.Behavior = &ascv2.HorizontalPodAutoscalerBehavior{
+		ScaleUp: &ascv2.HPAScalingRules{StabilizationWindowSeconds: ptr.To(int32(0))},
+		ScaleDown: &ascv2.HPAScalingRules{
+			StabilizationWindowSeconds: ptr.To(int32(600)),
+			SelectPolicy:               ptr.To(ascv2.MaxChangePolicySelect),
+			Policies:                   []ascv2.HPAScalingPolicy{{Type: ascv2.PercentScalingPolicy, Value: 100, PeriodSeconds: 15}},
+		},
+	}
+	report = helper.NewChangeReport("")
+	assert.Equal(helper.AutoScalerChanged(&autoScalerSpec, plugin.Autoscaler, &report), false)
+}
+
+func TestAutoscalerUsage(t *testing.T) {
+	assert := assert.New(t)
+
+	plugin := getPluginConfig()
+	loki := helper.LokiConfig{}
+	spec := flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}
+	builder := newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	assert.Nil(builder.prometheusRule())
+	assert.Equal(plugin.Autoscaler.Metrics, builder.autoScaler().Spec.Metrics)
+
+	spec.ConsolePlugin.AutoscalerUsage = &flowslatest.PluginAutoscalerUsage{
+		RequestsPerSecond: ptr.To(resource.MustParse("500m")),
+		Latency:           &metav1.Duration{Duration: 1500 * time.Millisecond},
+	}
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	metrics := builder.autoScaler().Spec.Metrics
+	assert.Len(metrics, 3)
+	assert.Equal(ascv2.ResourceMetricSourceType, metrics[0].Type)
+	assert.Equal("netobserv:plugin_http_requests:rate1m", metrics[1].Pods.Metric.Name)
+	assert.Equal("500m", metrics[1].Pods.Target.AverageValue.String())
+	assert.Len(spec.ConsolePlugin.Autoscaler.Metrics, 1)
+
+	// the desired metrics, including usage, are compared
+	previous := newBuilder(testNamespace, testImage, &flowslatest.FlowCollectorSpec{ConsolePlugin: plugin}, &loki, nil)
+	report := helper.NewChangeReport("")
+	assert.True(helper.AutoScalerChanged(previous.autoScaler(), *builder.autoscalerSpec(), &report))
+	report = helper.NewChangeReport("")
+	assert.False(helper.AutoScalerChanged(builder.autoScaler(), *builder.autoscalerSpec(), &report))
+
+	spec.ConsolePlugin.AutoscalerUsage.RequestsPerSecond = nil
+	builder = newBuilder(testNamespace, testImage, &spec, &loki, nil)
+	metrics = builder.autoScaler().Spec.Metrics
+	assert.Len(metrics, 2)
+	assert.Equal("netobserv:plugin_http_request_duration_seconds:avg1m", metrics[1].Pods.Metric.Name)
+	assert.Equal("1500m", metrics[1].Pods.Target.AverageValue.String())
+
+	rule := builder.prometheusRule()
+	assert.Len(rule.Spec.Groups, 1)
+	assert.Equal("netobserv:plugin_http_requests:rate1m", rule.Spec.Groups[0].Rules[0].Record)
+	assert.Equal(`sum by (namespace, pod) (rate(netobserv_plugin_http_request_duration_seconds_count{namespace="`+testNamespace+`"}[1m]))`, rule.Spec.Groups[0].Rules[0].Expr.String())
 }
 
 // ensure HTTPClientConfig Marshal / Unmarshal works as expected for ProxyURL *URL
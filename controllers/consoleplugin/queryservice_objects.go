@@ -0,0 +1,44 @@
+package consoleplugin
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const querySecretName = "query-serving-cert"
+const queryConfigMapName = "query-service-config"
+
+// newQueryBuilder returns a builder of the query service: the plugin backend deployed on its own, without the console
+func newQueryBuilder(ns, imageName string, desired *flowslatest.FlowCollectorSpec, loki *helper.LokiConfig, proxy *helper.ProxyConfig) builder {
+	b := newBuilder(ns, imageName, desired, loki, proxy)
+	b.name = constants.QueryServiceName
+	b.configMapName = queryConfigMapName
+	b.certSecretName = querySecretName
+	b.queryService = true
+	b.labels["app"] = constants.QueryServiceName
+	b.selector["app"] = constants.QueryServiceName
+	// the custom serving certificate and service annotations are meant for the plugin service
+	advanced := *b.advanced
+	advanced.ServingCert = nil
+	advanced.ServiceAnnotations = nil
+	b.advanced = &advanced
+	return b
+}
+
+func (b *builder) queryDeployment(cmDigest string) *appsv1.Deployment {
+	dep := b.deployment(cmDigest)
+	dep.Spec.Replicas = b.desired.QueryService.Replicas
+	dep.Spec.Template.Spec.Containers[0].Resources = *b.desired.QueryService.Resources.DeepCopy()
+	return dep
+}
+
+// queryClusterRole grants the same permissions as the plugin, to check the tokens and the roles of the callers
+func (b *builder) queryClusterRole() *rbacv1.ClusterRole {
+	cr := buildClusterRole(&b.desired.ConsolePlugin)
+	cr.Name = b.name
+	return cr
+}
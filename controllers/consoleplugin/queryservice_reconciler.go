@@ -0,0 +1,147 @@
+package consoleplugin
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+// QSReconciler reconciles the current query service state with the desired configuration
+type QSReconciler struct {
+	*reconcilers.Instance
+	deployment     *appsv1.Deployment
+	service        *corev1.Service
+	serviceAccount *corev1.ServiceAccount
+	configMap      *corev1.ConfigMap
+}
+
+func NewQueryServiceReconciler(cmn *reconcilers.Instance) QSReconciler {
+	return QSReconciler{
+		Instance:       cmn,
+		deployment:     cmn.Managed.NewDeployment(constants.QueryServiceName),
+		service:        cmn.Managed.NewService(constants.QueryServiceName),
+		serviceAccount: cmn.Managed.NewServiceAccount(constants.QueryServiceName),
+		configMap:      cmn.Managed.NewConfigMap(queryConfigMapName),
+	}
+}
+
+// CleanupNamespace cleans up old namespace
+func (r *QSReconciler) CleanupNamespace(ctx context.Context) {
+	r.Managed.CleanupPreviousNamespace(ctx)
+}
+
+// Reconcile is the reconciler entry point to reconcile the current query service state with the desired configuration
+func (r *QSReconciler) Reconcile(ctx context.Context, desired *flowslatest.FlowCollector) error {
+	l := log.FromContext(ctx).WithName("query-service")
+	ctx = log.IntoContext(ctx, l)
+
+	// Retrieve current owned objects
+	err := r.Managed.FetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching current query service objects: %w", err)
+	}
+
+	if !helper.UseQueryService(&desired.Spec) {
+		r.Status.SetUnused("Query service is disabled")
+		// delete any existing owned object
+		r.Managed.TryDeleteAll(ctx)
+		return nil
+	}
+
+	builder := newQueryBuilder(r.Managed.Namespace, r.Instance.Image, &desired.Spec, r.Loki, r.Proxy)
+
+	if err := r.reconcilePermissions(ctx, &builder); err != nil {
+		return fmt.Errorf("reconciling permissions: %w", err)
+	}
+
+	cmDigest, err := r.reconcileConfigMap(ctx, &builder)
+	if err != nil {
+		return fmt.Errorf("reconciling configuration: %w", err)
+	}
+
+	// Watch for trusted CA bundle injection; need to restart pods as the system bundle is only read at startup
+	if r.Proxy.UseTrustedCABundle() {
+		if builder.trustedCADigest, err = r.ReconcileTrustedCABundle(ctx); err != nil || builder.trustedCADigest == "" {
+			// not deployed until the bundle is injected
+			return err
+		}
+	}
+
+	// Watch for Loki credentials; need to restart pods on token rotation
+	if builder.lokiTokenDigest, err = r.ReconcileLokiCredentials(ctx); err != nil {
+		return fmt.Errorf("reading Loki credentials: %w", err)
+	}
+
+	if err = r.reconcileDeployment(ctx, &builder, &desired.Spec, cmDigest); err != nil {
+		return fmt.Errorf("reconciling deployment: %w", err)
+	}
+
+	report := helper.NewChangeReport("Query service")
+	defer report.LogIfNeeded(ctx)
+	if err = r.ReconcileService(ctx, r.service, builder.mainService(), &report); err != nil {
+		return fmt.Errorf("reconciling service: %w", err)
+	}
+
+	// Watch for Loki certificates if necessary; we'll ignore in that case the returned digest, as we don't need to restart pods on cert rotation
+	// because certificate is always reloaded from file
+	if _, err = r.Watcher.ProcessCACert(ctx, r.Client, &r.Loki.TLS, r.Namespace); err != nil {
+		return fmt.Errorf("reading Loki certificates: %w", err)
+	}
+	if _, _, err = r.Watcher.ProcessMTLSCerts(ctx, r.Client, &r.Loki.StatusTLS, r.Namespace); err != nil {
+		return fmt.Errorf("reading Loki status certificates: %w", err)
+	}
+	return nil
+}
+
+func (r *QSReconciler) reconcilePermissions(ctx context.Context, builder *builder) error {
+	if !r.Managed.Exists(r.serviceAccount) {
+		return r.CreateOwned(ctx, builder.serviceAccount())
+	} // update not needed for now
+
+	if err := r.ReconcileClusterRole(ctx, builder.queryClusterRole()); err != nil {
+		return err
+	}
+	return r.ReconcileClusterRoleBinding(ctx, builder.clusterRoleBinding())
+}
+
+func (r *QSReconciler) reconcileConfigMap(ctx context.Context, builder *builder) (string, error) {
+	newCM, configDigest, err := builder.configMap()
+	if err != nil {
+		return "", err
+	}
+	if !r.Managed.Exists(r.configMap) {
+		if err := r.CreateOwned(ctx, newCM); err != nil {
+			return "", err
+		}
+	} else if !reflect.DeepEqual(newCM.Data, r.configMap.Data) {
+		if err := r.UpdateIfOwned(ctx, r.configMap, newCM); err != nil {
+			return "", err
+		}
+	}
+	return configDigest, nil
+}
+
+func (r *QSReconciler) reconcileDeployment(ctx context.Context, builder *builder, desired *flowslatest.FlowCollectorSpec, cmDigest string) error {
+	report := helper.NewChangeReport("Query service deployment")
+	defer report.LogIfNeeded(ctx)
+
+	return reconcilers.ReconcileDeployment(
+		ctx,
+		r.Instance,
+		r.deployment,
+		builder.queryDeployment(cmDigest),
+		constants.QueryServiceName,
+		helper.PtrInt32(desired.QueryService.Replicas),
+		nil,
+		&report,
+	)
+}
@@ -12,6 +12,8 @@ const (
 	FLPName                  = "flowlogs-pipeline"
 	FLPPortName              = "flp" // must be <15 chars
 	PluginName               = "netobserv-plugin"
+	QueryServiceName         = "netobserv-query"
+	ReporterName             = "netobserv-reporter"
 
 	// EBPFAgentName and other constants for it
 	EBPFAgentName                     = "netobserv-ebpf-agent"
@@ -33,8 +35,21 @@ const (
 
 	TokensPath = "/var/run/secrets/tokens/"
 
+	// Trusted CA bundle, injected by OpenShift in a labelled ConfigMap
+	TrustedCABundleName       = "netobserv-trusted-ca-bundle"
+	TrustedCABundleLabel      = "config.openshift.io/inject-trusted-cabundle"
+	TrustedCABundleKey        = "ca-bundle.crt"
+	TrustedCABundleMountPath  = "/etc/pki/ca-trust/extracted/pem"
+	TrustedCABundleMountedKey = "tls-ca-bundle.pem"
+
+	// ManualClusterRBACName is the ConfigMap holding the manifests of cluster-scoped RBAC objects, when they are applied by an administrator
+	ManualClusterRBACName = "netobserv-cluster-rbac"
+
 	ClusterNameLabelName = "K8S_ClusterName"
 
+	// LoadGeneratorLokiApp is the value of the Loki `app` label for the stream of synthetic flows
+	LoadGeneratorLokiApp = "netobserv-load-generator"
+
 	MonitoringNamespace      = "openshift-monitoring"
 	MonitoringServiceAccount = "prometheus-k8s"
 
@@ -0,0 +1,137 @@
+package ebpf
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+// tcxMinKernel is the first kernel version providing TCX links
+var tcxMinKernel = kernelVersion{major: 6, minor: 6}
+
+// agentAttachModes counts the nodes per eBPF attach mode, as also reported in `status.agent.attachModes`
+var agentAttachModes = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "netobserv_operator_agent_attach_mode_nodes",
+		Help: "Number of nodes per eBPF agent attach mode",
+	},
+	[]string{"mode"},
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(agentAttachModes)
+}
+
+type kernelVersion struct {
+	major, minor int
+}
+
+// parseKernelVersion reads the major and minor numbers of a kernel release, such as "5.14.0-427.el9.x86_64"
+func parseKernelVersion(release string) (kernelVersion, bool) {
+	parts := strings.SplitN(release, ".", 3)
+	if len(parts) < 2 {
+		return kernelVersion{}, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return kernelVersion{}, false
+	}
+	// minor might be followed by a suffix when there is no patch number, such as "6.8-rc1"
+	minorStr := parts[1]
+	if i := strings.IndexFunc(minorStr, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		minorStr = minorStr[:i]
+	}
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil {
+		return kernelVersion{}, false
+	}
+	return kernelVersion{major: major, minor: minor}, true
+}
+
+func (k kernelVersion) atLeast(other kernelVersion) bool {
+	return k.major > other.major || (k.major == other.major && k.minor >= other.minor)
+}
+
+// nodeAttachMode returns the attach mode that the agent uses on a node, and whether the node kernel supports it
+func nodeAttachMode(configured flowslatest.EBPFAttachMode, kernel string) (flowslatest.EBPFAttachMode, bool) {
+	if configured == flowslatest.EBPFAttachTC {
+		return flowslatest.EBPFAttachTC, true
+	}
+	// when the kernel version is unknown, assume a recent one
+	v, ok := parseKernelVersion(kernel)
+	hasTCX := !ok || v.atLeast(tcxMinKernel)
+	if configured == flowslatest.EBPFAttachTCX {
+		return flowslatest.EBPFAttachTCX, hasTCX
+	}
+	if hasTCX {
+		return flowslatest.EBPFAttachTCX, true
+	}
+	return flowslatest.EBPFAttachTC, true
+}
+
+// reconcileAttachModes reports the attach mode used on each node running the agent, and returns warnings for the nodes where it isn't supported
+func (c *AgentController) reconcileAttachModes(ctx context.Context, pods []corev1.Pod, configured flowslatest.EBPFAttachMode) []string {
+	nodes := corev1.NodeList{}
+	if err := c.List(ctx, &nodes); err != nil {
+		// modes are still reported, assuming recent kernels
+		log.FromContext(ctx).Error(err, "can't list nodes to detect kernel versions")
+	}
+	modes := attachModesStatus(configured, pods, nodes.Items)
+	c.Status.SetAgentAttachModes(modes)
+	agentAttachModes.Reset()
+	var warnings []string
+	for i := range modes {
+		agentAttachModes.WithLabelValues(string(modes[i].Mode)).Set(float64(modes[i].Nodes))
+		if len(modes[i].Unsupported) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s is not supported by the kernel of nodes %s: set spec.agent.ebpf.attachMode to Any to fall back to TC on these nodes",
+				modes[i].Mode,
+				strings.Join(modes[i].Unsupported, ", "),
+			))
+		}
+	}
+	return warnings
+}
+
+func attachModesStatus(configured flowslatest.EBPFAttachMode, pods []corev1.Pod, nodes []corev1.Node) []flowslatest.AgentAttachModeCount {
+	kernels := make(map[string]string, len(nodes))
+	for i := range nodes {
+		kernels[nodes[i].Name] = nodes[i].Status.NodeInfo.KernelVersion
+	}
+	counts := map[flowslatest.EBPFAttachMode]*flowslatest.AgentAttachModeCount{}
+	for i := range pods {
+		node := pods[i].Spec.NodeName
+		if node == "" {
+			continue
+		}
+		mode, supported := nodeAttachMode(configured, kernels[node])
+		count, ok := counts[mode]
+		if !ok {
+			count = &flowslatest.AgentAttachModeCount{Mode: mode}
+			counts[mode] = count
+		}
+		count.Nodes++
+		if !supported {
+			count.Unsupported = append(count.Unsupported, node)
+		}
+	}
+	var modes []flowslatest.AgentAttachModeCount
+	for _, count := range counts {
+		sort.Strings(count.Unsupported)
+		if len(count.Unsupported) > maxUnhealthyNodes {
+			count.Unsupported = count.Unsupported[:maxUnhealthyNodes]
+		}
+		modes = append(modes, *count)
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i].Mode < modes[j].Mode })
+	return modes
+}
@@ -0,0 +1,86 @@
+package ebpf
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+// ebpfUser identifies, from its DaemonSets, a product that attaches its own eBPF programs to the network interfaces
+type ebpfUser struct {
+	name   string
+	labels client.MatchingLabels
+	// usesEBPF tells whether a DaemonSet actually runs eBPF programs; nil means always
+	usesEBPF func(*v1.DaemonSet) bool
+}
+
+var knownEBPFUsers = []ebpfUser{
+	{name: "Cilium", labels: client.MatchingLabels{"k8s-app": "cilium"}},
+	// Calico only attaches eBPF programs with its eBPF dataplane
+	{name: "Calico", labels: client.MatchingLabels{"k8s-app": "calico-node"}, usesEBPF: calicoEBPFEnabled},
+}
+
+func calicoEBPFEnabled(ds *v1.DaemonSet) bool {
+	for i := range ds.Spec.Template.Spec.Containers {
+		for _, env := range ds.Spec.Template.Spec.Containers[i].Env {
+			if env.Name == "FELIX_BPFENABLED" && env.Value == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reconcileConflicts looks for other eBPF users in the cluster, reports them in `status.agent.conflicts`,
+// and returns warnings when the attach mode doesn't let them coexist with the agent
+func (c *AgentController) reconcileConflicts(ctx context.Context, target *flowslatest.FlowCollectorEBPF) []string {
+	if c.Namespaced {
+		// the DaemonSets of other products run in namespaces that are not watched
+		log.FromContext(ctx).Info("The operator only watches some namespaces: eBPF conflicts not detected")
+		c.Status.SetAgentConflicts(nil)
+		return nil
+	}
+	var conflicts []flowslatest.AgentConflict
+	for i := range knownEBPFUsers {
+		user := &knownEBPFUsers[i]
+		list := v1.DaemonSetList{}
+		if err := c.List(ctx, &list, user.labels); err != nil {
+			log.FromContext(ctx).Error(err, "can't list DaemonSets to detect eBPF conflicts", "product", user.name)
+			continue
+		}
+		conflicts = append(conflicts, user.conflicts(list.Items, target.AttachMode)...)
+	}
+	c.Status.SetAgentConflicts(conflicts)
+	if len(conflicts) > 0 && target.AttachMode == flowslatest.EBPFAttachTC {
+		return []string{conflicts[0].Message}
+	}
+	return nil
+}
+
+func (u *ebpfUser) conflicts(daemonSets []v1.DaemonSet, mode flowslatest.EBPFAttachMode) []flowslatest.AgentConflict {
+	var conflicts []flowslatest.AgentConflict
+	for i := range daemonSets {
+		ds := &daemonSets[i]
+		if u.usesEBPF != nil && !u.usesEBPF(ds) {
+			continue
+		}
+		var msg string
+		if mode == flowslatest.EBPFAttachTC {
+			msg = fmt.Sprintf("%s attaches eBPF programs to the same network interfaces: set spec.agent.ebpf.attachMode to Any or TCX so that both can coexist", u.name)
+		} else {
+			msg = fmt.Sprintf("%s attaches eBPF programs to the same network interfaces: on nodes where TCX is not available (kernel older than 6.6), flows might be missing", u.name)
+		}
+		conflicts = append(conflicts, flowslatest.AgentConflict{
+			Name:      u.name,
+			Namespace: ds.Namespace,
+			DaemonSet: ds.Name,
+			Message:   msg,
+		})
+	}
+	return conflicts
+}
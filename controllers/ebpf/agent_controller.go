@@ -31,6 +31,7 @@ const (
 	envCacheMaxFlows              = "CACHE_MAX_FLOWS"
 	envExcludeInterfaces          = "EXCLUDE_INTERFACES"
 	envInterfaces                 = "INTERFACES"
+	envTCAttachMode               = "TC_ATTACH_MODE"
 	envAgentIP                    = "AGENT_IP"
 	envFlowsTargetHost            = "TARGET_HOST"
 	envFlowsTargetPort            = "TARGET_PORT"
@@ -40,6 +41,7 @@ const (
 	envKafkaTopic                 = "KAFKA_TOPIC"
 	envKafkaBatchSize             = "KAFKA_BATCH_SIZE"
 	envKafkaBatchMessages         = "KAFKA_BATCH_MESSAGES"
+	envKafkaCompression           = "KAFKA_COMPRESSION"
 	envKafkaEnableTLS             = "KAFKA_ENABLE_TLS"
 	envKafkaTLSInsecureSkipVerify = "KAFKA_TLS_INSECURE_SKIP_VERIFY"
 	envKafkaTLSCACertPath         = "KAFKA_TLS_CA_CERT_PATH"
@@ -52,10 +54,10 @@ const (
 	envLogLevel                   = "LOG_LEVEL"
 	envDedupe                     = "DEDUPER"
 	dedupeDefault                 = "firstCome"
-	envGoMemLimit                 = "GOMEMLIMIT"
 	envEnablePktDrop              = "ENABLE_PKT_DROPS"
 	envEnableDNSTracking          = "ENABLE_DNS_TRACKING"
 	envEnableFlowRTT              = "ENABLE_RTT"
+	envEnableNetworkEvents        = "ENABLE_NETWORK_EVENTS_MONITORING"
 	envEnableMetrics              = "METRICS_ENABLE"
 	envMetricsPort                = "METRICS_SERVER_PORT"
 	envMetricPrefix               = "METRICS_PREFIX"
@@ -87,6 +89,12 @@ const (
 	bpfTraceMountPath  = "/sys/kernel/debug"
 	bpfNetNSMountName  = "var-run-netns"
 	bpfNetNSMountPath  = "/var/run/netns"
+	ovsRunMountName    = "var-run-ovs"
+	ovsRunMountPath    = "/var/run/openvswitch"
+	ovnRunMountName    = "var-run-ovn"
+	ovnRunMountPath    = "/var/run/ovn"
+	// attachWarningReason is the reason of the warning condition about eBPF attach mode issues
+	attachWarningReason = "EBPFAttachMode"
 )
 
 const (
@@ -127,6 +135,16 @@ func (c *AgentController) Reconcile(ctx context.Context, target *flowslatest.Flo
 	if err != nil {
 		return fmt.Errorf("fetching current eBPF agent: %w", err)
 	}
+	if current != nil {
+		c.Status.SetObject("DaemonSet", current)
+	}
+	warnings := c.reconcileHealth(ctx, current, &target.Spec.Agent.EBPF)
+	warnings = append(warnings, c.reconcileConflicts(ctx, &target.Spec.Agent.EBPF)...)
+	if len(warnings) > 0 {
+		c.Status.SetWarning(attachWarningReason, strings.Join(warnings, "; "))
+	} else {
+		c.Status.ClearWarning()
+	}
 
 	// Retrieve other owned objects
 	err = c.Managed.FetchAll(ctx)
@@ -142,6 +160,7 @@ func (c *AgentController) Reconcile(ctx context.Context, target *flowslatest.Flo
 			return nil
 		}
 		rlog.Info("namespace cleanup: deleting eBPF agent", "currentAgent", target.Spec.Agent)
+		c.Status.RemoveObject("DaemonSet", current.Namespace, current.Name)
 		if err := c.Delete(ctx, current); err != nil {
 			if errors.IsNotFound(err) {
 				return nil
@@ -166,7 +185,11 @@ func (c *AgentController) Reconcile(ctx context.Context, target *flowslatest.Flo
 		return err
 	}
 
-	switch helper.DaemonSetChanged(current, desired) {
+	action := helper.DaemonSetChanged(current, desired)
+	if action == helper.ActionNone && c.CommonMetadataChanged(current, desired) {
+		action = helper.ActionUpdate
+	}
+	switch action {
 	case helper.ActionCreate:
 		rlog.Info("action: create agent")
 		c.Status.SetCreatingDaemonSet(desired)
@@ -293,7 +316,41 @@ func (c *AgentController) desired(ctx context.Context, coll *flowslatest.FlowCol
 		}
 	}
 
+	if coll.Spec.Agent.OVN.Enable != nil && *coll.Spec.Agent.OVN.Enable {
+		if !coll.Spec.Agent.EBPF.Privileged {
+			rlog.Error(fmt.Errorf("invalid configuration"), "To read OVN-Kubernetes observability samples privileged mode needs to be enabled")
+		} else {
+			// the agent connects to the OVS and OVN databases sockets to decode the samples
+			for _, dir := range []struct{ name, hostPath, mountPath string }{
+				{name: ovsRunMountName, hostPath: coll.Spec.Agent.OVN.OVSRunDir, mountPath: ovsRunMountPath},
+				{name: ovnRunMountName, hostPath: coll.Spec.Agent.OVN.OVNRunDir, mountPath: ovnRunMountPath},
+			} {
+				hostPath := dir.hostPath
+				if hostPath == "" {
+					hostPath = dir.mountPath
+				}
+				volumes = append(volumes, corev1.Volume{
+					Name: dir.name,
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Type: newHostPathType(corev1.HostPathDirectory),
+							Path: hostPath,
+						},
+					},
+				})
+				volumeMounts = append(volumeMounts, corev1.VolumeMount{
+					Name:      dir.name,
+					MountPath: dir.mountPath,
+				})
+			}
+		}
+	}
+
 	advancedConfig := helper.GetAdvancedAgentConfig(coll.Spec.Agent.EBPF.Advanced)
+	var updateStrategy v1.DaemonSetUpdateStrategy
+	if advancedConfig.UpdateStrategy != nil {
+		updateStrategy = *advancedConfig.UpdateStrategy
+	}
 
 	return &v1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -334,6 +391,7 @@ func (c *AgentController) desired(ctx context.Context, coll *flowslatest.FlowCol
 					PriorityClassName: advancedConfig.Scheduling.PriorityClassName,
 				},
 			},
+			UpdateStrategy: updateStrategy,
 		},
 	}, nil
 }
@@ -346,9 +404,25 @@ func (c *AgentController) envConfig(ctx context.Context, coll *flowslatest.FlowC
 			corev1.EnvVar{Name: envExport, Value: exportKafka},
 			corev1.EnvVar{Name: envKafkaBrokers, Value: coll.Spec.Kafka.Address},
 			corev1.EnvVar{Name: envKafkaTopic, Value: coll.Spec.Kafka.Topic},
-			corev1.EnvVar{Name: envKafkaBatchSize, Value: strconv.Itoa(coll.Spec.Agent.EBPF.KafkaBatchSize)},
-			// For easier user configuration, we can assume a constant message size per flow (~100B in protobuf)
-			corev1.EnvVar{Name: envKafkaBatchMessages, Value: strconv.Itoa(coll.Spec.Agent.EBPF.KafkaBatchSize / averageMessageSize)},
+		)
+		batchSize := coll.Spec.Agent.EBPF.KafkaBatchSize
+		// For easier user configuration, we can assume a constant message size per flow (~100B in protobuf)
+		batchMessages := batchSize / averageMessageSize
+		if adv := coll.Spec.Kafka.Advanced; adv != nil {
+			if adv.BatchBytes != nil {
+				batchSize = int(*adv.BatchBytes)
+				batchMessages = batchSize / averageMessageSize
+			}
+			if adv.BatchMessages != nil {
+				batchMessages = int(*adv.BatchMessages)
+			}
+			if adv.Compression != "" {
+				config = append(config, corev1.EnvVar{Name: envKafkaCompression, Value: strings.ToLower(string(adv.Compression))})
+			}
+		}
+		config = append(config,
+			corev1.EnvVar{Name: envKafkaBatchSize, Value: strconv.Itoa(batchSize)},
+			corev1.EnvVar{Name: envKafkaBatchMessages, Value: strconv.Itoa(batchMessages)},
 		)
 		if coll.Spec.Kafka.TLS.Enable {
 			// Annotate pod with certificate reference so that it is reloaded if modified
@@ -474,12 +548,12 @@ func (c *AgentController) configureFlowFilter(filter *flowslatest.EBPFFlowFilter
 			}
 
 		case "ICMP", "ICMPv6":
-			if *filter.ICMPType != 0 {
+			if filter.ICMPType != nil && *filter.ICMPType != 0 {
 				config = append(config, corev1.EnvVar{Name: envFlowFilterICMPType,
 					Value: strconv.Itoa(*filter.ICMPType),
 				})
 			}
-			if *filter.ICMPCode != 0 {
+			if filter.ICMPCode != nil && *filter.ICMPCode != 0 {
 				config = append(config, corev1.EnvVar{Name: envFlowFilterICMPCode,
 					Value: strconv.Itoa(*filter.ICMPCode)})
 			}
@@ -531,6 +605,13 @@ func (c *AgentController) setEnvConfig(coll *flowslatest.FlowCollector) []corev1
 		})
 	}
 
+	if coll.Spec.Agent.EBPF.AttachMode != "" {
+		config = append(config, corev1.EnvVar{
+			Name:  envTCAttachMode,
+			Value: strings.ToLower(string(coll.Spec.Agent.EBPF.AttachMode)),
+		})
+	}
+
 	if len(coll.Spec.Agent.EBPF.Interfaces) > 0 {
 		config = append(config, corev1.EnvVar{
 			Name:  envInterfaces,
@@ -561,15 +642,9 @@ func (c *AgentController) setEnvConfig(coll *flowslatest.FlowCollector) []corev1
 	}
 
 	// set GOMEMLIMIT which allows specifying a soft memory cap to force GC when resource limit is reached
-	// to prevent OOM
-	if coll.Spec.Agent.EBPF.Resources.Limits.Memory() != nil {
-		if memLimit, ok := coll.Spec.Agent.EBPF.Resources.Limits.Memory().AsInt64(); ok {
-			// we will set the GOMEMLIMIT to current memlimit - 10% as a headroom to account for
-			// memory sources the Go runtime is unaware of
-			memLimit -= int64(float64(memLimit) * 0.1)
-			config = append(config, corev1.EnvVar{Name: envGoMemLimit, Value: fmt.Sprint(memLimit)})
-		}
-	}
+	// to prevent OOM, and GOGC when configured
+	advancedConfig := helper.GetAdvancedAgentConfig(coll.Spec.Agent.EBPF.Advanced)
+	config = append(config, helper.GoRuntimeEnv(advancedConfig.GoRuntime, &coll.Spec.Agent.EBPF.Resources, advancedConfig.Env)...)
 
 	if helper.IsPktDropEnabled(&coll.Spec.Agent.EBPF) {
 		config = append(config, corev1.EnvVar{
@@ -578,6 +653,13 @@ func (c *AgentController) setEnvConfig(coll *flowslatest.FlowCollector) []corev1
 		})
 	}
 
+	if helper.IsOVNObservabilityEnabled(&coll.Spec.Agent) {
+		config = append(config, corev1.EnvVar{
+			Name:  envEnableNetworkEvents,
+			Value: "true",
+		})
+	}
+
 	if helper.IsDNSTrackingEnabled(&coll.Spec.Agent.EBPF) {
 		config = append(config, corev1.EnvVar{
 			Name:  envEnableDNSTracking,
@@ -605,7 +687,6 @@ func (c *AgentController) setEnvConfig(coll *flowslatest.FlowCollector) []corev1
 	dedupMerge := DedupeMergeDefault
 	// we need to sort env map to keep idempotency,
 	// as equal maps could be iterated in different order
-	advancedConfig := helper.GetAdvancedAgentConfig(coll.Spec.Agent.EBPF.Advanced)
 	for _, pair := range helper.KeySorted(advancedConfig.Env) {
 		k, v := pair[0], pair[1]
 		if k == envDedupe {
@@ -1,13 +1,18 @@
 package ebpf
 
 import (
+	"context"
+	"strconv"
 	"testing"
 
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 func sampleDS() appsv1.DaemonSet {
@@ -80,3 +85,159 @@ func DaemonSetChanged(t *testing.T) {
 	action = helper.DaemonSetChanged(&current, &desired)
 	assert.Equal(helper.ActionUpdate, int(action))
 }
+
+func agentPod(node string, ready bool, restarts int32, reason string) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	cs := corev1.ContainerStatus{Name: constants.EBPFAgentName, Image: "agent:v1", RestartCount: restarts}
+	if reason != "" {
+		cs.State.Waiting = &corev1.ContainerStateWaiting{Reason: reason}
+	}
+	return corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "agent-" + node},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{cs},
+		},
+	}
+}
+
+func TestAgentNodesStatus(t *testing.T) {
+	ds := appsv1.DaemonSet{Status: appsv1.DaemonSetStatus{
+		DesiredNumberScheduled: 14,
+		NumberReady:            12,
+		UpdatedNumberScheduled: 13,
+	}}
+	pods := []corev1.Pod{
+		agentPod("node-a", true, 0, ""),
+		agentPod("node-b", true, 2, ""),
+		agentPod("node-c", false, 5, "CrashLoopBackOff"),
+		agentPod("node-d", true, 7, ""),
+		agentPod("node-e", false, 0, "ImagePullBackOff"),
+	}
+	for i := 0; i < 10; i++ {
+		pods = append(pods, agentPod("node-z"+strconv.Itoa(i), true, 1, ""))
+	}
+
+	nodes := agentNodesStatus(&ds, pods)
+	assert.Equal(t, int32(14), nodes.Desired)
+	assert.Equal(t, int32(12), nodes.Ready)
+	assert.Equal(t, int32(1), nodes.Outdated)
+	assert.Len(t, nodes.Unhealthy, maxUnhealthyNodes)
+	assert.Equal(t, flowslatest.AgentNodeState{Node: "node-c", Pod: "agent-node-c", Restarts: 5, Reason: "CrashLoopBackOff", Image: "agent:v1"}, nodes.Unhealthy[0])
+	assert.Equal(t, "node-e", nodes.Unhealthy[1].Node)
+	assert.Equal(t, "node-d", nodes.Unhealthy[2].Node)
+	assert.Equal(t, "node-b", nodes.Unhealthy[3].Node)
+	assert.Equal(t, "node-z0", nodes.Unhealthy[4].Node)
+}
+
+func TestAgentConflicts(t *testing.T) {
+	cilium := appsv1.DaemonSet{ObjectMeta: v1.ObjectMeta{Name: "cilium", Namespace: "kube-system"}}
+	calico := appsv1.DaemonSet{ObjectMeta: v1.ObjectMeta{Name: "calico-node", Namespace: "calico-system"}}
+
+	conflicts := knownEBPFUsers[0].conflicts([]appsv1.DaemonSet{cilium}, flowslatest.EBPFAttachAny)
+	assert.Len(t, conflicts, 1)
+	assert.Equal(t, "Cilium", conflicts[0].Name)
+	assert.Equal(t, "kube-system", conflicts[0].Namespace)
+	assert.Equal(t, "cilium", conflicts[0].DaemonSet)
+	assert.Contains(t, conflicts[0].Message, "TCX is not available")
+
+	conflicts = knownEBPFUsers[0].conflicts([]appsv1.DaemonSet{cilium}, flowslatest.EBPFAttachTC)
+	assert.Contains(t, conflicts[0].Message, "set spec.agent.ebpf.attachMode")
+
+	// Calico without eBPF dataplane doesn't conflict
+	assert.Empty(t, knownEBPFUsers[1].conflicts([]appsv1.DaemonSet{calico}, flowslatest.EBPFAttachTC))
+	calico.Spec.Template.Spec.Containers = []corev1.Container{{Name: "calico-node", Env: []corev1.EnvVar{{Name: "FELIX_BPFENABLED", Value: "true"}}}}
+	assert.Len(t, knownEBPFUsers[1].conflicts([]appsv1.DaemonSet{calico}, flowslatest.EBPFAttachTC), 1)
+}
+
+func TestParseKernelVersion(t *testing.T) {
+	v, ok := parseKernelVersion("5.14.0-427.el9.x86_64")
+	assert.True(t, ok)
+	assert.Equal(t, kernelVersion{major: 5, minor: 14}, v)
+	assert.False(t, v.atLeast(tcxMinKernel))
+
+	v, ok = parseKernelVersion("6.8-rc1")
+	assert.True(t, ok)
+	assert.Equal(t, kernelVersion{major: 6, minor: 8}, v)
+	assert.True(t, v.atLeast(tcxMinKernel))
+
+	_, ok = parseKernelVersion("")
+	assert.False(t, ok)
+}
+
+func TestAttachModesStatus(t *testing.T) {
+	node := func(name, kernel string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: v1.ObjectMeta{Name: name},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{KernelVersion: kernel}},
+		}
+	}
+	nodes := []corev1.Node{
+		node("node-a", "5.14.0-427.el9.x86_64"),
+		node("node-b", "6.8.0-45-generic"),
+		node("node-c", "6.6.30"),
+	}
+	pods := []corev1.Pod{
+		agentPod("node-a", true, 0, ""),
+		agentPod("node-b", true, 0, ""),
+		agentPod("node-c", true, 0, ""),
+		// kernel unknown: assumed recent
+		agentPod("node-d", true, 0, ""),
+	}
+
+	modes := attachModesStatus(flowslatest.EBPFAttachAny, pods, nodes)
+	assert.Equal(t, []flowslatest.AgentAttachModeCount{
+		{Mode: flowslatest.EBPFAttachTC, Nodes: 1},
+		{Mode: flowslatest.EBPFAttachTCX, Nodes: 3},
+	}, modes)
+
+	modes = attachModesStatus(flowslatest.EBPFAttachTCX, pods, nodes)
+	assert.Equal(t, []flowslatest.AgentAttachModeCount{
+		{Mode: flowslatest.EBPFAttachTCX, Nodes: 4, Unsupported: []string{"node-a"}},
+	}, modes)
+
+	modes = attachModesStatus(flowslatest.EBPFAttachTC, pods, nodes)
+	assert.Equal(t, []flowslatest.AgentAttachModeCount{
+		{Mode: flowslatest.EBPFAttachTC, Nodes: 4},
+	}, modes)
+}
+
+func TestKafkaEnv(t *testing.T) {
+	coll := flowslatest.FlowCollector{}
+	coll.Spec.DeploymentModel = flowslatest.DeploymentModelKafka
+	coll.Spec.Kafka = flowslatest.FlowCollectorKafka{Address: "kafka:9092", Topic: "flows"}
+	coll.Spec.Agent.EBPF.KafkaBatchSize = 1048576
+	c := AgentController{}
+
+	// Batch derived from the agent batch size, no compression
+	env, err := c.envConfig(context.Background(), &coll, map[string]string{})
+	assert.NoError(t, err)
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaBrokers, Value: "kafka:9092"})
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaTopic, Value: "flows"})
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaBatchSize, Value: "1048576"})
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaBatchMessages, Value: "10485"})
+	for _, e := range env {
+		assert.NotEqual(t, envKafkaCompression, e.Name)
+	}
+
+	// Advanced overrides
+	coll.Spec.Kafka.Advanced = &flowslatest.AdvancedKafkaConfig{
+		BatchBytes:  ptr.To(int64(500000)),
+		Compression: flowslatest.KafkaCompressionZstd,
+	}
+	env, err = c.envConfig(context.Background(), &coll, map[string]string{})
+	assert.NoError(t, err)
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaBatchSize, Value: "500000"})
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaBatchMessages, Value: "5000"})
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaCompression, Value: "zstd"})
+
+	coll.Spec.Kafka.Advanced.BatchMessages = ptr.To(int32(200))
+	env, err = c.envConfig(context.Background(), &coll, map[string]string{})
+	assert.NoError(t, err)
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaBatchSize, Value: "500000"})
+	assert.Contains(t, env, corev1.EnvVar{Name: envKafkaBatchMessages, Value: "200"})
+}
@@ -0,0 +1,113 @@
+package ebpf
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+)
+
+// maxUnhealthyNodes is the maximum number of nodes listed in `status.agent.nodes.unhealthy`
+const maxUnhealthyNodes = 10
+
+// agentNodes counts the nodes per eBPF agent state, as also reported in `status.agent.nodes`
+var agentNodes = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "netobserv_operator_agent_nodes",
+		Help: "Number of nodes per eBPF agent state",
+	},
+	[]string{"state"},
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(agentNodes)
+}
+
+// reconcileHealth summarizes the agent pods per node, for the FlowCollector status and the operator metrics.
+// It returns warnings about nodes where the agent cannot run as configured.
+func (c *AgentController) reconcileHealth(ctx context.Context, current *v1.DaemonSet, target *flowslatest.FlowCollectorEBPF) []string {
+	if current == nil {
+		c.Status.SetAgentNodes(nil)
+		c.Status.SetAgentAttachModes(nil)
+		agentNodes.Reset()
+		agentAttachModes.Reset()
+		return nil
+	}
+	// only the agent pods are cached, selected by label
+	pods := corev1.PodList{}
+	if err := c.AgentPods.List(ctx, &pods, client.InNamespace(current.Namespace), client.MatchingLabels{"app": constants.EBPFAgentName}); err != nil {
+		// counts can still be reported from the DaemonSet status
+		log.FromContext(ctx).Error(err, "can't list eBPF agent pods")
+	}
+	nodes := agentNodesStatus(current, pods.Items)
+	c.Status.SetAgentNodes(nodes)
+	agentNodes.WithLabelValues("ready").Set(float64(nodes.Ready))
+	agentNodes.WithLabelValues("not_ready").Set(float64(nodes.Desired - nodes.Ready))
+	agentNodes.WithLabelValues("outdated").Set(float64(nodes.Outdated))
+	return c.reconcileAttachModes(ctx, pods.Items, target.AttachMode)
+}
+
+func agentNodesStatus(ds *v1.DaemonSet, pods []corev1.Pod) *flowslatest.AgentNodesStatus {
+	nodes := flowslatest.AgentNodesStatus{
+		Desired:  ds.Status.DesiredNumberScheduled,
+		Ready:    ds.Status.NumberReady,
+		Outdated: max(0, ds.Status.DesiredNumberScheduled-ds.Status.UpdatedNumberScheduled),
+	}
+	for i := range pods {
+		state := agentNodeState(&pods[i])
+		if !state.Ready || state.Restarts > 0 {
+			nodes.Unhealthy = append(nodes.Unhealthy, state)
+		}
+	}
+	// not ready first, then most restarted
+	sort.Slice(nodes.Unhealthy, func(i, j int) bool {
+		a, b := &nodes.Unhealthy[i], &nodes.Unhealthy[j]
+		if a.Ready != b.Ready {
+			return !a.Ready
+		}
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return a.Node < b.Node
+	})
+	if len(nodes.Unhealthy) > maxUnhealthyNodes {
+		nodes.Unhealthy = nodes.Unhealthy[:maxUnhealthyNodes]
+	}
+	return &nodes
+}
+
+func agentNodeState(pod *corev1.Pod) flowslatest.AgentNodeState {
+	state := flowslatest.AgentNodeState{
+		Node: pod.Spec.NodeName,
+		Pod:  pod.Name,
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			state.Ready = cond.Status == corev1.ConditionTrue
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != constants.EBPFAgentName {
+			continue
+		}
+		state.Restarts = cs.RestartCount
+		state.Image = cs.Image
+		if cs.State.Waiting != nil {
+			state.Reason = cs.State.Waiting.Reason
+		} else if cs.State.Terminated != nil {
+			state.Reason = cs.State.Terminated.Reason
+		}
+	}
+	if state.Reason == "" && !state.Ready {
+		state.Reason = pod.Status.Reason
+	}
+	return state
+}
@@ -129,6 +129,11 @@ func (c *Reconciler) reconcileVendorPermissions(
 	ctx context.Context, desired *flowslatest.FlowCollectorEBPF,
 ) error {
 	if c.UseOpenShiftSCC {
+		if c.Namespaced {
+			// SecurityContextConstraints are cluster-scoped: they are applied by an administrator
+			log.FromContext(ctx).Info("The operator only watches some namespaces: SecurityContextConstraints not reconciled", "name", constants.EBPFSecurityContext)
+			return nil
+		}
 		return c.reconcileOpenshiftPermissions(ctx, desired)
 	}
 	return nil
@@ -0,0 +1,22 @@
+package permissions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+)
+
+func TestSCCNotReconciledWhenNamespaced(t *testing.T) {
+	// no client: the SecurityContextConstraints must not be read nor written
+	r := NewReconciler(&reconcilers.Instance{Common: &reconcilers.Common{
+		Namespace:       "netobserv",
+		UseOpenShiftSCC: true,
+		Namespaced:      true,
+	}})
+	err := r.reconcileVendorPermissions(context.Background(), &flowslatest.FlowCollectorEBPF{Privileged: true})
+	assert.NoError(t, err)
+}
@@ -3,6 +3,8 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	osv1alpha1 "github.com/openshift/api/console/v1alpha1"
 	securityv1 "github.com/openshift/api/security/v1"
@@ -12,14 +14,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 	"github.com/netobserv/network-observability-operator/controllers/consoleplugin"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/ebpf"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
 	"github.com/netobserv/network-observability-operator/pkg/cleanup"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/loki"
 	"github.com/netobserv/network-observability-operator/pkg/manager"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
 	"github.com/netobserv/network-observability-operator/pkg/watchers"
@@ -32,18 +38,31 @@ const (
 // FlowCollectorReconciler reconciles a FlowCollector object
 type FlowCollectorReconciler struct {
 	client.Client
-	mgr     *manager.Manager
-	status  status.Instance
-	watcher *watchers.Watcher
+	mgr           *manager.Manager
+	status        status.Instance
+	agentStatus   status.Instance
+	pluginStatus  status.Instance
+	queryStatus   status.Instance
+	watcher       *watchers.Watcher
+	agentRequeue  *reconcilers.RequeueDelay
+	pluginRequeue *reconcilers.RequeueDelay
+	queryRequeue  *reconcilers.RequeueDelay
 }
 
 func Start(ctx context.Context, mgr *manager.Manager) error {
 	log := log.FromContext(ctx)
 	log.Info("Starting FlowCollector controller")
 	r := FlowCollectorReconciler{
-		Client: mgr.Client,
-		mgr:    mgr,
-		status: mgr.Status.ForComponent(status.FlowCollectorLegacy),
+		Client:       mgr.Client,
+		mgr:          mgr,
+		status:       mgr.Status.ForComponent(status.FlowCollectorLegacy),
+		agentStatus:  mgr.Status.ForComponent(status.EBPFAgent),
+		pluginStatus: mgr.Status.ForComponent(status.ConsolePlugin),
+		queryStatus:  mgr.Status.ForComponent(status.QueryService),
+		// agent, console plugin and query service are retried independently, so that one failing doesn't hold the others
+		agentRequeue:  reconcilers.NewRequeueDelay("ebpf-agent"),
+		pluginRequeue: reconcilers.NewRequeueDelay("console-plugin"),
+		queryRequeue:  reconcilers.NewRequeueDelay("query-service"),
 	}
 
 	builder := ctrl.NewControllerManagedBy(mgr.Manager).
@@ -57,7 +76,10 @@ func Start(ctx context.Context, mgr *manager.Manager) error {
 		Owns(&corev1.ServiceAccount{})
 
 	if mgr.IsOpenShift() {
-		builder.Owns(&securityv1.SecurityContextConstraints{})
+		if !mgr.Config.IsNamespaced() {
+			builder.Owns(&securityv1.SecurityContextConstraints{})
+		}
+		helper.WatchClusterProxy(builder)
 	}
 	if mgr.HasConsolePlugin() {
 		builder.Owns(&osv1alpha1.ConsolePlugin{})
@@ -68,6 +90,18 @@ func Start(ctx context.Context, mgr *manager.Manager) error {
 		log.Info("CNO not detected: using ovnKubernetes config and reconciler")
 	}
 
+	if mgr.HasLokiStack() {
+		loki.WatchLokiStacks(builder)
+	}
+
+	// reconcile again when APIs such as ServiceMonitor get installed or removed
+	builder.WatchesRawSource(
+		mgr.APIChangesSource(),
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}),
+	)
+
 	ctrl, err := builder.Build(&r)
 	if err != nil {
 		return err
@@ -104,70 +138,130 @@ func (r *FlowCollectorReconciler) Reconcile(ctx context.Context, _ ctrl.Request)
 	r.status.SetReady()
 	defer r.status.Commit(ctx, r.Client)
 
-	err = r.reconcile(ctx, clh, desired)
+	requeueAfter, err := r.reconcile(ctx, clh, desired)
 	if err != nil {
 		l.Error(err, "FlowCollector reconcile failure")
 		// Set status failure unless it was already set
 		if !r.status.HasFailure() {
-			r.status.SetFailure("FlowCollectorGenericError", err.Error())
+			_ = r.status.Error("FlowCollectorGenericError", err)
+		}
+		if status.KindOf(err) == status.ErrorInvalidSpec {
+			// not retried: a fix requires a change, which triggers a new reconcile
+			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	r.status.SetObservedGeneration(desired.Generation)
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (r *FlowCollectorReconciler) reconcile(ctx context.Context, clh *helper.Client, desired *flowslatest.FlowCollector) error {
+// reconcile returns an error when the reconcile can't proceed; failures of the agent and console plugin are reported in their own status instead,
+// with the delay after which they should be retried
+func (r *FlowCollectorReconciler) reconcile(ctx context.Context, clh *helper.Client, desired *flowslatest.FlowCollector) (time.Duration, error) {
+	if err := r.mgr.Config.CheckFlowCollector(&desired.Spec); err != nil {
+		return 0, r.status.Error("NamespacedInstall", err)
+	}
 	ns := helper.GetNamespace(&desired.Spec)
 	previousNamespace := r.status.GetDeployedNamespace(desired)
-	loki := helper.NewLokiConfig(&desired.Spec.Loki, ns)
-	reconcilersInfo := r.newCommonInfo(clh, ns, previousNamespace, &loki)
+	lokiConfig := helper.GetLokiConfig(&desired.Spec, ns)
+	proxy := helper.GetProxyConfig(ctx, r.Client, r.mgr.IsOpenShift(), &desired.Spec.Proxy)
+	if r.mgr.HasLokiStack() {
+		if err := loki.ApplyLokiStack(ctx, r.Client, &desired.Spec, &lokiConfig); err != nil {
+			return 0, r.status.Error("LokiStackError", err)
+		}
+	}
+	// only the console plugin uses Loki here, which reads from the migration target when selected
+	reconcilersInfo := r.newCommonInfo(clh, ns, previousNamespace, helper.GetLokiReadConfig(&desired.Spec, &lokiConfig), &proxy)
 
 	if err := r.checkFinalizer(ctx, desired); err != nil {
-		return err
+		return 0, err
 	}
 
 	if err := cleanup.CleanPastReferences(ctx, r.Client, ns); err != nil {
-		return err
+		return 0, err
 	}
 	r.watcher.Reset(ns)
 
 	// Create reconcilers
 	var cpReconciler consoleplugin.CPReconciler
 	if r.mgr.HasConsolePlugin() {
-		cpReconciler = consoleplugin.NewReconciler(reconcilersInfo.NewInstance(r.mgr.Config.ConsolePluginImage, r.status))
+		cpReconciler = consoleplugin.NewReconciler(reconcilersInfo.NewInstance(r.mgr.Config.ConsolePluginImage, r.pluginStatus))
 	}
+	// The query service runs the plugin backend on its own: it doesn't need the console
+	qsReconciler := consoleplugin.NewQueryServiceReconciler(reconcilersInfo.NewInstance(r.mgr.Config.ConsolePluginImage, r.queryStatus))
 
 	// Check namespace changed
 	if ns != previousNamespace {
-		if previousNamespace != "" && r.mgr.HasConsolePlugin() {
+		if previousNamespace != "" {
 			// Namespace updated, clean up previous namespace
 			log.FromContext(ctx).
 				Info("FlowCollector namespace change detected: cleaning up previous namespace", "old", previousNamespace, "new", ns)
-			cpReconciler.CleanupNamespace(ctx)
+			if r.mgr.HasConsolePlugin() {
+				cpReconciler.CleanupNamespace(ctx)
+			}
+			qsReconciler.CleanupNamespace(ctx)
 		}
 
 		// Update namespace in status
 		if err := r.status.SetDeployedNamespace(ctx, r.Client, ns); err != nil {
-			return r.status.Error("ChangeNamespaceError", err)
+			return 0, r.status.Error("ChangeNamespaceError", err)
 		}
 	}
 
+	// Agent, console plugin and query service don't depend on each other: reconcile them concurrently, so that a slow one doesn't delay the others
+	var wg sync.WaitGroup
+	var agentRetry, pluginRetry, queryRetry time.Duration
+
 	// eBPF agent
-	ebpfAgentController := ebpf.NewAgentController(reconcilersInfo.NewInstance(r.mgr.Config.EBPFAgentImage, r.status))
-	if err := ebpfAgentController.Reconcile(ctx, desired); err != nil {
-		return r.status.Error("ReconcileAgentFailed", err)
-	}
+	ebpfAgentController := ebpf.NewAgentController(reconcilersInfo.NewInstance(r.mgr.Config.EBPFAgentImage, r.agentStatus))
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		agentRetry = r.reconcileComponent(ctx, desired, &r.agentStatus, r.agentRequeue, "ReconcileAgentFailed", func() error {
+			return ebpfAgentController.Reconcile(ctx, desired)
+		})
+	}()
 
 	// Console plugin
 	if r.mgr.HasConsolePlugin() {
-		err := cpReconciler.Reconcile(ctx, desired)
-		if err != nil {
-			return r.status.Error("ReconcileConsolePluginFailed", err)
-		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pluginRetry = r.reconcileComponent(ctx, desired, &r.pluginStatus, r.pluginRequeue, "ReconcileConsolePluginFailed", func() error {
+				return cpReconciler.Reconcile(ctx, desired)
+			})
+		}()
+	} else {
+		r.pluginStatus.SetUnused("Console not detected: the console plugin is not available")
 	}
 
-	return nil
+	// Query service
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		queryRetry = r.reconcileComponent(ctx, desired, &r.queryStatus, r.queryRequeue, "ReconcileQueryServiceFailed", func() error {
+			return qsReconciler.Reconcile(ctx, desired)
+		})
+	}()
+
+	wg.Wait()
+	return reconcilers.MinRequeue(agentRetry, pluginRetry, queryRetry), nil
+}
+
+// reconcileComponent runs a component reconcile with its own requeue delay and status, and returns the delay after which it should be retried when failing
+func (r *FlowCollectorReconciler) reconcileComponent(ctx context.Context, desired *flowslatest.FlowCollector, cpntStatus *status.Instance,
+	requeue *reconcilers.RequeueDelay, step string, reconcile func() error) time.Duration {
+	retryAfter, err := requeue.Run(desired.Generation, func() error {
+		// same workflow as the legacy status: start as ready, then degrade if necessary
+		cpntStatus.SetReady()
+		return reconcile()
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Component reconcile failure", "component", cpntStatus.Name(), "retryAfter", retryAfter)
+		_ = cpntStatus.Error(step, err)
+	}
+	return retryAfter
 }
 
 func (r *FlowCollectorReconciler) checkFinalizer(ctx context.Context, desired *flowslatest.FlowCollector) error {
@@ -181,15 +275,18 @@ func (r *FlowCollectorReconciler) checkFinalizer(ctx context.Context, desired *f
 	return nil
 }
 
-func (r *FlowCollectorReconciler) newCommonInfo(clh *helper.Client, ns, prevNs string, loki *helper.LokiConfig) reconcilers.Common {
+func (r *FlowCollectorReconciler) newCommonInfo(clh *helper.Client, ns, prevNs string, loki *helper.LokiConfig, proxy *helper.ProxyConfig) reconcilers.Common {
 	return reconcilers.Common{
 		Client:            *clh,
 		Namespace:         ns,
 		PreviousNamespace: prevNs,
 		UseOpenShiftSCC:   r.mgr.IsOpenShift(),
-		AvailableAPIs:     &r.mgr.AvailableAPIs,
+		AvailableAPIs:     r.mgr.AvailableAPIs,
 		Watcher:           r.watcher,
 		Loki:              loki,
+		Proxy:             proxy,
 		IsDownstream:      r.mgr.Config.DownstreamDeployment,
+		Namespaced:        r.mgr.Config.IsNamespaced(),
+		AgentPods:         r.mgr.AgentPods,
 	}
 }
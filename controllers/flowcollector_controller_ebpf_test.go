@@ -112,6 +112,7 @@ func flowCollectorEBPFSpecs() {
 				v1.EnvVar{Name: "CACHE_ACTIVE_TIMEOUT", Value: "15s"},
 				v1.EnvVar{Name: "CACHE_MAX_FLOWS", Value: "100"},
 				v1.EnvVar{Name: "LOG_LEVEL", Value: "trace"},
+				v1.EnvVar{Name: "TC_ATTACH_MODE", Value: "any"},
 				v1.EnvVar{Name: "INTERFACES", Value: "veth0,/^br-/"},
 				v1.EnvVar{Name: "EXCLUDE_INTERFACES", Value: "br-3,lo"},
 				v1.EnvVar{Name: "BUFFERS_LENGTH", Value: "100"},
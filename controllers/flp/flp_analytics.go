@@ -0,0 +1,234 @@
+package flp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/metrics"
+)
+
+const (
+	anomalyBaseMetric = "workload_ingress_bytes_total"
+	anomalyRateRecord = "netobserv:workload_ingress_bytes:rate5m"
+	anomalyScore      = "netobserv_anomaly_score"
+
+	synOnlyMetric        = "security_syn_only_flows_total"
+	failedHandshakes     = "netobserv:security_failed_handshakes:5m"
+	synFloodRateRecord   = "netobserv:security_syn_only_flows:rate5m"
+	defaultScanThreshold = 100
+	defaultSynThreshold  = 100
+
+	egressBudgetMetric = "namespace_external_egress_bytes_total"
+	egressBudgetRecord = "netobserv:namespace_external_egress_bits:rate5m"
+)
+
+// analyticsMetrics returns the additional flowlogs-pipeline metrics needed by the analytics rules
+func analyticsMetrics(spec *flowslatest.FlowCollectorAnalytics) []api.MetricsItem {
+	var items []api.MetricsItem
+	if helper.IsSecurityAnalyticsEnabled(spec) {
+		// TCP flags are aggregated per flow: a value of 2 means that only SYN was seen, i.e. the handshake was not completed.
+		// The destination port is not a label, as it would grow the cardinality with the number of ports scanned.
+		items = append(items, api.MetricsItem{
+			Name: synOnlyMetric,
+			Type: "counter",
+			Filters: []api.MetricsFilter{
+				{Key: "Duplicate", Value: "true", Type: api.MetricFilterNotEqual},
+				{Key: "Proto", Value: "6", Type: api.MetricFilterEqual},
+				{Key: "Flags", Value: "2", Type: api.MetricFilterEqual},
+			},
+			Labels: []string{"SrcK8S_Namespace", "SrcK8S_OwnerName", "DstK8S_Namespace", "DstK8S_OwnerName"},
+		})
+	}
+	if len(spec.EgressBudgets) > 0 {
+		// only the budgeted namespaces are counted, to keep the cardinality low
+		namespaces := make([]string, 0, len(spec.EgressBudgets))
+		for i := range spec.EgressBudgets {
+			namespaces = append(namespaces, spec.EgressBudgets[i].Namespace)
+		}
+		// destinations outside of the cluster are not resolved to a Kubernetes resource
+		items = append(items, api.MetricsItem{
+			Name:     egressBudgetMetric,
+			Type:     "counter",
+			ValueKey: "Bytes",
+			Filters: []api.MetricsFilter{
+				{Key: "Duplicate", Value: "true", Type: api.MetricFilterNotEqual},
+				{Key: "FlowDirection", Value: "1|2", Type: api.MetricFilterRegex},
+				{Key: "SrcK8S_Namespace", Value: "^(" + strings.Join(namespaces, "|") + ")$", Type: api.MetricFilterRegex},
+				{Key: "DstK8S_Type", Type: api.MetricFilterAbsence},
+			},
+			Labels: []string{"SrcK8S_Namespace"},
+		})
+	}
+	return items
+}
+
+// analyticsWarnings reports the analytics settings that can't take effect with the current metrics configuration
+func analyticsWarnings(spec *flowslatest.FlowCollectorSpec) []string {
+	if helper.IsAnomalyDetectionEnabled(&spec.Analytics) && !slices.Contains(metrics.GetIncludeList(spec), anomalyBaseMetric) {
+		return []string{fmt.Sprintf("anomaly detection is enabled, but %s is not in spec.processor.metrics.includeList: anomaly rules are not created", anomalyBaseMetric)}
+	}
+	return nil
+}
+
+// ownsClusterRules returns true when this processor receives flows from the agents.
+// Since metrics are aggregated in Prometheus, cluster-wide rules are only defined by this processor,
+// to avoid duplicates when an additional transformer consumes flows from `spec.processor.kafkaSource`.
+func (b *builder) ownsClusterRules() bool {
+	return b.confKind != ConfKafkaTransformer || helper.UseKafka(b.desired)
+}
+
+// analyticsRules returns the recording and alerting rules deriving insights from the flow metrics.
+func (b *builder) analyticsRules() []monitoringv1.Rule {
+	if !b.ownsClusterRules() {
+		return nil
+	}
+	prefix := metrics.GetPrefix(b.desired)
+	var rules []monitoringv1.Rule
+	if helper.IsAnomalyDetectionEnabled(&b.desired.Analytics) && slices.Contains(metrics.GetIncludeList(b.desired), anomalyBaseMetric) {
+		rules = append(rules, anomalyDetectionRules(&b.desired.Analytics.AnomalyDetection, prefix)...)
+	}
+	if helper.IsSecurityAnalyticsEnabled(&b.desired.Analytics) {
+		rules = append(rules, securityRules(&b.desired.Analytics.Security, prefix)...)
+	}
+	if len(b.desired.Analytics.EgressBudgets) > 0 {
+		rules = append(rules, egressBudgetRules(b.desired.Analytics.EgressBudgets, prefix, helper.GetSampling(b.desired))...)
+	}
+	return rules
+}
+
+func anomalyDetectionRules(spec *flowslatest.AnomalyDetection, prefix string) []monitoringv1.Rule {
+	window := model.Duration(24 * time.Hour)
+	if spec.BaselineWindow != nil {
+		window = model.Duration(spec.BaselineWindow.Duration)
+	}
+	threshold := int32(3)
+	if spec.Threshold != nil {
+		threshold = *spec.Threshold
+	}
+	d := monitoringv1.Duration("10m")
+	return []monitoringv1.Rule{
+		{
+			Record: anomalyRateRecord,
+			Expr:   intstr.FromString(fmt.Sprintf("sum by (DstK8S_Namespace, DstK8S_OwnerName, DstK8S_OwnerType) (rate(%s%s[5m]))", prefix, anomalyBaseMetric)),
+		},
+		{
+			// Score is the number of standard deviations from the baseline; workloads with a flat baseline are ignored
+			Record: anomalyScore,
+			Expr: intstr.FromString(fmt.Sprintf(
+				"(%[1]s - avg_over_time(%[1]s[%[2]s])) / (stddev_over_time(%[1]s[%[2]s]) > 0)",
+				anomalyRateRecord,
+				window.String(),
+			)),
+		},
+		{
+			Alert: "NetObservTrafficAnomaly",
+			Annotations: map[string]string{
+				"description": "Ingress traffic of workload {{ $labels.DstK8S_OwnerName }} in namespace {{ $labels.DstK8S_Namespace }} deviates from its baseline by {{ $value | humanize }} standard deviations.",
+				"summary":     "NetObserv detected a traffic anomaly",
+			},
+			Expr: intstr.FromString(fmt.Sprintf("abs(%s) > %d", anomalyScore, threshold)),
+			For:  &d,
+			Labels: map[string]string{
+				"severity": "warning",
+				"app":      "netobserv",
+			},
+		},
+	}
+}
+
+func securityRules(spec *flowslatest.SecurityAnalytics, prefix string) []monitoringv1.Rule {
+	rules := []monitoringv1.Rule{
+		{
+			// Connection attempts of a source workload without completing the TCP handshake: each probed port is a distinct flow
+			Record: failedHandshakes,
+			Expr:   intstr.FromString(fmt.Sprintf("sum by (SrcK8S_Namespace, SrcK8S_OwnerName) (increase(%s%s[5m]))", prefix, synOnlyMetric)),
+		},
+		{
+			Record: synFloodRateRecord,
+			Expr:   intstr.FromString(fmt.Sprintf("sum by (DstK8S_Namespace, DstK8S_OwnerName) (rate(%s%s[5m]))", prefix, synOnlyMetric)),
+		},
+	}
+	if spec.Alerts != nil && !*spec.Alerts {
+		return rules
+	}
+	scanThreshold := int32(defaultScanThreshold)
+	if spec.PortScanThreshold != nil {
+		scanThreshold = *spec.PortScanThreshold
+	}
+	synThreshold := int32(defaultSynThreshold)
+	if spec.SynFloodThreshold != nil {
+		synThreshold = *spec.SynFloodThreshold
+	}
+	d := monitoringv1.Duration("5m")
+	return append(rules,
+		monitoringv1.Rule{
+			Alert: "NetObservPortScan",
+			Annotations: map[string]string{
+				"description": "Workload {{ $labels.SrcK8S_OwnerName }} in namespace {{ $labels.SrcK8S_Namespace }} attempted {{ $value | humanize }} connections without completing the TCP handshake in the last 5 minutes.",
+				"summary":     "NetObserv detected a possible port scan",
+			},
+			Expr: intstr.FromString(fmt.Sprintf("%s > %d", failedHandshakes, scanThreshold)),
+			For:  &d,
+			Labels: map[string]string{
+				"severity": "warning",
+				"app":      "netobserv",
+			},
+		},
+		monitoringv1.Rule{
+			Alert: "NetObservSYNFlood",
+			Annotations: map[string]string{
+				"description": "Workload {{ $labels.DstK8S_OwnerName }} in namespace {{ $labels.DstK8S_Namespace }} receives {{ $value | humanize }} TCP connection attempts per second that do not complete the handshake.",
+				"summary":     "NetObserv detected a possible SYN flood",
+			},
+			Expr: intstr.FromString(fmt.Sprintf("%s > %d", synFloodRateRecord, synThreshold)),
+			For:  &d,
+			Labels: map[string]string{
+				"severity": "warning",
+				"app":      "netobserv",
+			},
+		},
+	)
+}
+
+// egressBudgetRules records the egress bandwidth of the budgeted namespaces, extrapolated from the sampled flows, and alerts
+// on each namespace exceeding its budget
+func egressBudgetRules(budgets []flowslatest.EgressBudget, prefix string, sampling int) []monitoringv1.Rule {
+	rules := []monitoringv1.Rule{
+		{
+			Record: egressBudgetRecord,
+			Expr:   intstr.FromString(fmt.Sprintf("sum by (SrcK8S_Namespace) (rate(%s%s[5m])) * 8 * %d", prefix, egressBudgetMetric, max(sampling, 1))),
+		},
+	}
+	d := monitoringv1.Duration("5m")
+	for i := range budgets {
+		budget := &budgets[i]
+		severity := budget.Severity
+		if severity == "" {
+			severity = "warning"
+		}
+		rules = append(rules, monitoringv1.Rule{
+			Alert: "NetObservEgressBudgetExceeded",
+			Annotations: map[string]string{
+				"description": fmt.Sprintf("Namespace {{ $labels.SrcK8S_Namespace }} sends {{ $value | humanize }}bps outside of the cluster, exceeding its budget of %d Mbps.", budget.MaxMbps),
+				"summary":     "NetObserv detected a namespace exceeding its egress bandwidth budget",
+			},
+			Expr: intstr.FromString(fmt.Sprintf(`%s{SrcK8S_Namespace="%s"} > %d`, egressBudgetRecord, budget.Namespace, int64(budget.MaxMbps)*1_000_000)),
+			For:  &d,
+			Labels: map[string]string{
+				"severity":  severity,
+				"app":       "netobserv",
+				"namespace": budget.Namespace,
+			},
+		})
+	}
+	return rules
+}
@@ -0,0 +1,168 @@
+package flp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
+)
+
+const (
+	flowRateQuery              = "sum(rate(netobserv_ingest_flows_processed[5m]))"
+	lowTrafficFlowsPerSecond   = 1000
+	highTrafficFlowsPerSecond  = 20000
+	batchTuningHysteresis      = 0.2
+	defaultBatchTuningInterval = 5 * time.Minute
+	minBatchTuningInterval     = time.Minute
+)
+
+type batchSizes struct {
+	lokiWriteBatchSize     int64
+	lokiWriteBatchWait     time.Duration
+	kafkaConsumerBatchSize int
+}
+
+// batchProfiles are the bounds of the auto-tuning: short waits and small fetches at low traffic, large batches at peak
+var batchProfiles = map[flowslatest.BatchProfile]batchSizes{
+	flowslatest.BatchProfileLowTraffic:  {lokiWriteBatchSize: 100 * 1024, lokiWriteBatchWait: 500 * time.Millisecond, kafkaConsumerBatchSize: 1024 * 1024},
+	flowslatest.BatchProfileDefault:     {lokiWriteBatchSize: 100 * 1024, lokiWriteBatchWait: time.Second, kafkaConsumerBatchSize: 10 * 1024 * 1024},
+	flowslatest.BatchProfileHighTraffic: {lokiWriteBatchSize: 1024 * 1024, lokiWriteBatchWait: 2 * time.Second, kafkaConsumerBatchSize: 50 * 1024 * 1024},
+}
+
+func batchTuningInterval(spec *flowslatest.FLPBatchAutoTuning) time.Duration {
+	if spec.Interval == nil || spec.Interval.Duration <= 0 {
+		return defaultBatchTuningInterval
+	}
+	return max(spec.Interval.Duration, minBatchTuningInterval)
+}
+
+// nextBatchProfile returns the profile matching the flow rate, moving by at most one step from the current profile.
+// The rate must cross a threshold by the hysteresis margin, as each change restarts flowlogs-pipeline.
+func nextBatchProfile(current flowslatest.BatchProfile, flowsPerSecond float64) flowslatest.BatchProfile {
+	switch current {
+	case flowslatest.BatchProfileLowTraffic:
+		if flowsPerSecond > lowTrafficFlowsPerSecond*(1+batchTuningHysteresis) {
+			return flowslatest.BatchProfileDefault
+		}
+		return current
+	case flowslatest.BatchProfileHighTraffic:
+		if flowsPerSecond < highTrafficFlowsPerSecond*(1-batchTuningHysteresis) {
+			return flowslatest.BatchProfileDefault
+		}
+		return current
+	default:
+		if flowsPerSecond < lowTrafficFlowsPerSecond*(1-batchTuningHysteresis) {
+			return flowslatest.BatchProfileLowTraffic
+		}
+		if flowsPerSecond > highTrafficFlowsPerSecond*(1+batchTuningHysteresis) {
+			return flowslatest.BatchProfileHighTraffic
+		}
+		return flowslatest.BatchProfileDefault
+	}
+}
+
+func newBatchTuning(profile flowslatest.BatchProfile) *flowslatest.BatchTuningStatus {
+	sizes := batchProfiles[profile]
+	return &flowslatest.BatchTuningStatus{
+		Profile:                profile,
+		LokiWriteBatchSize:     sizes.lokiWriteBatchSize,
+		LokiWriteBatchWait:     &metav1.Duration{Duration: sizes.lokiWriteBatchWait},
+		KafkaConsumerBatchSize: sizes.kafkaConsumerBatchSize,
+	}
+}
+
+// updateBatchTuning returns the batch sizes for the flow rate observed at this time
+func updateBatchTuning(current *flowslatest.BatchTuningStatus, flowsPerSecond float64, now time.Time) *flowslatest.BatchTuningStatus {
+	next := newBatchTuning(nextBatchProfile(current.Profile, flowsPerSecond))
+	next.FlowsPerSecond = int64(math.Round(flowsPerSecond))
+	next.LastCheckTime = &metav1.Time{Time: now}
+	if next.Profile != current.Profile {
+		next.LastTransitionTime = &metav1.Time{Time: now}
+	} else {
+		next.LastTransitionTime = current.LastTransitionTime
+	}
+	return next
+}
+
+// withBatchTuning returns the spec with the batch sizes replaced by the tuned ones, if any
+func withBatchTuning(desired *flowslatest.FlowCollectorSpec, tuning *flowslatest.BatchTuningStatus) *flowslatest.FlowCollectorSpec {
+	if tuning == nil {
+		return desired
+	}
+	tuned := *desired
+	tuned.Loki.WriteBatchSize = tuning.LokiWriteBatchSize
+	tuned.Loki.WriteBatchWait = tuning.LokiWriteBatchWait
+	tuned.Processor.KafkaConsumerBatchSize = tuning.KafkaConsumerBatchSize
+	return &tuned
+}
+
+// tuneBatches returns the batch sizes to use, or nil when auto-tuning is disabled. The flow rate is queried once per interval;
+// in between, or when the query fails, the current batch sizes are kept.
+func (r *Reconciler) tuneBatches(ctx context.Context, fc *flowslatest.FlowCollector, now time.Time) (*flowslatest.BatchTuningStatus, error) {
+	if !helper.IsBatchAutoTuningEnabled(&fc.Spec.Processor) {
+		r.batchTuning = nil
+		r.closeBatchQuerier()
+		return nil, nil
+	}
+	spec := &fc.Spec.Processor.BatchAutoTuning
+	current := r.batchTuning
+	if current == nil {
+		// resume from the status after an operator restart, rather than restarting flowlogs-pipeline with the default profile
+		current = fc.Status.BatchTuning
+		if current == nil {
+			current = newBatchTuning(flowslatest.BatchProfileDefault)
+		}
+		r.batchTuning = current
+	}
+	if current.LastCheckTime != nil && now.Sub(current.LastCheckTime.Time) < batchTuningInterval(spec) {
+		return current, nil
+	}
+
+	if r.batchQuerier == nil || !r.batchQuerier.Matches(spec.URL, spec.InsecureSkipVerify) {
+		r.closeBatchQuerier()
+		r.batchQuerier = prometheus.NewQuerier(spec.URL, spec.InsecureSkipVerify, r.mgr.GetConfig())
+	}
+	samples, err := r.batchQuerier.Query(ctx, flowRateQuery)
+	if err != nil || len(samples) == 0 {
+		// retried at the next interval
+		checked := current.DeepCopy()
+		checked.LastCheckTime = &metav1.Time{Time: now}
+		r.batchTuning = checked
+		if err != nil {
+			return checked, fmt.Errorf("could not query the flow rate: %w", err)
+		}
+		return checked, nil
+	}
+
+	next := updateBatchTuning(current, samples[0].Value, now)
+	if next.Profile != current.Profile {
+		log.FromContext(ctx).Info("Batch profile changed",
+			"from", current.Profile, "to", next.Profile, "flowsPerSecond", next.FlowsPerSecond,
+			"lokiWriteBatchSize", next.LokiWriteBatchSize, "lokiWriteBatchWait", next.LokiWriteBatchWait.Duration,
+			"kafkaConsumerBatchSize", next.KafkaConsumerBatchSize)
+	}
+	r.batchTuning = next
+	return next, nil
+}
+
+// nextBatchCheck returns the delay until the flow rate must be checked again, or zero when auto-tuning is disabled
+func (r *Reconciler) nextBatchCheck(spec *flowslatest.FlowCollectorFLP, now time.Time) time.Duration {
+	if r.batchTuning == nil || r.batchTuning.LastCheckTime == nil {
+		return 0
+	}
+	return max(batchTuningInterval(&spec.BatchAutoTuning)-now.Sub(r.batchTuning.LastCheckTime.Time), time.Second)
+}
+
+func (r *Reconciler) closeBatchQuerier() {
+	if r.batchQuerier != nil {
+		r.batchQuerier.Close()
+		r.batchQuerier = nil
+	}
+}
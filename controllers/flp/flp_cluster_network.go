@@ -0,0 +1,60 @@
+package flp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const ovnKubernetesNetworkType = "OVNKubernetes"
+
+// readClusterNetwork returns the cluster network configuration applied by the Cluster Network Operator, or the desired one
+// while it is not applied yet
+func readClusterNetwork(ctx context.Context, cl client.Reader) (*flowslatest.ClusterNetworkStatus, error) {
+	network := configv1.Network{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: "cluster"}, &network); err != nil {
+		return nil, fmt.Errorf("can't get Network information: %w", err)
+	}
+	return clusterNetworkFrom(&network), nil
+}
+
+func clusterNetworkFrom(network *configv1.Network) *flowslatest.ClusterNetworkStatus {
+	res := flowslatest.ClusterNetworkStatus{
+		NetworkType:  network.Status.NetworkType,
+		MTU:          network.Status.ClusterNetworkMTU,
+		ServiceCIDRs: slices.Clone(network.Status.ServiceNetwork),
+	}
+	clusterNetwork := network.Status.ClusterNetwork
+	if len(clusterNetwork) == 0 {
+		clusterNetwork = network.Spec.ClusterNetwork
+	}
+	for _, podsNet := range clusterNetwork {
+		res.PodCIDRs = append(res.PodCIDRs, podsNet.CIDR)
+	}
+	if len(res.ServiceCIDRs) == 0 {
+		res.ServiceCIDRs = slices.Clone(network.Spec.ServiceNetwork)
+	}
+	if res.NetworkType == "" {
+		res.NetworkType = network.Spec.NetworkType
+	}
+	return &res
+}
+
+// clusterNetworkWarnings reports the enabled features that aren't supported by the cluster network type
+func clusterNetworkWarnings(network *flowslatest.ClusterNetworkStatus, spec *flowslatest.FlowCollectorSpec) []string {
+	if network == nil || network.NetworkType == "" || network.NetworkType == ovnKubernetesNetworkType {
+		return nil
+	}
+	var warnings []string
+	if helper.IsOVNObservabilityEnabled(&spec.Agent) {
+		warnings = append(warnings, fmt.Sprintf("OVN observability is enabled, but the cluster network type is %s: network events are not reported", network.NetworkType))
+	}
+	return warnings
+}
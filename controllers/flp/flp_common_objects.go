@@ -4,21 +4,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"maps"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/metrics"
+	"github.com/netobserv/network-observability-operator/pkg/overrides"
 	"github.com/netobserv/network-observability-operator/pkg/volumes"
 )
 
@@ -27,10 +35,15 @@ const (
 	configPath              = "/etc/flowlogs-pipeline"
 	configFile              = "config.json"
 	healthServiceName       = "health"
+	metricsTierLabel        = "netobserv.io/metrics-tier"
+	metricsTierHealth       = "health"
+	metricsTierFlows        = "flows"
 	prometheusServiceName   = "prometheus"
 	profilePortName         = "pprof"
 	healthTimeoutSeconds    = 5
+	healthFailureThreshold  = 3
 	livenessPeriodSeconds   = 10
+	readinessPeriodSeconds  = 10
 	startupFailureThreshold = 5
 	startupPeriodSeconds    = 10
 )
@@ -41,12 +54,14 @@ const (
 	ConfMonolith         ConfKind = "allInOne"
 	ConfKafkaIngester    ConfKind = "kafkaIngester"
 	ConfKafkaTransformer ConfKind = "kafkaTransformer"
+	ConfLoadGenerator    ConfKind = "loadGenerator"
 )
 
 var FlpConfSuffix = map[ConfKind]string{
 	ConfMonolith:         "",
 	ConfKafkaIngester:    "-ingester",
 	ConfKafkaTransformer: "-transformer",
+	ConfLoadGenerator:    "-loadgen",
 }
 
 type Builder struct {
@@ -62,6 +77,8 @@ type Builder struct {
 	loki            *helper.LokiConfig
 	pipeline        *PipelineBuilder
 	isDownstream    bool
+	// presetIngest is the ingest stage of pipelines built without one, such as the synthetic ingest of the load generator
+	presetIngest *config.StageParam
 }
 
 type builder = Builder
@@ -86,6 +103,25 @@ func NewBuilder(info *reconcilers.Instance, desired *flowslatest.FlowCollectorSp
 	case flowslatest.ServerTLSDisabled:
 		// nothing to do there
 	}
+	if helper.IsMetricsRBACProxyEnabled(&desired.Processor.Metrics) {
+		if promTLS == nil {
+			return builder{}, fmt.Errorf("processor metrics rbacProxy requires the metrics server TLS to be enabled")
+		}
+		if desired.Processor.Metrics.Server.Port == rbacProxyUpstreamPort {
+			return builder{}, fmt.Errorf("processor metrics server port %d is reserved when rbacProxy is enabled", rbacProxyUpstreamPort)
+		}
+	}
+	if helper.IsExternalIngestEnabled(&desired.Processor) {
+		ingest := &desired.Processor.ExternalIngest
+		if ingest.Authentication == flowslatest.ExternalIngestAuthMTLS && ingest.ClientCA == nil {
+			return builder{}, fmt.Errorf("processor externalIngest authentication set to MTLS but no clientCA is provided")
+		}
+		if ingest.Port == *helper.GetAdvancedProcessorConfig(desired.Processor.Advanced).Port {
+			return builder{}, fmt.Errorf("processor externalIngest port %d is already used by the flow ingest", ingest.Port)
+		}
+	}
+	// batch auto-tuning overrides the configured batch sizes
+	desired = withBatchTuning(desired, info.BatchTuning)
 	return builder{
 		info: info,
 		labels: map[string]string{
@@ -105,19 +141,21 @@ func NewBuilder(info *reconcilers.Instance, desired *flowslatest.FlowCollectorSp
 	}, nil
 }
 
-func name(ck ConfKind) string                 { return constants.FLPName + FlpConfSuffix[ck] }
-func RoleBindingName(ck ConfKind) string      { return name(ck) + "-role" }
-func RoleBindingMonoName(ck ConfKind) string  { return name(ck) + "-role-mono" }
-func promServiceName(ck ConfKind) string      { return name(ck) + "-prom" }
-func configMapName(ck ConfKind) string        { return name(ck) + "-config" }
-func serviceMonitorName(ck ConfKind) string   { return name(ck) + "-monitor" }
-func prometheusRuleName(ck ConfKind) string   { return name(ck) + "-alert" }
-func (b *builder) name() string               { return name(b.confKind) }
-func (b *builder) promServiceName() string    { return promServiceName(b.confKind) }
-func (b *builder) configMapName() string      { return configMapName(b.confKind) }
-func (b *builder) serviceMonitorName() string { return serviceMonitorName(b.confKind) }
-func (b *builder) prometheusRuleName() string { return prometheusRuleName(b.confKind) }
-func (b *builder) Pipeline() *PipelineBuilder { return b.pipeline }
+func name(ck ConfKind) string                      { return constants.FLPName + FlpConfSuffix[ck] }
+func RoleBindingName(ck ConfKind) string           { return name(ck) + "-role" }
+func RoleBindingMonoName(ck ConfKind) string       { return name(ck) + "-role-mono" }
+func promServiceName(ck ConfKind) string           { return name(ck) + "-prom" }
+func configMapName(ck ConfKind) string             { return name(ck) + "-config" }
+func serviceMonitorName(ck ConfKind) string        { return name(ck) + "-monitor" }
+func prometheusRuleName(ck ConfKind) string        { return name(ck) + "-alert" }
+func flowsServiceMonitorName(ck ConfKind) string   { return name(ck) + "-flows-monitor" }
+func (b *builder) name() string                    { return name(b.confKind) }
+func (b *builder) promServiceName() string         { return promServiceName(b.confKind) }
+func (b *builder) configMapName() string           { return configMapName(b.confKind) }
+func (b *builder) serviceMonitorName() string      { return serviceMonitorName(b.confKind) }
+func (b *builder) prometheusRuleName() string      { return prometheusRuleName(b.confKind) }
+func (b *builder) flowsServiceMonitorName() string { return flowsServiceMonitorName(b.confKind) }
+func (b *builder) Pipeline() *PipelineBuilder      { return b.pipeline }
 
 func (b *builder) NewGRPCPipeline() PipelineBuilder {
 	return b.initPipeline(config.NewGRPCPipeline("grpc", api.IngestGRPCProto{
@@ -126,21 +164,40 @@ func (b *builder) NewGRPCPipeline() PipelineBuilder {
 }
 
 func (b *builder) NewKafkaPipeline() PipelineBuilder {
-	decoder := api.Decoder{Type: "protobuf"}
+	kafka, decoder := kafkaInput(b.desired)
 	return b.initPipeline(config.NewKafkaPipeline("kafka-read", api.IngestKafka{
-		Brokers:           []string{b.desired.Kafka.Address},
-		Topic:             b.desired.Kafka.Topic,
+		Brokers:           []string{kafka.Address},
+		Topic:             kafka.Topic,
 		GroupID:           b.name(), // Without groupid, each message is delivered to each consumers
-		Decoder:           decoder,
-		TLS:               getKafkaTLS(&b.desired.Kafka.TLS, "kafka-cert", &b.volumes),
-		SASL:              getKafkaSASL(&b.desired.Kafka.SASL, "kafka-ingest", &b.volumes),
+		Decoder:           api.Decoder{Type: decoder},
+		TLS:               getKafkaTLS(&kafka.TLS, "kafka-cert", &b.volumes),
+		SASL:              getKafkaSASL(&kafka.SASL, "kafka-ingest", &b.volumes),
 		PullQueueCapacity: b.desired.Processor.KafkaConsumerQueueCapacity,
 		PullMaxBytes:      b.desired.Processor.KafkaConsumerBatchSize,
 	}))
 }
 
+// kafkaInput returns the Kafka configuration to consume flows from, and the decoder to use:
+// either the topic fed by the agents, or an external topic set in `spec.processor.kafkaSource`
+func kafkaInput(desired *flowslatest.FlowCollectorSpec) (*flowslatest.FlowCollectorKafka, api.DecoderEnum) {
+	if !helper.UseKafkaSource(desired) {
+		return &desired.Kafka, api.DecoderProtobuf
+	}
+	src := desired.Processor.KafkaSource
+	decoder := api.DecoderProtobuf
+	if src.Decoder == flowslatest.KafkaDecoderJSON {
+		decoder = api.DecoderJSON
+	}
+	return &flowslatest.FlowCollectorKafka{
+		Address: src.Address,
+		Topic:   src.Topic,
+		TLS:     src.TLS,
+		SASL:    src.SASL,
+	}, decoder
+}
+
 func (b *builder) initPipeline(ingest config.PipelineBuilderStage) PipelineBuilder {
-	pipeline := newPipelineBuilder(b.desired, b.flowMetrics, b.detectedSubnets, b.info.Loki, b.info.ClusterID, &b.volumes, &ingest)
+	pipeline := newPipelineBuilder(b.desired, b.flowMetrics, b.detectedSubnets, b.info.VirtualMachines, b.info.MultiHomedPods, b.info.ClusterNetwork, b.info.Loki, b.info.ClusterID, &b.volumes, &ingest)
 	b.pipeline = &pipeline
 	return pipeline
 }
@@ -162,10 +219,18 @@ func (b *builder) podTemplate(hasHostPort, hostNetwork bool, annotations map[str
 		ContainerPort: *advancedConfig.HealthPort,
 	})
 
-	ports = append(ports, corev1.ContainerPort{
-		Name:          prometheusServiceName,
-		ContainerPort: b.desired.Processor.Metrics.Server.Port,
-	})
+	var sidecars []corev1.Container
+	if b.useRBACProxy() {
+		sidecars = append(sidecars, b.rbacProxyContainer())
+	} else {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          prometheusServiceName,
+			ContainerPort: b.desired.Processor.Metrics.Server.Port,
+		})
+	}
+	if b.useExternalIngest() {
+		sidecars = append(sidecars, b.externalIngestContainer())
+	}
 
 	if advancedConfig.ProfilePort != nil {
 		ports = append(ports, corev1.ContainerPort{
@@ -175,6 +240,10 @@ func (b *builder) podTemplate(hasHostPort, hostNetwork bool, annotations map[str
 		})
 	}
 
+	if b.info.Proxy.UseTrustedCABundle() {
+		b.volumes.AddTrustedCABundle()
+	}
+
 	volumeMounts := b.volumes.AppendMounts([]corev1.VolumeMount{{
 		MountPath: configPath,
 		Name:      configVolume,
@@ -197,7 +266,9 @@ func (b *builder) podTemplate(hasHostPort, hostNetwork bool, annotations map[str
 	for _, pair := range helper.KeySorted(advancedConfig.Env) {
 		envs = append(envs, corev1.EnvVar{Name: pair[0], Value: pair[1]})
 	}
+	envs = append(envs, helper.GoRuntimeEnv(advancedConfig.GoRuntime, &b.desired.Processor.Resources, advancedConfig.Env)...)
 	envs = append(envs, constants.EnvNoHTTP2)
+	envs = append(envs, b.info.Proxy.EnvVars()...)
 
 	container := corev1.Container{
 		Name:            constants.FLPName,
@@ -211,17 +282,30 @@ func (b *builder) podTemplate(hasHostPort, hostNetwork bool, annotations map[str
 		SecurityContext: helper.ContainerDefaultSecurityContext(),
 	}
 	if *advancedConfig.EnableKubeProbes {
-		container.LivenessProbe = &corev1.Probe{
+		probes := helper.GetProbesConfig(advancedConfig.Probes)
+		container.LivenessProbe = helper.WithProbeConfig(&corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path: "/live",
 					Port: intstr.FromString(healthServiceName),
 				},
 			},
-			TimeoutSeconds: healthTimeoutSeconds,
-			PeriodSeconds:  livenessPeriodSeconds,
-		}
-		container.StartupProbe = &corev1.Probe{
+			TimeoutSeconds:   healthTimeoutSeconds,
+			PeriodSeconds:    livenessPeriodSeconds,
+			FailureThreshold: healthFailureThreshold,
+		}, probes.Liveness)
+		container.ReadinessProbe = helper.WithProbeConfig(&corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/ready",
+					Port: intstr.FromString(healthServiceName),
+				},
+			},
+			TimeoutSeconds:   healthTimeoutSeconds,
+			PeriodSeconds:    readinessPeriodSeconds,
+			FailureThreshold: healthFailureThreshold,
+		}, probes.Readiness)
+		container.StartupProbe = helper.WithProbeConfig(&corev1.Probe{
 			ProbeHandler: corev1.ProbeHandler{
 				HTTPGet: &corev1.HTTPGetAction{
 					Path: "/ready",
@@ -231,7 +315,7 @@ func (b *builder) podTemplate(hasHostPort, hostNetwork bool, annotations map[str
 			TimeoutSeconds:   healthTimeoutSeconds,
 			PeriodSeconds:    startupPeriodSeconds,
 			FailureThreshold: startupFailureThreshold,
-		}
+		}, probes.Startup)
 	}
 	dnsPolicy := corev1.DNSClusterFirst
 	if hostNetwork {
@@ -246,7 +330,7 @@ func (b *builder) podTemplate(hasHostPort, hostNetwork bool, annotations map[str
 		},
 		Spec: corev1.PodSpec{
 			Volumes:            volumes,
-			Containers:         []corev1.Container{container},
+			Containers:         append([]corev1.Container{container}, sidecars...),
 			ServiceAccountName: b.name(),
 			HostNetwork:        hostNetwork,
 			DNSPolicy:          dnsPolicy,
@@ -290,7 +374,11 @@ func (b *builder) GetJSONConfig() (string, error) {
 		Prefix:  "netobserv_",
 		NoPanic: true,
 	}
-	if b.desired.Processor.Metrics.Server.TLS.Type != flowslatest.ServerTLSDisabled {
+	if b.useRBACProxy() {
+		// kube-rbac-proxy serves TLS and forwards to the metrics server on localhost
+		metricsSettings.Address = "127.0.0.1"
+		metricsSettings.Port = rbacProxyUpstreamPort
+	} else if b.desired.Processor.Metrics.Server.TLS.Type != flowslatest.ServerTLSDisabled {
 		cert, key := b.volumes.AddCertificate(b.promTLS, "prom-certs")
 		if cert != "" && key != "" {
 			metricsSettings.TLS = &api.PromTLSConf{
@@ -299,14 +387,19 @@ func (b *builder) GetJSONConfig() (string, error) {
 			}
 		}
 	}
+	stages, params := b.pipeline.GetStages(), b.pipeline.GetStageParams()
+	if b.presetIngest != nil {
+		stages = append([]config.Stage{{Name: b.presetIngest.Name}}, stages...)
+		params = append([]config.StageParam{*b.presetIngest}, params...)
+	}
 	advancedConfig := helper.GetAdvancedProcessorConfig(b.desired.Processor.Advanced)
 	config := map[string]interface{}{
 		"log-level": b.desired.Processor.LogLevel,
 		"health": map[string]interface{}{
 			"port": *advancedConfig.HealthPort,
 		},
-		"pipeline":        b.pipeline.GetStages(),
-		"parameters":      b.pipeline.GetStageParams(),
+		"pipeline":        stages,
+		"parameters":      params,
 		"metricsSettings": metricsSettings,
 	}
 	if advancedConfig.ProfilePort != nil {
@@ -319,6 +412,11 @@ func (b *builder) GetJSONConfig() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// break-glass overrides, reported in status
+	bs, err = overrides.ApplyJSON(bs, b.info.ConfigOverrides)
+	if err != nil {
+		return "", err
+	}
 	return string(bs), nil
 }
 
@@ -389,22 +487,53 @@ func (b *builder) clusterRoleBinding(ck ConfKind, mono bool) *rbacv1.ClusterRole
 	}
 }
 
+// serviceMonitor scrapes all metrics, or only the health metrics when flow metrics are routed to another Prometheus
 func (b *builder) serviceMonitor() *monitoringv1.ServiceMonitor {
+	sm := b.newServiceMonitor(b.serviceMonitorName(), b.info.Namespace, b.labels)
+	if helper.IsFlowMetricsTiered(&b.desired.Processor.Metrics) {
+		b.setMetricsTier(sm, metricsTierHealth)
+	}
+	return sm
+}
+
+// healthServiceMonitor scrapes the health metrics from the privileged namespace, which remains monitored by the platform
+// Prometheus when the FlowCollector namespace is left to the user-workload monitoring
+func (b *builder) healthServiceMonitor() *monitoringv1.ServiceMonitor {
+	sm := b.newServiceMonitor(b.serviceMonitorName(), b.info.PrivilegedNamespace(), b.labels)
+	b.setMetricsTier(sm, metricsTierHealth)
+	return sm
+}
+
+// flowsServiceMonitor scrapes the flow metrics only, for the Prometheus selected in `spec.processor.metrics.routing`
+func (b *builder) flowsServiceMonitor() *monitoringv1.ServiceMonitor {
+	labels := maps.Clone(b.labels)
+	maps.Copy(labels, b.desired.Processor.Metrics.Routing.ServiceMonitorLabels)
+	sm := b.newServiceMonitor(b.flowsServiceMonitorName(), b.info.Namespace, labels)
+	b.setMetricsTier(sm, metricsTierFlows)
+	return sm
+}
+
+func (b *builder) newServiceMonitor(name, namespace string, labels map[string]string) *monitoringv1.ServiceMonitor {
 	serverName := fmt.Sprintf("%s.%s.svc", b.promServiceName(), b.info.Namespace)
 	scheme, smTLS := helper.GetServiceMonitorTLSConfig(&b.desired.Processor.Metrics.Server.TLS, serverName, b.isDownstream)
+	var tokenFile string
+	if b.useRBACProxy() {
+		tokenFile = rbacProxyTokenFile
+	}
 	return &monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      b.serviceMonitorName(),
-			Namespace: b.info.Namespace,
-			Labels:    b.labels,
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
 		},
 		Spec: monitoringv1.ServiceMonitorSpec{
 			Endpoints: []monitoringv1.Endpoint{
 				{
-					Port:      prometheusServiceName,
-					Interval:  "15s",
-					Scheme:    scheme,
-					TLSConfig: smTLS,
+					Port:            prometheusServiceName,
+					Interval:        "15s",
+					Scheme:          scheme,
+					TLSConfig:       smTLS,
+					BearerTokenFile: tokenFile,
 				},
 			},
 			NamespaceSelector: monitoringv1.NamespaceSelector{
@@ -419,6 +548,44 @@ func (b *builder) serviceMonitor() *monitoringv1.ServiceMonitor {
 	}
 }
 
+// setMetricsTier labels the ServiceMonitor with its metrics tier, and keeps only the metrics of that tier
+func (b *builder) setMetricsTier(sm *monitoringv1.ServiceMonitor, tier string) {
+	labels := maps.Clone(sm.Labels)
+	labels[metricsTierLabel] = tier
+	sm.Labels = labels
+	action := "drop"
+	if tier == metricsTierFlows {
+		action = "keep"
+	}
+	sm.Spec.Endpoints[0].MetricRelabelConfigs = []*monitoringv1.RelabelConfig{{
+		SourceLabels: []monitoringv1.LabelName{"__name__"},
+		Regex:        b.flowMetricsRegex(),
+		Action:       action,
+	}}
+}
+
+// flowMetricsRegex matches the names of the flow metrics generated by the pipeline, telling them apart from the health
+// metrics that share the same prefix
+func (b *builder) flowMetricsRegex() string {
+	var names []string
+	if b.pipeline != nil {
+		for _, param := range b.pipeline.GetStageParams() {
+			if param.Encode == nil || param.Encode.Prom == nil {
+				continue
+			}
+			for _, m := range param.Encode.Prom.Metrics {
+				names = append(names, regexp.QuoteMeta(m.Name))
+			}
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	return fmt.Sprintf("%s(%s)(_bucket|_count|_sum)?", regexp.QuoteMeta(metrics.GetPrefix(b.desired)), strings.Join(names, "|"))
+}
+
+// pipelineErrorRatioThreshold is the ratio of flows failing in a stage above which the pipeline is considered degraded
+const pipelineErrorRatioThreshold = "0.05"
+
 func shouldAddAlert(name flowslatest.FLPAlert, disabledList []flowslatest.FLPAlert) bool {
 	for _, disabledAlert := range disabledList {
 		if name == disabledAlert {
@@ -466,6 +633,69 @@ func (b *builder) prometheusRule() *monitoringv1.PrometheusRule {
 		})
 	}
 
+	// Flows failing in a pipeline stage
+	if shouldAddAlert(flowslatest.AlertPipelineErrors, b.desired.Processor.Metrics.DisableAlerts) {
+		rules = append(rules, monitoringv1.Rule{
+			Alert: string(flowslatest.AlertPipelineErrors),
+			Annotations: map[string]string{
+				"description": "NetObserv flowlogs-pipeline stage {{ $labels.stage }} fails to process {{ $value | humanizePercentage }} of the flows. Please check the flowlogs-pipeline logs and the pipeline health dashboard.",
+				"summary":     "NetObserv flowlogs-pipeline stage is failing to process flows",
+			},
+			Expr: intstr.FromString(fmt.Sprintf(
+				"sum by (stage) (rate(netobserv_ingest_errors[5m])) / ignoring(stage) group_left sum(rate(netobserv_ingest_flows_processed[5m])) > %s",
+				pipelineErrorRatioThreshold,
+			)),
+			For: &d,
+			Labels: map[string]string{
+				"severity": "warning",
+				"app":      "netobserv",
+			},
+		})
+	}
+
+	// NetObserv metrics exceeding their cardinality budget
+	if budget := b.desired.Processor.Metrics.MaxCardinality; budget > 0 && b.ownsClusterRules() &&
+		shouldAddAlert(flowslatest.AlertCardinality, b.desired.Processor.Metrics.DisableAlerts) {
+		rules = append(rules, monitoringv1.Rule{
+			Alert: string(flowslatest.AlertCardinality),
+			Annotations: map[string]string{
+				"description": "NetObserv metrics have {{ $value }} series, exceeding the budget set in FlowCollector spec.processor.metrics.maxCardinality. Please review the FlowMetric resources and the included metrics.",
+				"summary":     "NetObserv metrics exceed their cardinality budget",
+			},
+			Expr: intstr.FromString(fmt.Sprintf(`count({__name__=~"%s.+"}) > %d`, metrics.GetPrefix(b.desired), budget)),
+			For:  &d,
+			Labels: map[string]string{
+				"severity": "warning",
+				"app":      "netobserv",
+			},
+		})
+	}
+
+	groups := []monitoringv1.RuleGroup{
+		{
+			Name:  "NetobservFlowLogsPipeline",
+			Rules: rules,
+		},
+	}
+	if analytics := b.analyticsRules(); len(analytics) > 0 {
+		groups = append(groups, monitoringv1.RuleGroup{
+			Name:  "NetobservAnalytics",
+			Rules: analytics,
+		})
+	}
+	if recording := b.flowMetricsRecordingRules(); len(recording) > 0 {
+		groups = append(groups, monitoringv1.RuleGroup{
+			Name:  "NetobservFlowMetrics",
+			Rules: recording,
+		})
+	}
+	if retention := b.retentionRules(); len(retention) > 0 {
+		groups = append(groups, monitoringv1.RuleGroup{
+			Name:  "NetobservRetention",
+			Rules: retention,
+		})
+	}
+
 	flpPrometheusRuleObject := monitoringv1.PrometheusRule{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      b.prometheusRuleName(),
@@ -473,17 +703,64 @@ func (b *builder) prometheusRule() *monitoringv1.PrometheusRule {
 			Namespace: b.info.Namespace,
 		},
 		Spec: monitoringv1.PrometheusRuleSpec{
-			Groups: []monitoringv1.RuleGroup{
-				{
-					Name:  "NetobservFlowLogsPipeline",
-					Rules: rules,
-				},
-			},
+			Groups: groups,
 		},
 	}
 	return &flpPrometheusRuleObject
 }
 
+// isEmptyPrometheusRule returns true when the rule has neither alerts nor recording rules
+func isEmptyPrometheusRule(pr *monitoringv1.PrometheusRule) bool {
+	for i := range pr.Spec.Groups {
+		if len(pr.Spec.Groups[i].Rules) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// flowMetricsRecordingRules returns the recording rules defined in FlowMetric resources
+func (b *builder) flowMetricsRecordingRules() []monitoringv1.Rule {
+	if b.flowMetrics == nil || !b.ownsClusterRules() {
+		return nil
+	}
+	prefix := metrics.GetPrefix(b.desired)
+	var rules []monitoringv1.Rule
+	for i := range b.flowMetrics.Items {
+		fm := &b.flowMetrics.Items[i].Spec
+		series := fm.MetricName
+		if fm.Type == metricslatest.HistogramMetric {
+			series += "_bucket"
+		}
+		for _, rr := range fm.RecordingRules {
+			window := model.Duration(5 * time.Minute)
+			if rr.Window != nil {
+				window = model.Duration(rr.Window.Duration)
+			}
+			var by []string
+			for _, l := range rr.Labels {
+				by = append(by, fm.LabelName(l))
+			}
+			record := series
+			if len(by) > 0 {
+				record += "_by_" + strings.Join(by, "_")
+			}
+			if fm.Type == metricslatest.HistogramMetric {
+				by = append(by, "le")
+			}
+			expr := fmt.Sprintf("sum(rate(%s%s[%s]))", prefix, series, window.String())
+			if len(by) > 0 {
+				expr = fmt.Sprintf("sum by (%s) (rate(%s%s[%s]))", strings.Join(by, ", "), prefix, series, window.String())
+			}
+			rules = append(rules, monitoringv1.Rule{
+				Record: fmt.Sprintf("netobserv:%s:rate%s", record, window.String()),
+				Expr:   intstr.FromString(expr),
+			})
+		}
+	}
+	return rules
+}
+
 func buildClusterRoleIngester(useOpenShiftSCC bool) *rbacv1.ClusterRole {
 	cr := rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
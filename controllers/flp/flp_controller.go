@@ -3,21 +3,34 @@ package flp
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	endpointslatest "github.com/netobserv/network-observability-operator/apis/externalendpoints/v1alpha1"
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/cardinality"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/kubevirt"
+	"github.com/netobserv/network-observability-operator/pkg/logforwarder"
 	"github.com/netobserv/network-observability-operator/pkg/loki"
 	"github.com/netobserv/network-observability-operator/pkg/manager"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+	"github.com/netobserv/network-observability-operator/pkg/multus"
+	"github.com/netobserv/network-observability-operator/pkg/overrides"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
 	"github.com/netobserv/network-observability-operator/pkg/watchers"
 	configv1 "github.com/openshift/api/config/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	ascv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +47,13 @@ type Reconciler struct {
 	status           status.Instance
 	clusterID        string
 	currentNamespace string
+	requeueDelays    map[status.ComponentName]*reconcilers.RequeueDelay
+	batchQuerier     *prometheus.Querier
+	healthQuerier    *prometheus.Querier
+	// batchTuning is the last result of the batch auto-tuning, nil when disabled
+	batchTuning *flowslatest.BatchTuningStatus
+	// pipelineHealth is the last result of the pipeline health check, nil when disabled
+	pipelineHealth *pipelineHealth
 }
 
 func Start(ctx context.Context, mgr *manager.Manager) error {
@@ -44,6 +64,11 @@ func Start(ctx context.Context, mgr *manager.Manager) error {
 		Client: mgr.Client,
 		mgr:    mgr,
 		status: mgr.Status.ForComponent(status.FLPParent),
+		requeueDelays: map[status.ComponentName]*reconcilers.RequeueDelay{
+			status.FLPMonolith:      reconcilers.NewRequeueDelay("flp-monolith"),
+			status.FLPTransformOnly: reconcilers.NewRequeueDelay("flp-transformer"),
+			status.FLPLoadGenerator: reconcilers.NewRequeueDelay("flp-load-generator"),
+		},
 	}
 	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&flowslatest.FlowCollector{}, reconcilers.IgnoreStatusChange).
@@ -62,7 +87,55 @@ func Start(ctx context.Context, mgr *manager.Manager) error {
 				}
 				return []reconcile.Request{}
 			}),
+		).
+		Watches(
+			&endpointslatest.ExternalEndpoint{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+				if o.GetNamespace() == r.currentNamespace {
+					return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+				}
+				return []reconcile.Request{}
+			}),
+		)
+
+	if mgr.IsOpenShift() {
+		helper.WatchClusterProxy(builder)
+	}
+
+	if mgr.HasLokiStack() {
+		loki.WatchLokiStacks(builder)
+	}
+
+	if mgr.HasKubeVirt() {
+		kubevirt.WatchVirtualMachineInstances(builder)
+	}
+
+	if mgr.HasClusterLogForwarder() {
+		logforwarder.WatchClusterLogForwarders(builder)
+	}
+
+	if mgr.IsOpenShift() && mgr.HasCNO() {
+		// the cluster network configuration, or its status once applied by the Cluster Network Operator, may change
+		builder.Watches(
+			&configv1.Network{},
+			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+				if o.GetName() != "cluster" {
+					return []reconcile.Request{}
+				}
+				return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+			}),
 		)
+	}
+
+	multus.WatchPods(builder)
+
+	// reconcile again when APIs such as ServiceMonitor get installed or removed
+	builder.WatchesRawSource(
+		mgr.APIChangesSource(),
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}),
+	)
 
 	ctrl, err := builder.Build(&r)
 	if err != nil {
@@ -98,28 +171,52 @@ func (r *Reconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result
 	r.status.SetUnknown()
 	defer r.status.Commit(ctx, r.Client)
 
-	err = r.reconcile(ctx, clh, fc)
+	// Features disabled by the operator feature gates are turned off in the desired state
+	fc, gated := r.mgr.Config.FeatureGates.Apply(fc)
+
+	requeueAfter, err := r.reconcile(ctx, clh, fc, gated)
 	if err != nil {
-		l.Error(err, "FLP reconcile failure")
+		l.Error(err, "FLP reconcile failure", "retryAfter", requeueAfter)
 		// Set status failure unless it was already set
 		if !r.status.HasFailure() {
-			r.status.SetFailure("FLPError", err.Error())
+			_ = r.status.Error("FLPError", err)
+		}
+		if requeueAfter > 0 || status.KindOf(err) == status.ErrorInvalidSpec {
+			// retried after a backoff, or not at all when a fix requires a change, which triggers a new reconcile
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
+	migration, requeueAfter := loki.MigrationStatus(&fc.Spec, fc.Status.LokiMigration, r.lokiWriterReady(), time.Now())
+	r.status.SetLokiMigration(migration)
 	r.status.SetReady()
-	return ctrl.Result{}, nil
+	r.status.SetObservedGeneration(fc.Generation)
+	// refresh the migration checklist when the overlap period ends, check the flow rate again for batch auto-tuning,
+	// and the error ratio for the pipeline health check
+	now := time.Now()
+	return ctrl.Result{RequeueAfter: reconcilers.MinRequeue(requeueAfter, r.nextBatchCheck(&fc.Spec.Processor, now), r.nextHealthCheck(&fc.Spec.Processor.Metrics, now))}, nil
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, clh *helper.Client, fc *flowslatest.FlowCollector) error {
+// reconcile returns, along with any error, the delay after which failing sub-reconcilers should be retried.
+// Gated lists the fields ignored because of the operator feature gates, reported as warnings.
+func (r *Reconciler) reconcile(ctx context.Context, clh *helper.Client, fc *flowslatest.FlowCollector, gated []string) (time.Duration, error) {
 	log := log.FromContext(ctx)
 
+	if err := r.mgr.Config.CheckFlowCollector(&fc.Spec); err != nil {
+		return 0, r.status.Error("NamespacedInstall", err)
+	}
 	ns := helper.GetNamespace(&fc.Spec)
 	r.currentNamespace = ns
 	previousNamespace := r.status.GetDeployedNamespace(fc)
-	loki := helper.NewLokiConfig(&fc.Spec.Loki, ns)
-	cmn := r.newCommonInfo(clh, ns, previousNamespace, &loki)
+	lokiConfig := helper.GetLokiConfig(&fc.Spec, ns)
+	proxy := helper.GetProxyConfig(ctx, r.Client, r.mgr.IsOpenShift(), &fc.Spec.Proxy)
+	if r.mgr.HasLokiStack() {
+		if err := loki.ApplyLokiStack(ctx, r.Client, &fc.Spec, &lokiConfig); err != nil {
+			return 0, r.status.Error("LokiStackError", err)
+		}
+	}
+	cmn := r.newCommonInfo(clh, ns, previousNamespace, &lokiConfig, &proxy)
 
 	r.watcher.Reset(ns)
 
@@ -134,6 +231,18 @@ func (r *Reconciler) reconcile(ctx context.Context, clh *helper.Client, fc *flow
 		}
 	}
 
+	// Read the cluster network configuration, used to classify flows
+	var clusterNetwork *flowslatest.ClusterNetworkStatus
+	if r.mgr.IsOpenShift() && r.mgr.HasCNO() && helper.IsClusterNetworkOperatorManaged(&fc.Spec.Processor) {
+		var err error
+		clusterNetwork, err = readClusterNetwork(ctx, r.Client)
+		if err != nil {
+			log.Error(err, "error while reading the cluster network configuration")
+		}
+	}
+	r.status.SetClusterNetwork(clusterNetwork)
+	cmn.ClusterNetwork = clusterNetwork
+
 	// Auto-detect subnets
 	var subnetLabels []flowslatest.SubnetLabel
 	if r.mgr.IsOpenShift() && helper.AutoDetectOpenShiftNetworks(&fc.Spec.Processor) {
@@ -144,54 +253,167 @@ func (r *Reconciler) reconcile(ctx context.Context, clh *helper.Client, fc *flow
 		}
 	}
 
+	// List external endpoints, which are labelled like subnets
+	ee := endpointslatest.ExternalEndpointList{}
+	if err := r.Client.List(ctx, &ee, &client.ListOptions{Namespace: ns}); err != nil {
+		return 0, r.status.Error("CantListExternalEndpoints", err)
+	}
+	subnetLabels = append(subnetLabels, externalEndpointsToSubnetLabels(ee.Items)...)
+
+	// Optional enrichments depend on APIs that might not be installed
+	var warnings []string
+
+	// Adjust the batch sizes to the flow rate
+	batchTuning, err := r.tuneBatches(ctx, fc, time.Now())
+	if err != nil {
+		log.Error(err, "batch auto-tuning failure")
+		warnings = append(warnings, fmt.Sprintf("batch auto-tuning is enabled, but %s: keeping the %s profile", err.Error(), batchTuning.Profile))
+	}
+	r.status.SetBatchTuning(batchTuning)
+	cmn.BatchTuning = batchTuning
+	if helper.IsVirtualizationEnabled(&fc.Spec.Processor) {
+		if !r.mgr.HasKubeVirt() {
+			warnings = append(warnings, "virtualization is enabled, but the KubeVirt API is not installed: flows are not attributed to virtual machines")
+		} else {
+			// List virtual machines, which IP addresses are used to attribute flows
+			vms, err := kubevirt.ListVirtualMachines(ctx, r.Client)
+			if err != nil {
+				return 0, r.status.Error("CantListVirtualMachines", err)
+			}
+			cmn.VirtualMachines = vms
+		}
+	}
+	if helper.IsMultiHomingEnabled(&fc.Spec.Processor) {
+		// List the pods attached to secondary networks, which addresses are used to attribute flows
+		pods, err := multus.ListPods(ctx, r.Client)
+		if err != nil {
+			return 0, r.status.Error("CantListMultiHomedPods", err)
+		}
+		cmn.MultiHomedPods = pods
+	}
+	if helper.IsServiceMeshEnabled(&fc.Spec.Processor) && !r.mgr.HasIstio() {
+		warnings = append(warnings, "service mesh is enabled, but the Istio API is not installed: mesh fields are unknown")
+	}
+	warnings = append(warnings, clusterNetworkWarnings(clusterNetwork, &fc.Spec)...)
+	if helper.GetClusterLogForwarderExporter(&fc.Spec) != nil && !r.mgr.HasClusterLogForwarder() {
+		warnings = append(warnings, "a ClusterLogForwarder exporter is configured, but the ClusterLogForwarder API is not installed: flows are not forwarded")
+	}
+	warnings = append(warnings, analyticsWarnings(&fc.Spec)...)
+	warningReason := "EnrichmentAPINotFound"
+	// A degraded stage is reported first, as flows are being lost
+	degraded, err := r.checkPipelineHealth(ctx, fc, time.Now())
+	if err != nil {
+		log.Error(err, "pipeline health check failure")
+		warnings = append(warnings, fmt.Sprintf("the pipeline health check is enabled, but %s", err.Error()))
+	} else if degraded != "" {
+		warningReason = pipelineDegradedReason
+		warnings = append([]string{degraded}, warnings...)
+	}
+	if len(gated) > 0 {
+		warningReason = "FeatureGateDisabled"
+		warnings = append(gated, warnings...)
+	}
+	if name := helper.GetAdvancedProcessorConfig(fc.Spec.Processor.Advanced).OverridesConfigMap; name != "" {
+		// Break-glass overrides of the generated configuration, reported first as they may explain other issues
+		warningReason = overrides.ReasonConfigOverridden
+		ovr, err := overrides.Read(ctx, *clh, r.watcher, name, ns)
+		if err != nil {
+			log.Error(err, "overrides not applied")
+			warnings = append([]string{err.Error()}, warnings...)
+		} else if len(ovr) > 0 {
+			log.Info("Overriding the flowlogs-pipeline configuration", "configMap", name, "keys", len(ovr))
+			cmn.ConfigOverrides = ovr
+			warnings = append([]string{overrides.Warning(name, ovr)}, warnings...)
+		}
+	}
+	if len(warnings) > 0 {
+		r.status.SetWarning(warningReason, strings.Join(warnings, "; "))
+	} else {
+		r.status.ClearWarning()
+	}
+
 	// List custom metrics
 	fm := metricslatest.FlowMetricList{}
 	if err := r.Client.List(ctx, &fm, &client.ListOptions{Namespace: ns}); err != nil {
-		return r.status.Error("CantListFlowMetrics", err)
+		return 0, r.status.Error("CantListFlowMetrics", err)
+	}
+	if err := r.reportDeprecatedFields(ctx, fm.Items); err != nil {
+		return 0, r.status.Error("CantUpdateFlowMetrics", err)
 	}
+	// FlowMetrics disabled by the cardinality guard aren't generated
+	fm.Items = cardinality.FilterEnabled(fm.Items)
 
 	// Create sub-reconcilers
 	// TODO: refactor to move these subReconciler allocations in `Start`. It will involve some decoupling work, as currently
 	// `reconcilers.Common` is dependent on the FlowCollector object, which isn't known at start time.
-	reconcilers := []subReconciler{
+	subReconcilers := []subReconciler{
 		newMonolithReconciler(cmn.NewInstance(r.mgr.Config.FlowlogsPipelineImage, r.mgr.Status.ForComponent(status.FLPMonolith))),
 		newTransformerReconciler(cmn.NewInstance(r.mgr.Config.FlowlogsPipelineImage, r.mgr.Status.ForComponent(status.FLPTransformOnly))),
+		newLoadGenReconciler(cmn.NewInstance(r.mgr.Config.FlowlogsPipelineImage, r.mgr.Status.ForComponent(status.FLPLoadGenerator))),
 	}
 
 	// Check namespace changed
 	if ns != previousNamespace {
 		if previousNamespace != "" {
 			log.Info("FlowCollector namespace change detected: cleaning up previous namespace", "old", previousNamespace, "new", ns)
-			for _, sr := range reconcilers {
+			for _, sr := range subReconcilers {
 				sr.cleanupNamespace(sr.context(ctx))
 			}
 		}
 		// Update namespace in status
 		if err := r.status.SetDeployedNamespace(ctx, r.Client, ns); err != nil {
-			return r.status.Error("ChangeNamespaceError", err)
+			return 0, r.status.Error("ChangeNamespaceError", err)
+		}
+	}
+
+	// sub-reconcilers are retried independently, each with its own requeue delay
+	var requeueAfter time.Duration
+	var failure error
+	for _, sr := range subReconcilers {
+		retryAfter, err := r.requeueDelays[sr.getStatus().Name()].Run(fc.Generation, func() error {
+			return sr.reconcile(sr.context(ctx), fc, &fm, subnetLabels)
+		})
+		if err != nil {
+			failure = sr.getStatus().Error("FLPReconcileError", err)
+			requeueAfter = reconcilers.MinRequeue(requeueAfter, retryAfter)
 		}
 	}
 
-	for _, sr := range reconcilers {
-		if err := sr.reconcile(sr.context(ctx), fc, &fm, subnetLabels); err != nil {
-			return sr.getStatus().Error("FLPReconcileError", err)
+	if err := reconcileFlowsSchema(ctx, clh, ns, previousNamespace, &fc.Spec); err != nil && failure == nil {
+		failure = r.status.Error("FlowsSchemaError", err)
+	}
+
+	// Hand flows to the ClusterLogForwarder once flowlogs-pipeline is deployed, and clean up the previous one
+	if r.mgr.HasClusterLogForwarder() {
+		if err := logforwarder.Reconcile(ctx, r.Client, ns, &fc.Spec); err != nil && failure == nil {
+			failure = r.status.Error("ClusterLogForwarderError", err)
 		}
 	}
 
-	return nil
+	return requeueAfter, failure
 }
 
-func (r *Reconciler) newCommonInfo(clh *helper.Client, ns, prevNs string, loki *helper.LokiConfig) reconcilers.Common {
+// lokiWriterReady returns true when the flowlogs-pipeline writing to Loki, monolith or transformer, is rolled out with the current configuration
+func (r *Reconciler) lokiWriterReady() bool {
+	monolith := r.mgr.Status.ForComponent(status.FLPMonolith)
+	transformer := r.mgr.Status.ForComponent(status.FLPTransformOnly)
+	return monolith.IsReady() || transformer.IsReady()
+}
+
+func (r *Reconciler) newCommonInfo(clh *helper.Client, ns, prevNs string, loki *helper.LokiConfig, proxy *helper.ProxyConfig) reconcilers.Common {
 	return reconcilers.Common{
 		Client:            *clh,
 		Namespace:         ns,
 		PreviousNamespace: prevNs,
 		UseOpenShiftSCC:   r.mgr.IsOpenShift(),
-		AvailableAPIs:     &r.mgr.AvailableAPIs,
+		AvailableAPIs:     r.mgr.AvailableAPIs,
 		Watcher:           r.watcher,
 		Loki:              loki,
 		ClusterID:         r.clusterID,
+		Proxy:             proxy,
 		IsDownstream:      r.mgr.Config.DownstreamDeployment,
+		RBACProxyImage:    r.mgr.Config.KubeRBACProxyImage,
+		Namespaced:        r.mgr.Config.IsNamespaced(),
 	}
 }
 
@@ -206,6 +428,32 @@ func annotateKafkaExporterCerts(ctx context.Context, info *reconcilers.Common, e
 	return nil
 }
 
+// watchAdditionalLokis watches the certificates and credentials of Loki exporters and of the migration target Loki;
+// digests are ignored, as these files are read on each request
+func watchAdditionalLokis(ctx context.Context, info *reconcilers.Common, spec *flowslatest.FlowCollectorSpec) error {
+	var configs []helper.LokiConfig
+	for _, exporter := range spec.Exporters {
+		if exporter.Type == flowslatest.LokiExporter {
+			configs = append(configs, helper.NewLokiExporterConfig(&exporter.Loki))
+		}
+	}
+	if target := helper.NewLokiMigrationTargetConfig(spec); target != nil && helper.UseLoki(spec) {
+		configs = append(configs, *target)
+	}
+	for i := range configs {
+		lc := &configs[i]
+		if _, err := info.Watcher.ProcessCACert(ctx, info.Client, &lc.TLS, info.Namespace); err != nil {
+			return err
+		}
+		for _, file := range lc.CredentialsFiles() {
+			if _, err := info.Watcher.ProcessFileReference(ctx, info.Client, file, info.Namespace); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func annotateKafkaCerts(ctx context.Context, info *reconcilers.Common, spec *flowslatest.FlowCollectorKafka, prefix string, annotations map[string]string) error {
 	caDigest, userDigest, err := info.Watcher.ProcessMTLSCerts(ctx, info.Client, &spec.TLS, info.Namespace)
 	if err != nil {
@@ -249,6 +497,48 @@ func reconcileMonitoringCerts(ctx context.Context, info *reconcilers.Common, tls
 	return nil
 }
 
+// newHealthServiceMonitor registers the ServiceMonitor of the health metrics installed in the privileged namespace
+func newHealthServiceMonitor(cmn *reconcilers.Instance, name string) *monitoringv1.ServiceMonitor {
+	sm := monitoringv1.ServiceMonitor{}
+	var previous string
+	if cmn.PreviousNamespace != "" {
+		previous = cmn.PreviousPrivilegedNamespace()
+	}
+	cmn.Managed.AddManagedObjectInNamespace(cmn.PrivilegedNamespace(), previous, name, &sm)
+	return &sm
+}
+
+// reconcileServiceMonitors reconciles a single ServiceMonitor scraping all metrics, or separate ServiceMonitors for the
+// health metrics and the flow metrics when they are routed to different Prometheus instances
+func reconcileServiceMonitors(ctx context.Context, i *reconcilers.Instance, b builder, current, health, flows *monitoringv1.ServiceMonitor, report *helper.ChangeReport) error {
+	metricsSpec := &b.desired.Processor.Metrics
+	tier := helper.GetFlowMetricsTier(metricsSpec)
+
+	if tier == flowslatest.FlowMetricsUserWorkload {
+		i.Managed.TryDelete(ctx, current)
+		if tls := &metricsSpec.Server.TLS; !tls.InsecureSkipVerify && tls.ProvidedCaFile != nil && tls.ProvidedCaFile.File != "" {
+			// the CA referenced by the health ServiceMonitor is read from its own namespace
+			if _, err := i.Watcher.ProcessFileReference(ctx, i.Client, *tls.ProvidedCaFile, i.PrivilegedNamespace()); err != nil {
+				return err
+			}
+		}
+		if err := reconcilers.GenericReconcile(ctx, i.Managed, &i.Client, health, b.healthServiceMonitor(), report, helper.ServiceMonitorChanged); err != nil {
+			return err
+		}
+	} else {
+		i.Managed.TryDelete(ctx, health)
+		if err := reconcilers.GenericReconcile(ctx, i.Managed, &i.Client, current, b.serviceMonitor(), report, helper.ServiceMonitorChanged); err != nil {
+			return err
+		}
+	}
+
+	if tier == flowslatest.FlowMetricsPlatform {
+		i.Managed.TryDelete(ctx, flows)
+		return nil
+	}
+	return reconcilers.GenericReconcile(ctx, i.Managed, &i.Client, flows, b.flowsServiceMonitor(), report, helper.ServiceMonitorChanged)
+}
+
 func reconcileLokiRoles(ctx context.Context, r *reconcilers.Common, b *builder) error {
 	roles := loki.ClusterRoles(b.desired.Loki.Mode)
 	if len(roles) > 0 {
@@ -266,6 +556,9 @@ func reconcileLokiRoles(ctx context.Context, r *reconcilers.Common, b *builder)
 	return nil
 }
 
+// clusterConfigNamespace holds the cluster-config-v1 ConfigMap, with the install configuration
+const clusterConfigNamespace = "kube-system"
+
 func (r *Reconciler) getOpenShiftSubnets(ctx context.Context) ([]flowslatest.SubnetLabel, error) {
 	var subnets []flowslatest.SubnetLabel
 
@@ -276,20 +569,17 @@ func (r *Reconciler) getOpenShiftSubnets(ctx context.Context) ([]flowslatest.Sub
 		if err != nil {
 			return nil, fmt.Errorf("can't get Network information: %w", err)
 		}
-		var podCIDRs []string
-		for _, podsNet := range network.Spec.ClusterNetwork {
-			podCIDRs = append(podCIDRs, podsNet.CIDR)
-		}
-		if len(podCIDRs) > 0 {
+		clusterNetwork := clusterNetworkFrom(network)
+		if len(clusterNetwork.PodCIDRs) > 0 {
 			subnets = append(subnets, flowslatest.SubnetLabel{
 				Name:  "Pods",
-				CIDRs: podCIDRs,
+				CIDRs: clusterNetwork.PodCIDRs,
 			})
 		}
-		if len(network.Spec.ServiceNetwork) > 0 {
+		if len(clusterNetwork.ServiceCIDRs) > 0 {
 			subnets = append(subnets, flowslatest.SubnetLabel{
 				Name:  "Services",
-				CIDRs: network.Spec.ServiceNetwork,
+				CIDRs: clusterNetwork.ServiceCIDRs,
 			})
 		}
 		if network.Spec.ExternalIP != nil && len(network.Spec.ExternalIP.AutoAssignCIDRs) > 0 {
@@ -301,8 +591,12 @@ func (r *Reconciler) getOpenShiftSubnets(ctx context.Context) ([]flowslatest.Sub
 	}
 
 	// Nodes subnet found in CM cluster-config-v1 (kube-system)
+	if !r.mgr.Config.IsWatched(clusterConfigNamespace) {
+		log.FromContext(ctx).Info("The operator doesn't watch the " + clusterConfigNamespace + " namespace: Machines subnet not detected")
+		return subnets, nil
+	}
 	cm := &corev1.ConfigMap{}
-	if err := r.Get(ctx, types.NamespacedName{Name: "cluster-config-v1", Namespace: "kube-system"}, cm); err != nil {
+	if err := r.Get(ctx, types.NamespacedName{Name: "cluster-config-v1", Namespace: clusterConfigNamespace}, cm); err != nil {
 		return nil, fmt.Errorf(`can't read "cluster-config-v1" ConfigMap: %w`, err)
 	}
 	machines, err := readMachineNetworks(cm)
@@ -317,6 +611,25 @@ func (r *Reconciler) getOpenShiftSubnets(ctx context.Context) ([]flowslatest.Sub
 	return subnets, nil
 }
 
+func externalEndpointsToSubnetLabels(endpoints []endpointslatest.ExternalEndpoint) []flowslatest.SubnetLabel {
+	// Sort to keep the generated configuration stable
+	slices.SortFunc(endpoints, func(a, b endpointslatest.ExternalEndpoint) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	var subnets []flowslatest.SubnetLabel
+	for i := range endpoints {
+		name := endpoints[i].Spec.DisplayName
+		if name == "" {
+			name = endpoints[i].Name
+		}
+		subnets = append(subnets, flowslatest.SubnetLabel{
+			Name:  name,
+			CIDRs: endpoints[i].Spec.CIDRs,
+		})
+	}
+	return subnets
+}
+
 func readMachineNetworks(cm *corev1.ConfigMap) ([]flowslatest.SubnetLabel, error) {
 	var subnets []flowslatest.SubnetLabel
 
@@ -351,3 +664,28 @@ func readMachineNetworks(cm *corev1.ConfigMap) ([]flowslatest.SubnetLabel, error
 
 	return subnets, nil
 }
+
+// reportDeprecatedFields sets the `Deprecated` condition on the FlowMetrics using deprecated fields, and removes it from the others
+func (r *Reconciler) reportDeprecatedFields(ctx context.Context, items []metricslatest.FlowMetric) error {
+	for i := range items {
+		fm := &items[i]
+		var changed bool
+		if fields := fm.Spec.DeprecatedFields(); len(fields) > 0 {
+			changed = meta.SetStatusCondition(&fm.Status.Conditions, metav1.Condition{
+				Type:               metricslatest.ConditionDeprecated,
+				Status:             metav1.ConditionTrue,
+				Reason:             "DeprecatedFields",
+				Message:            fmt.Sprintf("Deprecated fields are used, which will be removed in a future version: %s", strings.Join(fields, ", ")),
+				ObservedGeneration: fm.Generation,
+			})
+		} else {
+			changed = meta.RemoveStatusCondition(&fm.Status.Conditions, metricslatest.ConditionDeprecated)
+		}
+		if changed {
+			if err := r.Status().Update(ctx, fm); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
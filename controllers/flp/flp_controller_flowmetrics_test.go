@@ -11,7 +11,7 @@ import (
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 )
 
@@ -433,7 +433,8 @@ func ControllerSpecs() {
 				}, &pr)
 			}, timeout, interval).Should(Succeed())
 			Expect(pr.Spec.Groups).Should(HaveLen(1))
-			Expect(pr.Spec.Groups[0].Rules).Should(HaveLen(1))
+			// NoFlows and PipelineErrors alerts
+			Expect(pr.Spec.Groups[0].Rules).Should(HaveLen(2))
 
 			// Manually delete ServiceMonitor
 			By("Deleting ServiceMonitor")
@@ -0,0 +1,73 @@
+package flp
+
+import (
+	"fmt"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const (
+	dscpClassField = "DscpClass"
+	dscpClassOther = "Other"
+)
+
+// dscpClass is a service class of RFC 4594, with the DSCP values recommended for it
+type dscpClass struct {
+	name   string
+	values []int
+}
+
+var dscpClasses = []dscpClass{
+	{name: "Network Control", values: []int{48, 56}},             // CS6, CS7
+	{name: "Telephony", values: []int{46}},                       // EF
+	{name: "Signaling", values: []int{40}},                       // CS5
+	{name: "Multimedia Conferencing", values: []int{34, 36, 38}}, // AF41, AF42, AF43
+	{name: "Real-Time Interactive", values: []int{32}},           // CS4
+	{name: "Multimedia Streaming", values: []int{26, 28, 30}},    // AF31, AF32, AF33
+	{name: "Broadcast Video", values: []int{24}},                 // CS3
+	{name: "Low-Latency Data", values: []int{18, 20, 22}},        // AF21, AF22, AF23
+	{name: "OAM", values: []int{16}},                             // CS2
+	{name: "High-Throughput Data", values: []int{10, 12, 14}},    // AF11, AF12, AF13
+	{name: "Standard", values: []int{0}},                         // CS0 / DF
+	{name: "Low-Priority Data", values: []int{8, 1}},             // CS1, LE (RFC 8622)
+}
+
+// addDSCPClassificationStage sets the service class of flows from their DSCP value. It is done before the outputs,
+// so that the class can be used in Loki filters as well as in metrics.
+func (b *PipelineBuilder) addDSCPClassificationStage(lastStage config.PipelineBuilderStage) config.PipelineBuilderStage {
+	if !helper.IsDSCPClassificationEnabled(&b.desired.Processor) {
+		return lastStage
+	}
+	return lastStage.TransformFilter("dscp-class", api.TransformFilter{Rules: dscpClassFilterRules()})
+}
+
+func dscpClassFilterRules() []api.TransformFilterRule {
+	var rules []api.TransformFilterRule
+	for _, c := range dscpClasses {
+		for _, v := range c.values {
+			rules = append(rules, api.TransformFilterRule{
+				Type: api.AddFieldIf,
+				AddFieldIf: &api.TransformFilterRuleWithAssignee{
+					Input:      "Dscp",
+					Output:     dscpClassField,
+					Parameters: fmt.Sprintf("== %d", v),
+					Assignee:   c.name,
+				},
+			})
+		}
+	}
+	return append(rules,
+		api.TransformFilterRule{
+			Type:                  api.AddFieldIfDoesntExist,
+			AddFieldIfDoesntExist: &api.TransformFilterGenericRule{Input: dscpClassField, Value: dscpClassOther},
+		},
+		// add_field_if marks matching entries with an extra field, which is not needed
+		api.TransformFilterRule{
+			Type:        api.RemoveField,
+			RemoveField: &api.TransformFilterGenericRule{Input: dscpClassField + "_Evaluate"},
+		},
+	)
+}
@@ -0,0 +1,108 @@
+package flp
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const (
+	egressClassField    = "DstEgressClass"
+	egressClassCluster  = "InCluster"
+	egressClassNode     = "Node"
+	egressClassPrivate  = "ExternalPrivate"
+	egressClassInternet = "Internet"
+)
+
+var (
+	// detected subnets that are part of the cluster, by class
+	clusterSubnetClasses = map[string]string{
+		"Pods":       egressClassCluster,
+		"Services":   egressClassCluster,
+		"ExternalIP": egressClassCluster,
+		"Machines":   egressClassNode,
+	}
+	// RFC 1918, RFC 6598 and RFC 4193 ranges
+	defaultPrivateCIDRs = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"}
+)
+
+// addEgressClassificationStages sets the class of the flows destination. The cluster and private ranges are matched as
+// subnets, in this order since the private ranges usually include the cluster ones. Then, destinations resolved by the
+// Kubernetes enrichment are classified on their type, and the remaining ones are on the internet.
+func (b *PipelineBuilder) addEgressClassificationStages(lastStage config.PipelineBuilderStage) config.PipelineBuilderStage {
+	if !helper.IsEgressClassificationEnabled(&b.desired.Processor) {
+		return lastStage
+	}
+	spec := &b.desired.Processor.EgressClassification
+	var node, cluster []string
+	for _, subnet := range b.detectedSubnets {
+		switch clusterSubnetClasses[subnet.Name] {
+		case egressClassNode:
+			node = append(node, subnet.CIDRs...)
+		case egressClassCluster:
+			cluster = append(cluster, subnet.CIDRs...)
+		}
+	}
+	if b.clusterNetwork != nil {
+		// cluster subnets read from the Cluster Network Operator, when not already detected as subnet labels
+		for _, cidr := range append(slices.Clone(b.clusterNetwork.PodCIDRs), b.clusterNetwork.ServiceCIDRs...) {
+			if !slices.Contains(cluster, cidr) {
+				cluster = append(cluster, cidr)
+			}
+		}
+	}
+	cluster = append(cluster, spec.ClusterCIDRs...)
+	private := spec.PrivateCIDRs
+	if len(private) == 0 {
+		private = defaultPrivateCIDRs
+	}
+	var labels []api.NetworkTransformSubnetLabel
+	for _, l := range []api.NetworkTransformSubnetLabel{
+		{Name: egressClassNode, CIDRs: node},
+		{Name: egressClassCluster, CIDRs: cluster},
+		{Name: egressClassPrivate, CIDRs: slices.Clone(private)},
+	} {
+		if len(l.CIDRs) > 0 {
+			labels = append(labels, l)
+		}
+	}
+	lastStage = lastStage.TransformNetwork("egress-class", api.TransformNetwork{
+		Rules:        api.NetworkTransformRules{addSubnetLabelRule("DstAddr", egressClassField)},
+		SubnetLabels: labels,
+	})
+	return lastStage.TransformFilter("egress-class-fallback", api.TransformFilter{Rules: egressClassFilterRules()})
+}
+
+func egressClassFilterRules() []api.TransformFilterRule {
+	var rules []api.TransformFilterRule
+	for _, t := range []struct{ k8sType, class string }{
+		{k8sType: "Node", class: egressClassNode},
+		{k8sType: "Pod", class: egressClassCluster},
+		{k8sType: "Service", class: egressClassCluster},
+	} {
+		rules = append(rules, api.TransformFilterRule{
+			Type: api.AddFieldIf,
+			AddFieldIf: &api.TransformFilterRuleWithAssignee{
+				Input:      "DstK8S_Type",
+				Output:     egressClassField,
+				Parameters: fmt.Sprintf("== '%s'", t.k8sType),
+				Assignee:   t.class,
+			},
+		})
+	}
+	return append(rules,
+		api.TransformFilterRule{
+			Type:                  api.AddFieldIfDoesntExist,
+			AddFieldIfDoesntExist: &api.TransformFilterGenericRule{Input: egressClassField, Value: egressClassInternet},
+		},
+		// add_field_if marks matching entries with an extra field, which is not needed
+		api.TransformFilterRule{
+			Type:        api.RemoveField,
+			RemoveField: &api.TransformFilterGenericRule{Input: egressClassField + "_Evaluate"},
+		},
+	)
+}
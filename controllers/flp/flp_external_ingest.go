@@ -0,0 +1,153 @@
+package flp
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/watchers"
+)
+
+const (
+	externalIngestContainerName = "kube-rbac-proxy-ingest"
+	externalIngestPortName      = "ingest-tls"
+	// externalIngestPath is the gRPC method of the eBPF agent protobuf schema (pbflow) that senders call
+	externalIngestPath     = "/pbflow.Collector/Send"
+	externalIngestCertsVol = "ingest-certs"
+	externalIngestCAVol    = "ingest-client-ca"
+)
+
+func externalIngestServiceName() string    { return name(ConfMonolith) + "-ingest" }
+func externalIngestSenderRoleName() string { return constants.FLPName + "-ingest-sender" }
+
+// useExternalIngest tells whether kube-rbac-proxy must be deployed in front of the gRPC ingest, which only the monolith exposes
+func (b *builder) useExternalIngest() bool {
+	return b.confKind == ConfMonolith && helper.IsExternalIngestEnabled(&b.desired.Processor)
+}
+
+func (b *builder) externalIngestServerCert() *flowslatest.CertificateReference {
+	if cert := b.desired.Processor.ExternalIngest.ServerCert; cert != nil {
+		return cert
+	}
+	// generated by the OpenShift service CA, from the service annotation
+	return &flowslatest.CertificateReference{
+		Type:     "secret",
+		Name:     externalIngestServiceName(),
+		CertFile: "tls.crt",
+		CertKey:  "tls.key",
+	}
+}
+
+// externalIngestContainer returns a kube-rbac-proxy authenticating and authorizing senders, before forwarding their
+// requests to the gRPC ingest in clear text HTTP/2
+func (b *builder) externalIngestContainer() corev1.Container {
+	spec := &b.desired.Processor.ExternalIngest
+	cert, key := b.volumes.AddCertificate(b.externalIngestServerCert(), externalIngestCertsVol)
+	args := []string{
+		fmt.Sprintf("--secure-listen-address=0.0.0.0:%d", spec.Port),
+		fmt.Sprintf("--upstream=http://127.0.0.1:%d/", *helper.GetAdvancedProcessorConfig(b.desired.Processor.Advanced).Port),
+		"--upstream-force-h2c",
+		"--allow-paths=" + externalIngestPath,
+		"--tls-cert-file=" + cert,
+		"--tls-private-key-file=" + key,
+	}
+	if spec.Authentication == flowslatest.ExternalIngestAuthMTLS && spec.ClientCA != nil {
+		args = append(args, "--client-ca-file="+b.volumes.AddVolume(spec.ClientCA, externalIngestCAVol))
+	}
+	var mounts []corev1.VolumeMount
+	for _, m := range b.volumes.GetMounts() {
+		if m.Name == externalIngestCertsVol || m.Name == externalIngestCAVol {
+			mounts = append(mounts, m)
+		}
+	}
+	return corev1.Container{
+		Name:            externalIngestContainerName,
+		Image:           b.info.RBACProxyImage,
+		ImagePullPolicy: corev1.PullPolicy(b.desired.Processor.ImagePullPolicy),
+		Args:            args,
+		Ports: []corev1.ContainerPort{{
+			Name:          externalIngestPortName,
+			ContainerPort: spec.Port,
+			Protocol:      corev1.ProtocolTCP,
+		}},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("30Mi"),
+			},
+		},
+		VolumeMounts:    mounts,
+		SecurityContext: helper.ContainerDefaultSecurityContext(),
+	}
+}
+
+func (b *builder) externalIngestService() *corev1.Service {
+	spec := &b.desired.Processor.ExternalIngest
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalIngestServiceName(),
+			Namespace: b.info.Namespace,
+			Labels:    b.labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     spec.ServiceType,
+			Selector: b.selector,
+			Ports: []corev1.ServicePort{{
+				Name:       externalIngestPortName,
+				Port:       spec.Port,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt32(spec.Port),
+			}},
+		},
+	}
+	if spec.ServerCert == nil {
+		svc.ObjectMeta.Annotations = map[string]string{
+			constants.OpenShiftCertificateAnnotation: externalIngestServiceName(),
+		}
+	}
+	return &svc
+}
+
+// buildClusterRoleIngestSender returns the role to bind to the external senders
+func buildClusterRoleIngestSender() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: externalIngestSenderRoleName(),
+		},
+		Rules: []rbacv1.PolicyRule{{
+			NonResourceURLs: []string{externalIngestPath},
+			Verbs:           []string{"create"},
+		}},
+	}
+}
+
+// reconcileExternalIngestCerts watches the server certificate and client CA, returning digests to restart pods on rotation
+func reconcileExternalIngestCerts(ctx context.Context, info *reconcilers.Common, spec *flowslatest.FLPExternalIngest, annotations map[string]string) error {
+	if spec.ServerCert != nil {
+		digest, err := info.Watcher.ProcessCertRef(ctx, info.Client, spec.ServerCert, info.Namespace)
+		if err != nil {
+			return err
+		}
+		annotations[watchers.Annotation(externalIngestCertsVol)] = digest
+	}
+	if spec.Authentication == flowslatest.ExternalIngestAuthMTLS && spec.ClientCA != nil {
+		digest, err := info.Watcher.ProcessFileReference(ctx, info.Client, *spec.ClientCA, info.Namespace)
+		if err != nil {
+			return err
+		}
+		annotations[watchers.Annotation(externalIngestCAVol)] = digest
+	}
+	return nil
+}
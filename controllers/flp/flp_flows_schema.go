@@ -0,0 +1,61 @@
+package flp
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/flowschema"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const (
+	flowsSchemaName        = "netobserv-flows-schema"
+	flowsSchemaFile        = "schema.json"
+	flowsSchemaVersionFile = "version"
+	flowsSchemaVersionLbl  = "netobserv.io/flows-schema-version"
+)
+
+// flowsSchemaConfigMap returns the ConfigMap publishing the JSON schema of the flow records written with the current
+// configuration, so that consumers of the Loki or exporters data can validate their parsers
+func flowsSchemaConfigMap(ns string, spec *flowslatest.FlowCollectorSpec) (*corev1.ConfigMap, error) {
+	schema, err := flowschema.Build(spec)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      flowsSchemaName,
+			Namespace: ns,
+			Labels: map[string]string{
+				flowsSchemaVersionLbl: schema.Version,
+			},
+		},
+		Data: map[string]string{
+			flowsSchemaFile:        string(bs),
+			flowsSchemaVersionFile: schema.Version,
+		},
+	}, nil
+}
+
+func reconcileFlowsSchema(ctx context.Context, clh *helper.Client, ns, previousNamespace string, spec *flowslatest.FlowCollectorSpec) error {
+	if previousNamespace != "" && previousNamespace != ns {
+		old := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: flowsSchemaName, Namespace: previousNamespace}}
+		if err := reconcilers.ReconcileConfigMap(ctx, clh, &old, true); err != nil {
+			return err
+		}
+	}
+	cm, err := flowsSchemaConfigMap(ns, spec)
+	if err != nil {
+		return err
+	}
+	return reconcilers.ReconcileConfigMap(ctx, clh, cm, false)
+}
@@ -0,0 +1,92 @@
+package flp
+
+import (
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+// syntheticBatchMaxLen is the number of generated flows forwarded at once, as the agents would do
+const syntheticBatchMaxLen = 100
+
+type loadGenBuilder struct {
+	generic builder
+}
+
+func newLoadGenBuilder(info *reconcilers.Instance, desired *flowslatest.FlowCollectorSpec, flowMetrics *metricslatest.FlowMetricList, detectedSubnets []flowslatest.SubnetLabel) (loadGenBuilder, error) {
+	// synthetic flows must not reach the systems consuming the real ones
+	spec := *desired
+	spec.Exporters = nil
+	gen, err := NewBuilder(info, &spec, flowMetrics, detectedSubnets, ConfLoadGenerator)
+	return loadGenBuilder{
+		generic: gen,
+	}, err
+}
+
+func (b *loadGenBuilder) deployment(annotations map[string]string) *appsv1.Deployment {
+	pod := b.generic.podTemplate(false /*no listen*/, false /*no host network*/, annotations)
+	pod.Spec.Containers[0].Resources = *b.generic.desired.LoadGenerator.Resources.DeepCopy()
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.generic.name(),
+			Namespace: b.generic.info.Namespace,
+			Labels:    b.generic.labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			// the generated rate is set per pod: a single replica keeps it as configured
+			Replicas: ptr.To(int32(1)),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: b.generic.selector,
+			},
+			Template: pod,
+		},
+	}
+}
+
+// configMap returns a pipeline ingesting synthetic flows at the configured rate, followed by the same stages
+// as the processor. Flows are written to their own Loki stream, and not exported.
+func (b *loadGenBuilder) configMap() (*corev1.ConfigMap, string, error) {
+	spec := &b.generic.desired.LoadGenerator
+	b.generic.presetIngest = &config.StageParam{
+		Name: config.PresetIngesterStage,
+		Ingest: &config.Ingest{
+			Type: api.SyntheticType,
+			Synthetic: &api.IngestSynthetic{
+				Connections:    int(helper.PtrInt32(spec.Connections)),
+				BatchMaxLen:    syntheticBatchMaxLen,
+				FlowLogsPerMin: int(helper.PtrInt32(spec.FlowsPerSecond)) * 60,
+			},
+		},
+	}
+	pipeline := b.generic.initPipeline(config.NewPresetIngesterPipeline())
+	pipeline.lokiApp = constants.LoadGeneratorLokiApp
+	if err := pipeline.AddProcessorStages(); err != nil {
+		return nil, "", err
+	}
+	return b.generic.ConfigMap()
+}
+
+func (b *loadGenBuilder) promService() *corev1.Service {
+	return b.generic.promService()
+}
+
+func (b *loadGenBuilder) serviceAccount() *corev1.ServiceAccount {
+	return b.generic.serviceAccount()
+}
+
+// clusterRoleBinding grants the generator the same permissions as the transformer, which it needs to enrich flows
+func (b *loadGenBuilder) clusterRoleBinding() *rbacv1.ClusterRoleBinding {
+	crb := b.generic.clusterRoleBinding(ConfLoadGenerator, false)
+	crb.RoleRef.Name = name(ConfKafkaTransformer)
+	return crb
+}
@@ -0,0 +1,174 @@
+package flp
+
+import (
+	"context"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+// loadGenReconciler deploys the flow generator used for performance tests: a flowlogs-pipeline ingesting synthetic
+// flows instead of the agents ones, and processing them like the processor does
+type loadGenReconciler struct {
+	*reconcilers.Instance
+	deployment     *appsv1.Deployment
+	promService    *corev1.Service
+	serviceAccount *corev1.ServiceAccount
+	configMap      *corev1.ConfigMap
+	roleBinding    *rbacv1.ClusterRoleBinding
+	serviceMonitor *monitoringv1.ServiceMonitor
+}
+
+func newLoadGenReconciler(cmn *reconcilers.Instance) *loadGenReconciler {
+	name := name(ConfLoadGenerator)
+	rec := loadGenReconciler{
+		Instance:       cmn,
+		deployment:     cmn.Managed.NewDeployment(name),
+		promService:    cmn.Managed.NewService(promServiceName(ConfLoadGenerator)),
+		serviceAccount: cmn.Managed.NewServiceAccount(name),
+		configMap:      cmn.Managed.NewConfigMap(configMapName(ConfLoadGenerator)),
+		roleBinding:    cmn.Managed.NewCRB(RoleBindingName(ConfLoadGenerator)),
+	}
+	if cmn.AvailableAPIs.HasSvcMonitor() {
+		rec.serviceMonitor = cmn.Managed.NewServiceMonitor(serviceMonitorName(ConfLoadGenerator))
+	}
+	return &rec
+}
+
+func (r *loadGenReconciler) context(ctx context.Context) context.Context {
+	l := log.FromContext(ctx).WithName("load-generator")
+	return log.IntoContext(ctx, l)
+}
+
+// cleanupNamespace cleans up old namespace
+func (r *loadGenReconciler) cleanupNamespace(ctx context.Context) {
+	r.Managed.CleanupPreviousNamespace(ctx)
+}
+
+func (r *loadGenReconciler) getStatus() *status.Instance {
+	return &r.Status
+}
+
+func (r *loadGenReconciler) reconcile(ctx context.Context, desired *flowslatest.FlowCollector, flowMetrics *metricslatest.FlowMetricList, detectedSubnets []flowslatest.SubnetLabel) error {
+	// Retrieve current owned objects
+	err := r.Managed.FetchAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !helper.IsLoadGeneratorEnabled(&desired.Spec.LoadGenerator) {
+		r.Status.SetUnused("Load generator is disabled")
+		r.Managed.TryDeleteAll(ctx)
+		return nil
+	}
+
+	r.Status.SetReady() // will be overidden if necessary, as error or pending
+
+	builder, err := newLoadGenBuilder(r.Instance, &desired.Spec, flowMetrics, detectedSubnets)
+	if err != nil {
+		return err
+	}
+	newCM, configDigest, err := builder.configMap()
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{
+		constants.PodConfigurationDigest: configDigest,
+	}
+	if !r.Managed.Exists(r.configMap) {
+		if err := r.CreateOwned(ctx, newCM); err != nil {
+			return err
+		}
+	} else if !equality.Semantic.DeepDerivative(newCM.Data, r.configMap.Data) {
+		if err := r.UpdateIfOwned(ctx, r.configMap, newCM); err != nil {
+			return err
+		}
+	}
+	if err := r.reconcilePermissions(ctx, &builder); err != nil {
+		return err
+	}
+
+	if err := r.reconcilePrometheusService(ctx, &builder); err != nil {
+		return err
+	}
+
+	// Watch for Loki certificate and credentials, as generated flows are written to Loki in their own stream
+	if _, err = r.Watcher.ProcessCACert(ctx, r.Client, &r.Loki.TLS, r.Namespace); err != nil {
+		return err
+	}
+	if _, err = r.ReconcileLokiCredentials(ctx); err != nil {
+		return err
+	}
+	if err = watchAdditionalLokis(ctx, r.Common, &desired.Spec); err != nil {
+		return err
+	}
+
+	// Watch for monitoring caCert
+	if err = reconcileMonitoringCerts(ctx, r.Common, &desired.Spec.Processor.Metrics.Server.TLS, r.Namespace); err != nil {
+		return err
+	}
+
+	return r.reconcileDeployment(ctx, &builder, annotations)
+}
+
+func (r *loadGenReconciler) reconcileDeployment(ctx context.Context, builder *loadGenBuilder, annotations map[string]string) error {
+	report := helper.NewChangeReport("FLP load generator Deployment")
+	defer report.LogIfNeeded(ctx)
+
+	return reconcilers.ReconcileDeployment(
+		ctx,
+		r.Instance,
+		r.deployment,
+		builder.deployment(annotations),
+		constants.FLPName,
+		1,
+		nil,
+		&report,
+	)
+}
+
+func (r *loadGenReconciler) reconcilePrometheusService(ctx context.Context, builder *loadGenBuilder) error {
+	report := helper.NewChangeReport("FLP load generator prometheus service")
+	defer report.LogIfNeeded(ctx)
+
+	if err := r.ReconcileService(ctx, r.promService, builder.promService(), &report); err != nil {
+		return err
+	}
+	// no PrometheusRule: alerting on synthetic flows would be misleading
+	if r.AvailableAPIs.HasSvcMonitor() {
+		serviceMonitor := builder.generic.serviceMonitor()
+		if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.serviceMonitor, serviceMonitor, &report, helper.ServiceMonitorChanged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *loadGenReconciler) reconcilePermissions(ctx context.Context, builder *loadGenBuilder) error {
+	if !r.Managed.Exists(r.serviceAccount) {
+		return r.CreateOwned(ctx, builder.serviceAccount())
+	} // We only configure name, update is not needed for now
+
+	if err := r.ReconcileClusterRole(ctx, BuildClusterRoleTransformer()); err != nil {
+		return err
+	}
+	if err := r.ReconcileClusterRoleBinding(ctx, builder.clusterRoleBinding()); err != nil {
+		return err
+	}
+
+	if err := reconcileLokiRoles(ctx, r.Common, &builder.generic); err != nil {
+		return err
+	}
+	return reconcileRBACProxyRoles(ctx, r.Common, &builder.generic)
+}
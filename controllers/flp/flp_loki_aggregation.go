@@ -0,0 +1,68 @@
+package flp
+
+import (
+	"slices"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/loki"
+)
+
+const defaultLokiAggregationWindow = 15 * time.Second
+
+// addLokiAggregation rolls up the flows written to Loki per key set, made of the Loki labels and the configured fields.
+// It relies on connection tracking, with a key set standing for a connection: a heartbeat record is emitted every window
+// for each active key set, and an end record once the key set is idle for a window.
+func (b *PipelineBuilder) addLokiAggregation(lastStage config.PipelineBuilderStage) config.PipelineBuilderStage {
+	spec := b.desired.Loki.Aggregation
+	window := defaultLokiAggregationWindow
+	if spec.Window != nil {
+		window = spec.Window.Duration
+	}
+
+	keys := slices.Clone(loki.GetLokiLabels(b.desired))
+	for _, field := range spec.GroupBy {
+		if !slices.Contains(keys, field) {
+			keys = append(keys, field)
+		}
+	}
+	// the record type label is set by the aggregation itself
+	keys = slices.DeleteFunc(keys, func(k string) bool { return k == api.RecordTypeFieldName })
+
+	outputFields := []api.OutputField{
+		{Name: "Bytes", Operation: "sum"},
+		{Name: "Packets", Operation: "sum"},
+		{Name: "numFlowLogs", Operation: "count"},
+		{Name: "TimeFlowStartMs", Operation: "min", ReportMissing: true},
+		{Name: "TimeFlowEndMs", Operation: "max", ReportMissing: true},
+	}
+	if helper.IsPktDropEnabled(&b.desired.Agent.EBPF) {
+		outputFields = append(outputFields,
+			api.OutputField{Name: "PktDropBytes", Operation: "sum"},
+			api.OutputField{Name: "PktDropPackets", Operation: "sum"},
+		)
+	}
+	if helper.IsDNSTrackingEnabled(&b.desired.Agent.EBPF) {
+		outputFields = append(outputFields, api.OutputField{Name: "DnsLatencyMs", Operation: "max"})
+	}
+	if helper.IsFlowRTTEnabled(&b.desired.Agent.EBPF) {
+		outputFields = append(outputFields, api.OutputField{Name: "TimeFlowRttNs", Operation: "max"})
+	}
+
+	return lastStage.ConnTrack("loki-aggregate", api.ConnTrack{
+		KeyDefinition: api.KeyDefinition{
+			FieldGroups: []api.FieldGroup{{Name: "key", Fields: keys}},
+			Hash:        api.ConnTrackHash{FieldGroupRefs: []string{"key"}},
+		},
+		OutputRecordTypes: helper.GetLokiRecordTypes(b.desired),
+		OutputFields:      outputFields,
+		Scheduling: []api.ConnTrackSchedulingGroup{{
+			HeartbeatInterval:    api.Duration{Duration: window},
+			EndConnectionTimeout: api.Duration{Duration: window},
+			TerminatingTimeout:   api.Duration{Duration: window},
+		}},
+	})
+}
@@ -7,8 +7,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
 )
 
 type monolithBuilder struct {
@@ -24,7 +25,7 @@ func newMonolithBuilder(info *reconcilers.Instance, desired *flowslatest.FlowCol
 
 func (b *monolithBuilder) daemonSet(annotations map[string]string) *appsv1.DaemonSet {
 	pod := b.generic.podTemplate(true /*listens*/, !b.generic.info.UseOpenShiftSCC, annotations)
-	return &appsv1.DaemonSet{
+	ds := appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      b.generic.name(),
 			Namespace: b.generic.info.Namespace,
@@ -37,6 +38,10 @@ func (b *monolithBuilder) daemonSet(annotations map[string]string) *appsv1.Daemo
 			Template: pod,
 		},
 	}
+	if strategy := helper.GetAdvancedProcessorConfig(b.generic.desired.Processor.Advanced).UpdateStrategy; strategy != nil {
+		ds.Spec.UpdateStrategy = *strategy
+	}
+	return &ds
 }
 
 func (b *monolithBuilder) configMap() (*corev1.ConfigMap, string, error) {
@@ -52,6 +57,10 @@ func (b *monolithBuilder) promService() *corev1.Service {
 	return b.generic.promService()
 }
 
+func (b *monolithBuilder) externalIngestService() *corev1.Service {
+	return b.generic.externalIngestService()
+}
+
 func (b *monolithBuilder) serviceAccount() *corev1.ServiceAccount {
 	return b.generic.serviceAccount()
 }
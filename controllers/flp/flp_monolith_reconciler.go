@@ -11,22 +11,26 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+	"github.com/netobserv/network-observability-operator/pkg/watchers"
 )
 
 type monolithReconciler struct {
 	*reconcilers.Instance
 	daemonSet      *appsv1.DaemonSet
 	promService    *corev1.Service
+	ingestService  *corev1.Service
 	serviceAccount *corev1.ServiceAccount
 	configMap      *corev1.ConfigMap
 	roleBindingIn  *rbacv1.ClusterRoleBinding
 	roleBindingTr  *rbacv1.ClusterRoleBinding
 	serviceMonitor *monitoringv1.ServiceMonitor
+	healthMonitor  *monitoringv1.ServiceMonitor
+	flowsMonitor   *monitoringv1.ServiceMonitor
 	prometheusRule *monitoringv1.PrometheusRule
 }
 
@@ -36,6 +40,7 @@ func newMonolithReconciler(cmn *reconcilers.Instance) *monolithReconciler {
 		Instance:       cmn,
 		daemonSet:      cmn.Managed.NewDaemonSet(name),
 		promService:    cmn.Managed.NewService(promServiceName(ConfMonolith)),
+		ingestService:  cmn.Managed.NewService(externalIngestServiceName()),
 		serviceAccount: cmn.Managed.NewServiceAccount(name),
 		configMap:      cmn.Managed.NewConfigMap(configMapName(ConfMonolith)),
 		roleBindingIn:  cmn.Managed.NewCRB(RoleBindingMonoName(ConfKafkaIngester)),
@@ -43,6 +48,8 @@ func newMonolithReconciler(cmn *reconcilers.Instance) *monolithReconciler {
 	}
 	if cmn.AvailableAPIs.HasSvcMonitor() {
 		rec.serviceMonitor = cmn.Managed.NewServiceMonitor(serviceMonitorName(ConfMonolith))
+		rec.healthMonitor = newHealthServiceMonitor(cmn, serviceMonitorName(ConfMonolith))
+		rec.flowsMonitor = cmn.Managed.NewServiceMonitor(flowsServiceMonitorName(ConfMonolith))
 	}
 	if cmn.AvailableAPIs.HasPromRule() {
 		rec.prometheusRule = cmn.Managed.NewPrometheusRule(prometheusRuleName(ConfMonolith))
@@ -109,17 +116,41 @@ func (r *monolithReconciler) reconcile(ctx context.Context, desired *flowslatest
 		return err
 	}
 
+	if err = r.reconcileExternalIngest(ctx, &builder, &desired.Spec.Processor, annotations); err != nil {
+		return err
+	}
+
 	// Watch for Loki certificate if necessary; we'll ignore in that case the returned digest, as we don't need to restart pods on cert rotation
 	// because certificate is always reloaded from file
 	if _, err = r.Watcher.ProcessCACert(ctx, r.Client, &r.Loki.TLS, r.Namespace); err != nil {
 		return err
 	}
 
+	// Watch for Loki credentials if necessary; the digest is ignored too, as credentials are read from file on each request
+	if _, err = r.ReconcileLokiCredentials(ctx); err != nil {
+		return err
+	}
+
 	// Watch for Kafka exporter certificate if necessary; need to restart pods in case of cert rotation
 	if err = annotateKafkaExporterCerts(ctx, r.Common, desired.Spec.Exporters, annotations); err != nil {
 		return err
 	}
 
+	// Watch for Loki exporters and migration target certificates and credentials
+	if err = watchAdditionalLokis(ctx, r.Common, &desired.Spec); err != nil {
+		return err
+	}
+
+	// Watch for trusted CA bundle injection; need to restart pods as the system bundle is only read at startup
+	if r.Proxy.UseTrustedCABundle() {
+		digest, err := r.ReconcileTrustedCABundle(ctx)
+		if err != nil || digest == "" {
+			// not deployed until the bundle is injected
+			return err
+		}
+		annotations[watchers.Annotation("trusted-ca")] = digest
+	}
+
 	// Watch for monitoring caCert
 	if err = reconcileMonitoringCerts(ctx, r.Common, &desired.Spec.Processor.Metrics.Server.TLS, r.Namespace); err != nil {
 		return err
@@ -136,20 +167,39 @@ func (r *monolithReconciler) reconcilePrometheusService(ctx context.Context, bui
 		return err
 	}
 	if r.AvailableAPIs.HasSvcMonitor() {
-		serviceMonitor := builder.generic.serviceMonitor()
-		if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.serviceMonitor, serviceMonitor, &report, helper.ServiceMonitorChanged); err != nil {
+		if err := reconcileServiceMonitors(ctx, r.Instance, builder.generic, r.serviceMonitor, r.healthMonitor, r.flowsMonitor, &report); err != nil {
 			return err
 		}
 	}
 	if r.AvailableAPIs.HasPromRule() {
 		promRules := builder.generic.prometheusRule()
-		if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.prometheusRule, promRules, &report, helper.PrometheusRuleChanged); err != nil {
+		if isEmptyPrometheusRule(promRules) {
+			// e.g. all alerts are disabled: don't leave a stale rule behind
+			r.Managed.TryDelete(ctx, r.prometheusRule)
+		} else if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.prometheusRule, promRules, &report, helper.PrometheusRuleChanged); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (r *monolithReconciler) reconcileExternalIngest(ctx context.Context, builder *monolithBuilder, desiredFLP *flowslatest.FlowCollectorFLP, annotations map[string]string) error {
+	if !helper.IsExternalIngestEnabled(desiredFLP) {
+		r.Managed.TryDelete(ctx, r.ingestService)
+		return nil
+	}
+	report := helper.NewChangeReport("FLP external ingest service")
+	defer report.LogIfNeeded(ctx)
+
+	if err := r.ReconcileService(ctx, r.ingestService, builder.externalIngestService(), &report); err != nil {
+		return err
+	}
+	if err := r.ReconcileClusterRole(ctx, buildClusterRoleIngestSender()); err != nil {
+		return err
+	}
+	return reconcileExternalIngestCerts(ctx, r.Common, &desiredFLP.ExternalIngest, annotations)
+}
+
 func (r *monolithReconciler) reconcileDaemonSet(ctx context.Context, desiredDS *appsv1.DaemonSet) error {
 	report := helper.NewChangeReport("FLP DaemonSet")
 	defer report.LogIfNeeded(ctx)
@@ -185,5 +235,8 @@ func (r *monolithReconciler) reconcilePermissions(ctx context.Context, builder *
 		}
 	}
 
-	return reconcileLokiRoles(ctx, r.Common, &builder.generic)
+	if err := reconcileLokiRoles(ctx, r.Common, &builder.generic); err != nil {
+		return err
+	}
+	return reconcileRBACProxyRoles(ctx, r.Common, &builder.generic)
 }
@@ -0,0 +1,89 @@
+package flp
+
+import (
+	"net/netip"
+	"slices"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/multus"
+)
+
+// addMultiHomingStages attributes flows on the secondary networks of pods to these pods, by matching their IP
+// addresses as subnets. A stage is needed per field, as each stage matches a single set of labels.
+func (b *PipelineBuilder) addMultiHomingStages(lastStage config.PipelineBuilderStage) config.PipelineBuilderStage {
+	if !helper.IsMultiHomingEnabled(&b.desired.Processor) {
+		return lastStage
+	}
+	labels := multiHomedPodsToSubnetLabels(b.multiHomedPods)
+	if len(labels.names) == 0 {
+		return lastStage
+	}
+	for _, stage := range []struct {
+		name   string
+		field  string
+		labels []api.NetworkTransformSubnetLabel
+	}{
+		{name: "enrich-multus", field: "K8S_Name", labels: labels.names},
+		{name: "enrich-multus-namespace", field: "K8S_Namespace", labels: labels.namespaces},
+		{name: "enrich-multus-owner", field: "K8S_OwnerName", labels: labels.owners},
+		{name: "enrich-multus-owner-type", field: "K8S_OwnerType", labels: labels.ownerTypes},
+		{name: "enrich-multus-type", field: "K8S_Type", labels: labels.types},
+	} {
+		lastStage = lastStage.TransformNetwork(stage.name, api.TransformNetwork{
+			Rules: api.NetworkTransformRules{
+				addSubnetLabelRule("SrcAddr", "Src"+stage.field),
+				addSubnetLabelRule("DstAddr", "Dst"+stage.field),
+			},
+			SubnetLabels: stage.labels,
+		})
+	}
+	return lastStage
+}
+
+type multiHomedSubnetLabels struct {
+	names, namespaces, owners, ownerTypes, types []api.NetworkTransformSubnetLabel
+}
+
+// multiHomedPodsToSubnetLabels returns the labels of the secondary addresses of pods, per field. Pods are sorted by
+// namespace, so that namespaces and owners are grouped to keep the configuration small.
+func multiHomedPodsToSubnetLabels(pods []multus.Pod) multiHomedSubnetLabels {
+	var l multiHomedSubnetLabels
+	podType := api.NetworkTransformSubnetLabel{Name: "Pod"}
+	for i := range pods {
+		var cidrs []string
+		for _, ip := range pods[i].IPs {
+			addr, err := netip.ParseAddr(ip)
+			// link-local addresses aren't unique to the pod
+			if err != nil || addr.IsLinkLocalUnicast() {
+				continue
+			}
+			cidrs = append(cidrs, netip.PrefixFrom(addr, addr.BitLen()).String())
+		}
+		if len(cidrs) == 0 {
+			continue
+		}
+		l.names = append(l.names, api.NetworkTransformSubnetLabel{Name: pods[i].Name, CIDRs: cidrs})
+		l.namespaces = appendSubnetLabel(l.namespaces, pods[i].Namespace, cidrs)
+		l.owners = appendSubnetLabel(l.owners, pods[i].OwnerName, cidrs)
+		l.ownerTypes = appendSubnetLabel(l.ownerTypes, pods[i].OwnerType, cidrs)
+		podType.CIDRs = append(podType.CIDRs, cidrs...)
+	}
+	if len(podType.CIDRs) > 0 {
+		l.types = []api.NetworkTransformSubnetLabel{podType}
+	}
+	return l
+}
+
+// appendSubnetLabel adds the CIDRs to the label with this name, if any, or to a new label
+func appendSubnetLabel(labels []api.NetworkTransformSubnetLabel, name string, cidrs []string) []api.NetworkTransformSubnetLabel {
+	for i := range labels {
+		if labels[i].Name == name {
+			labels[i].CIDRs = append(labels[i].CIDRs, cidrs...)
+			return labels
+		}
+	}
+	return append(labels, api.NetworkTransformSubnetLabel{Name: name, CIDRs: slices.Clone(cidrs)})
+}
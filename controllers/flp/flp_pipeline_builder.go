@@ -2,6 +2,7 @@ package flp
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -9,14 +10,18 @@ import (
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
 	promConfig "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/pkg/conversion"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/kubevirt"
 	"github.com/netobserv/network-observability-operator/pkg/loki"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
 	"github.com/netobserv/network-observability-operator/pkg/metrics"
+	"github.com/netobserv/network-observability-operator/pkg/multus"
 	"github.com/netobserv/network-observability-operator/pkg/volumes"
 )
 
@@ -25,15 +30,23 @@ type PipelineBuilder struct {
 	desired         *flowslatest.FlowCollectorSpec
 	flowMetrics     metricslatest.FlowMetricList
 	detectedSubnets []flowslatest.SubnetLabel
+	virtualMachines []kubevirt.VirtualMachine
+	multiHomedPods  []multus.Pod
+	clusterNetwork  *flowslatest.ClusterNetworkStatus
 	volumes         *volumes.Builder
 	loki            *helper.LokiConfig
 	clusterID       string
+	// lokiApp overrides the Loki `app` label, to write flows to a separate stream
+	lokiApp string
 }
 
 func newPipelineBuilder(
 	desired *flowslatest.FlowCollectorSpec,
 	flowMetrics *metricslatest.FlowMetricList,
 	detectedSubnets []flowslatest.SubnetLabel,
+	virtualMachines []kubevirt.VirtualMachine,
+	multiHomedPods []multus.Pod,
+	clusterNetwork *flowslatest.ClusterNetworkStatus,
 	loki *helper.LokiConfig,
 	clusterID string,
 	volumes *volumes.Builder,
@@ -44,6 +57,9 @@ func newPipelineBuilder(
 		desired:              desired,
 		flowMetrics:          *flowMetrics,
 		detectedSubnets:      detectedSubnets,
+		virtualMachines:      virtualMachines,
+		multiHomedPods:       multiHomedPods,
+		clusterNetwork:       clusterNetwork,
 		loki:                 loki,
 		clusterID:            clusterID,
 		volumes:              volumes,
@@ -136,61 +152,36 @@ func (b *PipelineBuilder) AddProcessorStages() error {
 		},
 		SubnetLabels: flpLabels,
 	})
+	enrichedStage = b.addVirtualMachineStages(enrichedStage)
+	enrichedStage = b.addMultiHomingStages(enrichedStage)
+	enrichedStage = b.addDSCPClassificationStage(enrichedStage)
+	enrichedStage = b.addEgressClassificationStages(enrichedStage)
+
+	// anonymization stages, applied before storage and exports
+	anonymizedStage := enrichedStage
+	if helper.IsAnonymizationEnabled(b.desired.Processor.Anonymization) {
+		anonymizedStage = addAnonymizationStages("anonymize", enrichedStage, b.desired.Processor.Anonymization)
+	}
 
 	// loki stage (write) configuration
-	advancedConfig := helper.GetAdvancedLokiConfig(b.desired.Loki.Advanced)
 	if helper.UseLoki(b.desired) {
-		lokiWrite := api.WriteLoki{
-			Labels:         loki.GetLokiLabels(b.desired),
-			BatchSize:      int(b.desired.Loki.WriteBatchSize),
-			BatchWait:      helper.UnstructuredDuration(b.desired.Loki.WriteBatchWait),
-			MaxBackoff:     helper.UnstructuredDuration(advancedConfig.WriteMaxBackoff),
-			MaxRetries:     int(helper.PtrInt32(advancedConfig.WriteMaxRetries)),
-			MinBackoff:     helper.UnstructuredDuration(advancedConfig.WriteMinBackoff),
-			StaticLabels:   model.LabelSet{},
-			Timeout:        helper.UnstructuredDuration(b.desired.Loki.WriteTimeout),
-			URL:            b.loki.IngesterURL,
-			TimestampLabel: "TimeFlowEndMs",
-			TimestampScale: "1ms",
-			TenantID:       b.loki.TenantID,
-		}
-
-		for k, v := range advancedConfig.StaticLabels {
-			lokiWrite.StaticLabels[model.LabelName(k)] = model.LabelValue(v)
-		}
-
-		var authorization *promConfig.Authorization
-		if b.loki.UseHostToken() || b.loki.UseForwardToken() {
-			b.volumes.AddToken(constants.FLPName)
-			authorization = &promConfig.Authorization{
-				Type:            "Bearer",
-				CredentialsFile: constants.TokensPath + constants.FLPName,
-			}
+		lokiWrite, err := b.lokiWrite(b.loki, "loki", b.desired.Loki.WriteBatchSize, b.desired.Loki.WriteBatchWait, b.desired.Loki.WriteTimeout)
+		if err != nil {
+			return err
 		}
-
-		if b.loki.TLS.Enable {
-			if b.loki.TLS.InsecureSkipVerify {
-				lokiWrite.ClientConfig = &promConfig.HTTPClientConfig{
-					Authorization: authorization,
-					TLSConfig: promConfig.TLSConfig{
-						InsecureSkipVerify: true,
-					},
-				}
-			} else {
-				caPath := b.volumes.AddCACertificate(&b.loki.TLS, "loki-certs")
-				lokiWrite.ClientConfig = &promConfig.HTTPClientConfig{
-					Authorization: authorization,
-					TLSConfig: promConfig.TLSConfig{
-						CAFile: caPath,
-					},
-				}
-			}
-		} else {
-			lokiWrite.ClientConfig = &promConfig.HTTPClientConfig{
-				Authorization: authorization,
+		lokiStage := anonymizedStage
+		if helper.IsLokiAggregationEnabled(&b.desired.Loki) {
+			lokiStage = b.addLokiAggregation(anonymizedStage)
+		}
+		lokiStage.WriteLoki("loki", lokiWrite)
+		// during a migration, flows are also written to the target Loki
+		if target := helper.NewLokiMigrationTargetConfig(b.desired); target != nil {
+			migrationWrite, err := b.lokiWrite(target, "loki-migration", b.desired.Loki.WriteBatchSize, b.desired.Loki.WriteBatchWait, b.desired.Loki.WriteTimeout)
+			if err != nil {
+				return fmt.Errorf("loki migration target: %w", err)
 			}
+			lokiStage.WriteLoki("loki-migration", migrationWrite)
 		}
-		enrichedStage.WriteLoki("loki", lokiWrite)
 	}
 
 	// write on Stdout if logging trace enabled
@@ -201,27 +192,201 @@ func (b *PipelineBuilder) AddProcessorStages() error {
 	// obtain encode_prometheus stage from metrics_definitions
 	names := metrics.GetIncludeList(b.desired)
 	promMetrics := metrics.GetDefinitions(names)
+	promMetrics = append(promMetrics, analyticsMetrics(&b.desired.Analytics)...)
+	promMetrics = append(promMetrics, retentionMetrics(b.desired)...)
 
 	for i := range b.flowMetrics.Items {
 		fm := &b.flowMetrics.Items[i]
 		m, err := flowMetricToFLP(&fm.Spec)
 		if err != nil {
-			return fmt.Errorf("error reading FlowMetric definition '%s': %w", fm.Name, err)
+			return status.InvalidSpecError("error reading FlowMetric definition '%s': %w", fm.Name, err)
 		}
 		promMetrics = append(promMetrics, *m)
 	}
 
 	if len(promMetrics) > 0 {
+		// metrics are anonymized as the other outputs; the service mesh enrichment reads the IPs, hence runs first
+		promStage := anonymizedStage
+		if helper.IsServiceMeshEnabled(&b.desired.Processor) {
+			promStage = b.addServiceMeshStages(enrichedStage)
+			if helper.IsAnonymizationEnabled(b.desired.Processor.Anonymization) {
+				promStage = addAnonymizationStages("anonymize-metrics", promStage, b.desired.Processor.Anonymization)
+			}
+		}
+		if rules := flowMetricsRemapRules(&b.flowMetrics); len(rules) > 0 {
+			promStage = promStage.TransformGeneric("metrics-remap", api.TransformGeneric{Policy: api.PreserveOriginalKeys, Rules: rules})
+		}
+		if labels := b.desired.Processor.Metrics.StaticLabels; len(labels) > 0 {
+			promStage, promMetrics = addMetricsStaticLabels(promStage, promMetrics, labels)
+		}
 		// prometheus stage (encode) configuration
 		promEncode := api.PromEncode{
-			Prefix:  "netobserv_",
+			Prefix:  metrics.GetPrefix(b.desired),
 			Metrics: promMetrics,
 		}
-		enrichedStage.EncodePrometheus("prometheus", promEncode)
+		promStage.EncodePrometheus("prometheus", promEncode)
 	}
 
-	b.addCustomExportStages(&enrichedStage)
-	return nil
+	return b.addCustomExportStages(&enrichedStage, &anonymizedStage)
+}
+
+// lokiWrite builds a Loki write stage configuration; volumePrefix makes the names of the mounted credentials unique per Loki instance
+func (b *PipelineBuilder) lokiWrite(lc *helper.LokiConfig, volumePrefix string, batchSize int64, batchWait, timeout *metav1.Duration) (api.WriteLoki, error) {
+	advancedConfig := helper.GetAdvancedLokiConfig(b.desired.Loki.Advanced)
+	lokiWrite := api.WriteLoki{
+		Labels:         loki.GetLokiLabels(b.desired),
+		BatchSize:      int(batchSize),
+		BatchWait:      helper.UnstructuredDuration(batchWait),
+		MaxBackoff:     helper.UnstructuredDuration(advancedConfig.WriteMaxBackoff),
+		MaxRetries:     int(helper.PtrInt32(advancedConfig.WriteMaxRetries)),
+		MinBackoff:     helper.UnstructuredDuration(advancedConfig.WriteMinBackoff),
+		StaticLabels:   model.LabelSet{},
+		Timeout:        helper.UnstructuredDuration(timeout),
+		URL:            lc.IngesterURL,
+		TimestampLabel: "TimeFlowEndMs",
+		TimestampScale: "1ms",
+		TenantID:       lc.TenantID,
+	}
+
+	for k, v := range advancedConfig.StaticLabels {
+		lokiWrite.StaticLabels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	if b.lokiApp != "" {
+		lokiWrite.StaticLabels["app"] = model.LabelValue(b.lokiApp)
+	}
+
+	var authorization *promConfig.Authorization
+	if lc.UseHostToken() || lc.UseForwardToken() {
+		b.volumes.AddToken(constants.FLPName)
+		authorization = &promConfig.Authorization{
+			Type:            "Bearer",
+			CredentialsFile: constants.TokensPath + constants.FLPName,
+		}
+	} else if lc.UseSecretToken() {
+		authorization = &promConfig.Authorization{
+			Type:            "Bearer",
+			CredentialsFile: b.volumes.AddVolume(lc.AuthTokenSecret, volumePrefix+"-token"),
+		}
+	} else if lc.AuthToken == flowslatest.LokiAuthSecretToken {
+		return lokiWrite, status.InvalidSpecError("loki authToken %s requires authTokenSecret", lc.AuthToken)
+	}
+
+	var basicAuth *promConfig.BasicAuth
+	if lc.BasicAuth != nil {
+		if authorization != nil {
+			return lokiWrite, status.InvalidSpecError("loki basicAuth can't be combined with authToken %s", lc.AuthToken)
+		}
+		basicAuth = &promConfig.BasicAuth{
+			Username:     lc.BasicAuth.Username,
+			PasswordFile: b.volumes.AddVolume(&lc.BasicAuth.Password, volumePrefix+"-basic-auth"),
+		}
+	}
+
+	lokiWrite.ClientConfig = &promConfig.HTTPClientConfig{
+		Authorization: authorization,
+		BasicAuth:     basicAuth,
+	}
+	if lc.TLS.Enable {
+		if lc.TLS.InsecureSkipVerify {
+			lokiWrite.ClientConfig.TLSConfig = promConfig.TLSConfig{
+				InsecureSkipVerify: true,
+			}
+		} else {
+			caPath := b.volumes.AddCACertificate(&lc.TLS, volumePrefix+"-certs")
+			lokiWrite.ClientConfig.TLSConfig = promConfig.TLSConfig{
+				CAFile: caPath,
+			}
+		}
+	}
+	return lokiWrite, nil
+}
+
+// addMetricsStaticLabels sets the static labels as flow fields, in a stage dedicated to metrics so that they aren't sent to other outputs,
+// and adds them to the labels of every metric
+func addMetricsStaticLabels(lastStage config.PipelineBuilderStage, promMetrics []api.MetricsItem, labels map[string]string) (config.PipelineBuilderStage, []api.MetricsItem) {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var rules []api.TransformFilterRule
+	for _, k := range keys {
+		rules = append(rules, api.TransformFilterRule{
+			Type: api.AddField,
+			AddField: &api.TransformFilterGenericRule{
+				Input: k,
+				Value: labels[k],
+			},
+		})
+	}
+	items := make([]api.MetricsItem, 0, len(promMetrics))
+	for _, m := range promMetrics {
+		// copy labels so that predefined definitions are not altered
+		m.Labels = append(append([]string{}, m.Labels...), keys...)
+		items = append(items, m)
+	}
+	return lastStage.TransformFilter("metrics-labels", api.TransformFilter{Rules: rules}), items
+}
+
+func addAnonymizationStages(name string, lastStage config.PipelineBuilderStage, spec *flowslatest.Anonymization) config.PipelineBuilderStage {
+	var addrFields []string
+	if spec.IPs == flowslatest.AnonymizationIPTruncate || spec.IPs == flowslatest.AnonymizationIPDrop {
+		addrFields = []string{"SrcAddr", "DstAddr"}
+	}
+	if spec.IPs == flowslatest.AnonymizationIPTruncate {
+		mask := fmt.Sprintf("/%d", helper.GetAnonymizationPrefixLength(spec))
+		rules := api.NetworkTransformRules{}
+		for _, field := range addrFields {
+			rules = append(rules, api.NetworkTransformRule{
+				Type: api.NetworkAddSubnet,
+				AddSubnet: &api.NetworkAddSubnetRule{
+					Input:      field,
+					Output:     field,
+					SubnetMask: mask,
+				},
+			})
+		}
+		lastStage = lastStage.TransformNetwork(name+"-truncate", api.TransformNetwork{Rules: rules})
+	}
+
+	var removedFields []string
+	if spec.IPs == flowslatest.AnonymizationIPDrop {
+		removedFields = append(removedFields, addrFields...)
+	}
+	if spec.DropMACs {
+		removedFields = append(removedFields, "SrcMac", "DstMac")
+	}
+	if spec.DropPodNames {
+		for _, side := range []string{"Src", "Dst"} {
+			removedFields = append(removedFields, side+"K8S_Name", side+"K8S_OwnerName", side+"K8S_HostName", side+"K8S_HostIP")
+		}
+	}
+	if len(removedFields) > 0 {
+		rules := []api.TransformFilterRule{}
+		for _, field := range removedFields {
+			rules = append(rules, api.TransformFilterRule{
+				Type:        api.RemoveField,
+				RemoveField: &api.TransformFilterGenericRule{Input: field},
+			})
+		}
+		lastStage = lastStage.TransformFilter(name, api.TransformFilter{Rules: rules})
+	}
+	return lastStage
+}
+
+// flowMetricsRemapRules returns the rules copying the remapped flow fields into the fields named after the metric labels,
+// in a stage dedicated to metrics so that they aren't sent to other outputs
+func flowMetricsRemapRules(flowMetrics *metricslatest.FlowMetricList) []api.GenericTransformRule {
+	var rules []api.GenericTransformRule
+	for i := range flowMetrics.Items {
+		for _, pair := range helper.KeySorted(flowMetrics.Items[i].Spec.Remap) {
+			rule := api.GenericTransformRule{Input: pair[0], Output: pair[1]}
+			if !slices.Contains(rules, rule) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
 }
 
 func flowMetricToFLP(flowMetric *metricslatest.FlowMetricSpec) (*api.MetricsItem, error) {
@@ -229,9 +394,19 @@ func flowMetricToFLP(flowMetric *metricslatest.FlowMetricSpec) (*api.MetricsItem
 		Name:     flowMetric.MetricName,
 		Type:     api.MetricEncodeOperationEnum(strings.ToLower(string(flowMetric.Type))),
 		Filters:  []api.MetricsFilter{},
-		Labels:   flowMetric.Labels,
+		Labels:   make([]string, 0, len(flowMetric.Labels)),
 		ValueKey: flowMetric.ValueField,
 	}
+	for _, l := range flowMetric.Labels {
+		m.Labels = append(m.Labels, flowMetric.LabelName(l))
+	}
+	if flowMetric.Divider != "" {
+		divider, err := strconv.ParseFloat(flowMetric.Divider, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse metric divider as float: '%s'; error was: %w", flowMetric.Divider, err)
+		}
+		m.ValueScale = divider
+	}
 	for _, f := range flowMetric.Filters {
 		m.Filters = append(m.Filters, api.MetricsFilter{Key: f.Field, Value: f.Value, Type: api.MetricFilterEnum(conversion.PascalToLower(string(f.MatchType), '_'))})
 	}
@@ -250,6 +425,13 @@ func flowMetricToFLP(flowMetric *metricslatest.FlowMetricSpec) (*api.MetricsItem
 		}
 		m.Buckets = append(m.Buckets, f)
 	}
+	for _, rr := range flowMetric.RecordingRules {
+		for _, l := range rr.Labels {
+			if !slices.Contains(flowMetric.Labels, l) {
+				return nil, fmt.Errorf("recording rule label '%s' is not a label of the metric", l)
+			}
+		}
+	}
 	return m, nil
 }
 
@@ -429,24 +611,131 @@ func (b *PipelineBuilder) addTransformFilter(lastStage config.PipelineBuilderSta
 	return lastStage
 }
 
-func (b *PipelineBuilder) addCustomExportStages(enrichedStage *config.PipelineBuilderStage) {
+func (b *PipelineBuilder) addCustomExportStages(enrichedStage, anonymizedStage *config.PipelineBuilderStage) error {
 	for i, exporter := range b.desired.Exporters {
+		// exporters may override the processor anonymization settings
+		fromStage := anonymizedStage
+		if exporter.Anonymization != nil {
+			fromStage = enrichedStage
+			if helper.IsAnonymizationEnabled(exporter.Anonymization) {
+				stage := addAnonymizationStages(fmt.Sprintf("anonymize-export-%d", i), *enrichedStage, exporter.Anonymization)
+				fromStage = &stage
+			}
+		}
+		if len(exporter.Filters) > 0 || exporter.Fields != nil {
+			stage := addExporterSelectionStages(i, *fromStage, exporter)
+			fromStage = &stage
+		}
 		if exporter.Type == flowslatest.KafkaExporter {
-			b.createKafkaWriteStage(fmt.Sprintf("kafka-export-%d", i), &exporter.Kafka, enrichedStage)
+			b.createKafkaWriteStage(fmt.Sprintf("kafka-export-%d", i), &exporter.Kafka, fromStage)
 		}
 		if exporter.Type == flowslatest.IpfixExporter {
-			createIPFIXWriteStage(fmt.Sprintf("IPFIX-export-%d", i), &exporter.IPFIX, enrichedStage)
+			createIPFIXWriteStage(fmt.Sprintf("IPFIX-export-%d", i), &exporter.IPFIX, fromStage)
+		}
+		if exporter.Type == flowslatest.LokiExporter {
+			name := fmt.Sprintf("loki-export-%d", i)
+			lc := helper.NewLokiExporterConfig(&exporter.Loki)
+			lokiWrite, err := b.lokiWrite(&lc, name, exporter.Loki.WriteBatchSize, exporter.Loki.WriteBatchWait, exporter.Loki.WriteTimeout)
+			if err != nil {
+				return fmt.Errorf("exporter %d: %w", i, err)
+			}
+			fromStage.WriteLoki(name, lokiWrite)
+		}
+		if exporter.Type == flowslatest.ClusterLogForwarderExporter {
+			// flows are written as JSON lines, collected by the ClusterLogForwarder from the container logs
+			fromStage.WriteStdout(fmt.Sprintf("clf-export-%d", i), api.WriteStdout{Format: "json"})
+		}
+	}
+	return nil
+}
+
+// addExporterSelectionStages adds the stages to filter flows and select fields sent to a single exporter
+func addExporterSelectionStages(index int, lastStage config.PipelineBuilderStage, exporter *flowslatest.FlowCollectorExporter) config.PipelineBuilderStage {
+	if len(exporter.Filters) > 0 {
+		rules := []api.TransformFilterRule{}
+		for _, f := range exporter.Filters {
+			rules = append(rules, exporterFilterToFLP(&f)...)
+		}
+		lastStage = lastStage.TransformFilter(fmt.Sprintf("filter-export-%d", index), api.TransformFilter{Rules: rules})
+	}
+	if exporter.Fields != nil {
+		if len(exporter.Fields.Include) > 0 {
+			rules := []api.GenericTransformRule{}
+			for _, field := range exporter.Fields.Include {
+				rules = append(rules, api.GenericTransformRule{Input: field, Output: field})
+			}
+			lastStage = lastStage.TransformGeneric(fmt.Sprintf("include-export-%d", index), api.TransformGeneric{
+				Policy: api.ReplaceKeys,
+				Rules:  rules,
+			})
+		}
+		if len(exporter.Fields.Exclude) > 0 {
+			rules := []api.TransformFilterRule{}
+			for _, field := range exporter.Fields.Exclude {
+				rules = append(rules, api.TransformFilterRule{
+					Type:        api.RemoveField,
+					RemoveField: &api.TransformFilterGenericRule{Input: field},
+				})
+			}
+			lastStage = lastStage.TransformFilter(fmt.Sprintf("exclude-export-%d", index), api.TransformFilter{Rules: rules})
 		}
 	}
+	return lastStage
+}
+
+// exporterFilterToFLP converts a filter describing the flows to keep into FLP rules describing the flows to remove
+func exporterFilterToFLP(f *flowslatest.ExporterFilter) []api.TransformFilterRule {
+	switch f.MatchType {
+	case flowslatest.ExporterFilterNotEqual:
+		return []api.TransformFilterRule{{
+			Type:               api.RemoveEntryIfEqual,
+			RemoveEntryIfEqual: &api.TransformFilterGenericRule{Input: f.Field, Value: f.Value},
+		}}
+	case flowslatest.ExporterFilterPresence:
+		return []api.TransformFilterRule{{
+			Type:                     api.RemoveEntryIfDoesntExist,
+			RemoveEntryIfDoesntExist: &api.TransformFilterGenericRule{Input: f.Field},
+		}}
+	case flowslatest.ExporterFilterAbsence:
+		return []api.TransformFilterRule{{
+			Type:                api.RemoveEntryIfExists,
+			RemoveEntryIfExists: &api.TransformFilterGenericRule{Input: f.Field},
+		}}
+	default:
+		// flows without the field must be removed too, as they aren't covered by remove_entry_if_not_equal
+		return []api.TransformFilterRule{{
+			Type:                     api.RemoveEntryIfDoesntExist,
+			RemoveEntryIfDoesntExist: &api.TransformFilterGenericRule{Input: f.Field},
+		}, {
+			Type:                  api.RemoveEntryIfNotEqual,
+			RemoveEntryIfNotEqual: &api.TransformFilterGenericRule{Input: f.Field, Value: f.Value},
+		}}
+	}
 }
 
 func (b *PipelineBuilder) createKafkaWriteStage(name string, spec *flowslatest.FlowCollectorKafka, fromStage *config.PipelineBuilderStage) config.PipelineBuilderStage {
-	return fromStage.EncodeKafka(name, api.EncodeKafka{
+	encode := api.EncodeKafka{
 		Address: spec.Address,
 		Topic:   spec.Topic,
 		TLS:     getKafkaTLS(&spec.TLS, name, b.volumes),
 		SASL:    getKafkaSASL(&spec.SASL, name, b.volumes),
-	})
+	}
+	if adv := spec.Advanced; adv != nil {
+		if adv.BatchBytes != nil {
+			encode.BatchBytes = *adv.BatchBytes
+		}
+		if adv.BatchMessages != nil {
+			encode.BatchSize = int(*adv.BatchMessages)
+		}
+		if adv.Balancer != "" {
+			// FLP balancers are camel case, e.g. "RoundRobin" => "roundRobin"
+			encode.Balancer = api.KafkaEncodeBalancerEnum(strings.ToLower(string(adv.Balancer[:1])) + string(adv.Balancer[1:]))
+		}
+		if adv.WriteTimeout != nil {
+			encode.WriteTimeout = int64(adv.WriteTimeout.Duration.Seconds())
+		}
+	}
+	return fromStage.EncodeKafka(name, encode)
 }
 
 func (b *PipelineBuilder) AddKafkaWriteStage(name string, spec *flowslatest.FlowCollectorKafka) config.PipelineBuilderStage {
@@ -0,0 +1,93 @@
+package flp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
+)
+
+const (
+	// stageErrorRatioQuery is the error ratio of each stage, as evaluated by the NetObservPipelineErrors alert
+	stageErrorRatioQuery          = "sum by (stage) (rate(netobserv_ingest_errors[5m])) / ignoring(stage) group_left sum(rate(netobserv_ingest_flows_processed[5m]))"
+	defaultPipelineHealthInterval = 5 * time.Minute
+	minPipelineHealthInterval     = time.Minute
+	pipelineDegradedReason        = "PipelineDegraded"
+)
+
+// pipelineHealth is the last result of the pipeline health check
+type pipelineHealth struct {
+	lastCheck time.Time
+	warning   string
+}
+
+func pipelineHealthInterval(spec *flowslatest.PipelineHealthCheck) time.Duration {
+	if spec.Interval == nil || spec.Interval.Duration <= 0 {
+		return defaultPipelineHealthInterval
+	}
+	return max(spec.Interval.Duration, minPipelineHealthInterval)
+}
+
+// degradedStages returns a warning listing the stages whose error ratio exceeds the alert threshold, or an empty string if none
+func degradedStages(samples []prometheus.Sample) string {
+	threshold, _ := strconv.ParseFloat(pipelineErrorRatioThreshold, 64)
+	var stages []string
+	for _, s := range samples {
+		if s.Value > threshold {
+			stages = append(stages, fmt.Sprintf("%s (%.1f%%)", s.Labels["stage"], s.Value*100))
+		}
+	}
+	if len(stages) == 0 {
+		return ""
+	}
+	sort.Strings(stages)
+	return fmt.Sprintf("the flowlogs-pipeline error ratio exceeds %.0f%% in stages: %s", threshold*100, strings.Join(stages, ", "))
+}
+
+// checkPipelineHealth returns a warning when a flowlogs-pipeline stage is degraded. The error ratio is queried once per interval;
+// in between, the last result is kept.
+func (r *Reconciler) checkPipelineHealth(ctx context.Context, fc *flowslatest.FlowCollector, now time.Time) (string, error) {
+	if !helper.IsPipelineHealthCheckEnabled(&fc.Spec.Processor.Metrics) {
+		r.pipelineHealth = nil
+		r.closeHealthQuerier()
+		return "", nil
+	}
+	spec := &fc.Spec.Processor.Metrics.PipelineHealthCheck
+	if r.pipelineHealth != nil && now.Sub(r.pipelineHealth.lastCheck) < pipelineHealthInterval(spec) {
+		return r.pipelineHealth.warning, nil
+	}
+
+	if r.healthQuerier == nil || !r.healthQuerier.Matches(spec.URL, spec.InsecureSkipVerify) {
+		r.closeHealthQuerier()
+		r.healthQuerier = prometheus.NewQuerier(spec.URL, spec.InsecureSkipVerify, r.mgr.GetConfig())
+	}
+	samples, err := r.healthQuerier.Query(ctx, stageErrorRatioQuery)
+	if err != nil {
+		// retried at the next interval
+		r.pipelineHealth = &pipelineHealth{lastCheck: now}
+		return "", fmt.Errorf("could not query the flowlogs-pipeline error ratio: %w", err)
+	}
+	r.pipelineHealth = &pipelineHealth{lastCheck: now, warning: degradedStages(samples)}
+	return r.pipelineHealth.warning, nil
+}
+
+// nextHealthCheck returns the delay until the error ratio must be checked again, or zero when the check is disabled
+func (r *Reconciler) nextHealthCheck(spec *flowslatest.FLPMetrics, now time.Time) time.Duration {
+	if r.pipelineHealth == nil {
+		return 0
+	}
+	return max(pipelineHealthInterval(&spec.PipelineHealthCheck)-now.Sub(r.pipelineHealth.lastCheck), time.Second)
+}
+
+func (r *Reconciler) closeHealthQuerier() {
+	if r.healthQuerier != nil {
+		r.healthQuerier.Close()
+		r.healthQuerier = nil
+	}
+}
@@ -0,0 +1,120 @@
+package flp
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const (
+	rbacProxyName = "kube-rbac-proxy"
+	// rbacProxyUpstreamPort is the port of the metrics server, listening on localhost only, when kube-rbac-proxy is in front of it
+	rbacProxyUpstreamPort = 9401
+	// rbacProxyTokenFile is the token used by Prometheus to authenticate against kube-rbac-proxy
+	rbacProxyTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+func rbacProxyRoleName() string                   { return constants.FLPName + "-" + rbacProxyName }
+func rbacProxyRoleBindingName(ck ConfKind) string { return name(ck) + "-" + rbacProxyName }
+
+func (b *builder) useRBACProxy() bool {
+	return helper.IsMetricsRBACProxyEnabled(&b.desired.Processor.Metrics)
+}
+
+func (b *builder) rbacProxyContainer() corev1.Container {
+	cert, key := b.volumes.AddCertificate(b.promTLS, "prom-certs")
+	var mounts []corev1.VolumeMount
+	for _, m := range b.volumes.GetMounts() {
+		if m.Name == "prom-certs" {
+			mounts = append(mounts, m)
+		}
+	}
+	return corev1.Container{
+		Name:            rbacProxyName,
+		Image:           b.info.RBACProxyImage,
+		ImagePullPolicy: corev1.PullPolicy(b.desired.Processor.ImagePullPolicy),
+		Args: []string{
+			fmt.Sprintf("--secure-listen-address=0.0.0.0:%d", b.desired.Processor.Metrics.Server.Port),
+			fmt.Sprintf("--upstream=http://127.0.0.1:%d/", rbacProxyUpstreamPort),
+			"--allow-paths=/metrics",
+			"--tls-cert-file=" + cert,
+			"--tls-private-key-file=" + key,
+			"--http2-disable",
+		},
+		Ports: []corev1.ContainerPort{{
+			Name:          prometheusServiceName,
+			ContainerPort: b.desired.Processor.Metrics.Server.Port,
+		}},
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("5m"),
+				corev1.ResourceMemory: resource.MustParse("20Mi"),
+			},
+		},
+		VolumeMounts:    mounts,
+		SecurityContext: helper.ContainerDefaultSecurityContext(),
+	}
+}
+
+// The operator needs to have at least the same permissions as kube-rbac-proxy in order to grant them
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+func buildClusterRoleRBACProxy() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: rbacProxyRoleName(),
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{"authentication.k8s.io"},
+			Verbs:     []string{"create"},
+			Resources: []string{"tokenreviews"},
+		}, {
+			APIGroups: []string{"authorization.k8s.io"},
+			Verbs:     []string{"create"},
+			Resources: []string{"subjectaccessreviews"},
+		}},
+	}
+}
+
+func (b *builder) clusterRoleBindingRBACProxy() *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: rbacProxyRoleBindingName(b.confKind),
+			Labels: map[string]string{
+				"app": b.name(),
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     rbacProxyRoleName(),
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Name:      b.name(),
+			Namespace: b.info.Namespace,
+		}},
+	}
+}
+
+func reconcileRBACProxyRoles(ctx context.Context, r *reconcilers.Common, b *builder) error {
+	if !b.useRBACProxy() && !b.useExternalIngest() {
+		return nil
+	}
+	if err := r.ReconcileClusterRole(ctx, buildClusterRoleRBACProxy()); err != nil {
+		return err
+	}
+	return r.ReconcileClusterRoleBinding(ctx, b.clusterRoleBindingRBACProxy())
+}
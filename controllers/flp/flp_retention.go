@@ -0,0 +1,58 @@
+package flp
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/metrics"
+)
+
+// longTermBaseMetrics are aggregated per pair of namespaces, for long-term retention
+var longTermBaseMetrics = []string{"namespace_flows_total", "namespace_ingress_bytes_total", "namespace_ingress_packets_total"}
+
+// retentionMetrics returns the base metrics needed by the long-term aggregates, which are not already generated from the include list
+func retentionMetrics(spec *flowslatest.FlowCollectorSpec) []api.MetricsItem {
+	if !helper.IsLongTermAggregatesEnabled(&spec.RetentionPolicy) {
+		return nil
+	}
+	included := metrics.GetIncludeList(spec)
+	var missing []string
+	for _, name := range longTermBaseMetrics {
+		if !slices.Contains(included, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return metrics.GetDefinitions(missing)
+}
+
+// retentionRules returns the recording rules materializing the long-term aggregates
+func (b *builder) retentionRules() []monitoringv1.Rule {
+	if !b.ownsClusterRules() || !helper.IsLongTermAggregatesEnabled(&b.desired.RetentionPolicy) {
+		return nil
+	}
+	window := model.Duration(time.Hour)
+	if b.desired.RetentionPolicy.AggregationWindow != nil {
+		window = model.Duration(b.desired.RetentionPolicy.AggregationWindow.Duration)
+	}
+	prefix := metrics.GetPrefix(b.desired)
+	var rules []monitoringv1.Rule
+	for _, name := range longTermBaseMetrics {
+		rules = append(rules, monitoringv1.Rule{
+			Record: fmt.Sprintf("netobserv:%s:rate%s", strings.TrimSuffix(name, "_total"), window.String()),
+			Expr:   intstr.FromString(fmt.Sprintf("sum by (SrcK8S_Namespace, DstK8S_Namespace) (rate(%s%s[%s]))", prefix, name, window.String())),
+		})
+	}
+	return rules
+}
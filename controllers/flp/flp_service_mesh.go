@@ -0,0 +1,60 @@
+package flp
+
+import (
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+// meshField is a field read from a label that the Istio sidecar injector sets on pods
+type meshField struct {
+	label string
+	field string
+	// value when the label is missing, as reported by the Istio telemetry
+	unknown string
+}
+
+var meshFields = []meshField{
+	{label: "service.istio.io/canonical-name", field: "MeshService", unknown: "unknown"},
+	{label: "service.istio.io/canonical-revision", field: "MeshRevision", unknown: "unknown"},
+	{label: "security.istio.io/tlsMode", field: "MeshTLSMode", unknown: "disabled"},
+}
+
+// addServiceMeshStages copies the Istio labels of pods into dedicated fields. The Kubernetes enrichment copies all
+// the labels of pods, hence it is only done on the metrics branch, where only the fields used as labels are kept.
+func (b *PipelineBuilder) addServiceMeshStages(lastStage config.PipelineBuilderStage) config.PipelineBuilderStage {
+	if !helper.IsServiceMeshEnabled(&b.desired.Processor) {
+		return lastStage
+	}
+	addZone := helper.IsZoneEnabled(&b.desired.Processor)
+	var defaults []api.TransformFilterRule
+	var renames []api.GenericTransformRule
+	var rules api.NetworkTransformRules
+	for _, side := range []string{"Src", "Dst"} {
+		prefix := side + "K8S_Labels"
+		rules = append(rules, api.NetworkTransformRule{
+			Type: api.NetworkAddKubernetes,
+			Kubernetes: &api.K8sRule{
+				Input:        side + "Addr",
+				Output:       side + "K8S",
+				LabelsPrefix: prefix,
+				AddZone:      addZone,
+			},
+		})
+		for _, f := range meshFields {
+			input := prefix + "_" + f.label
+			defaults = append(defaults, api.TransformFilterRule{
+				Type:                  api.AddFieldIfDoesntExist,
+				AddFieldIfDoesntExist: &api.TransformFilterGenericRule{Input: input, Value: f.unknown},
+			})
+			renames = append(renames, api.GenericTransformRule{Input: input, Output: side + "K8S_" + f.field})
+		}
+	}
+	lastStage = lastStage.TransformNetwork("enrich-mesh", api.TransformNetwork{Rules: rules})
+	lastStage = lastStage.TransformFilter("mesh-defaults", api.TransformFilter{Rules: defaults})
+	return lastStage.TransformGeneric("mesh-fields", api.TransformGeneric{
+		Policy: api.PreserveOriginalKeys,
+		Rules:  renames,
+	})
+}
@@ -17,13 +17,19 @@ limitations under the License.
 package flp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	ascv2 "k8s.io/api/autoscaling/v2"
@@ -33,12 +39,19 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 
+	endpointslatest "github.com/netobserv/network-observability-operator/apis/externalendpoints/v1alpha1"
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/discover"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/kubevirt"
+	"github.com/netobserv/network-observability-operator/pkg/manager"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+	"github.com/netobserv/network-observability-operator/pkg/metrics"
+	"github.com/netobserv/network-observability-operator/pkg/multus"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
 )
 
 var resources = corev1.ResourceRequirements{
@@ -273,10 +286,12 @@ func TestDaemonSetChanged(t *testing.T) {
 	_, digest, err = b.configMap()
 	assert.NoError(err)
 	fourth := b.daemonSet(annotate(digest))
+	assert.Contains(fourth.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "GOMEMLIMIT", Value: "483183820800"})
 
 	report = helper.NewChangeReport("")
 	assert.True(helper.PodChanged(&third.Spec.Template, &fourth.Spec.Template, constants.FLPName, &report))
-	assert.Contains(report.String(), "req/limit changed")
+	// GOMEMLIMIT follows the memory limit, so the env changes too
+	assert.Contains(report.String(), "Env changed")
 
 	// Check reverting limits
 	cfg.Processor.Resources.Limits = map[corev1.ResourceName]resource.Quantity{
@@ -290,7 +305,7 @@ func TestDaemonSetChanged(t *testing.T) {
 
 	report = helper.NewChangeReport("")
 	assert.True(helper.PodChanged(&fourth.Spec.Template, &fifth.Spec.Template, constants.FLPName, &report))
-	assert.Contains(report.String(), "req/limit changed")
+	assert.Contains(report.String(), "Env changed")
 	report = helper.NewChangeReport("")
 	assert.False(helper.PodChanged(&third.Spec.Template, &fifth.Spec.Template, constants.FLPName, &report))
 	assert.Contains(report.String(), "no change")
@@ -332,6 +347,78 @@ func TestDaemonSetChanged(t *testing.T) {
 	assert.Contains(report.String(), "Volumes changed")
 }
 
+func TestDaemonSetUpdateStrategy(t *testing.T) {
+	assert := assert.New(t)
+
+	ns := "namespace"
+	cfg := getConfig()
+	b := monoBuilder(ns, &cfg)
+	_, digest, err := b.configMap()
+	assert.NoError(err)
+	first := b.daemonSet(annotate(digest))
+	assert.Empty(first.Spec.UpdateStrategy.Type)
+
+	maxUnavailable := intstr.FromString("10%")
+	cfg.Processor.Advanced.UpdateStrategy = &appsv1.DaemonSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+	}
+	b = monoBuilder(ns, &cfg)
+	second := b.daemonSet(annotate(digest))
+	assert.Equal(maxUnavailable, *second.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable)
+
+	report := helper.NewChangeReport("")
+	assert.False(helper.PodChanged(&first.Spec.Template, &second.Spec.Template, constants.FLPName, &report))
+	assert.True(helper.DaemonSetUpdateStrategyChanged(first, second, &report))
+	assert.Contains(report.String(), "Update strategy changed")
+
+	// The cluster defaults are not a change
+	defaulted := first.DeepCopy()
+	defaulted.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: ptr.To(intstr.FromInt32(1)), MaxSurge: ptr.To(intstr.FromInt32(0))},
+	}
+	report = helper.NewChangeReport("")
+	assert.False(helper.DaemonSetUpdateStrategyChanged(defaulted, first, &report))
+
+	// Removing the custom strategy restores the default one
+	report = helper.NewChangeReport("")
+	assert.True(helper.DaemonSetUpdateStrategyChanged(second, first, &report))
+	assert.Contains(report.String(), "Update strategy changed")
+}
+
+func TestDaemonSetProbes(t *testing.T) {
+	assert := assert.New(t)
+
+	ns := "namespace"
+	cfg := getConfig()
+	cfg.Processor.Advanced.EnableKubeProbes = ptr.To(true)
+	b := monoBuilder(ns, &cfg)
+	_, digest, err := b.configMap()
+	assert.NoError(err)
+	first := b.daemonSet(annotate(digest))
+	container := first.Spec.Template.Spec.Containers[0]
+	assert.Equal("/live", container.LivenessProbe.HTTPGet.Path)
+	assert.Equal("/ready", container.ReadinessProbe.HTTPGet.Path)
+	assert.Equal(int32(5), container.StartupProbe.FailureThreshold)
+
+	// Tune the startup probe, for pods that are slow to start
+	cfg.Processor.Advanced.Probes = &flowslatest.ProbesConfig{
+		Startup: &flowslatest.ProbeConfig{FailureThreshold: ptr.To(int32(30)), InitialDelaySeconds: ptr.To(int32(10))},
+	}
+	b = monoBuilder(ns, &cfg)
+	second := b.daemonSet(annotate(digest))
+	container = second.Spec.Template.Spec.Containers[0]
+	assert.Equal(int32(30), container.StartupProbe.FailureThreshold)
+	assert.Equal(int32(10), container.StartupProbe.InitialDelaySeconds)
+	assert.Equal(int32(startupPeriodSeconds), container.StartupProbe.PeriodSeconds)
+	assert.Equal(first.Spec.Template.Spec.Containers[0].LivenessProbe, container.LivenessProbe)
+
+	report := helper.NewChangeReport("")
+	assert.True(helper.PodChanged(&first.Spec.Template, &second.Spec.Template, constants.FLPName, &report))
+	assert.Contains(report.String(), "Startup probe changed")
+}
+
 func TestDeploymentNoChange(t *testing.T) {
 	assert := assert.New(t)
 
@@ -404,7 +491,8 @@ func TestDeploymentChanged(t *testing.T) {
 
 	report = helper.NewChangeReport("")
 	assert.True(checkChanged(third, fourth, cfg))
-	assert.Contains(report.String(), "req/limit changed")
+	// GOMEMLIMIT follows the memory limit, so the env changes too
+	assert.Contains(report.String(), "Env changed")
 
 	// Check reverting limits
 	cfg.Processor.Resources.Limits = map[corev1.ResourceName]resource.Quantity{
@@ -418,7 +506,7 @@ func TestDeploymentChanged(t *testing.T) {
 
 	report = helper.NewChangeReport("")
 	assert.True(checkChanged(fourth, fifth, cfg))
-	assert.Contains(report.String(), "req/limit changed")
+	assert.Contains(report.String(), "Env changed")
 	report = helper.NewChangeReport("")
 	assert.False(checkChanged(third, fifth, cfg))
 	assert.Contains(report.String(), "no change")
@@ -434,6 +522,32 @@ func TestDeploymentChanged(t *testing.T) {
 	report = helper.NewChangeReport("")
 	assert.False(checkChanged(fifth, sixth, cfg2))
 	assert.Contains(report.String(), "no change")
+
+	// Check strategy change
+	cfg.Processor.Advanced.DeploymentStrategy = &appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	b = transfBuilder(ns, &cfg)
+	_, digest, err = b.configMap()
+	assert.NoError(err)
+	seventh := b.deployment(annotate(digest))
+	assert.Equal(appsv1.RecreateDeploymentStrategyType, seventh.Spec.Strategy.Type)
+
+	report = helper.NewChangeReport("")
+	assert.True(checkChanged(fifth, seventh, cfg))
+	assert.Contains(report.String(), "Strategy changed")
+
+	// The cluster defaults are not a change
+	defaulted := fifth.DeepCopy()
+	defaulted.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type:          appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: ptr.To(intstr.FromString("25%")), MaxSurge: ptr.To(intstr.FromString("25%"))},
+	}
+	assert.False(checkChanged(defaulted, fifth, cfg))
+
+	// Removing the custom strategy restores the default one
+	cfg.Processor.Advanced.DeploymentStrategy = nil
+	report = helper.NewChangeReport("")
+	assert.True(checkChanged(seventh, fifth, cfg))
+	assert.Contains(report.String(), "Strategy changed")
 }
 
 func TestDeploymentChangedReplicasNoHPA(t *testing.T) {
@@ -570,6 +684,58 @@ func TestServiceMonitorChanged(t *testing.T) {
 	assert.Contains(report.String(), "ServiceMonitor spec changed")
 }
 
+func TestServiceMonitorsRouting(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	b := monoBuilder("namespace", &cfg)
+	_, _, err := b.configMap()
+	assert.NoError(err)
+
+	// Platform: all metrics scraped by a single ServiceMonitor
+	sm := b.generic.serviceMonitor()
+	assert.Empty(sm.Spec.Endpoints[0].MetricRelabelConfigs)
+	assert.NotContains(sm.Labels, metricsTierLabel)
+
+	// External: health metrics and flow metrics are split
+	cfg.Processor.Metrics.Routing = flowslatest.MetricsRouting{
+		FlowMetrics:          flowslatest.FlowMetricsExternal,
+		ServiceMonitorLabels: map[string]string{"prometheus": "tenants"},
+	}
+	b = monoBuilder("namespace", &cfg)
+	_, _, err = b.configMap()
+	assert.NoError(err)
+	health := b.generic.serviceMonitor()
+	assert.Equal("flowlogs-pipeline-monitor", health.Name)
+	assert.Equal("health", health.Labels[metricsTierLabel])
+	assert.Equal("drop", health.Spec.Endpoints[0].MetricRelabelConfigs[0].Action)
+	flows := b.generic.flowsServiceMonitor()
+	assert.Equal("flowlogs-pipeline-flows-monitor", flows.Name)
+	assert.Equal("namespace", flows.Namespace)
+	assert.Equal("flows", flows.Labels[metricsTierLabel])
+	assert.Equal("tenants", flows.Labels["prometheus"])
+	assert.NotContains(health.Labels, "prometheus")
+	relabel := flows.Spec.Endpoints[0].MetricRelabelConfigs[0]
+	assert.Equal("keep", relabel.Action)
+	assert.Equal(health.Spec.Endpoints[0].MetricRelabelConfigs[0].Regex, relabel.Regex)
+
+	regex := regexp.MustCompile("^(?:" + relabel.Regex + ")$")
+	assert.True(regex.MatchString("netobserv_namespace_flows_total"))
+	assert.True(regex.MatchString("netobserv_node_ingress_bytes_total"))
+	assert.False(regex.MatchString("netobserv_ingest_flows_processed"))
+	assert.False(regex.MatchString("netobserv_node_ingress_bytes_total_foo"))
+
+	// UserWorkload: health metrics scraped from the privileged namespace
+	cfg.Processor.Metrics.Routing.FlowMetrics = flowslatest.FlowMetricsUserWorkload
+	b = monoBuilder("namespace", &cfg)
+	_, _, err = b.configMap()
+	assert.NoError(err)
+	health = b.generic.healthServiceMonitor()
+	assert.Equal("namespace-privileged", health.Namespace)
+	assert.Equal([]string{"namespace"}, health.Spec.NamespaceSelector.MatchNames)
+	assert.Equal("health", health.Labels[metricsTierLabel])
+}
+
 func TestPrometheusRuleNoChange(t *testing.T) {
 	assert := assert.New(t)
 
@@ -614,6 +780,186 @@ func TestPrometheusRuleChanged(t *testing.T) {
 	assert.Contains(report.String(), "PrometheusRule labels changed")
 }
 
+func TestPrometheusRuleWithPipelineErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	b := monoBuilder("namespace", &cfg)
+	var expr string
+	for _, r := range b.generic.prometheusRule().Spec.Groups[0].Rules {
+		if r.Alert == string(flowslatest.AlertPipelineErrors) {
+			expr = r.Expr.String()
+		}
+	}
+	assert.Equal("sum by (stage) (rate(netobserv_ingest_errors[5m])) / ignoring(stage) group_left sum(rate(netobserv_ingest_flows_processed[5m])) > 0.05", expr)
+
+	cfg.Processor.Metrics.DisableAlerts = []flowslatest.FLPAlert{flowslatest.AlertPipelineErrors}
+	b = monoBuilder("namespace", &cfg)
+	for _, r := range b.generic.prometheusRule().Spec.Groups[0].Rules {
+		assert.NotEqual(string(flowslatest.AlertPipelineErrors), r.Alert)
+	}
+}
+
+func TestPrometheusRuleEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	b := monoBuilder("namespace", &cfg)
+	assert.False(isEmptyPrometheusRule(b.generic.prometheusRule()))
+
+	// Without any alert, the rule is deleted rather than kept empty
+	cfg.Processor.Metrics.DisableAlerts = []flowslatest.FLPAlert{flowslatest.AlertNoFlows, flowslatest.AlertLokiError, flowslatest.AlertPipelineErrors}
+	b = monoBuilder("namespace", &cfg)
+	assert.True(isEmptyPrometheusRule(b.generic.prometheusRule()))
+}
+
+func TestPrometheusRuleWithCardinalityBudget(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	b := monoBuilder("namespace", &cfg)
+	for _, r := range b.generic.prometheusRule().Spec.Groups[0].Rules {
+		assert.NotEqual(string(flowslatest.AlertCardinality), r.Alert)
+	}
+
+	cfg.Processor.Metrics.MaxCardinality = 50000
+	b = monoBuilder("namespace", &cfg)
+	var expr string
+	for _, r := range b.generic.prometheusRule().Spec.Groups[0].Rules {
+		if r.Alert == string(flowslatest.AlertCardinality) {
+			expr = r.Expr.String()
+		}
+	}
+	assert.Equal(`count({__name__=~"netobserv_.+"}) > 50000`, expr)
+
+	cfg.Processor.Metrics.DisableAlerts = []flowslatest.FLPAlert{flowslatest.AlertCardinality}
+	b = monoBuilder("namespace", &cfg)
+	for _, r := range b.generic.prometheusRule().Spec.Groups[0].Rules {
+		assert.NotEqual(string(flowslatest.AlertCardinality), r.Alert)
+	}
+}
+
+func TestPrometheusRuleWithAnomalyDetection(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	b := monoBuilder("namespace", &cfg)
+	rules := b.generic.prometheusRule()
+	assert.Len(rules.Spec.Groups, 1)
+
+	cfg.Analytics.AnomalyDetection = flowslatest.AnomalyDetection{
+		Enable:         ptr.To(true),
+		BaselineWindow: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+		Threshold:      ptr.To(int32(4)),
+	}
+	b = monoBuilder("namespace", &cfg)
+	rules = b.generic.prometheusRule()
+	assert.Len(rules.Spec.Groups, 2)
+	analytics := rules.Spec.Groups[1]
+	assert.Equal("NetobservAnalytics", analytics.Name)
+	assert.Len(analytics.Rules, 3)
+	assert.Equal("netobserv_anomaly_score", analytics.Rules[1].Record)
+	assert.Contains(analytics.Rules[1].Expr.String(), "avg_over_time(netobserv:workload_ingress_bytes:rate5m[1w])")
+	assert.Equal("abs(netobserv_anomaly_score) > 4", analytics.Rules[2].Expr.String())
+	assert.Empty(analyticsWarnings(&cfg))
+
+	// Base metric not included
+	cfg.Processor.Metrics.IncludeList = &[]flowslatest.FLPMetric{"namespace_flows_total"}
+	b = monoBuilder("namespace", &cfg)
+	rules = b.generic.prometheusRule()
+	assert.Len(rules.Spec.Groups, 1)
+	assert.Equal([]string{"anomaly detection is enabled, but workload_ingress_bytes_total is not in spec.processor.metrics.includeList: anomaly rules are not created"}, analyticsWarnings(&cfg))
+}
+
+func TestSecurityAnalytics(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Analytics.Security = flowslatest.SecurityAnalytics{
+		Enable:            ptr.To(true),
+		PortScanThreshold: ptr.To(int32(50)),
+	}
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, _ := validatePipelineConfig(t, cm)
+	promMetrics := cfs.Parameters[5].Encode.Prom.Metrics
+	assert.Equal("security_syn_only_flows_total", promMetrics[len(promMetrics)-1].Name)
+
+	rules := b.generic.prometheusRule()
+	assert.Len(rules.Spec.Groups, 2)
+	analytics := rules.Spec.Groups[1].Rules
+	assert.Len(analytics, 4)
+	assert.Equal("netobserv:security_failed_handshakes:5m > 50", analytics[2].Expr.String())
+	assert.Equal("netobserv:security_syn_only_flows:rate5m > 100", analytics[3].Expr.String())
+
+	// Metrics only
+	cfg.Analytics.Security.Alerts = ptr.To(false)
+	b = monoBuilder("namespace", &cfg)
+	rules = b.generic.prometheusRule()
+	assert.Len(rules.Spec.Groups[1].Rules, 2)
+}
+
+func TestEgressBudgets(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Agent.EBPF.Sampling = ptr.To(int32(10))
+	cfg.Analytics.EgressBudgets = []flowslatest.EgressBudget{
+		{Namespace: "team-a", MaxMbps: 100},
+		{Namespace: "team-b", MaxMbps: 5, Severity: "critical"},
+	}
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, _ := validatePipelineConfig(t, cm)
+	promMetrics := cfs.Parameters[5].Encode.Prom.Metrics
+	budgetMetric := promMetrics[len(promMetrics)-1]
+	assert.Equal("namespace_external_egress_bytes_total", budgetMetric.Name)
+	assert.Equal("^(team-a|team-b)$", budgetMetric.Filters[2].Value)
+
+	rules := b.generic.prometheusRule()
+	assert.Len(rules.Spec.Groups, 2)
+	analytics := rules.Spec.Groups[1].Rules
+	assert.Len(analytics, 3)
+	assert.Equal("sum by (SrcK8S_Namespace) (rate(netobserv_namespace_external_egress_bytes_total[5m])) * 8 * 10", analytics[0].Expr.String())
+	assert.Equal(`netobserv:namespace_external_egress_bits:rate5m{SrcK8S_Namespace="team-a"} > 100000000`, analytics[1].Expr.String())
+	assert.Equal("warning", analytics[1].Labels["severity"])
+	assert.Equal("team-b", analytics[2].Labels["namespace"])
+	assert.Equal("critical", analytics[2].Labels["severity"])
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	b := monoBuilder("namespace", &cfg)
+	assert.Len(b.generic.prometheusRule().Spec.Groups, 1)
+
+	cfg.RetentionPolicy = flowslatest.FlowCollectorRetentionPolicy{
+		LongTermAggregates: ptr.To(true),
+		AggregationWindow:  &metav1.Duration{Duration: 30 * time.Minute},
+	}
+	b = monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, _ := validatePipelineConfig(t, cm)
+	// namespace_flows_total is already in the default include list
+	var names []string
+	for _, m := range cfs.Parameters[5].Encode.Prom.Metrics {
+		names = append(names, m.Name)
+	}
+	assert.Equal([]string{"node_ingress_bytes_total", "namespace_flows_total", "workload_ingress_bytes_total", "namespace_ingress_bytes_total", "namespace_ingress_packets_total"}, names)
+
+	rules := b.generic.prometheusRule()
+	assert.Len(rules.Spec.Groups, 2)
+	retention := rules.Spec.Groups[1]
+	assert.Equal("NetobservRetention", retention.Name)
+	assert.Len(retention.Rules, 3)
+	assert.Equal("netobserv:namespace_ingress_bytes:rate30m", retention.Rules[1].Record)
+	assert.Equal("sum by (SrcK8S_Namespace, DstK8S_Namespace) (rate(netobserv_namespace_ingress_bytes_total[30m]))", retention.Rules[1].Expr.String())
+}
+
 func TestConfigMapShouldDeserializeAsJSONWithLokiManual(t *testing.T) {
 	assert := assert.New(t)
 
@@ -838,6 +1184,34 @@ func TestPipelineConfig(t *testing.T) {
 	)
 }
 
+func TestPipelineWithKafkaSource(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.KafkaSource = &flowslatest.FlowCollectorKafkaSource{
+		Address: "external-kafka",
+		Topic:   "external-flows",
+		Decoder: flowslatest.KafkaDecoderJSON,
+	}
+
+	b := transfBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, _ := validatePipelineConfig(t, cm)
+	assert.Equal([]string{"external-kafka"}, cfs.Parameters[0].Ingest.Kafka.Brokers)
+	assert.Equal("external-flows", cfs.Parameters[0].Ingest.Kafka.Topic)
+	assert.Equal(api.DecoderJSON, cfs.Parameters[0].Ingest.Kafka.Decoder.Type)
+
+	// Kafka deployment model takes precedence
+	cfg.DeploymentModel = flowslatest.DeploymentModelKafka
+	b = transfBuilder("namespace", &cfg)
+	cm, _, err = b.configMap()
+	assert.NoError(err)
+	cfs, _ = validatePipelineConfig(t, cm)
+	assert.Equal([]string{"kafka"}, cfs.Parameters[0].Ingest.Kafka.Brokers)
+	assert.Equal(api.DecoderProtobuf, cfs.Parameters[0].Ingest.Kafka.Decoder.Type)
+}
+
 func TestPipelineTraceStage(t *testing.T) {
 	assert := assert.New(t)
 
@@ -920,6 +1294,37 @@ func TestMergeMetricsConfiguration_WithList(t *testing.T) {
 	assert.Equal("netobserv_", cfs.Parameters[5].Encode.Prom.Prefix)
 }
 
+func TestMergeMetricsConfiguration_PrefixAndStaticLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.Metrics.Prefix = ptr.To("prod_eu_")
+	cfg.Processor.Metrics.StaticLabels = map[string]string{"region": "eu", "cluster": "prod"}
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"loki","follows":"enrich"},{"name":"stdout","follows":"enrich"},{"name":"metrics-labels","follows":"enrich"},{"name":"prometheus","follows":"metrics-labels"}]`,
+		pipeline,
+	)
+	rules := cfs.Parameters[5].Transform.Filter.Rules
+	assert.Len(rules, 2)
+	assert.Equal(api.TransformFilterGenericRule{Input: "cluster", Value: "prod"}, *rules[0].AddField)
+	assert.Equal(api.TransformFilterGenericRule{Input: "region", Value: "eu"}, *rules[1].AddField)
+
+	prom := cfs.Parameters[6].Encode.Prom
+	assert.Equal("prod_eu_", prom.Prefix)
+	for _, m := range prom.Metrics {
+		assert.Subset(m.Labels, []string{"cluster", "region"})
+	}
+	// predefined definitions must not be altered
+	for _, m := range metrics.GetDefinitions([]string{"namespace_flows_total"}) {
+		assert.NotContains(m.Labels, "cluster")
+	}
+}
+
 func TestMergeMetricsConfiguration_EmptyList(t *testing.T) {
 	assert := assert.New(t)
 
@@ -968,6 +1373,623 @@ func TestPipelineWithExporter(t *testing.T) {
 	assert.Equal("tcp", cfs.Parameters[7].Write.Ipfix.Transport)
 }
 
+func TestPipelineWithLokiExporter(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type: flowslatest.LokiExporter,
+		Loki: flowslatest.FlowCollectorLokiExporter{
+			URL:      "http://loki-secondary:3100/",
+			TenantID: "secondary",
+			BasicAuth: &flowslatest.LokiBasicAuth{
+				Username: "user",
+				Password: flowslatest.FileReference{Type: flowslatest.RefTypeSecret, Name: "loki-pass", File: "password"},
+			},
+			WriteBatchSize: 1024,
+			WriteBatchWait: &metav1.Duration{Duration: 2 * time.Second},
+			WriteTimeout:   &metav1.Duration{Duration: 5 * time.Second},
+		},
+	})
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"loki","follows":"enrich"},{"name":"stdout","follows":"enrich"},{"name":"prometheus","follows":"enrich"},{"name":"loki-export-0","follows":"enrich"}]`,
+		pipeline,
+	)
+
+	lw := cfs.Parameters[6].Write.Loki
+	assert.Equal("http://loki-secondary:3100/", lw.URL)
+	assert.Equal("secondary", lw.TenantID)
+	assert.Equal(1024, lw.BatchSize)
+	assert.Equal("2s", lw.BatchWait)
+	assert.Equal("5s", lw.Timeout)
+	assert.Equal("user", lw.ClientConfig.BasicAuth.Username)
+	assert.Equal("/var/loki-export-0-basic-auth/password", lw.ClientConfig.BasicAuth.PasswordFile)
+	// labels are shared with the main Loki
+	assert.Equal(cfs.Parameters[3].Write.Loki.Labels, lw.Labels)
+}
+
+func TestPipelineWithClusterLogForwarderExporter(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type: flowslatest.ClusterLogForwarderExporter,
+		ClusterLogForwarder: flowslatest.FlowCollectorLogForwarderExporter{
+			Name:      "instance",
+			Namespace: "openshift-logging",
+			Outputs:   []string{"splunk"},
+		},
+	})
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"loki","follows":"enrich"},{"name":"stdout","follows":"enrich"},{"name":"prometheus","follows":"enrich"},{"name":"clf-export-0","follows":"enrich"}]`,
+		pipeline,
+	)
+	assert.Equal("json", cfs.Parameters[6].Write.Stdout.Format)
+}
+
+func TestPipelineWithLokiMigration(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Loki.Migration = &flowslatest.LokiMigration{
+		Target: flowslatest.LokiManualParams{
+			IngesterURL: "http://new-loki:3100/",
+			TenantID:    "netobserv",
+			AuthToken:   flowslatest.LokiAuthSecretToken,
+			AuthTokenSecret: &flowslatest.FileReference{
+				Type: flowslatest.RefTypeSecret,
+				Name: "new-loki-token",
+				File: "token",
+			},
+		},
+	}
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"loki","follows":"enrich"},{"name":"loki-migration","follows":"enrich"},{"name":"stdout","follows":"enrich"},{"name":"prometheus","follows":"enrich"}]`,
+		pipeline,
+	)
+
+	current := cfs.Parameters[3].Write.Loki
+	target := cfs.Parameters[4].Write.Loki
+	assert.Equal("http://loki:3100/", current.URL)
+	assert.Equal("http://new-loki:3100/", target.URL)
+	assert.Equal("netobserv", target.TenantID)
+	assert.Equal("/var/loki-migration-token/token", target.ClientConfig.Authorization.CredentialsFile)
+	assert.Equal(current.BatchSize, target.BatchSize)
+	assert.Equal(current.Labels, target.Labels)
+}
+
+func TestPipelineWithLokiAggregation(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.LogTypes = ptr.To(flowslatest.LogTypeFlows)
+	cfg.Loki.Aggregation = &flowslatest.LokiAggregation{
+		Enable:  ptr.To(true),
+		Window:  &metav1.Duration{Duration: 30 * time.Second},
+		GroupBy: []string{"Proto", "SrcK8S_Namespace"},
+	}
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"enrich","follows":"grpc"},{"name":"loki-aggregate","follows":"enrich"},{"name":"loki","follows":"loki-aggregate"},{"name":"stdout","follows":"enrich"},{"name":"prometheus","follows":"enrich"}]`,
+		pipeline,
+	)
+
+	ct := cfs.Parameters[2].Extract.ConnTrack
+	assert.Equal(
+		[]string{"SrcK8S_Namespace", "SrcK8S_OwnerName", "SrcK8S_Type", "DstK8S_Namespace", "DstK8S_OwnerName", "DstK8S_Type", "K8S_FlowLayer", "FlowDirection", "Proto"},
+		ct.KeyDefinition.FieldGroups[0].Fields,
+	)
+	assert.Equal([]api.ConnTrackOutputRecordTypeEnum{api.ConnTrackHeartbeat, api.ConnTrackEndConnection}, ct.OutputRecordTypes)
+	assert.Equal(30*time.Second, ct.Scheduling[0].HeartbeatInterval.Duration)
+	assert.Equal(30*time.Second, ct.Scheduling[0].EndConnectionTimeout.Duration)
+	assert.Contains(cfs.Parameters[3].Write.Loki.Labels, "_RecordType")
+}
+
+func TestPipelineWithKafkaAdvanced(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type: flowslatest.KafkaExporter,
+		Kafka: flowslatest.FlowCollectorKafka{
+			Address: "kafka-test",
+			Topic:   "topic-test",
+			Advanced: &flowslatest.AdvancedKafkaConfig{
+				BatchBytes:    ptr.To(int64(1048576)),
+				BatchMessages: ptr.To(int32(500)),
+				Balancer:      flowslatest.KafkaBalancerLeastBytes,
+				WriteTimeout:  &metav1.Duration{Duration: 30 * time.Second},
+			},
+		},
+	})
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, _ := validatePipelineConfig(t, cm)
+
+	kafka := cfs.Parameters[6].Encode.Kafka
+	assert.Equal("kafka-test", kafka.Address)
+	assert.Equal(int64(1048576), kafka.BatchBytes)
+	assert.Equal(500, kafka.BatchSize)
+	assert.Equal(api.KafkaLeastBytes, kafka.Balancer)
+	assert.Equal(int64(30), kafka.WriteTimeout)
+}
+
+func TestPipelineWithAnonymization(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.Anonymization = &flowslatest.Anonymization{
+		IPs:                  flowslatest.AnonymizationIPTruncate,
+		TruncatePrefixLength: ptr.To(int32(16)),
+		DropMACs:             true,
+	}
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type:  flowslatest.KafkaExporter,
+		Kafka: flowslatest.FlowCollectorKafka{Address: "kafka-test", Topic: "topic-test"},
+	})
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type:          flowslatest.KafkaExporter,
+		Kafka:         flowslatest.FlowCollectorKafka{Address: "kafka-test", Topic: "topic-drop"},
+		Anonymization: &flowslatest.Anonymization{IPs: flowslatest.AnonymizationIPDrop, DropPodNames: true},
+	})
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type:          flowslatest.KafkaExporter,
+		Kafka:         flowslatest.FlowCollectorKafka{Address: "kafka-test", Topic: "topic-full"},
+		Anonymization: &flowslatest.Anonymization{},
+	})
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"anonymize-truncate","follows":"enrich"},{"name":"anonymize","follows":"anonymize-truncate"},{"name":"loki","follows":"anonymize"},{"name":"stdout","follows":"enrich"},{"name":"prometheus","follows":"anonymize"},{"name":"kafka-export-0","follows":"anonymize"},{"name":"anonymize-export-1","follows":"enrich"},{"name":"kafka-export-1","follows":"anonymize-export-1"},{"name":"kafka-export-2","follows":"enrich"}]`,
+		pipeline,
+	)
+
+	assert.Equal("/16", cfs.Parameters[3].Transform.Network.Rules[0].AddSubnet.SubnetMask)
+	assert.Equal("SrcAddr", cfs.Parameters[3].Transform.Network.Rules[0].AddSubnet.Output)
+	assert.Len(cfs.Parameters[4].Transform.Filter.Rules, 2)
+	assert.Equal("SrcMac", cfs.Parameters[4].Transform.Filter.Rules[0].RemoveField.Input)
+	assert.Len(cfs.Parameters[9].Transform.Filter.Rules, 10)
+	assert.Equal("SrcK8S_Name", cfs.Parameters[9].Transform.Filter.Rules[2].RemoveField.Input)
+	assert.Equal("SrcK8S_OwnerName", cfs.Parameters[9].Transform.Filter.Rules[3].RemoveField.Input)
+	assert.Equal("SrcK8S_HostIP", cfs.Parameters[9].Transform.Filter.Rules[5].RemoveField.Input)
+
+	// the service mesh enrichment reads the IPs: metrics are anonymized after it
+	cfg.Processor.ServiceMesh.Enable = ptr.To(true)
+	b = monoBuilder("namespace", &cfg)
+	cm, _, err = b.configMap()
+	assert.NoError(err)
+	_, pipeline = validatePipelineConfig(t, cm)
+	assert.Contains(pipeline, `{"name":"enrich-mesh","follows":"enrich"}`)
+	assert.Contains(pipeline, `{"name":"anonymize-metrics-truncate","follows":"mesh-fields"},{"name":"anonymize-metrics","follows":"anonymize-metrics-truncate"},{"name":"prometheus","follows":"anonymize-metrics"}`)
+}
+
+func TestPipelineWithExporterSelection(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type:  flowslatest.KafkaExporter,
+		Kafka: flowslatest.FlowCollectorKafka{Address: "kafka-test", Topic: "topic-full"},
+	})
+	cfg.Exporters = append(cfg.Exporters, &flowslatest.FlowCollectorExporter{
+		Type:  flowslatest.IpfixExporter,
+		IPFIX: flowslatest.FlowCollectorIPFIXReceiver{TargetHost: "ipfix-receiver-test", TargetPort: 9999, Transport: "TCP"},
+		Filters: []flowslatest.ExporterFilter{
+			{Field: "K8S_FlowLayer", Value: "app", MatchType: flowslatest.ExporterFilterEqual},
+			{Field: "DstK8S_Namespace", MatchType: flowslatest.ExporterFilterAbsence},
+		},
+		Fields: &flowslatest.ExporterFields{
+			Include: []string{"SrcAddr", "DstAddr", "Bytes", "SrcMac"},
+			Exclude: []string{"SrcMac"},
+		},
+	})
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"loki","follows":"enrich"},{"name":"stdout","follows":"enrich"},{"name":"prometheus","follows":"enrich"},{"name":"kafka-export-0","follows":"enrich"},{"name":"filter-export-1","follows":"enrich"},{"name":"include-export-1","follows":"filter-export-1"},{"name":"exclude-export-1","follows":"include-export-1"},{"name":"IPFIX-export-1","follows":"exclude-export-1"}]`,
+		pipeline,
+	)
+
+	filterRules := cfs.Parameters[7].Transform.Filter.Rules
+	assert.Len(filterRules, 3)
+	assert.Equal(api.RemoveEntryIfDoesntExist, filterRules[0].Type)
+	assert.Equal("K8S_FlowLayer", filterRules[0].RemoveEntryIfDoesntExist.Input)
+	assert.Equal(api.RemoveEntryIfNotEqual, filterRules[1].Type)
+	assert.Equal("app", filterRules[1].RemoveEntryIfNotEqual.Value)
+	assert.Equal(api.RemoveEntryIfExists, filterRules[2].Type)
+	assert.Equal("DstK8S_Namespace", filterRules[2].RemoveEntryIfExists.Input)
+	assert.Equal(api.ReplaceKeys, cfs.Parameters[8].Transform.Generic.Policy)
+	assert.Len(cfs.Parameters[8].Transform.Generic.Rules, 4)
+	assert.Equal("SrcMac", cfs.Parameters[9].Transform.Filter.Rules[0].RemoveField.Input)
+}
+
+func TestPipelineWithLoadGenerator(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.LoadGenerator = flowslatest.FlowCollectorLoadGenerator{
+		Enable:         ptr.To(true),
+		FlowsPerSecond: ptr.To(int32(5000)),
+		Connections:    ptr.To(int32(200)),
+		Resources:      corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}},
+	}
+	cfg.Exporters = []*flowslatest.FlowCollectorExporter{{
+		Type:  flowslatest.IpfixExporter,
+		IPFIX: flowslatest.FlowCollectorIPFIXReceiver{TargetHost: "ipfix-receiver-test", TargetPort: 9999, Transport: "TCP"},
+	}}
+
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki}
+	b, err := newLoadGenBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"preset-ingester"},{"name":"extract_conntrack","follows":"preset-ingester"},{"name":"enrich","follows":"extract_conntrack"},{"name":"loki","follows":"enrich"},{"name":"stdout","follows":"enrich"},{"name":"prometheus","follows":"enrich"}]`,
+		pipeline,
+	)
+	assert.Equal(api.SyntheticType, cfs.Parameters[0].Ingest.Type)
+	assert.Equal(api.IngestSynthetic{Connections: 200, BatchMaxLen: 100, FlowLogsPerMin: 300000}, *cfs.Parameters[0].Ingest.Synthetic)
+	// synthetic flows are written to their own Loki stream, and not exported
+	assert.Equal(`{app="netobserv-load-generator"}`, fmt.Sprintf("%v", cfs.Parameters[3].Write.Loki.StaticLabels))
+	assert.Len(cfg.Exporters, 1)
+
+	d := b.deployment(annotate("digest"))
+	assert.Equal("flowlogs-pipeline-loadgen", d.Name)
+	assert.Equal(int32(1), *d.Spec.Replicas)
+	assert.Equal("1Gi", d.Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String())
+	assert.Equal(name(ConfKafkaTransformer), b.clusterRoleBinding().RoleRef.Name)
+}
+
+func TestPipelineWithVirtualMachines(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.Virtualization.Enable = ptr.To(true)
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki, VirtualMachines: []kubevirt.VirtualMachine{
+		{Name: "db", Namespace: "vms", IPs: []string{"10.128.0.12", "192.168.100.5", "fe80::1"}},
+		{Name: "web", Namespace: "vms", IPs: []string{"10.129.0.7"}},
+		{Name: "web", Namespace: "other", IPs: []string{"fd00::5"}},
+	}}
+	b, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"enrich-vm","follows":"enrich"},{"name":"enrich-vm-namespace","follows":"enrich-vm"},{"name":"enrich-vm-kind","follows":"enrich-vm-namespace"},{"name":"loki","follows":"enrich-vm-kind"},{"name":"stdout","follows":"enrich-vm-kind"},{"name":"prometheus","follows":"enrich-vm-kind"}]`,
+		pipeline,
+	)
+
+	names := cfs.Parameters[3].Transform.Network
+	assert.Equal("SrcK8S_VirtualMachine", names.Rules[0].AddSubnetLabel.Output)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "db", CIDRs: []string{"10.128.0.12/32", "192.168.100.5/32"}},
+		{Name: "web", CIDRs: []string{"10.129.0.7/32"}},
+		{Name: "web", CIDRs: []string{"fd00::5/128"}},
+	}, names.SubnetLabels)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "vms", CIDRs: []string{"10.128.0.12/32", "192.168.100.5/32", "10.129.0.7/32"}},
+		{Name: "other", CIDRs: []string{"fd00::5/128"}},
+	}, cfs.Parameters[4].Transform.Network.SubnetLabels)
+	kinds := cfs.Parameters[5].Transform.Network
+	assert.Equal("DstK8S_OwnerType", kinds.Rules[1].AddSubnetLabel.Output)
+	assert.Len(kinds.SubnetLabels, 1)
+	assert.Equal("VirtualMachineInstance", kinds.SubnetLabels[0].Name)
+
+	// Disabled: no stage is added
+	cfg.Processor.Virtualization.Enable = ptr.To(false)
+	b, _ = newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	cm, _, err = b.configMap()
+	assert.NoError(err)
+	_, pipeline = validatePipelineConfig(t, cm)
+	assert.NotContains(pipeline, "enrich-vm")
+}
+
+func TestPipelineWithMultiHoming(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.MultiHoming.Enable = ptr.To(true)
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki, MultiHomedPods: []multus.Pod{
+		{Name: "amf-7d4b9-x7k2p", Namespace: "5gc", OwnerName: "amf", OwnerType: "Deployment", IPs: []string{"192.168.10.5", "192.168.20.5", "fe80::1"}},
+		{Name: "amf-7d4b9-q9z8w", Namespace: "5gc", OwnerName: "amf", OwnerType: "Deployment", IPs: []string{"192.168.10.6"}},
+		{Name: "smf-0", Namespace: "5gc", OwnerName: "smf", OwnerType: "StatefulSet", IPs: []string{"fd00:30::7"}},
+	}}
+	b, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"enrich-multus","follows":"enrich"},{"name":"enrich-multus-namespace","follows":"enrich-multus"},{"name":"enrich-multus-owner","follows":"enrich-multus-namespace"},{"name":"enrich-multus-owner-type","follows":"enrich-multus-owner"},{"name":"enrich-multus-type","follows":"enrich-multus-owner-type"},{"name":"loki","follows":"enrich-multus-type"},{"name":"stdout","follows":"enrich-multus-type"},{"name":"prometheus","follows":"enrich-multus-type"}]`,
+		pipeline,
+	)
+
+	names := cfs.Parameters[3].Transform.Network
+	assert.Equal("SrcK8S_Name", names.Rules[0].AddSubnetLabel.Output)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "amf-7d4b9-x7k2p", CIDRs: []string{"192.168.10.5/32", "192.168.20.5/32"}},
+		{Name: "amf-7d4b9-q9z8w", CIDRs: []string{"192.168.10.6/32"}},
+		{Name: "smf-0", CIDRs: []string{"fd00:30::7/128"}},
+	}, names.SubnetLabels)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "5gc", CIDRs: []string{"192.168.10.5/32", "192.168.20.5/32", "192.168.10.6/32", "fd00:30::7/128"}},
+	}, cfs.Parameters[4].Transform.Network.SubnetLabels)
+	owners := cfs.Parameters[5].Transform.Network
+	assert.Equal("DstK8S_OwnerName", owners.Rules[1].AddSubnetLabel.Output)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "amf", CIDRs: []string{"192.168.10.5/32", "192.168.20.5/32", "192.168.10.6/32"}},
+		{Name: "smf", CIDRs: []string{"fd00:30::7/128"}},
+	}, owners.SubnetLabels)
+	assert.Equal("Deployment", cfs.Parameters[6].Transform.Network.SubnetLabels[0].Name)
+	types := cfs.Parameters[7].Transform.Network
+	assert.Equal("SrcK8S_Type", types.Rules[0].AddSubnetLabel.Output)
+	assert.Len(types.SubnetLabels, 1)
+	assert.Equal("Pod", types.SubnetLabels[0].Name)
+
+	// Disabled: no stage is added
+	cfg.Processor.MultiHoming.Enable = ptr.To(false)
+	b, _ = newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	cm, _, err = b.configMap()
+	assert.NoError(err)
+	_, pipeline = validatePipelineConfig(t, cm)
+	assert.NotContains(pipeline, "enrich-multus")
+}
+
+func TestPipelineWithDSCPClassification(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.DSCPClassification.Enable = ptr.To(true)
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"dscp-class","follows":"enrich"},{"name":"loki","follows":"dscp-class"},{"name":"stdout","follows":"dscp-class"},{"name":"prometheus","follows":"dscp-class"}]`,
+		pipeline,
+	)
+
+	rules := cfs.Parameters[3].Transform.Filter.Rules
+	assert.Equal(api.TransformFilterRuleWithAssignee{
+		Input: "Dscp", Output: "DscpClass", Parameters: "== 46", Assignee: "Telephony",
+	}, *rules[2].AddFieldIf)
+	assert.Equal(api.TransformFilterGenericRule{Input: "DscpClass", Value: "Other"}, *rules[len(rules)-2].AddFieldIfDoesntExist)
+	assert.Equal("DscpClass_Evaluate", rules[len(rules)-1].RemoveField.Input)
+}
+
+func TestPipelineWithEgressClassification(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.EgressClassification.Enable = ptr.To(true)
+	cfg.Processor.EgressClassification.ClusterCIDRs = []string{"10.200.0.0/16"}
+	detected := []flowslatest.SubnetLabel{
+		{Name: "Pods", CIDRs: []string{"10.128.0.0/14"}},
+		{Name: "Services", CIDRs: []string{"172.30.0.0/16"}},
+		{Name: "Machines", CIDRs: []string{"10.0.0.0/16"}},
+		{Name: "my-db", CIDRs: []string{"10.1.2.3/32"}},
+	}
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki}
+	b, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, detected)
+	assert.NoError(err)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"egress-class","follows":"enrich"},{"name":"egress-class-fallback","follows":"egress-class"},{"name":"loki","follows":"egress-class-fallback"},{"name":"stdout","follows":"egress-class-fallback"},{"name":"prometheus","follows":"egress-class-fallback"}]`,
+		pipeline,
+	)
+
+	// nodes and cluster ranges come before the private ranges that contain them
+	classes := cfs.Parameters[3].Transform.Network
+	assert.Equal("DstEgressClass", classes.Rules[0].AddSubnetLabel.Output)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "Node", CIDRs: []string{"10.0.0.0/16"}},
+		{Name: "InCluster", CIDRs: []string{"10.128.0.0/14", "172.30.0.0/16", "10.200.0.0/16"}},
+		{Name: "ExternalPrivate", CIDRs: []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"}},
+	}, classes.SubnetLabels)
+
+	rules := cfs.Parameters[4].Transform.Filter.Rules
+	assert.Equal(api.TransformFilterRuleWithAssignee{
+		Input: "DstK8S_Type", Output: "DstEgressClass", Parameters: "== 'Pod'", Assignee: "InCluster",
+	}, *rules[1].AddFieldIf)
+	assert.Equal(api.TransformFilterGenericRule{Input: "DstEgressClass", Value: "Internet"}, *rules[len(rules)-2].AddFieldIfDoesntExist)
+	assert.Equal("DstEgressClass_Evaluate", rules[len(rules)-1].RemoveField.Input)
+
+	// custom private ranges replace the default ones
+	cfg.Processor.EgressClassification.PrivateCIDRs = []string{"192.168.0.0/16"}
+	b, err = newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+	cm, _, err = b.configMap()
+	assert.NoError(err)
+	cfs, _ = validatePipelineConfig(t, cm)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "InCluster", CIDRs: []string{"10.200.0.0/16"}},
+		{Name: "ExternalPrivate", CIDRs: []string{"192.168.0.0/16"}},
+	}, cfs.Parameters[3].Transform.Network.SubnetLabels)
+
+	// cluster subnets read from the Cluster Network Operator, without subnet labels auto-detection
+	info.ClusterNetwork = &flowslatest.ClusterNetworkStatus{PodCIDRs: []string{"10.128.0.0/14"}, ServiceCIDRs: []string{"172.30.0.0/16"}}
+	b, err = newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, detected[:1])
+	assert.NoError(err)
+	cm, _, err = b.configMap()
+	assert.NoError(err)
+	cfs, _ = validatePipelineConfig(t, cm)
+	assert.Equal([]api.NetworkTransformSubnetLabel{
+		{Name: "InCluster", CIDRs: []string{"10.128.0.0/14", "172.30.0.0/16", "10.200.0.0/16"}},
+		{Name: "ExternalPrivate", CIDRs: []string{"192.168.0.0/16"}},
+	}, cfs.Parameters[3].Transform.Network.SubnetLabels)
+}
+
+func TestClusterNetworkFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	// desired configuration, not applied yet
+	network := configv1.Network{
+		Spec: configv1.NetworkSpec{
+			ClusterNetwork: []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14"}},
+			ServiceNetwork: []string{"172.30.0.0/16"},
+			NetworkType:    "OVNKubernetes",
+		},
+	}
+	assert.Equal(&flowslatest.ClusterNetworkStatus{
+		NetworkType:  "OVNKubernetes",
+		PodCIDRs:     []string{"10.128.0.0/14"},
+		ServiceCIDRs: []string{"172.30.0.0/16"},
+	}, clusterNetworkFrom(&network))
+
+	// applied configuration
+	network.Status = configv1.NetworkStatus{
+		ClusterNetwork:    []configv1.ClusterNetworkEntry{{CIDR: "10.128.0.0/14"}, {CIDR: "fd01::/48"}},
+		ServiceNetwork:    []string{"172.30.0.0/16", "fd02::/112"},
+		NetworkType:       "OVNKubernetes",
+		ClusterNetworkMTU: 1400,
+	}
+	cn := clusterNetworkFrom(&network)
+	assert.Equal(&flowslatest.ClusterNetworkStatus{
+		NetworkType:  "OVNKubernetes",
+		MTU:          1400,
+		PodCIDRs:     []string{"10.128.0.0/14", "fd01::/48"},
+		ServiceCIDRs: []string{"172.30.0.0/16", "fd02::/112"},
+	}, cn)
+
+	// features depending on OVN-Kubernetes
+	cfg := getConfig()
+	cfg.Agent.EBPF.Privileged = true
+	cfg.Agent.OVN.Enable = ptr.To(true)
+	assert.Empty(clusterNetworkWarnings(cn, &cfg))
+	cn.NetworkType = "OpenShiftSDN"
+	assert.Equal([]string{"OVN observability is enabled, but the cluster network type is OpenShiftSDN: network events are not reported"}, clusterNetworkWarnings(cn, &cfg))
+	assert.Empty(clusterNetworkWarnings(nil, &cfg))
+}
+
+func TestBatchTuning(t *testing.T) {
+	assert := assert.New(t)
+
+	// one step at a time, beyond the hysteresis margin
+	assert.Equal(flowslatest.BatchProfileDefault, nextBatchProfile(flowslatest.BatchProfileDefault, 900))
+	assert.Equal(flowslatest.BatchProfileLowTraffic, nextBatchProfile(flowslatest.BatchProfileDefault, 500))
+	assert.Equal(flowslatest.BatchProfileLowTraffic, nextBatchProfile(flowslatest.BatchProfileLowTraffic, 1100))
+	assert.Equal(flowslatest.BatchProfileDefault, nextBatchProfile(flowslatest.BatchProfileLowTraffic, 50000))
+	assert.Equal(flowslatest.BatchProfileHighTraffic, nextBatchProfile(flowslatest.BatchProfileDefault, 30000))
+	assert.Equal(flowslatest.BatchProfileHighTraffic, nextBatchProfile(flowslatest.BatchProfileHighTraffic, 17000))
+	assert.Equal(flowslatest.BatchProfileDefault, nextBatchProfile(flowslatest.BatchProfileHighTraffic, 100))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tuning := updateBatchTuning(newBatchTuning(flowslatest.BatchProfileDefault), 30000.4, start)
+	assert.Equal(flowslatest.BatchProfileHighTraffic, tuning.Profile)
+	assert.Equal(int64(30000), tuning.FlowsPerSecond)
+	assert.Equal(int64(1024*1024), tuning.LokiWriteBatchSize)
+	assert.Equal(2*time.Second, tuning.LokiWriteBatchWait.Duration)
+	assert.Equal(50*1024*1024, tuning.KafkaConsumerBatchSize)
+	assert.Equal(start, tuning.LastTransitionTime.Time)
+	tuning = updateBatchTuning(tuning, 18000, start.Add(5*time.Minute))
+	assert.Equal(flowslatest.BatchProfileHighTraffic, tuning.Profile)
+	assert.Equal(start.Add(5*time.Minute), tuning.LastCheckTime.Time)
+	assert.Equal(start, tuning.LastTransitionTime.Time)
+
+	// tuned values replace the configured ones
+	cfg := getConfig()
+	cfg.DeploymentModel = flowslatest.DeploymentModelKafka
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki, BatchTuning: tuning}
+	b, err := newTransfoBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, _ := validatePipelineConfig(t, cm)
+	assert.Equal(50*1024*1024, cfs.Parameters[0].Ingest.Kafka.PullMaxBytes)
+	assert.Equal(1024*1024, cfs.Parameters[3].Write.Loki.BatchSize)
+	assert.Equal("2s", cfs.Parameters[3].Write.Loki.BatchWait)
+	assert.Equal(int64(102400), cfg.Loki.WriteBatchSize)
+}
+
+func TestConfigOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki, ConfigOverrides: map[string]string{
+		"parameters.loki.write.loki.batchSize": "2097152",
+		"log-level":                            "debug",
+	}}
+	b, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, _ := validatePipelineConfig(t, cm)
+	assert.Equal("debug", cfs.LogLevel)
+	assert.Equal(2097152, cfs.Parameters[3].Write.Loki.BatchSize)
+
+	// unknown stage
+	info.ConfigOverrides = map[string]string{"parameters.kafka.ingest.kafka.topic": "flows"}
+	b, err = newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+	_, _, err = b.configMap()
+	assert.EqualError(err, `override parameters.kafka.ingest.kafka.topic: no element "kafka"`)
+}
+
+func TestPipelineWithServiceMesh(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.ServiceMesh.Enable = ptr.To(true)
+
+	b := monoBuilder("namespace", &cfg)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	// mesh stages are only on the metrics branch
+	assert.Equal(
+		`[{"name":"grpc"},{"name":"extract_conntrack","follows":"grpc"},{"name":"enrich","follows":"extract_conntrack"},{"name":"loki","follows":"enrich"},{"name":"stdout","follows":"enrich"},{"name":"enrich-mesh","follows":"enrich"},{"name":"mesh-defaults","follows":"enrich-mesh"},{"name":"mesh-fields","follows":"mesh-defaults"},{"name":"prometheus","follows":"mesh-fields"}]`,
+		pipeline,
+	)
+
+	enrich := cfs.Parameters[5].Transform.Network
+	assert.Equal("SrcK8S_Labels", enrich.Rules[0].Kubernetes.LabelsPrefix)
+	defaults := cfs.Parameters[6].Transform.Filter
+	assert.Equal("DstK8S_Labels_security.istio.io/tlsMode", defaults.Rules[5].AddFieldIfDoesntExist.Input)
+	assert.Equal("disabled", defaults.Rules[5].AddFieldIfDoesntExist.Value)
+	fields := cfs.Parameters[7].Transform.Generic
+	assert.Equal(api.PreserveOriginalKeys, fields.Policy)
+	assert.Equal(api.GenericTransformRule{Input: "SrcK8S_Labels_service.istio.io/canonical-name", Output: "SrcK8S_MeshService"}, fields.Rules[0])
+	assert.Equal(api.GenericTransformRule{Input: "DstK8S_Labels_service.istio.io/canonical-revision", Output: "DstK8S_MeshRevision"}, fields.Rules[4])
+}
+
 func TestPipelineWithoutLoki(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1033,3 +2055,224 @@ publish: External`,
 			},
 		}, machines)
 }
+
+func TestLokiAuthFromSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	getLokiClientConfig := func(cfg *flowslatest.FlowCollectorSpec) (*api.WriteLoki, error) {
+		b := monoBuilder("namespace", cfg)
+		cm, _, err := b.configMap()
+		if err != nil {
+			return nil, err
+		}
+		var decoded config.ConfigFileStruct
+		assert.NoError(json.Unmarshal([]byte(cm.Data[configFile]), &decoded))
+		return decoded.Parameters[3].Write.Loki, nil
+	}
+
+	// Bearer token
+	cfg := getConfig()
+	cfg.Loki.Manual.AuthToken = flowslatest.LokiAuthSecretToken
+	cfg.Loki.Manual.AuthTokenSecret = &flowslatest.FileReference{Type: flowslatest.RefTypeSecret, Name: "loki-creds", File: "token"}
+	lokiCfg, err := getLokiClientConfig(&cfg)
+	assert.NoError(err)
+	assert.Equal("Bearer", lokiCfg.ClientConfig.Authorization.Type)
+	assert.Equal("/var/loki-token/token", lokiCfg.ClientConfig.Authorization.CredentialsFile)
+	assert.Nil(lokiCfg.ClientConfig.BasicAuth)
+
+	// Basic auth
+	cfg = getConfig()
+	cfg.Loki.Manual.BasicAuth = &flowslatest.LokiBasicAuth{
+		Username: "123456",
+		Password: flowslatest.FileReference{Type: flowslatest.RefTypeSecret, Name: "loki-creds", File: "api-key"},
+	}
+	lokiCfg, err = getLokiClientConfig(&cfg)
+	assert.NoError(err)
+	assert.Nil(lokiCfg.ClientConfig.Authorization)
+	assert.Equal("123456", lokiCfg.ClientConfig.BasicAuth.Username)
+	assert.Equal("/var/loki-basic-auth/api-key", lokiCfg.ClientConfig.BasicAuth.PasswordFile)
+
+	// Both can't be combined
+	cfg.Loki.Manual.AuthToken = flowslatest.LokiAuthForwardUserToken
+	_, err = getLokiClientConfig(&cfg)
+	assert.Error(err)
+}
+
+func TestExternalEndpointsToSubnetLabels(t *testing.T) {
+	endpoints := []endpointslatest.ExternalEndpoint{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "partners"},
+			Spec: endpointslatest.ExternalEndpointSpec{
+				DisplayName: "Partner APIs",
+				CIDRs:       []string{"203.0.113.0/28", "198.51.100.7/32"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "billing-db"},
+			Spec: endpointslatest.ExternalEndpointSpec{
+				CIDRs: []string{"192.168.10.0/24"},
+			},
+		},
+	}
+
+	assert.Equal(t,
+		[]flowslatest.SubnetLabel{
+			{
+				Name:  "billing-db",
+				CIDRs: []string{"192.168.10.0/24"},
+			},
+			{
+				Name:  "Partner APIs",
+				CIDRs: []string{"203.0.113.0/28", "198.51.100.7/32"},
+			},
+		}, externalEndpointsToSubnetLabels(endpoints))
+}
+
+func TestRBACProxy(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.Metrics.RBACProxy.Enable = ptr.To(true)
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki, RBACProxyImage: "kube-rbac-proxy:v1"}
+
+	// TLS is required
+	_, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.Error(err)
+
+	cfg.Processor.Metrics.Server.TLS.Type = flowslatest.ServerTLSAuto
+	b, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+
+	cm, digest, err := b.configMap()
+	assert.NoError(err)
+	var decoded config.ConfigFileStruct
+	err = json.Unmarshal([]byte(cm.Data[configFile]), &decoded)
+	assert.NoError(err)
+	assert.Equal("127.0.0.1", decoded.MetricsSettings.Address)
+	assert.Equal(rbacProxyUpstreamPort, decoded.MetricsSettings.Port)
+	assert.Nil(decoded.MetricsSettings.TLS)
+
+	ds := b.daemonSet(annotate(digest))
+	containers := ds.Spec.Template.Spec.Containers
+	assert.Len(containers, 2)
+	for _, p := range containers[0].Ports {
+		assert.NotEqual(prometheusServiceName, p.Name)
+	}
+	proxy := containers[1]
+	assert.Equal("kube-rbac-proxy:v1", proxy.Image)
+	assert.Equal(int32(9090), proxy.Ports[0].ContainerPort)
+	assert.Contains(proxy.Args, "--upstream=http://127.0.0.1:9401/")
+	assert.Contains(proxy.Args, "--tls-cert-file=/var/prom-certs/tls.crt")
+	assert.Equal("prom-certs", proxy.VolumeMounts[0].Name)
+
+	sm := b.generic.serviceMonitor()
+	assert.Equal("https", sm.Spec.Endpoints[0].Scheme)
+	assert.Equal(rbacProxyTokenFile, sm.Spec.Endpoints[0].BearerTokenFile)
+}
+
+func TestExternalIngest(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := getConfig()
+	cfg.Processor.ExternalIngest = flowslatest.FLPExternalIngest{
+		Enable:         ptr.To(true),
+		Port:           9443,
+		ServiceType:    corev1.ServiceTypeLoadBalancer,
+		Authentication: flowslatest.ExternalIngestAuthMTLS,
+	}
+	loki := helper.NewLokiConfig(&cfg.Loki, "any")
+	info := reconcilers.Common{Namespace: "namespace", Loki: &loki, RBACProxyImage: "kube-rbac-proxy:v1"}
+
+	// A client CA is required with MTLS
+	_, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.Error(err)
+
+	cfg.Processor.ExternalIngest.ClientCA = &flowslatest.FileReference{Type: "configmap", Name: "senders-ca", File: "ca.crt"}
+	b, err := newMonolithBuilder(info.NewInstance(image, status.Instance{}), &cfg, &metricslatest.FlowMetricList{}, nil)
+	assert.NoError(err)
+
+	ds := b.daemonSet(annotate("digest"))
+	containers := ds.Spec.Template.Spec.Containers
+	assert.Len(containers, 2)
+	proxy := containers[1]
+	assert.Equal(externalIngestContainerName, proxy.Name)
+	assert.Equal("kube-rbac-proxy:v1", proxy.Image)
+	assert.Equal(int32(9443), proxy.Ports[0].ContainerPort)
+	assert.Contains(proxy.Args, "--upstream=http://127.0.0.1:2055/")
+	assert.Contains(proxy.Args, "--upstream-force-h2c")
+	assert.Contains(proxy.Args, "--allow-paths=/pbflow.Collector/Send")
+	assert.Contains(proxy.Args, "--tls-cert-file=/var/ingest-certs/tls.crt")
+	assert.Contains(proxy.Args, "--client-ca-file=/var/ingest-client-ca/ca.crt")
+	assert.Len(proxy.VolumeMounts, 2)
+
+	svc := b.externalIngestService()
+	assert.Equal("flowlogs-pipeline-ingest", svc.Name)
+	assert.Equal(corev1.ServiceTypeLoadBalancer, svc.Spec.Type)
+	assert.Equal(int32(9443), svc.Spec.Ports[0].Port)
+	assert.Equal("flowlogs-pipeline-ingest", svc.Annotations[constants.OpenShiftCertificateAnnotation])
+
+	// Not exposed by the transformer
+	cfg.DeploymentModel = flowslatest.DeploymentModelKafka
+	tb := transfBuilder("namespace", &cfg)
+	assert.Len(tb.deployment(annotate("digest")).Spec.Template.Spec.Containers, 1)
+}
+
+func TestPipelineHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	ratios := `{"status":"success","data":{"resultType":"vector","result":[` +
+		`{"metric":{"stage":"enrich"},"value":[1704067200,"0.01"]},` +
+		`{"metric":{"stage":"loki"},"value":[1704067200,"0.125"]}]}}`
+	queries := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		queries++
+		assert.Equal(stageErrorRatioQuery, req.URL.Query().Get("query"))
+		_, _ = w.Write([]byte(ratios))
+	}))
+	defer srv.Close()
+
+	fc := flowslatest.FlowCollector{Spec: flowslatest.FlowCollectorSpec{Processor: flowslatest.FlowCollectorFLP{
+		Metrics: flowslatest.FLPMetrics{PipelineHealthCheck: flowslatest.PipelineHealthCheck{Enable: ptr.To(true), URL: srv.URL}},
+	}}}
+	r := Reconciler{healthQuerier: prometheus.NewQuerier(srv.URL, false, nil)}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// only the stages above the alert threshold are reported
+	warning, err := r.checkPipelineHealth(context.Background(), &fc, start)
+	assert.NoError(err)
+	assert.Equal("the flowlogs-pipeline error ratio exceeds 5% in stages: loki (12.5%)", warning)
+	assert.Equal(5*time.Minute, r.nextHealthCheck(&fc.Spec.Processor.Metrics, start))
+
+	// the last result is kept until the next interval
+	ratios = `{"status":"success","data":{"resultType":"vector","result":[]}}`
+	warning, err = r.checkPipelineHealth(context.Background(), &fc, start.Add(time.Minute))
+	assert.NoError(err)
+	assert.NotEmpty(warning)
+	assert.Equal(1, queries)
+	warning, err = r.checkPipelineHealth(context.Background(), &fc, start.Add(5*time.Minute))
+	assert.NoError(err)
+	assert.Empty(warning)
+	assert.Equal(2, queries)
+
+	// disabled
+	fc.Spec.Processor.Metrics.PipelineHealthCheck.Enable = nil
+	warning, err = r.checkPipelineHealth(context.Background(), &fc, start.Add(10*time.Minute))
+	assert.NoError(err)
+	assert.Empty(warning)
+	assert.Nil(r.healthQuerier)
+	assert.Zero(r.nextHealthCheck(&fc.Spec.Processor.Metrics, start.Add(10*time.Minute)))
+}
+
+func TestOpenShiftSubnetsNamespaced(t *testing.T) {
+	assert := assert.New(t)
+
+	// no client: cluster-config-v1 must not be read when kube-system isn't watched
+	r := Reconciler{mgr: &manager.Manager{
+		AvailableAPIs: &discover.AvailableAPIs{},
+		Config:        &manager.Config{WatchNamespaces: []string{"netobserv", "netobserv-privileged"}},
+	}}
+	subnets, err := r.getOpenShiftSubnets(context.Background())
+	assert.NoError(err)
+	assert.Empty(subnets)
+}
@@ -8,8 +8,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
 )
 
 type transfoBuilder struct {
@@ -25,7 +26,7 @@ func newTransfoBuilder(info *reconcilers.Instance, desired *flowslatest.FlowColl
 
 func (b *transfoBuilder) deployment(annotations map[string]string) *appsv1.Deployment {
 	pod := b.generic.podTemplate(false /*no listen*/, false /*no host network*/, annotations)
-	return &appsv1.Deployment{
+	dep := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      b.generic.name(),
 			Namespace: b.generic.info.Namespace,
@@ -39,6 +40,10 @@ func (b *transfoBuilder) deployment(annotations map[string]string) *appsv1.Deplo
 			Template: pod,
 		},
 	}
+	if strategy := helper.GetAdvancedProcessorConfig(b.generic.desired.Processor.Advanced).DeploymentStrategy; strategy != nil {
+		dep.Spec.Strategy = *strategy
+	}
+	return &dep
 }
 
 func (b *transfoBuilder) configMap() (*corev1.ConfigMap, string, error) {
@@ -70,6 +75,7 @@ func (b *transfoBuilder) autoScaler() *ascv2.HorizontalPodAutoscaler {
 			MinReplicas: b.generic.desired.Processor.KafkaConsumerAutoscaler.MinReplicas,
 			MaxReplicas: b.generic.desired.Processor.KafkaConsumerAutoscaler.MaxReplicas,
 			Metrics:     b.generic.desired.Processor.KafkaConsumerAutoscaler.Metrics,
+			Behavior:    b.generic.desired.Processor.KafkaConsumerAutoscaler.Behavior,
 		},
 	}
 }
@@ -12,11 +12,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+	"github.com/netobserv/network-observability-operator/pkg/watchers"
 )
 
 type transformerReconciler struct {
@@ -28,6 +29,8 @@ type transformerReconciler struct {
 	configMap      *corev1.ConfigMap
 	roleBinding    *rbacv1.ClusterRoleBinding
 	serviceMonitor *monitoringv1.ServiceMonitor
+	healthMonitor  *monitoringv1.ServiceMonitor
+	flowsMonitor   *monitoringv1.ServiceMonitor
 	prometheusRule *monitoringv1.PrometheusRule
 }
 
@@ -44,6 +47,8 @@ func newTransformerReconciler(cmn *reconcilers.Instance) *transformerReconciler
 	}
 	if cmn.AvailableAPIs.HasSvcMonitor() {
 		rec.serviceMonitor = cmn.Managed.NewServiceMonitor(serviceMonitorName(ConfKafkaTransformer))
+		rec.healthMonitor = newHealthServiceMonitor(cmn, serviceMonitorName(ConfKafkaTransformer))
+		rec.flowsMonitor = cmn.Managed.NewServiceMonitor(flowsServiceMonitorName(ConfKafkaTransformer))
 	}
 	if cmn.AvailableAPIs.HasPromRule() {
 		rec.prometheusRule = cmn.Managed.NewPrometheusRule(prometheusRuleName(ConfKafkaTransformer))
@@ -72,7 +77,7 @@ func (r *transformerReconciler) reconcile(ctx context.Context, desired *flowslat
 		return err
 	}
 
-	if !helper.UseKafka(&desired.Spec) {
+	if !helper.UseKafka(&desired.Spec) && !helper.UseKafkaSource(&desired.Spec) {
 		r.Status.SetUnused("Transformer only used with Kafka")
 		r.Managed.TryDeleteAll(ctx)
 		return nil
@@ -115,14 +120,35 @@ func (r *transformerReconciler) reconcile(ctx context.Context, desired *flowslat
 		return err
 	}
 
+	// Watch for Loki credentials if necessary; the digest is ignored too, as credentials are read from file on each request
+	if _, err = r.ReconcileLokiCredentials(ctx); err != nil {
+		return err
+	}
+
 	// Watch for Kafka certificate if necessary; need to restart pods in case of cert rotation
-	if err = annotateKafkaCerts(ctx, r.Common, &desired.Spec.Kafka, "kafka", annotations); err != nil {
+	kafka, _ := kafkaInput(&desired.Spec)
+	if err = annotateKafkaCerts(ctx, r.Common, kafka, "kafka", annotations); err != nil {
 		return err
 	}
 	// Same for Kafka exporters
 	if err = annotateKafkaExporterCerts(ctx, r.Common, desired.Spec.Exporters, annotations); err != nil {
 		return err
 	}
+
+	// Watch for Loki exporters and migration target certificates and credentials
+	if err = watchAdditionalLokis(ctx, r.Common, &desired.Spec); err != nil {
+		return err
+	}
+	// Watch for trusted CA bundle injection; need to restart pods as the system bundle is only read at startup
+	if r.Proxy.UseTrustedCABundle() {
+		digest, err := r.ReconcileTrustedCABundle(ctx)
+		if err != nil || digest == "" {
+			// not deployed until the bundle is injected
+			return err
+		}
+		annotations[watchers.Annotation("trusted-ca")] = digest
+	}
+
 	// Watch for monitoring caCert
 	if err = reconcileMonitoringCerts(ctx, r.Common, &desired.Spec.Processor.Metrics.Server.TLS, r.Namespace); err != nil {
 		return err
@@ -173,14 +199,16 @@ func (r *transformerReconciler) reconcilePrometheusService(ctx context.Context,
 		return err
 	}
 	if r.AvailableAPIs.HasSvcMonitor() {
-		serviceMonitor := builder.generic.serviceMonitor()
-		if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.serviceMonitor, serviceMonitor, &report, helper.ServiceMonitorChanged); err != nil {
+		if err := reconcileServiceMonitors(ctx, r.Instance, builder.generic, r.serviceMonitor, r.healthMonitor, r.flowsMonitor, &report); err != nil {
 			return err
 		}
 	}
 	if r.AvailableAPIs.HasPromRule() {
 		promRules := builder.generic.prometheusRule()
-		if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.prometheusRule, promRules, &report, helper.PrometheusRuleChanged); err != nil {
+		if isEmptyPrometheusRule(promRules) {
+			// e.g. all alerts are disabled: don't leave a stale rule behind
+			r.Managed.TryDelete(ctx, r.prometheusRule)
+		} else if err := reconcilers.GenericReconcile(ctx, r.Managed, &r.Client, r.prometheusRule, promRules, &report, helper.PrometheusRuleChanged); err != nil {
 			return err
 		}
 	}
@@ -202,5 +230,8 @@ func (r *transformerReconciler) reconcilePermissions(ctx context.Context, builde
 		return err
 	}
 
-	return reconcileLokiRoles(ctx, r.Common, &builder.generic)
+	if err := reconcileLokiRoles(ctx, r.Common, &builder.generic); err != nil {
+		return err
+	}
+	return reconcileRBACProxyRoles(ctx, r.Common, &builder.generic)
 }
@@ -0,0 +1,92 @@
+package flp
+
+import (
+	"net/netip"
+	"slices"
+
+	"github.com/netobserv/flowlogs-pipeline/pkg/api"
+	"github.com/netobserv/flowlogs-pipeline/pkg/config"
+
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/kubevirt"
+)
+
+// vmOwnerType is the owner kind of virt-launcher pods, set by the Kubernetes enrichment on the pod network
+const vmOwnerType = "VirtualMachineInstance"
+
+// addVirtualMachineStages attributes flows to KubeVirt virtual machines, by matching their IP addresses as subnets.
+// The Kubernetes enrichment doesn't know the addresses of secondary interfaces: flows on these interfaces also get
+// the namespace and owner of the virtual machine, like flows on the pod network get them from the virt-launcher pod.
+func (b *PipelineBuilder) addVirtualMachineStages(lastStage config.PipelineBuilderStage) config.PipelineBuilderStage {
+	if !helper.IsVirtualizationEnabled(&b.desired.Processor) {
+		return lastStage
+	}
+	names, namespaces, kinds := virtualMachinesToSubnetLabels(b.virtualMachines)
+	if len(names) == 0 {
+		return lastStage
+	}
+	lastStage = lastStage.TransformNetwork("enrich-vm", api.TransformNetwork{
+		Rules: api.NetworkTransformRules{
+			addSubnetLabelRule("SrcAddr", "SrcK8S_VirtualMachine"),
+			addSubnetLabelRule("DstAddr", "DstK8S_VirtualMachine"),
+			addSubnetLabelRule("SrcAddr", "SrcK8S_OwnerName"),
+			addSubnetLabelRule("DstAddr", "DstK8S_OwnerName"),
+		},
+		SubnetLabels: names,
+	})
+	lastStage = lastStage.TransformNetwork("enrich-vm-namespace", api.TransformNetwork{
+		Rules: api.NetworkTransformRules{
+			addSubnetLabelRule("SrcAddr", "SrcK8S_Namespace"),
+			addSubnetLabelRule("DstAddr", "DstK8S_Namespace"),
+		},
+		SubnetLabels: namespaces,
+	})
+	return lastStage.TransformNetwork("enrich-vm-kind", api.TransformNetwork{
+		Rules: api.NetworkTransformRules{
+			addSubnetLabelRule("SrcAddr", "SrcK8S_OwnerType"),
+			addSubnetLabelRule("DstAddr", "DstK8S_OwnerType"),
+		},
+		SubnetLabels: kinds,
+	})
+}
+
+func addSubnetLabelRule(input, output string) api.NetworkTransformRule {
+	return api.NetworkTransformRule{
+		Type: api.NetworkAddSubnetLabel,
+		AddSubnetLabel: &api.NetworkAddSubnetLabelRule{
+			Input:  input,
+			Output: output,
+		},
+	}
+}
+
+// virtualMachinesToSubnetLabels returns the labels of the virtual machines addresses by name, by namespace, and
+// a single label for the owner kind. Namespaces are grouped to keep the configuration small.
+func virtualMachinesToSubnetLabels(vms []kubevirt.VirtualMachine) (names, namespaces, kinds []api.NetworkTransformSubnetLabel) {
+	kind := api.NetworkTransformSubnetLabel{Name: vmOwnerType}
+	for i := range vms {
+		var cidrs []string
+		for _, ip := range vms[i].IPs {
+			addr, err := netip.ParseAddr(ip)
+			// link-local addresses reported by the guest agent aren't unique to the virtual machine
+			if err != nil || addr.IsLinkLocalUnicast() {
+				continue
+			}
+			cidrs = append(cidrs, netip.PrefixFrom(addr, addr.BitLen()).String())
+		}
+		if len(cidrs) == 0 {
+			continue
+		}
+		names = append(names, api.NetworkTransformSubnetLabel{Name: vms[i].Name, CIDRs: cidrs})
+		if n := len(namespaces); n > 0 && namespaces[n-1].Name == vms[i].Namespace {
+			namespaces[n-1].CIDRs = append(namespaces[n-1].CIDRs, cidrs...)
+		} else {
+			namespaces = append(namespaces, api.NetworkTransformSubnetLabel{Name: vms[i].Namespace, CIDRs: slices.Clone(cidrs)})
+		}
+		kind.CIDRs = append(kind.CIDRs, cidrs...)
+	}
+	if len(kind.CIDRs) > 0 {
+		kinds = []api.NetworkTransformSubnetLabel{kind}
+	}
+	return names, namespaces, kinds
+}
@@ -4,13 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
 	"github.com/netobserv/flowlogs-pipeline/pkg/config"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
@@ -110,3 +112,93 @@ func TestFlowMetricToFLP(t *testing.T) {
 		Buckets: []float64{1, 5, 10, 50, 100},
 	}, *m2)
 }
+
+func TestFlowMetricRecordingRules(t *testing.T) {
+	assert := assert.New(t)
+
+	b, err := defaultBuilderWithMetrics(&metricslatest.FlowMetricList{
+		Items: []metricslatest.FlowMetric{
+			{Spec: metricslatest.FlowMetricSpec{
+				MetricName: "m_1_total",
+				Type:       metricslatest.CounterMetric,
+				Labels:     []string{"SrcK8S_Namespace", "SrcK8S_OwnerName"},
+				RecordingRules: []metricslatest.RecordingRule{
+					{Labels: []string{"SrcK8S_Namespace"}},
+					{Window: &metav1.Duration{Duration: time.Hour}},
+				},
+			}},
+			{Spec: metricslatest.FlowMetricSpec{
+				MetricName:     "m_2",
+				Type:           metricslatest.HistogramMetric,
+				Labels:         []string{"SrcK8S_Namespace"},
+				RecordingRules: []metricslatest.RecordingRule{{Labels: []string{"SrcK8S_Namespace"}}},
+			}},
+		},
+	})
+	assert.NoError(err)
+	groups := b.generic.prometheusRule().Spec.Groups
+	assert.Len(groups, 2)
+	assert.Equal("NetobservFlowMetrics", groups[1].Name)
+	rules := groups[1].Rules
+	assert.Len(rules, 3)
+	assert.Equal("netobserv:m_1_total_by_SrcK8S_Namespace:rate5m", rules[0].Record)
+	assert.Equal("sum by (SrcK8S_Namespace) (rate(netobserv_m_1_total[5m]))", rules[0].Expr.String())
+	assert.Equal("netobserv:m_1_total:rate1h", rules[1].Record)
+	assert.Equal("sum(rate(netobserv_m_1_total[1h]))", rules[1].Expr.String())
+	assert.Equal("netobserv:m_2_bucket_by_SrcK8S_Namespace:rate5m", rules[2].Record)
+	assert.Equal("sum by (SrcK8S_Namespace, le) (rate(netobserv_m_2_bucket[5m]))", rules[2].Expr.String())
+
+	// Label not part of the metric
+	b, err = defaultBuilderWithMetrics(&metricslatest.FlowMetricList{
+		Items: []metricslatest.FlowMetric{
+			{Spec: metricslatest.FlowMetricSpec{
+				MetricName:     "m_1_total",
+				Type:           metricslatest.CounterMetric,
+				Labels:         []string{"SrcK8S_Namespace"},
+				RecordingRules: []metricslatest.RecordingRule{{Labels: []string{"DstK8S_Namespace"}}},
+			}},
+		},
+	})
+	assert.NoError(err)
+	_, _, err = b.configMap()
+	assert.ErrorContains(err, "recording rule label 'DstK8S_Namespace' is not a label of the metric")
+}
+
+func TestFlowMetricRemapAndDivider(t *testing.T) {
+	assert := assert.New(t)
+
+	b, err := defaultBuilderWithMetrics(&metricslatest.FlowMetricList{
+		Items: []metricslatest.FlowMetric{
+			{Spec: metricslatest.FlowMetricSpec{
+				MetricName:     "m_1_seconds",
+				Type:           metricslatest.HistogramMetric,
+				ValueField:     "TimeFlowRttNs",
+				Divider:        "1000000000",
+				Labels:         []string{"SrcK8S_Namespace", "DstK8S_Namespace"},
+				Remap:          map[string]string{"SrcK8S_Namespace": "from", "DstK8S_Namespace": "to"},
+				RecordingRules: []metricslatest.RecordingRule{{Labels: []string{"SrcK8S_Namespace"}}},
+			}},
+		},
+	})
+	assert.NoError(err)
+	cm, _, err := b.configMap()
+	assert.NoError(err)
+	cfs, pipeline := validatePipelineConfig(t, cm)
+	assert.Contains(pipeline, `{"name":"metrics-remap","follows":"enrich"},{"name":"prometheus","follows":"metrics-remap"}`)
+	remap := cfs.Parameters[5].Transform.Generic
+	assert.Equal(api.PreserveOriginalKeys, remap.Policy)
+	assert.Equal([]api.GenericTransformRule{
+		{Input: "DstK8S_Namespace", Output: "to"},
+		{Input: "SrcK8S_Namespace", Output: "from"},
+	}, remap.Rules)
+
+	items, err := getConfiguredMetrics(cm)
+	assert.NoError(err)
+	m1 := metric(items, "m_1_seconds")
+	assert.Equal([]string{"from", "to"}, m1.Labels)
+	assert.Equal(float64(1000000000), m1.ValueScale)
+
+	rules := b.generic.prometheusRule().Spec.Groups[1].Rules
+	assert.Equal("netobserv:m_1_seconds_bucket_by_from:rate5m", rules[0].Record)
+	assert.Equal("sum by (from, le) (rate(netobserv_m_1_seconds_bucket[5m]))", rules[0].Expr.String())
+}
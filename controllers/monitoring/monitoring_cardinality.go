@@ -0,0 +1,105 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	"github.com/netobserv/network-observability-operator/pkg/cardinality"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/metrics"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
+)
+
+const (
+	defaultCardinalityInterval = 5 * time.Minute
+	// prometheusLookback is the delay during which Prometheus still returns the series of a disabled metric
+	prometheusLookback = 5 * time.Minute
+)
+
+// checkCardinality counts the series of the NetObserv metrics in Prometheus and compares them with `spec.processor.metrics.maxCardinality`.
+// An exceeded budget is reported as a warning, and may disable the worst offending FlowMetric. At most one FlowMetric is disabled
+// per interval, and not before the series of the previous one expire, so that they are not counted again to disable another one.
+// It returns the delay until the next check, or zero when no budget is configured, and the reason and message of a warning, if any.
+func (r *Reconciler) checkCardinality(ctx context.Context, desired *flowslatest.FlowCollector) (time.Duration, string, string) {
+	spec := &desired.Spec.Processor.Metrics
+	if spec.MaxCardinality == 0 {
+		r.closeQuerier()
+		return 0, "", ""
+	}
+	interval := defaultCardinalityInterval
+	if spec.CardinalityGuard.Interval != nil && spec.CardinalityGuard.Interval.Duration > 0 {
+		interval = spec.CardinalityGuard.Interval.Duration
+	}
+	guard := &spec.CardinalityGuard
+	if r.querier == nil || !r.querier.Matches(guard.URL, guard.InsecureSkipVerify) {
+		r.closeQuerier()
+		r.querier = prometheus.NewQuerier(guard.URL, guard.InsecureSkipVerify, r.mgr.GetConfig())
+	}
+
+	counts, err := cardinality.SeriesCount(ctx, r.querier, metrics.GetPrefix(&desired.Spec))
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Cardinality check failure")
+		return interval, "CardinalityCheckFailed", fmt.Sprintf("Could not count the series of NetObserv metrics: %s", err.Error())
+	}
+	usage := cardinality.Usage{Prefix: metrics.GetPrefix(&desired.Spec), PerMetric: counts}
+	total := usage.Total()
+	if total <= int(spec.MaxCardinality) {
+		return interval, "", ""
+	}
+
+	message := fmt.Sprintf("NetObserv metrics have %d series, exceeding spec.processor.metrics.maxCardinality (%d)", total, spec.MaxCardinality)
+	if spec.CardinalityGuard.Action == flowslatest.CardinalityGuardDisableFlowMetric {
+		disabled, err := r.disableWorstFlowMetric(ctx, helper.GetNamespace(&desired.Spec), &usage, spec.MaxCardinality, max(interval, prometheusLookback))
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to disable FlowMetric")
+			message += fmt.Sprintf("; could not disable a FlowMetric: %s", err.Error())
+		} else if disabled != "" {
+			message += fmt.Sprintf("; FlowMetric %s has been disabled", disabled)
+		}
+	}
+	return interval, cardinality.ReasonBudgetExceeded, message
+}
+
+// disableWorstFlowMetric disables the FlowMetric generating the most series, and returns its name, or an empty string if none was found
+// or if another FlowMetric was disabled less than minDelay ago
+func (r *Reconciler) disableWorstFlowMetric(ctx context.Context, ns string, usage *cardinality.Usage, budget int32, minDelay time.Duration) (string, error) {
+	list := metricslatest.FlowMetricList{}
+	if err := r.List(ctx, &list, &client.ListOptions{Namespace: ns}); err != nil {
+		return "", err
+	}
+	if last := cardinality.LastDisable(list.Items); last != nil && time.Since(last.Time) < minDelay {
+		log.FromContext(ctx).Info("Waiting for the series of the last disabled FlowMetric to expire", "lastDisable", last.Time)
+		return "", nil
+	}
+	worst, series := usage.WorstFlowMetric(list.Items)
+	if worst == nil {
+		return "", nil
+	}
+	cardinality.Disable(worst, series, usage, budget)
+	if err := r.Status().Update(ctx, worst); err != nil {
+		return "", err
+	}
+	log.FromContext(ctx).Info("FlowMetric disabled by the cardinality guard", "name", worst.Name, "series", series)
+	r.recorder.Eventf(
+		worst,
+		corev1.EventTypeWarning,
+		cardinality.ReasonBudgetExceeded,
+		"FlowMetric disabled: it generates %d series, while NetObserv metrics have %d series, exceeding the budget of %d",
+		series, usage.Total(), budget,
+	)
+	return worst.Name, nil
+}
+
+func (r *Reconciler) closeQuerier() {
+	if r.querier != nil {
+		r.querier.Close()
+		r.querier = nil
+	}
+}
@@ -3,40 +3,66 @@ package monitoring
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/cardinality"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
 	"github.com/netobserv/network-observability-operator/pkg/manager"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
 	"github.com/netobserv/network-observability-operator/pkg/metrics"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
 )
 
 type Reconciler struct {
 	client.Client
-	mgr    *manager.Manager
-	status status.Instance
+	mgr      *manager.Manager
+	status   status.Instance
+	recorder record.EventRecorder
+	querier  *prometheus.Querier
 }
 
 func Start(ctx context.Context, mgr *manager.Manager) error {
 	log := log.FromContext(ctx)
 	log.Info("Starting Monitoring controller")
 	r := Reconciler{
-		Client: mgr.Client,
-		mgr:    mgr,
-		status: mgr.Status.ForComponent(status.Monitoring),
+		Client:   mgr.Client,
+		mgr:      mgr,
+		status:   mgr.Status.ForComponent(status.Monitoring),
+		recorder: mgr.GetEventRecorderFor("netobserv-operator"),
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&flowslatest.FlowCollector{}, reconcilers.IgnoreStatusChange).
 		Named("monitoring").
 		Owns(&corev1.Namespace{}).
+		// reconcile again when FlowMetric charts change; status changes, such as a FlowMetric disabled by the cardinality guard, are ignored
+		Watches(
+			&metricslatest.FlowMetric{},
+			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+			}),
+			reconcilers.IgnoreStatusChange,
+		).
+		// reconcile again when APIs such as ServiceMonitor get installed or removed
+		WatchesRawSource(
+			mgr.APIChangesSource(),
+			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+			}),
+		).
 		Complete(&r)
 }
 
@@ -54,72 +80,152 @@ func (r *Reconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result
 		// Delete case
 		return ctrl.Result{}, nil
 	}
+	// Features disabled by the operator feature gates are turned off in the desired state
+	desired, _ = r.mgr.Config.FeatureGates.Apply(desired)
 
 	r.status.SetUnknown()
 	defer r.status.Commit(ctx, r.Client)
 
-	err = r.reconcile(ctx, clh, desired)
+	tracked := reconcilers.TrackObjects(*clh, r.status)
+	warnings, err := r.reconcile(ctx, &tracked, desired)
 	if err != nil {
 		l.Error(err, "Monitoring reconcile failure")
 		// Set status failure unless it was already set
 		if !r.status.HasFailure() {
-			r.status.SetFailure("MonitoringError", err.Error())
+			_ = r.status.Error("MonitoringError", err)
+		}
+		if status.KindOf(err) == status.ErrorInvalidSpec {
+			// not retried: a fix requires a change, which triggers a new reconcile
+			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
 	r.status.SetReady()
-	return ctrl.Result{}, nil
+	requeueAfter, reason, warning := r.checkCardinality(ctx, desired)
+	if warning != "" {
+		warnings = append([]string{warning}, warnings...)
+	} else {
+		reason = "NamespacedInstall"
+	}
+	if len(warnings) > 0 {
+		r.status.SetWarning(reason, strings.Join(warnings, "; "))
+	} else {
+		r.status.ClearWarning()
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-func (r *Reconciler) reconcile(ctx context.Context, clh *helper.Client, desired *flowslatest.FlowCollector) error {
+// reconcile returns warnings about the objects that can't be managed, along with any error
+func (r *Reconciler) reconcile(ctx context.Context, clh *helper.Client, desired *flowslatest.FlowCollector) ([]string, error) {
+	if err := r.mgr.Config.CheckFlowCollector(&desired.Spec); err != nil {
+		return nil, r.status.Error("NamespacedInstall", err)
+	}
 	ns := helper.GetNamespace(&desired.Spec)
 
 	// If namespace does not exist, we create it
 	nsExist, err := r.namespaceExist(ctx, ns)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	desiredNs := buildNamespace(ns, r.mgr.Config.DownstreamDeployment)
+	userWorkload := helper.GetFlowMetricsTier(&desired.Spec.Processor.Metrics) == flowslatest.FlowMetricsUserWorkload
+	desiredNs := buildNamespace(ns, r.mgr.Config.DownstreamDeployment, userWorkload)
 	if nsExist == nil {
 		err = r.Create(ctx, desiredNs)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else if !helper.IsSubSet(nsExist.ObjectMeta.Labels, desiredNs.ObjectMeta.Labels) {
 		err = r.Update(ctx, desiredNs)
 		if err != nil {
-			return err
+			return nil, err
+		}
+	} else if _, labelled := nsExist.Labels[downstreamLabelKey]; labelled && r.mgr.Config.DownstreamDeployment && userWorkload {
+		// the label is only removed, leaving the other namespace labels untouched
+		delete(nsExist.Labels, downstreamLabelKey)
+		if err = r.Update(ctx, nsExist); err != nil {
+			return nil, err
 		}
 	}
 	if r.mgr.Config.DownstreamDeployment {
 		desiredRole := buildRoleMonitoringReader()
 		if err := reconcilers.ReconcileClusterRole(ctx, clh, desiredRole); err != nil {
-			return err
+			return nil, err
 		}
 		desiredBinding := buildRoleBindingMonitoringReader(ns)
 		if err := reconcilers.ReconcileClusterRoleBinding(ctx, clh, desiredBinding); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	if r.mgr.HasSvcMonitor() {
-		names := metrics.GetIncludeList(&desired.Spec)
-		desiredFlowDashboardCM, del, err := buildFlowMetricsDashboard(ns, names)
-		if err != nil {
-			return err
-		} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredFlowDashboardCM, del); err != nil {
-			return err
-		}
+	if !r.mgr.Config.IsWatched(dashboardCMNamespace) {
+		// dashboards can only be created by the operator in this namespace
+		return []string{fmt.Sprintf("the operator doesn't watch the %s namespace: dashboards are not managed", dashboardCMNamespace)}, nil
+	}
 
-		desiredHealthDashboardCM, del, err := buildHealthDashboard(ns)
-		if err != nil {
-			return err
-		} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredHealthDashboardCM, del); err != nil {
-			return err
-		}
+	// Without the ServiceMonitor API, metrics aren't scraped: dashboards previously created are deleted
+	noMetrics := !r.mgr.HasSvcMonitor()
+	names := metrics.GetIncludeList(&desired.Spec)
+	prefix := metrics.GetPrefix(&desired.Spec)
+	desiredFlowDashboardCM, del, err := buildFlowMetricsDashboard(ns, prefix, names)
+	if err != nil {
+		return nil, err
+	} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredFlowDashboardCM, del || noMetrics); err != nil {
+		return nil, err
+	}
+
+	desiredHealthDashboardCM, del, err := buildHealthDashboard(ns, prefix)
+	if err != nil {
+		return nil, err
+	} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredHealthDashboardCM, del || noMetrics); err != nil {
+		return nil, err
+	}
+
+	desiredPipelineDashboardCM, del, err := buildPipelineDashboard(ns)
+	if err != nil {
+		return nil, err
+	} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredPipelineDashboardCM, del || noMetrics); err != nil {
+		return nil, err
+	}
+
+	desiredMeshDashboardCM, del, err := buildServiceMeshDashboard(ns, prefix, names)
+	if err != nil {
+		return nil, err
+	} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredMeshDashboardCM, del || noMetrics); err != nil {
+		return nil, err
+	}
+
+	desiredEgressCostDashboardCM, del, err := buildEgressCostDashboard(ns, prefix, names, &desired.Spec)
+	if err != nil {
+		return nil, err
+	} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredEgressCostDashboardCM, del || noMetrics); err != nil {
+		return nil, err
+	}
+
+	desiredLoadTestDashboardCM, del, err := buildLoadTestDashboard(ns, &desired.Spec.LoadGenerator)
+	if err != nil {
+		return nil, err
+	} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredLoadTestDashboardCM, del || noMetrics); err != nil {
+		return nil, err
+	}
+
+	desiredPluginDashboardCM, del, err := buildPluginDashboard(ns)
+	noPlugin := !r.mgr.HasConsolePlugin() || !helper.UseConsolePlugin(&desired.Spec)
+	if err != nil {
+		return nil, err
+	} else if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredPluginDashboardCM, del || noMetrics || noPlugin); err != nil {
+		return nil, err
+	}
+
+	fm := metricslatest.FlowMetricList{}
+	if err := r.List(ctx, &fm, &client.ListOptions{Namespace: ns}); err != nil {
+		return nil, err
+	}
+	desiredChartsDashboardCM := buildFlowMetricsChartsDashboard(ns, prefix, cardinality.FilterEnabled(fm.Items))
+	if err = reconcilers.ReconcileConfigMap(ctx, clh, desiredChartsDashboardCM, len(desiredChartsDashboardCM.Data) == 0 || noMetrics); err != nil {
+		return nil, err
 	}
-	return nil
+	return nil, nil
 }
 
 func (r *Reconciler) namespaceExist(ctx context.Context, nsName string) (*corev1.Namespace, error) {
@@ -1,6 +1,9 @@
 package monitoring
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -10,7 +13,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	. "github.com/netobserv/network-observability-operator/controllers/controllerstest"
+	"github.com/netobserv/network-observability-operator/pkg/cardinality"
 	"github.com/netobserv/network-observability-operator/pkg/dashboards"
 	"github.com/netobserv/network-observability-operator/pkg/test"
 )
@@ -128,6 +133,73 @@ func ControllerSpecs() {
 		})
 	})
 
+	Context("Exceeding the cardinality budget", func() {
+		var server *httptest.Server
+		flowMetricKeys := []types.NamespacedName{
+			{Name: "metric-a", Namespace: operatorNamespace},
+			{Name: "metric-b", Namespace: operatorNamespace},
+		}
+
+		It("Should disable a single FlowMetric", func() {
+			// Prometheus keeps returning the series of a disabled metric during its lookback delay
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+					{"metric":{"__name__":"netobserv_metric_a_total"},"value":[1700000000,"3000"]},
+					{"metric":{"__name__":"netobserv_metric_b_total"},"value":[1700000000,"2000"]}
+				]}}`))
+			}))
+			for _, key := range flowMetricKeys {
+				Expect(k8sClient.Create(ctx, &metricslatest.FlowMetric{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+					Spec: metricslatest.FlowMetricSpec{
+						MetricName: strings.ReplaceAll(key.Name, "-", "_") + "_total",
+						Type:       metricslatest.CounterMetric,
+						Filters:    []metricslatest.MetricFilter{},
+					},
+				})).Should(Succeed())
+			}
+			updateCR(crKey, func(fc *flowslatest.FlowCollector) {
+				fc.Spec.Processor.Metrics.MaxCardinality = 1000
+				fc.Spec.Processor.Metrics.CardinalityGuard = flowslatest.CardinalityGuard{
+					Action:   flowslatest.CardinalityGuardDisableFlowMetric,
+					URL:      server.URL,
+					Interval: &metav1.Duration{Duration: time.Second},
+				}
+			})
+
+			disabled := func() interface{} {
+				var names []string
+				for _, key := range flowMetricKeys {
+					fm := metricslatest.FlowMetric{}
+					if err := k8sClient.Get(ctx, key, &fm); err != nil {
+						return err
+					}
+					if cardinality.IsDisabled(&fm) {
+						names = append(names, fm.Name)
+					}
+				}
+				return names
+			}
+			By("Expecting the worst FlowMetric to be disabled")
+			Eventually(disabled, timeout, interval).Should(Equal([]string{"metric-a"}))
+
+			By("Expecting no other FlowMetric to be disabled while the series remain")
+			Consistently(disabled, 5*time.Second, interval).Should(Equal([]string{"metric-a"}))
+		})
+
+		It("Should cleanup", func() {
+			server.Close()
+			for _, key := range flowMetricKeys {
+				Expect(k8sClient.Delete(ctx, &metricslatest.FlowMetric{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				})).Should(Succeed())
+			}
+			updateCR(crKey, func(fc *flowslatest.FlowCollector) {
+				fc.Spec.Processor.Metrics.MaxCardinality = 0
+			})
+		})
+	})
+
 	Context("Checking CR ownership", func() {
 		It("Should be garbage collected", func() {
 			// Retrieve CR to get its UID
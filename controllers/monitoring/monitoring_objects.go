@@ -1,8 +1,15 @@
 package monitoring
 
 import (
+	"regexp"
+	"slices"
+	"strings"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/pkg/dashboards"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,11 +27,32 @@ const (
 
 	healthDashboardCMName = "grafana-dashboard-netobserv-health"
 	healthDashboardCMFile = "netobserv-health-metrics.json"
+
+	pipelineDashboardCMName = "grafana-dashboard-netobserv-pipeline"
+	pipelineDashboardCMFile = "netobserv-pipeline-metrics.json"
+
+	pluginDashboardCMName = "grafana-dashboard-netobserv-plugin"
+	pluginDashboardCMFile = "netobserv-plugin-metrics.json"
+
+	loadTestDashboardCMName = "grafana-dashboard-netobserv-load-test"
+	loadTestDashboardCMFile = "netobserv-load-test-metrics.json"
+
+	meshDashboardCMName = "grafana-dashboard-netobserv-service-mesh"
+	meshDashboardCMFile = "netobserv-service-mesh-metrics.json"
+
+	egressCostDashboardCMName = "grafana-dashboard-netobserv-egress-cost"
+	egressCostDashboardCMFile = "netobserv-egress-cost-metrics.json"
+
+	chartsDashboardCMName = "grafana-dashboard-netobserv-flowmetrics-charts"
 )
 
-func buildNamespace(ns string, isDownstream bool) *corev1.Namespace {
+var dashboardFileCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+// buildNamespace returns the FlowCollector namespace, labelled for the platform monitoring unless the flow metrics are
+// routed to the user-workload monitoring, which doesn't scrape platform namespaces
+func buildNamespace(ns string, isDownstream, userWorkload bool) *corev1.Namespace {
 	labels := map[string]string{}
-	if isDownstream {
+	if isDownstream && !userWorkload {
 		labels[downstreamLabelKey] = downstreamLabelValue
 	}
 	return &corev1.Namespace{
@@ -74,8 +102,8 @@ func buildRoleBindingMonitoringReader(ns string) *rbacv1.ClusterRoleBinding {
 	}
 }
 
-func buildFlowMetricsDashboard(namespace string, metrics []string) (*corev1.ConfigMap, bool, error) {
-	dashboard, err := dashboards.CreateFlowMetricsDashboard(namespace, metrics)
+func buildFlowMetricsDashboard(namespace, prefix string, metrics []string) (*corev1.ConfigMap, bool, error) {
+	dashboard, err := dashboards.CreateFlowMetricsDashboard(namespace, prefix, metrics)
 	if err != nil {
 		return nil, false, err
 	}
@@ -95,8 +123,8 @@ func buildFlowMetricsDashboard(namespace string, metrics []string) (*corev1.Conf
 	return &configMap, len(dashboard) == 0, nil
 }
 
-func buildHealthDashboard(namespace string) (*corev1.ConfigMap, bool, error) {
-	dashboard, err := dashboards.CreateHealthDashboard(namespace)
+func buildHealthDashboard(namespace, prefix string) (*corev1.ConfigMap, bool, error) {
+	dashboard, err := dashboards.CreateHealthDashboard(namespace, prefix)
 	if err != nil {
 		return nil, false, err
 	}
@@ -115,3 +143,139 @@ func buildHealthDashboard(namespace string) (*corev1.ConfigMap, bool, error) {
 	}
 	return &configMap, len(dashboard) == 0, nil
 }
+
+func buildPipelineDashboard(namespace string) (*corev1.ConfigMap, bool, error) {
+	dashboard, err := dashboards.CreatePipelineHealthDashboard(namespace)
+	if err != nil {
+		return nil, false, err
+	}
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pipelineDashboardCMName,
+			Namespace: dashboardCMNamespace,
+			Labels: map[string]string{
+				dashboardCMAnnotation: "true",
+			},
+		},
+		Data: map[string]string{
+			pipelineDashboardCMFile: dashboard,
+		},
+	}
+	return &configMap, len(dashboard) == 0, nil
+}
+
+func buildPluginDashboard(namespace string) (*corev1.ConfigMap, bool, error) {
+	dashboard, err := dashboards.CreatePluginDashboard(namespace)
+	if err != nil {
+		return nil, false, err
+	}
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pluginDashboardCMName,
+			Namespace: dashboardCMNamespace,
+			Labels: map[string]string{
+				dashboardCMAnnotation: "true",
+			},
+		},
+		Data: map[string]string{
+			pluginDashboardCMFile: dashboard,
+		},
+	}
+	return &configMap, len(dashboard) == 0, nil
+}
+
+// buildServiceMeshDashboard returns a dashboard only when a mesh metric is generated
+func buildServiceMeshDashboard(namespace, prefix string, metrics []string) (*corev1.ConfigMap, bool, error) {
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      meshDashboardCMName,
+			Namespace: dashboardCMNamespace,
+			Labels: map[string]string{
+				dashboardCMAnnotation: "true",
+			},
+		},
+	}
+	if !slices.ContainsFunc(metrics, func(m string) bool { return strings.HasPrefix(m, "mesh_") }) {
+		return &configMap, true, nil
+	}
+
+	dashboard, err := dashboards.CreateServiceMeshDashboard(namespace, prefix, metrics)
+	if err != nil {
+		return nil, false, err
+	}
+	configMap.Data = map[string]string{
+		meshDashboardCMFile: dashboard,
+	}
+	return &configMap, len(dashboard) == 0, nil
+}
+
+// buildEgressCostDashboard returns a dashboard only when an egress class metric is generated
+func buildEgressCostDashboard(namespace, prefix string, metrics []string, spec *flowslatest.FlowCollectorSpec) (*corev1.ConfigMap, bool, error) {
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      egressCostDashboardCMName,
+			Namespace: dashboardCMNamespace,
+			Labels: map[string]string{
+				dashboardCMAnnotation: "true",
+			},
+		},
+	}
+	if !slices.ContainsFunc(metrics, func(m string) bool { return strings.Contains(m, "_egress_class_") }) {
+		return &configMap, true, nil
+	}
+
+	dashboard, err := dashboards.CreateEgressCostDashboard(namespace, prefix, metrics, helper.GetEgressCostPerGB(&spec.Processor), helper.GetSampling(spec))
+	if err != nil {
+		return nil, false, err
+	}
+	configMap.Data = map[string]string{
+		egressCostDashboardCMFile: dashboard,
+	}
+	return &configMap, len(dashboard) == 0, nil
+}
+
+// buildFlowMetricsChartsDashboard returns the dashboards configured in FlowMetric charts, with a file per dashboard
+func buildFlowMetricsChartsDashboard(namespace, prefix string, items []metricslatest.FlowMetric) *corev1.ConfigMap {
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      chartsDashboardCMName,
+			Namespace: dashboardCMNamespace,
+			Labels: map[string]string{
+				dashboardCMAnnotation: "true",
+			},
+		},
+		Data: map[string]string{},
+	}
+	for name, dashboard := range dashboards.CreateFlowMetricsChartsDashboards(namespace, prefix, items) {
+		file := "netobserv-" + strings.Trim(dashboardFileCleaner.ReplaceAllString(strings.ToLower(name), "-"), "-") + ".json"
+		configMap.Data[file] = dashboard
+	}
+	return &configMap
+}
+
+// buildLoadTestDashboard returns a dashboard only when the flow generator is enabled
+func buildLoadTestDashboard(namespace string, spec *flowslatest.FlowCollectorLoadGenerator) (*corev1.ConfigMap, bool, error) {
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      loadTestDashboardCMName,
+			Namespace: dashboardCMNamespace,
+			Labels: map[string]string{
+				dashboardCMAnnotation: "true",
+			},
+		},
+	}
+	if !helper.IsLoadGeneratorEnabled(spec) {
+		return &configMap, true, nil
+	}
+
+	dashboard, err := dashboards.CreateLoadTestDashboard(namespace, helper.PtrInt32(spec.FlowsPerSecond))
+	if err != nil {
+		return nil, false, err
+	}
+	configMap.Data = map[string]string{
+		loadTestDashboardCMFile: dashboard,
+	}
+	return &configMap, len(dashboard) == 0, nil
+}
@@ -2,14 +2,22 @@ package reconcilers
 
 import (
 	"context"
+	"fmt"
 
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/pkg/discover"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/kubevirt"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+	"github.com/netobserv/network-observability-operator/pkg/multus"
 	"github.com/netobserv/network-observability-operator/pkg/watchers"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Common struct {
@@ -21,7 +29,21 @@ type Common struct {
 	AvailableAPIs     *discover.AvailableAPIs
 	Loki              *helper.LokiConfig
 	ClusterID         string
+	Proxy             *helper.ProxyConfig
 	IsDownstream      bool
+	RBACProxyImage    string
+	VirtualMachines   []kubevirt.VirtualMachine
+	MultiHomedPods    []multus.Pod
+	// ClusterNetwork is the cluster network configuration read from the Cluster Network Operator, when available
+	ClusterNetwork *flowslatest.ClusterNetworkStatus
+	// BatchTuning holds the batch sizes picked from the flow rate, when batch auto-tuning is enabled
+	BatchTuning *flowslatest.BatchTuningStatus
+	// ConfigOverrides are the break-glass overrides of the generated configuration, from `advanced.overridesConfigMap`
+	ConfigOverrides map[string]string
+	// Namespaced is true when the operator only watches some namespaces, hence can't manage cluster-scoped objects
+	Namespaced bool
+	// AgentPods reads the eBPF agent pods from a cache restricted to them
+	AgentPods client.Reader
 }
 
 func (c *Common) PrivilegedNamespace() string {
@@ -39,16 +61,28 @@ type Instance struct {
 	Status  status.Instance
 }
 
+// NewInstance returns the reconciler context of a component. Its objects, including cluster-scoped ones, are reported in
+// the component status as they are written.
 func (c *Common) NewInstance(image string, st status.Instance) *Instance {
-	managed := NewNamespacedObjectManager(c)
+	cmn := *c
+	cmn.Client = TrackObjects(c.Client, st).WithOwnPendingClusterRBAC()
+	managed := NewNamespacedObjectManager(&cmn, st)
 	return &Instance{
-		Common:  c,
+		Common:  &cmn,
 		Managed: managed,
 		Image:   image,
 		Status:  st,
 	}
 }
 
+// TrackObjects returns a client that reports in `status.components` the objects it writes or deletes
+func TrackObjects(cl helper.Client, st status.Instance) helper.Client {
+	return cl.WithObjectsTracking(
+		func(obj client.Object) { st.SetObject(kindName(obj), obj) },
+		func(obj client.Object) { st.RemoveObject(kindName(obj), obj.GetNamespace(), obj.GetName()) },
+	)
+}
+
 func (c *Common) ReconcileClusterRoleBinding(ctx context.Context, desired *rbacv1.ClusterRoleBinding) error {
 	return ReconcileClusterRoleBinding(ctx, &c.Client, desired)
 }
@@ -65,6 +99,60 @@ func (c *Common) ReconcileRole(ctx context.Context, desired *rbacv1.Role) error
 	return ReconcileRole(ctx, &c.Client, desired)
 }
 
+// ReconcileTrustedCABundle ensures the ConfigMap receiving the cluster trusted CA bundle exists, and returns a digest of the bundle.
+// Only the labels are set by the operator: the bundle content is injected by OpenShift, hence no update is made here.
+// Until the bundle is injected, the digest is empty and the component is reported in progress, as mounting the missing key
+// would prevent the pods from starting. The ConfigMap is watched, so that the injection triggers a new reconcile.
+func (i *Instance) ReconcileTrustedCABundle(ctx context.Context) (string, error) {
+	c := i.Common
+	cm := corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: constants.TrustedCABundleName, Namespace: c.Namespace}, &cm); err != nil {
+		if !errors.IsNotFound(err) {
+			return "", fmt.Errorf("can't read ConfigMap %s: %w", constants.TrustedCABundleName, err)
+		}
+		cm = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.TrustedCABundleName,
+				Namespace: c.Namespace,
+				Labels: map[string]string{
+					constants.TrustedCABundleLabel: "true",
+				},
+			},
+		}
+		if err := c.CreateOwned(ctx, &cm); err != nil {
+			return "", err
+		}
+	}
+	// the ConfigMap is watched, so that the injection triggers a new reconcile
+	digest, err := c.Watcher.ProcessFileReference(ctx, c.Client, flowslatest.FileReference{
+		Type:      flowslatest.RefTypeConfigMap,
+		Name:      constants.TrustedCABundleName,
+		Namespace: c.Namespace,
+		File:      constants.TrustedCABundleKey,
+	}, c.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if cm.Data[constants.TrustedCABundleKey] == "" {
+		i.Status.SetInProgress("TrustedCABundlePending", fmt.Sprintf("Waiting for the trusted CA bundle to be injected in ConfigMap %s", constants.TrustedCABundleName))
+		return "", nil
+	}
+	return digest, nil
+}
+
+// ReconcileLokiCredentials copies the Loki credentials files in the namespace when needed, and returns their digest.
+func (c *Common) ReconcileLokiCredentials(ctx context.Context) (string, error) {
+	digest := ""
+	for _, file := range c.Loki.CredentialsFiles() {
+		d, err := c.Watcher.ProcessFileReference(ctx, c.Client, file, c.Namespace)
+		if err != nil {
+			return "", err
+		}
+		digest += d
+	}
+	return digest, nil
+}
+
 func (c *Common) ReconcileConfigMap(ctx context.Context, desired *corev1.ConfigMap, delete bool) error {
 	return ReconcileConfigMap(ctx, &c.Client, desired, delete)
 }
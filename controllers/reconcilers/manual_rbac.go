@@ -0,0 +1,119 @@
+package reconcilers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+// reconcileManualClusterRole writes the ClusterRole manifest for an administrator to apply it, instead of creating it.
+// A missing ClusterRole is reported by the next ClusterRoleBinding reconcile, as roles are not used until they are bound.
+func reconcileManualClusterRole(ctx context.Context, cl *helper.Client, ns string, desired *rbacv1.ClusterRole) error {
+	manifest := &rbacv1.ClusterRole{
+		TypeMeta:        metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta:      metav1.ObjectMeta{Name: desired.Name, Labels: desired.Labels},
+		Rules:           desired.Rules,
+		AggregationRule: desired.AggregationRule,
+	}
+	actual := rbacv1.ClusterRole{}
+	applied, err := getManuallyApplied(ctx, cl, manifest, &actual)
+	if err != nil {
+		return err
+	}
+	if !applied || !reflect.DeepEqual(actual.Rules, desired.Rules) {
+		cl.AddPendingClusterRBAC("ClusterRole " + desired.Name)
+	}
+	return writeManualClusterRBAC(ctx, cl, ns, manifest)
+}
+
+// reconcileManualClusterRoleBinding writes the ClusterRoleBinding manifest for an administrator to apply it, instead of creating it.
+// It returns a permission error while the binding, or a ClusterRole reconciled before, isn't applied as expected, so that the
+// component reports it in its condition.
+func reconcileManualClusterRoleBinding(ctx context.Context, cl *helper.Client, ns string, desired *rbacv1.ClusterRoleBinding) error {
+	manifest := &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Labels: desired.Labels},
+		RoleRef:    desired.RoleRef,
+		Subjects:   desired.Subjects,
+	}
+	actual := rbacv1.ClusterRoleBinding{}
+	applied, err := getManuallyApplied(ctx, cl, manifest, &actual)
+	if err != nil {
+		return err
+	}
+	if !applied || actual.RoleRef != desired.RoleRef || !reflect.DeepEqual(actual.Subjects, desired.Subjects) {
+		cl.AddPendingClusterRBAC("ClusterRoleBinding " + desired.Name)
+	}
+	if err := writeManualClusterRBAC(ctx, cl, ns, manifest); err != nil {
+		return err
+	}
+	if pending := cl.PendingClusterRBAC(); len(pending) > 0 {
+		return status.PermissionError(fmt.Errorf("missing or outdated %s: apply the manifests of the %s/%s config map",
+			strings.Join(pending, ", "), ns, constants.ManualClusterRBACName))
+	}
+	return nil
+}
+
+func getManuallyApplied(ctx context.Context, cl *helper.Client, manifest, actual client.Object) (bool, error) {
+	if err := cl.Get(ctx, types.NamespacedName{Name: manifest.GetName()}, actual); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("can't read %s %s: %w", manifest.GetObjectKind().GroupVersionKind().Kind, manifest.GetName(), err)
+	}
+	return true, nil
+}
+
+// manualRBACMutex serializes the writes to the ManualClusterRBACName config map, shared by the components reconciled concurrently
+var manualRBACMutex sync.Mutex
+
+// writeManualClusterRBAC stores a manifest in the ManualClusterRBACName config map, with a key per object
+func writeManualClusterRBAC(ctx context.Context, cl *helper.Client, ns string, manifest client.Object) error {
+	kind := manifest.GetObjectKind().GroupVersionKind().Kind
+	b, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	key := strings.ToLower(kind) + "-" + manifest.GetName() + ".yaml"
+
+	manualRBACMutex.Lock()
+	defer manualRBACMutex.Unlock()
+	// the cache may not have caught up with a previous write, hence the retries
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cm := corev1.ConfigMap{}
+		if err := cl.Get(ctx, types.NamespacedName{Name: constants.ManualClusterRBACName, Namespace: ns}, &cm); err != nil {
+			if !errors.IsNotFound(err) {
+				return fmt.Errorf("can't read ConfigMap %s: %w", constants.ManualClusterRBACName, err)
+			}
+			return cl.CreateOwned(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.ManualClusterRBACName, Namespace: ns},
+				Data:       map[string]string{key: string(b)},
+			})
+		}
+		if cm.Data[key] == string(b) {
+			return nil
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = string(b)
+		log.FromContext(ctx).Info("Writing manifest to apply", "ConfigMap", constants.ManualClusterRBACName, "key", key)
+		return cl.Update(ctx, &cm)
+	})
+}
@@ -0,0 +1,111 @@
+package reconcilers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+// objectsClient is a minimal client storing objects by name
+type objectsClient struct {
+	client.Client
+	objects map[string]client.Object
+}
+
+func (c *objectsClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	stored, ok := c.objects[key.Name]
+	if !ok {
+		return kerr.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (c *objectsClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	c.objects[obj.GetName()] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func (c *objectsClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.objects[obj.GetName()] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func TestManualClusterRBAC(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	stub := &objectsClient{objects: map[string]client.Object{}}
+
+	role := rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "netobserv-reader"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+	}
+	binding := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "netobserv-reader-flp"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "netobserv-reader"},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: "flowlogs-pipeline", Namespace: "netobserv"}},
+	}
+
+	// nothing applied: manifests are written, and the binding reports both objects
+	cl := helper.UnmanagedClient(stub)
+	assert.NoError(reconcileManualClusterRole(ctx, &cl, "netobserv", &role))
+	err := reconcileManualClusterRoleBinding(ctx, &cl, "netobserv", &binding)
+	assert.EqualError(err, "missing or outdated ClusterRole netobserv-reader, ClusterRoleBinding netobserv-reader-flp: apply the manifests of the netobserv/netobserv-cluster-rbac config map")
+	assert.Equal(status.ErrorPermission, status.KindOf(err))
+
+	cm := stub.objects[constants.ManualClusterRBACName].(*corev1.ConfigMap)
+	assert.Contains(cm.Data["clusterrole-netobserv-reader.yaml"], "kind: ClusterRole\n")
+	assert.Contains(cm.Data["clusterrolebinding-netobserv-reader-flp.yaml"], "name: flowlogs-pipeline\n")
+
+	// applied by an administrator
+	stub.objects["netobserv-reader"] = role.DeepCopy()
+	stub.objects["netobserv-reader-flp"] = binding.DeepCopy()
+	cl = helper.UnmanagedClient(stub)
+	assert.NoError(reconcileManualClusterRole(ctx, &cl, "netobserv", &role))
+	assert.NoError(reconcileManualClusterRoleBinding(ctx, &cl, "netobserv", &binding))
+
+	// outdated binding
+	binding.Subjects = append(binding.Subjects, rbacv1.Subject{Kind: "ServiceAccount", Name: "netobserv-plugin", Namespace: "netobserv"})
+	cl = helper.UnmanagedClient(stub)
+	err = reconcileManualClusterRoleBinding(ctx, &cl, "netobserv", &binding)
+	assert.EqualError(err, "missing or outdated ClusterRoleBinding netobserv-reader-flp: apply the manifests of the netobserv/netobserv-cluster-rbac config map")
+}
+
+func TestManualClusterRBACPerComponent(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	stub := &objectsClient{objects: map[string]client.Object{}}
+	shared := helper.UnmanagedClient(stub)
+
+	binding := func(name string) *rbacv1.ClusterRoleBinding {
+		return &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: name},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: name, Namespace: "netobserv"}},
+		}
+	}
+
+	// each component reports its own pending objects, while they share the manifests config map
+	plugin := shared.WithOwnPendingClusterRBAC()
+	query := shared.WithOwnPendingClusterRBAC()
+	err := reconcileManualClusterRoleBinding(ctx, &plugin, "netobserv", binding("netobserv-plugin"))
+	assert.EqualError(err, "missing or outdated ClusterRoleBinding netobserv-plugin: apply the manifests of the netobserv/netobserv-cluster-rbac config map")
+	err = reconcileManualClusterRoleBinding(ctx, &query, "netobserv", binding("netobserv-query"))
+	assert.EqualError(err, "missing or outdated ClusterRoleBinding netobserv-query: apply the manifests of the netobserv/netobserv-cluster-rbac config map")
+	assert.Empty(shared.PendingClusterRBAC())
+
+	cm := stub.objects[constants.ManualClusterRBACName].(*corev1.ConfigMap)
+	assert.Len(cm.Data, 2)
+}
@@ -14,11 +14,16 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
 )
 
-// NamespacedObjectManager provides some helpers to manage (fetch, delete) namespace-scoped objects
+// NamespacedObjectManager provides some helpers to manage (fetch, delete) namespace-scoped objects.
+// It also keeps the objects inventory, reported in FlowCollector `status.components`, up to date with the fetched objects.
+// Written and deleted objects are reported by the client, see TrackObjects.
 type NamespacedObjectManager struct {
 	client            client.Client
+	status            status.Instance
 	Namespace         string
 	PreviousNamespace string
 	managedObjects    []managedObject
@@ -29,16 +34,39 @@ type managedObject struct {
 	kind        string
 	placeholder client.Object
 	found       bool
+	// namespace and previousNamespace are set for objects installed outside of the managed namespace
+	namespace         string
+	previousNamespace string
+}
+
+func (o *managedObject) namespaceOr(def string) string {
+	if o.namespace != "" {
+		return o.namespace
+	}
+	return def
 }
 
-func NewNamespacedObjectManager(cmn *Common) *NamespacedObjectManager {
+func (o *managedObject) previousNamespaceOr(def string) string {
+	if o.namespace != "" {
+		return o.previousNamespace
+	}
+	return def
+}
+
+func NewNamespacedObjectManager(cmn *Common, st status.Instance) *NamespacedObjectManager {
 	return &NamespacedObjectManager{
 		client:            cmn.Client,
+		status:            st,
 		Namespace:         cmn.Namespace,
 		PreviousNamespace: cmn.PreviousNamespace,
 	}
 }
 
+// kindName returns the short kind of an object, e.g. "Deployment" for *appsv1.Deployment
+func kindName(obj client.Object) string {
+	return reflect.TypeOf(obj).Elem().Name()
+}
+
 // AddManagedObject should be used to register managed objects to be fetched by FetchAll, or deleted when namespace changes
 // This is only for namespace-scoped objects that are installed in the desired namespace (in FlowCollector CRD: spec.namespace)
 // Cluster-scope objects, or objects installed in a different namespace (e.g. OVS configmap) should not be registered with this function.
@@ -50,6 +78,18 @@ func (m *NamespacedObjectManager) AddManagedObject(name string, placeholder clie
 	})
 }
 
+// AddManagedObjectInNamespace registers a managed object installed in another namespace than the managed one, such as
+// the privileged namespace, which follows the managed namespace changes.
+func (m *NamespacedObjectManager) AddManagedObjectInNamespace(namespace, previousNamespace, name string, placeholder client.Object) {
+	m.managedObjects = append(m.managedObjects, managedObject{
+		name:              name,
+		kind:              reflect.TypeOf(placeholder).String(),
+		placeholder:       placeholder,
+		namespace:         namespace,
+		previousNamespace: previousNamespace,
+	})
+}
+
 func (m *NamespacedObjectManager) NewConfigMap(name string) *corev1.ConfigMap {
 	cm := corev1.ConfigMap{}
 	m.AddManagedObject(name, &cm)
@@ -113,10 +153,12 @@ func (m *NamespacedObjectManager) FetchAll(ctx context.Context) error {
 	for i, ref := range m.managedObjects {
 		m.managedObjects[i].found = false
 		objLog := ref.kind + "/" + ref.name
-		err := m.client.Get(ctx, types.NamespacedName{Name: ref.name, Namespace: m.Namespace}, ref.placeholder)
+		ns := ref.namespaceOr(m.Namespace)
+		err := m.client.Get(ctx, types.NamespacedName{Name: ref.name, Namespace: ns}, ref.placeholder)
 		if err != nil {
 			if errors.IsNotFound(err) {
 				notFound = append(notFound, objLog)
+				m.status.RemoveObject(kindName(ref.placeholder), ns, ref.name)
 			} else {
 				log.Error(err, "Failed to get "+objLog)
 				return err
@@ -124,6 +166,7 @@ func (m *NamespacedObjectManager) FetchAll(ctx context.Context) error {
 		} else {
 			fetched = append(fetched, objLog)
 			m.managedObjects[i].found = true
+			m.status.SetObject(kindName(ref.placeholder), ref.placeholder)
 			// On success, placeholder is filled with resource. Caller should keep a pointer to it.
 		}
 	}
@@ -138,12 +181,12 @@ func (m *NamespacedObjectManager) FetchAll(ctx context.Context) error {
 
 // CleanupPreviousNamespace removes all managed objects (registered using AddManagedObject) from the previous namespace.
 func (m *NamespacedObjectManager) CleanupPreviousNamespace(ctx context.Context) {
-	m.cleanup(ctx, m.PreviousNamespace)
-}
-
-func (m *NamespacedObjectManager) cleanup(ctx context.Context, namespace string) {
 	log := log.FromContext(ctx)
 	for _, obj := range m.managedObjects {
+		namespace := obj.previousNamespaceOr(m.PreviousNamespace)
+		if namespace == "" {
+			continue
+		}
 		ref := obj.placeholder.DeepCopyObject().(client.Object)
 		ref.SetName(obj.name)
 		ref.SetNamespace(namespace)
@@ -36,6 +36,9 @@ var (
 )
 
 func ReconcileClusterRoleBinding(ctx context.Context, cl *helper.Client, desired *rbacv1.ClusterRoleBinding) error {
+	if ns, manual := cl.ManualClusterRBAC(); manual {
+		return reconcileManualClusterRoleBinding(ctx, cl, ns, desired)
+	}
 	actual := rbacv1.ClusterRoleBinding{}
 	if err := cl.Get(ctx, types.NamespacedName{Name: desired.ObjectMeta.Name}, &actual); err != nil {
 		if errors.IsNotFound(err) {
@@ -44,6 +47,7 @@ func ReconcileClusterRoleBinding(ctx context.Context, cl *helper.Client, desired
 		return fmt.Errorf("can't reconcile ClusterRoleBinding %s: %w", desired.Name, err)
 	}
 	if helper.IsSubSet(actual.Labels, desired.Labels) &&
+		!cl.CommonMetadataChanged(&actual, desired) &&
 		actual.RoleRef == desired.RoleRef &&
 		reflect.DeepEqual(actual.Subjects, desired.Subjects) {
 		if actual.RoleRef != desired.RoleRef {
@@ -64,13 +68,14 @@ func ReconcileClusterRoleBinding(ctx context.Context, cl *helper.Client, desired
 
 func ReconcileRoleBinding(ctx context.Context, cl *helper.Client, desired *rbacv1.RoleBinding) error {
 	actual := rbacv1.RoleBinding{}
-	if err := cl.Get(ctx, types.NamespacedName{Name: desired.ObjectMeta.Name}, &actual); err != nil {
+	if err := cl.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, &actual); err != nil {
 		if errors.IsNotFound(err) {
 			return cl.CreateOwned(ctx, desired)
 		}
 		return fmt.Errorf("can't reconcile RoleBinding %s: %w", desired.Name, err)
 	}
 	if helper.IsSubSet(actual.Labels, desired.Labels) &&
+		!cl.CommonMetadataChanged(&actual, desired) &&
 		actual.RoleRef == desired.RoleRef &&
 		reflect.DeepEqual(actual.Subjects, desired.Subjects) {
 		if actual.RoleRef != desired.RoleRef {
@@ -90,6 +95,9 @@ func ReconcileRoleBinding(ctx context.Context, cl *helper.Client, desired *rbacv
 }
 
 func ReconcileClusterRole(ctx context.Context, cl *helper.Client, desired *rbacv1.ClusterRole) error {
+	if ns, manual := cl.ManualClusterRBAC(); manual {
+		return reconcileManualClusterRole(ctx, cl, ns, desired)
+	}
 	actual := rbacv1.ClusterRole{}
 	if err := cl.Get(ctx, types.NamespacedName{Name: desired.Name}, &actual); err != nil {
 		if errors.IsNotFound(err) {
@@ -99,6 +107,7 @@ func ReconcileClusterRole(ctx context.Context, cl *helper.Client, desired *rbacv
 	}
 
 	if helper.IsSubSet(actual.Labels, desired.Labels) &&
+		!cl.CommonMetadataChanged(&actual, desired) &&
 		reflect.DeepEqual(actual.Rules, desired.Rules) {
 		// cluster role already reconciled. Exiting
 		return nil
@@ -109,7 +118,7 @@ func ReconcileClusterRole(ctx context.Context, cl *helper.Client, desired *rbacv
 
 func ReconcileRole(ctx context.Context, cl *helper.Client, desired *rbacv1.Role) error {
 	actual := rbacv1.Role{}
-	if err := cl.Get(ctx, types.NamespacedName{Name: desired.Name}, &actual); err != nil {
+	if err := cl.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, &actual); err != nil {
 		if errors.IsNotFound(err) {
 			return cl.CreateOwned(ctx, desired)
 		}
@@ -117,6 +126,7 @@ func ReconcileRole(ctx context.Context, cl *helper.Client, desired *rbacv1.Role)
 	}
 
 	if helper.IsSubSet(actual.Labels, desired.Labels) &&
+		!cl.CommonMetadataChanged(&actual, desired) &&
 		reflect.DeepEqual(actual.Rules, desired.Rules) {
 		// role already reconciled. Exiting
 		return nil
@@ -138,10 +148,15 @@ func ReconcileConfigMap(ctx context.Context, cl *helper.Client, desired *corev1.
 	}
 
 	if delete {
-		return cl.Delete(ctx, desired)
+		if !helper.IsOwned(&actual) {
+			// not created by the operator: leave it
+			return nil
+		}
+		return cl.Delete(ctx, &actual)
 	}
 
 	if helper.IsSubSet(actual.Labels, desired.Labels) &&
+		!cl.CommonMetadataChanged(&actual, desired) &&
 		reflect.DeepEqual(actual.Data, desired.Data) {
 		// configmap already reconciled. Exiting
 		return nil
@@ -156,7 +171,10 @@ func ReconcileDaemonSet(ctx context.Context, ci *Instance, old, new *appsv1.Daem
 		return ci.CreateOwned(ctx, new)
 	}
 	ci.Status.CheckDaemonSetProgress(old)
-	if helper.PodChanged(&old.Spec.Template, &new.Spec.Template, containerName, report) {
+	if helper.PodChanged(&old.Spec.Template, &new.Spec.Template, containerName, report) ||
+		helper.DaemonSetUpdateStrategyChanged(old, new, report) ||
+		ci.CommonMetadataChanged(old, new) {
+		ci.Status.SetUpdatingDaemonSet(new)
 		return ci.UpdateIfOwned(ctx, old, new)
 	}
 	return nil
@@ -168,7 +186,9 @@ func ReconcileDeployment(ctx context.Context, ci *Instance, old, new *appsv1.Dep
 		return ci.CreateOwned(ctx, new)
 	}
 	ci.Status.CheckDeploymentProgress(old)
-	if helper.DeploymentChanged(old, new, containerName, !helper.HPAEnabled(hpa), replicas, report) {
+	if helper.DeploymentChanged(old, new, containerName, !helper.HPAEnabled(hpa), replicas, report) ||
+		ci.CommonMetadataChanged(old, new) {
+		ci.Status.SetUpdatingDeployment(new)
 		return ci.UpdateIfOwned(ctx, old, new)
 	}
 	return nil
@@ -179,7 +199,7 @@ func ReconcileHPA(ctx context.Context, ci *Instance, old, new *ascv2.HorizontalP
 	if helper.HPAEnabled(desired) {
 		if !ci.Managed.Exists(old) {
 			return ci.CreateOwned(ctx, new)
-		} else if helper.AutoScalerChanged(old, *desired, report) {
+		} else if helper.AutoScalerChanged(old, *desired, report) || ci.CommonMetadataChanged(old, new) {
 			return ci.UpdateIfOwned(ctx, old, new)
 		}
 	} else {
@@ -193,10 +213,11 @@ func ReconcileService(ctx context.Context, ci *Instance, old, new *corev1.Servic
 		if err := ci.CreateOwned(ctx, new); err != nil {
 			return err
 		}
-	} else if helper.ServiceChanged(old, new, report) {
+	} else if helper.ServiceChanged(old, new, report) || ci.CommonMetadataChanged(old, new) {
 		// In case we're updating an existing service, we need to build from the old one to keep immutable fields such as clusterIP
 		newSVC := old.DeepCopy()
 		newSVC.Spec.Ports = new.Spec.Ports
+		newSVC.ObjectMeta.Labels = new.ObjectMeta.Labels
 		newSVC.ObjectMeta.Annotations = new.ObjectMeta.Annotations
 		if err := ci.UpdateIfOwned(ctx, old, newSVC); err != nil {
 			return err
@@ -209,7 +230,7 @@ func GenericReconcile[K client.Object](ctx context.Context, m *NamespacedObjectM
 	if !m.Exists(old) {
 		return cl.CreateOwned(ctx, new)
 	}
-	if changeFunc(old, new, report) {
+	if changeFunc(old, new, report) || cl.CommonMetadataChanged(old, new) {
 		return cl.UpdateIfOwned(ctx, old, new)
 	}
 	return nil
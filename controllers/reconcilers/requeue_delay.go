@@ -0,0 +1,101 @@
+package reconcilers
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+const (
+	backoffBase   = 5 * time.Second
+	backoffMax    = 5 * time.Minute
+	backoffJitter = 0.2
+)
+
+var (
+	reconcileRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "netobserv_operator_reconcile_retries_total",
+			Help: "Number of failed reconciles of a component, each one followed by a retry after a backoff",
+		},
+		[]string{"component"},
+	)
+	reconcileFailures = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "netobserv_operator_reconcile_consecutive_failures",
+			Help: "Number of consecutive failed reconciles of a component",
+		},
+		[]string{"component"},
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(reconcileRetries, reconcileFailures)
+}
+
+// RequeueDelay computes the delay after which a failing component is reconciled again, with an exponential and jittered backoff,
+// so that a persistently failing component neither hot-loops nor delays the other components through a shared requeue.
+// It only schedules the retries: it doesn't prevent reconciles triggered in between, by a FlowCollector spec change or by a change
+// of a watched object such as a Secret, which run the component right away as they may fix the failure.
+// The backoff is reset on success, and when the FlowCollector generation changes.
+type RequeueDelay struct {
+	component  string
+	failures   int
+	generation int64
+	lastErr    error
+}
+
+func NewRequeueDelay(component string) *RequeueDelay {
+	return &RequeueDelay{component: component}
+}
+
+// Run always calls reconcile. On failure, it returns the delay after which the component should be requeued; it is zero
+// for invalid spec errors, which can't be fixed without a spec change.
+func (b *RequeueDelay) Run(generation int64, reconcile func() error) (time.Duration, error) {
+	if err := reconcile(); err != nil {
+		return b.retryAfter(b.failure(generation, err)), err
+	}
+	b.failures = 0
+	b.lastErr = nil
+	reconcileFailures.WithLabelValues(b.component).Set(0)
+	return 0, nil
+}
+
+func (b *RequeueDelay) failure(generation int64, err error) time.Duration {
+	if generation != b.generation {
+		b.failures = 0
+	}
+	b.failures++
+	b.generation = generation
+	b.lastErr = err
+	delay := backoffBase << min(b.failures-1, 10)
+	if delay > backoffMax {
+		delay = backoffMax
+	}
+	delay = time.Duration(float64(delay) * (1 - backoffJitter + 2*backoffJitter*rand.Float64()))
+	reconcileRetries.WithLabelValues(b.component).Inc()
+	reconcileFailures.WithLabelValues(b.component).Set(float64(b.failures))
+	return delay
+}
+
+func (b *RequeueDelay) retryAfter(delay time.Duration) time.Duration {
+	if status.KindOf(b.lastErr) == status.ErrorInvalidSpec {
+		return 0
+	}
+	return delay
+}
+
+// MinRequeue returns the shortest non-zero delay, or zero when none is set
+func MinRequeue(delays ...time.Duration) time.Duration {
+	var shortest time.Duration
+	for _, d := range delays {
+		if d > 0 && (shortest == 0 || d < shortest) {
+			shortest = d
+		}
+	}
+	return shortest
+}
@@ -0,0 +1,60 @@
+package reconcilers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+func TestRequeueDelay(t *testing.T) {
+	assert := assert.New(t)
+	b := NewRequeueDelay("test")
+	calls := 0
+	failing := func() error {
+		calls++
+		return errors.New("boom")
+	}
+
+	// first failure: base delay with jitter
+	retry, err := b.Run(1, failing)
+	assert.EqualError(err, "boom")
+	assert.InDelta(float64(backoffBase), float64(retry), float64(backoffBase)*backoffJitter)
+	assert.Equal(1, calls)
+
+	// a reconcile triggered before the requeue, e.g. by a watched Secret, runs right away; the delay keeps growing
+	retry, _ = b.Run(1, failing)
+	assert.InDelta(float64(2*backoffBase), float64(retry), float64(2*backoffBase)*backoffJitter)
+	assert.Equal(2, calls)
+
+	// delay is capped
+	for i := 0; i < 20; i++ {
+		retry, _ = b.Run(1, failing)
+	}
+	assert.LessOrEqual(retry, time.Duration(float64(backoffMax)*(1+backoffJitter)))
+
+	// a spec change resets the backoff
+	retry, _ = b.Run(2, failing)
+	assert.InDelta(float64(backoffBase), float64(retry), float64(backoffBase)*backoffJitter)
+	assert.Equal(23, calls)
+
+	// success resets the backoff
+	retry, err = b.Run(2, func() error { return nil })
+	assert.NoError(err)
+	assert.Zero(retry)
+	assert.Equal(0, b.failures)
+
+	// invalid spec errors are not requeued
+	retry, err = b.Run(2, func() error { return status.InvalidSpecError("bad") })
+	assert.Error(err)
+	assert.Zero(retry)
+}
+
+func TestMinRequeue(t *testing.T) {
+	assert.Zero(t, MinRequeue())
+	assert.Zero(t, MinRequeue(0, 0))
+	assert.Equal(t, time.Second, MinRequeue(0, 5*time.Second, time.Second))
+}
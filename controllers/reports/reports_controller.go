@@ -0,0 +1,199 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	reportslatest "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/controllers/reconcilers"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/manager"
+	"github.com/netobserv/network-observability-operator/pkg/metrics"
+	"github.com/netobserv/network-observability-operator/pkg/report"
+)
+
+type Reconciler struct {
+	client.Client
+	mgr *manager.Manager
+	// currentNamespace is where FlowReport resources are read from
+	currentNamespace string
+}
+
+func Start(ctx context.Context, mgr *manager.Manager) error {
+	log := log.FromContext(ctx)
+	log.Info("Starting Reports controller")
+	r := Reconciler{
+		Client: mgr.Client,
+		mgr:    mgr,
+	}
+	mapToFlowCollector := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []reconcile.Request {
+		if o.GetNamespace() == r.currentNamespace {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}
+		return []reconcile.Request{}
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&flowslatest.FlowCollector{}, reconcilers.IgnoreStatusChange).
+		Named("reports").
+		Watches(&reportslatest.FlowReport{}, mapToFlowCollector, reconcilers.IgnoreStatusChange).
+		Watches(&batchv1.CronJob{}, mapToFlowCollector).
+		Complete(&r)
+}
+
+// Reconcile creates the CronJobs generating the FlowReports, along with the service account they run with
+func (r *Reconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	l := log.Log.WithName("reports") // clear context (too noisy)
+	ctx = log.IntoContext(ctx, l)
+
+	clh, desired, err := helper.NewFlowCollectorClientHelper(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get FlowCollector: %w", err)
+	} else if desired == nil {
+		// Delete case
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.mgr.Config.CheckFlowCollector(&desired.Spec); err != nil {
+		// reported in the FlowCollector status by the main controller
+		l.Info("The operator can't manage this FlowCollector: reports not reconciled", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
+	ns := helper.GetNamespace(&desired.Spec)
+	r.currentNamespace = ns
+	list := reportslatest.FlowReportList{}
+	if err := r.List(ctx, &list, &client.ListOptions{Namespace: ns}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing FlowReports: %w", err)
+	}
+	if len(list.Items) == 0 {
+		// the CronJobs are garbage collected with their FlowReport, the shared objects are deleted with the last one
+		if err := deleteReporter(ctx, clh, ns); err != nil {
+			l.Error(err, "Reports cleanup failure")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.reconcileReporter(ctx, clh, ns); err != nil {
+		l.Error(err, "Reports reconcile failure")
+		return ctrl.Result{}, err
+	}
+
+	prefix := metrics.GetPrefix(&desired.Spec)
+	var errs []error
+	for i := range list.Items {
+		if err := r.reconcileReport(ctx, &list.Items[i], prefix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return ctrl.Result{}, fmt.Errorf("failed to reconcile %d FlowReport(s), first error: %w", len(errs), errs[0])
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileReporter reconciles the service account of the report jobs and its permissions, shared by all the reports
+func (r *Reconciler) reconcileReporter(ctx context.Context, clh *helper.Client, ns string) error {
+	sa := buildServiceAccount(ns)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(sa), &corev1.ServiceAccount{}); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		if err := clh.CreateOwned(ctx, sa); err != nil {
+			return err
+		}
+	}
+	if err := reconcilers.ReconcileRole(ctx, clh, buildRole(ns)); err != nil {
+		return err
+	}
+	if err := reconcilers.ReconcileRoleBinding(ctx, clh, buildRoleBinding(ns)); err != nil {
+		return err
+	}
+	if err := reconcilers.ReconcileClusterRole(ctx, clh, buildClusterRole()); err != nil {
+		return err
+	}
+	return reconcilers.ReconcileClusterRoleBinding(ctx, clh, buildClusterRoleBinding(ns))
+}
+
+// deleteReporter deletes the service account of the report jobs and its permissions. Cluster-scoped objects managed by an
+// administrator are left as is.
+func deleteReporter(ctx context.Context, clh *helper.Client, ns string) error {
+	objs := []client.Object{buildServiceAccount(ns), buildRole(ns), buildRoleBinding(ns)}
+	if _, manual := clh.ManualClusterRBAC(); !manual {
+		objs = append(objs, buildClusterRoleBinding(ns), buildClusterRole())
+	}
+	for _, obj := range objs {
+		if err := clh.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("could not delete %s %s: %w", reflect.TypeOf(obj).Elem().Name(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileReport(ctx context.Context, fr *reportslatest.FlowReport, prefix string) error {
+	err := r.reconcileCronJob(ctx, fr, prefix)
+	cond := metav1.Condition{
+		Type:               reportslatest.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Ready",
+		ObservedGeneration: fr.Generation,
+	}
+	if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "CronJobError"
+		cond.Message = err.Error()
+		if verr := report.Validate(&fr.Spec); verr != nil {
+			cond.Reason = "InvalidSpec"
+			// not retried: a fix requires a change, which triggers a new reconcile
+			err = nil
+		}
+	}
+	if meta.SetStatusCondition(&fr.Status.Conditions, cond) {
+		if uerr := r.Status().Update(ctx, fr); uerr != nil {
+			return uerr
+		}
+	}
+	return err
+}
+
+func (r *Reconciler) reconcileCronJob(ctx context.Context, fr *reportslatest.FlowReport, prefix string) error {
+	if err := report.Validate(&fr.Spec); err != nil {
+		return err
+	}
+	desired, err := buildCronJob(fr, r.mgr.Config.ReporterImage, prefix)
+	if err != nil {
+		return err
+	}
+	cl := helper.Client{
+		Client: r.Client,
+		SetControllerReference: func(obj client.Object) error {
+			return controllerutil.SetControllerReference(fr, obj, r.Scheme())
+		},
+	}
+	actual := batchv1.CronJob{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), &actual); err != nil {
+		if errors.IsNotFound(err) {
+			return cl.CreateOwned(ctx, desired)
+		}
+		return err
+	}
+	if !equality.Semantic.DeepDerivative(desired.Spec, actual.Spec) || !helper.IsSubSet(actual.Labels, desired.Labels) {
+		return cl.UpdateIfOwned(ctx, &actual, desired)
+	}
+	return nil
+}
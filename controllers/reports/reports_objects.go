@@ -0,0 +1,183 @@
+package reports
+
+import (
+	"encoding/json"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	reportslatest "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/report"
+)
+
+const (
+	reporterName    = constants.ReporterName
+	cronJobPrefix   = "netobserv-report-"
+	containerName   = "report"
+	smtpUsernameKey = "username"
+	smtpPasswordKey = "password"
+)
+
+func reporterLabels() map[string]string {
+	return map[string]string{"app": reporterName}
+}
+
+func buildServiceAccount(ns string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reporterName,
+			Namespace: ns,
+			Labels:    reporterLabels(),
+		},
+	}
+}
+
+// buildRole allows the report jobs to write their ConfigMap output
+func buildRole(ns string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reporterName,
+			Namespace: ns,
+			Labels:    reporterLabels(),
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Verbs:     []string{"get", "create", "update"},
+			Resources: []string{"configmaps"},
+		}},
+	}
+}
+
+func buildRoleBinding(ns string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      reporterName,
+			Namespace: ns,
+			Labels:    reporterLabels(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     reporterName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Name:      reporterName,
+			Namespace: ns,
+		}},
+	}
+}
+
+// buildClusterRole allows the report jobs to query the cluster-wide Thanos querier, which checks access to namespaces
+func buildClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   reporterName,
+			Labels: reporterLabels(),
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{""},
+			Verbs:     []string{"get"},
+			Resources: []string{"namespaces"},
+		}},
+	}
+}
+
+func buildClusterRoleBinding(ns string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   reporterName,
+			Labels: reporterLabels(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     reporterName,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      "ServiceAccount",
+			Name:      reporterName,
+			Namespace: ns,
+		}},
+	}
+}
+
+func secretEnv(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+func buildCronJob(fr *reportslatest.FlowReport, image, prefix string) (*batchv1.CronJob, error) {
+	cfg, err := json.Marshal(report.Config{
+		Name:      fr.Name,
+		Namespace: fr.Namespace,
+		Prefix:    prefix,
+		Spec:      fr.Spec,
+	})
+	if err != nil {
+		return nil, err
+	}
+	env := []corev1.EnvVar{{Name: report.EnvConfig, Value: string(cfg)}}
+	out := &fr.Spec.Output
+	switch {
+	case out.Type == reportslatest.OutputS3 && out.S3 != nil:
+		env = append(env,
+			secretEnv(report.EnvS3AccessKey, out.S3.CredentialsSecret, report.EnvS3AccessKey),
+			secretEnv(report.EnvS3SecretKey, out.S3.CredentialsSecret, report.EnvS3SecretKey),
+		)
+	case out.Type == reportslatest.OutputEmail && out.Email != nil && out.Email.CredentialsSecret != "":
+		env = append(env,
+			secretEnv(report.EnvSMTPUsername, out.Email.CredentialsSecret, smtpUsernameKey),
+			secretEnv(report.EnvSMTPPassword, out.Email.CredentialsSecret, smtpPasswordKey),
+		)
+	}
+	labels := reporterLabels()
+	labels[report.LabelReport] = fr.Name
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cronJobPrefix + fr.Name,
+			Namespace: fr.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   fr.Spec.Schedule,
+			Suspend:                    ptr.To(fr.Spec.Suspend),
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To(int32(3)),
+			FailedJobsHistoryLimit:     ptr.To(int32(1)),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To(int32(2)),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: labels},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: reporterName,
+							RestartPolicy:      corev1.RestartPolicyNever,
+							Containers: []corev1.Container{{
+								Name:            containerName,
+								Image:           image,
+								Command:         []string{"/manager"},
+								Args:            report.JobArgs(),
+								Env:             env,
+								SecurityContext: helper.ContainerDefaultSecurityContext(),
+							}},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,73 @@
+package reports
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	reportslatest "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
+	"github.com/netobserv/network-observability-operator/pkg/report"
+)
+
+func TestCronJob(t *testing.T) {
+	assert := assert.New(t)
+
+	fr := reportslatest.FlowReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "weekly", Namespace: "netobserv"},
+		Spec: reportslatest.FlowReportSpec{
+			Schedule:    "0 6 * * 1",
+			Aggregation: reportslatest.AggregationNamespaceEgress,
+			Output: reportslatest.FlowReportOutput{
+				Type: reportslatest.OutputS3,
+				S3:   &reportslatest.FlowReportS3{Endpoint: "https://s3.example.com", Bucket: "reports", CredentialsSecret: "s3-creds"},
+			},
+		},
+	}
+	cj, err := buildCronJob(&fr, "quay.io/netobserv/network-observability-operator:main", "netobserv_")
+	assert.NoError(err)
+	assert.Equal("netobserv-report-weekly", cj.Name)
+	assert.Equal("netobserv", cj.Namespace)
+	assert.Equal("0 6 * * 1", cj.Spec.Schedule)
+	assert.False(*cj.Spec.Suspend)
+
+	pod := cj.Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(reporterName, pod.ServiceAccountName)
+	assert.Len(pod.Containers, 1)
+	c := pod.Containers[0]
+	assert.Equal("quay.io/netobserv/network-observability-operator:main", c.Image)
+	assert.Equal([]string{"--report"}, c.Args)
+	assert.Len(c.Env, 3)
+
+	var cfg report.Config
+	assert.Equal(report.EnvConfig, c.Env[0].Name)
+	assert.NoError(json.Unmarshal([]byte(c.Env[0].Value), &cfg))
+	assert.Equal("weekly", cfg.Name)
+	assert.Equal("netobserv_", cfg.Prefix)
+	assert.Equal(reportslatest.AggregationNamespaceEgress, cfg.Spec.Aggregation)
+
+	assert.Equal(report.EnvS3AccessKey, c.Env[1].Name)
+	assert.Equal("s3-creds", c.Env[1].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(report.EnvS3AccessKey, c.Env[1].ValueFrom.SecretKeyRef.Key)
+
+	// Email without credentials
+	fr.Spec.Suspend = true
+	fr.Spec.Output = reportslatest.FlowReportOutput{
+		Type:  reportslatest.OutputEmail,
+		Email: &reportslatest.FlowReportEmail{SMTPAddress: "smtp.example.com:25", From: "netobserv@example.com", To: []string{"ops@example.com"}},
+	}
+	cj, err = buildCronJob(&fr, "quay.io/netobserv/network-observability-operator:main", "netobserv_")
+	assert.NoError(err)
+	assert.True(*cj.Spec.Suspend)
+	assert.Len(cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env, 1)
+
+	// Email with credentials
+	fr.Spec.Output.Email.CredentialsSecret = "smtp-creds"
+	cj, err = buildCronJob(&fr, "quay.io/netobserv/network-observability-operator:main", "netobserv_")
+	assert.NoError(err)
+	env := cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env
+	assert.Len(env, 3)
+	assert.Equal(report.EnvSMTPPassword, env[2].Name)
+	assert.Equal(smtpPasswordKey, env[2].ValueFrom.SecretKeyRef.Key)
+}
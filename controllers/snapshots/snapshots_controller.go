@@ -0,0 +1,173 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/manager"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+// settleTime is how long a generation must stay ready to be considered as known-good
+const settleTime = 2 * time.Minute
+
+type Reconciler struct {
+	client.Client
+	config   *manager.Config
+	status   status.Instance
+	recorder record.EventRecorder
+	// readyGeneration is the generation seen ready since readySince, 0 when the FlowCollector isn't ready
+	readyGeneration int64
+	readySince      time.Time
+}
+
+func Start(ctx context.Context, mgr *manager.Manager) error {
+	log := log.FromContext(ctx)
+	log.Info("Starting Snapshots controller")
+	r := Reconciler{
+		Client:   mgr.Client,
+		config:   mgr.Config,
+		status:   mgr.Status.ForComponent(status.Snapshots),
+		recorder: mgr.GetEventRecorderFor("netobserv-operator"),
+	}
+	// status changes are not ignored, as snapshots are taken when the FlowCollector is ready
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&flowslatest.FlowCollector{}).
+		Named("snapshots").
+		Complete(&r)
+}
+
+// Reconcile keeps a snapshot of the generations that are ready for some time, and reverts the specification to one
+// of them when `spec.rollbackTo` is set
+func (r *Reconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	l := log.Log.WithName("snapshots") // clear context (too noisy)
+	ctx = log.IntoContext(ctx, l)
+
+	clh, desired, err := helper.NewFlowCollectorClientHelper(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get FlowCollector: %w", err)
+	} else if desired == nil {
+		// Delete case
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.config.CheckFlowCollector(&desired.Spec); err != nil {
+		// reported in the FlowCollector status by the main controller
+		l.Info("The operator can't manage this FlowCollector: snapshots not reconciled", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	if desired.Spec.RollbackTo != nil {
+		// the status is only reported about rollbacks, to not add a condition to every FlowCollector
+		defer r.status.Commit(ctx, r.Client)
+		if err := r.rollback(ctx, desired); err != nil {
+			l.Error(err, "Rollback failure")
+			_ = r.status.Error("RollbackFailed", err)
+			if status.KindOf(err) == status.ErrorInvalidSpec {
+				// not retried: a fix requires a change, which triggers a new reconcile
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		r.status.SetReady()
+		return ctrl.Result{}, nil
+	}
+	if r.status.HasFailure() {
+		// the failed rollback request was removed
+		r.status.SetReady()
+		r.status.Commit(ctx, r.Client)
+	}
+
+	retry, err := r.snapshot(ctx, clh, desired)
+	if err != nil {
+		l.Error(err, "Snapshot failure")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: retry}, nil
+}
+
+// snapshot records the current generation once it has been ready for settleTime, and returns the delay after which
+// it should be checked again
+func (r *Reconciler) snapshot(ctx context.Context, clh *helper.Client, desired *flowslatest.FlowCollector) (time.Duration, error) {
+	// the Ready condition applies to the observed generation, which lags behind right after a change
+	if !meta.IsStatusConditionTrue(desired.Status.Conditions, "Ready") || desired.Status.ObservedGeneration != desired.Generation {
+		r.readyGeneration = 0
+		return 0, nil
+	}
+	if r.readyGeneration != desired.Generation {
+		r.readyGeneration = desired.Generation
+		r.readySince = time.Now()
+	}
+	if wait := settleTime - time.Since(r.readySince); wait > 0 {
+		return wait, nil
+	}
+
+	ns := helper.GetNamespace(&desired.Spec)
+	cm := corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Name: configMapName, Namespace: ns}, &cm); err != nil {
+		if !errors.IsNotFound(err) {
+			return 0, err
+		}
+		created := buildConfigMap(ns)
+		if _, err := addSnapshot(created, desired.Generation, &desired.Spec); err != nil {
+			return 0, err
+		}
+		return 0, clh.CreateOwned(ctx, created)
+	}
+	added, err := addSnapshot(&cm, desired.Generation, &desired.Spec)
+	if err != nil || !added {
+		return 0, err
+	}
+	log.FromContext(ctx).Info("Recording FlowCollector snapshot", "generation", desired.Generation)
+	return 0, r.Update(ctx, &cm)
+}
+
+// rollback replaces the specification with the snapshot of the requested generation, after validating it
+func (r *Reconciler) rollback(ctx context.Context, desired *flowslatest.FlowCollector) error {
+	target := *desired.Spec.RollbackTo
+	cm := corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Name: configMapName, Namespace: helper.GetNamespace(&desired.Spec)}, &cm); err != nil {
+		if errors.IsNotFound(err) {
+			return status.InvalidSpecError("no snapshot available: config map %s not found", configMapName)
+		}
+		return err
+	}
+	spec, err := getSnapshot(&cm, target)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Rolling back FlowCollector", "generation", desired.Generation, "target", target)
+	fc := flowslatest.FlowCollector{}
+	changed := false
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := r.Get(ctx, constants.FlowCollectorName, &fc); err != nil {
+			return err
+		}
+		// the request changed meanwhile, which triggers a new reconcile
+		changed = fc.Spec.RollbackTo == nil || *fc.Spec.RollbackTo != target
+		if changed {
+			return nil
+		}
+		fc.Spec = *spec
+		// the API server validates the snapshot against the current schema
+		return r.Update(ctx, &fc)
+	})
+	if err != nil || changed {
+		return err
+	}
+	r.recorder.Eventf(&fc, corev1.EventTypeNormal, "RolledBack", "Specification reverted to the snapshot of generation %d", target)
+	return nil
+}
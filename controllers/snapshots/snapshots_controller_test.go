@@ -0,0 +1,46 @@
+package snapshots
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+func TestSnapshotWaitsForObservedGeneration(t *testing.T) {
+	assert := assert.New(t)
+
+	// no client: the snapshot must not be recorded in these cases
+	r := Reconciler{}
+	fc := flowslatest.FlowCollector{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Status: flowslatest.FlowCollectorStatus{
+			Conditions:         []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}},
+			ObservedGeneration: 1,
+		},
+	}
+
+	// the Ready condition is about the previous generation
+	wait, err := r.snapshot(context.Background(), nil, &fc)
+	assert.NoError(err)
+	assert.Zero(wait)
+	assert.Zero(r.readyGeneration)
+
+	// the current generation is ready: it must stay ready for the settle time
+	fc.Status.ObservedGeneration = 2
+	wait, err = r.snapshot(context.Background(), nil, &fc)
+	assert.NoError(err)
+	assert.InDelta(settleTime, wait, float64(time.Second))
+	assert.Equal(int64(2), r.readyGeneration)
+
+	// a new generation resets the settle time
+	fc.Generation = 3
+	wait, err = r.snapshot(context.Background(), nil, &fc)
+	assert.NoError(err)
+	assert.Zero(wait)
+	assert.Zero(r.readyGeneration)
+}
@@ -0,0 +1,92 @@
+package snapshots
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+const (
+	configMapName = "flowcollector-snapshots"
+	keyPrefix     = "generation-"
+	// maxSnapshots bounds the history, to keep the config map far below its size limit
+	maxSnapshots = 5
+)
+
+func buildConfigMap(ns string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: ns,
+		},
+		Data: map[string]string{},
+	}
+}
+
+func snapshotKey(generation int64) string {
+	return keyPrefix + strconv.FormatInt(generation, 10)
+}
+
+// generations returns the generations having a snapshot, from the oldest to the newest
+func generations(cm *corev1.ConfigMap) []int64 {
+	var gens []int64
+	for k := range cm.Data {
+		if g, ok := strings.CutPrefix(k, keyPrefix); ok {
+			if gen, err := strconv.ParseInt(g, 10, 64); err == nil {
+				gens = append(gens, gen)
+			}
+		}
+	}
+	slices.Sort(gens)
+	return gens
+}
+
+// addSnapshot stores the specification of a generation, and removes the oldest snapshots beyond maxSnapshots.
+// It returns false when the generation already has a snapshot.
+func addSnapshot(cm *corev1.ConfigMap, generation int64, spec *flowslatest.FlowCollectorSpec) (bool, error) {
+	key := snapshotKey(generation)
+	if _, ok := cm.Data[key]; ok {
+		return false, nil
+	}
+	snapshot := spec.DeepCopy()
+	// a snapshot is never a rollback request by itself
+	snapshot.RollbackTo = nil
+	b, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return false, err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = string(b)
+	if gens := generations(cm); len(gens) > maxSnapshots {
+		for _, g := range gens[:len(gens)-maxSnapshots] {
+			delete(cm.Data, snapshotKey(g))
+		}
+	}
+	return true, nil
+}
+
+// getSnapshot returns the specification stored for a generation. Snapshots with unknown fields, such as fields
+// removed by an operator upgrade, are rejected rather than partially applied.
+func getSnapshot(cm *corev1.ConfigMap, generation int64) (*flowslatest.FlowCollectorSpec, error) {
+	data, ok := cm.Data[snapshotKey(generation)]
+	if !ok {
+		return nil, status.InvalidSpecError("no snapshot for generation %d in config map %s, available generations are %v", generation, configMapName, generations(cm))
+	}
+	spec := flowslatest.FlowCollectorSpec{}
+	if err := yaml.UnmarshalStrict([]byte(data), &spec); err != nil {
+		return nil, status.InvalidSpecError("invalid snapshot for generation %d: %w", generation, err)
+	}
+	if spec.RollbackTo != nil {
+		return nil, status.InvalidSpecError("invalid snapshot for generation %d: it requests a rollback", generation)
+	}
+	return &spec, nil
+}
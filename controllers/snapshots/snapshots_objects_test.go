@@ -0,0 +1,55 @@
+package snapshots
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+func TestSnapshots(t *testing.T) {
+	assert := assert.New(t)
+
+	cm := buildConfigMap("netobserv")
+	spec := flowslatest.FlowCollectorSpec{Namespace: "netobserv", DeploymentModel: flowslatest.DeploymentModelDirect}
+	for gen := int64(1); gen <= 7; gen++ {
+		spec.Agent.EBPF.Sampling = ptr.To(int32(gen))
+		added, err := addSnapshot(cm, gen, &spec)
+		assert.NoError(err)
+		assert.True(added)
+	}
+	// already recorded
+	added, err := addSnapshot(cm, 7, &spec)
+	assert.NoError(err)
+	assert.False(added)
+
+	// oldest snapshots are removed
+	assert.Equal([]int64{3, 4, 5, 6, 7}, generations(cm))
+
+	snapshot, err := getSnapshot(cm, 4)
+	assert.NoError(err)
+	assert.Equal(int32(4), *snapshot.Agent.EBPF.Sampling)
+	assert.Equal(flowslatest.DeploymentModelDirect, snapshot.DeploymentModel)
+
+	// rollback requests are not recorded
+	spec.RollbackTo = ptr.To(int64(4))
+	_, err = addSnapshot(cm, 8, &spec)
+	assert.NoError(err)
+	snapshot, err = getSnapshot(cm, 8)
+	assert.NoError(err)
+	assert.Nil(snapshot.RollbackTo)
+
+	// unknown generation
+	_, err = getSnapshot(cm, 1)
+	assert.ErrorContains(err, "available generations are [4 5 6 7 8]")
+	assert.Equal(status.ErrorInvalidSpec, status.KindOf(err))
+
+	// unknown fields are rejected
+	cm.Data[snapshotKey(5)] += "removedField: true\n"
+	_, err = getSnapshot(cm, 5)
+	assert.ErrorContains(err, "invalid snapshot for generation 5")
+	assert.Equal(status.ErrorInvalidSpec, status.KindOf(err))
+}
@@ -24,11 +24,14 @@ import (
 	"fmt"
 	_ "net/http/pprof"
 	"os"
+	"strings"
+	"time"
 
 	"go.uber.org/zap/zapcore"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
+	configv1 "github.com/openshift/api/config/v1"
 	osv1alpha1 "github.com/openshift/api/console/v1alpha1"
 	operatorsv1 "github.com/openshift/api/operator/v1"
 	securityv1 "github.com/openshift/api/security/v1"
@@ -42,21 +45,29 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	endpointsv1alpha1 "github.com/netobserv/network-observability-operator/apis/externalendpoints/v1alpha1"
 	// nolint:staticcheck
 	flowsv1beta1 "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta1"
 	flowsv1beta2 "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 	metricsv1alpha1 "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricsv1beta1 "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	reportsv1alpha1 "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
 	"github.com/netobserv/network-observability-operator/controllers"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/featuregates"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/loki"
 	"github.com/netobserv/network-observability-operator/pkg/manager"
+	"github.com/netobserv/network-observability-operator/pkg/report"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -77,9 +88,13 @@ func init() {
 	utilruntime.Must(flowsv1beta1.AddToScheme(scheme))
 	utilruntime.Must(flowsv1beta2.AddToScheme(scheme))
 	utilruntime.Must(metricsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(metricsv1beta1.AddToScheme(scheme))
+	utilruntime.Must(reportsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(endpointsv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme))
 	utilruntime.Must(ascv2.AddToScheme(scheme))
 	utilruntime.Must(osv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
 	utilruntime.Must(apiregv1.AddToScheme(scheme))
 	utilruntime.Must(securityv1.AddToScheme(scheme))
 	utilruntime.Must(operatorsv1.AddToScheme(scheme))
@@ -95,6 +110,7 @@ func main() {
 	var pprofAddr string
 	var enableHTTP2 bool
 	var versionFlag bool
+	var featureGates string
 
 	config := manager.Config{}
 
@@ -107,9 +123,25 @@ func main() {
 	flag.StringVar(&config.EBPFAgentImage, "ebpf-agent-image", "quay.io/netobserv/netobserv-ebpf-agent:main", "The image of the eBPF agent")
 	flag.StringVar(&config.FlowlogsPipelineImage, "flowlogs-pipeline-image", "quay.io/netobserv/flowlogs-pipeline:main", "The image of Flowlogs Pipeline")
 	flag.StringVar(&config.ConsolePluginImage, "console-plugin-image", "quay.io/netobserv/network-observability-console-plugin:main", "The image of the Console Plugin")
+	flag.StringVar(&config.KubeRBACProxyImage, "kube-rbac-proxy-image", "gcr.io/kubebuilder/kube-rbac-proxy:v0.15.0", "The image of kube-rbac-proxy, used in front of metrics endpoints")
+	flag.StringVar(&config.ReporterImage, "reporter-image", "", "The image of the FlowReport jobs, which must run this operator version. Defaults to the image of the operator pod.")
+	flag.DurationVar(&config.APIDiscoveryInterval, "api-discovery-interval", time.Minute, "The period at which the available APIs are discovered again, "+
+		"to pick up APIs installed after the operator such as the Prometheus Operator. Set to 0 to disable.")
 	flag.BoolVar(&config.DownstreamDeployment, "downstream-deployment", false, "Either this deployment is a downstream deployment ot not")
+	flag.Func("watch-namespaces", "Comma-separated list of namespaces where the operator manages objects, for clusters where cluster-wide operators are forbidden. "+
+		"They must include the FlowCollector namespace and its privileged namespace. Leave unset to watch all namespaces.", func(s string) error {
+		for _, ns := range strings.Split(s, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				config.WatchNamespaces = append(config.WatchNamespaces, ns)
+			}
+		}
+		return nil
+	})
+	flag.StringVar(&featureGates, "feature-gates", os.Getenv("FEATURE_GATES"), "Comma-separated list of Feature=true|false to enable or disable experimental features, such as 'BatchAutoTuning=true'. "+
+		"Defaults to the FEATURE_GATES environment variable.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", enableHTTP2, "If HTTP/2 should be enabled for the metrics and webhook servers.")
 	flag.BoolVar(&versionFlag, "v", false, "print version")
+	reportFlag := bindReportFlags(flag.CommandLine)
 	opts := zap.Options{
 		Development: true,
 		TimeEncoder: zapcore.ISO8601TimeEncoder,
@@ -124,8 +156,25 @@ func main() {
 		fmt.Println(appVersion)
 		os.Exit(0)
 	}
+	if *reportFlag {
+		runReport()
+	}
 	setupLog.Info("Starting " + appVersion)
 
+	gates, err := featuregates.Parse(featureGates)
+	if err != nil {
+		setupLog.Error(err, "invalid feature gates")
+		os.Exit(1)
+	}
+	config.FeatureGates = gates
+	setupLog.Info("Feature gates: " + gates.String())
+
+	cfg := ctrl.GetConfigOrDie()
+
+	if err := defaultReporterImage(cfg, &config); err != nil {
+		setupLog.Error(err, "unable to start the manager")
+		os.Exit(1)
+	}
 	if err := config.Validate(); err != nil {
 		setupLog.Error(err, "unable to start the manager")
 		os.Exit(1)
@@ -135,6 +184,7 @@ func main() {
 		setupLog.Error(err, "unable to parse CRD")
 		os.Exit(1)
 	}
+	helper.SetOperatorVersion(buildVersion)
 
 	disableHTTP2 := func(c *tls.Config) {
 		if enableHTTP2 {
@@ -143,8 +193,6 @@ func main() {
 		c.NextProtos = []string{"http/1.1"}
 	}
 
-	cfg := ctrl.GetConfigOrDie()
-
 	mgr, err := manager.NewManager(context.Background(), cfg, &config, &ctrl.Options{
 		Scheme: scheme,
 		Metrics: server.Options{
@@ -165,10 +213,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&flowsv1beta2.FlowCollector{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&flowsv1beta2.FlowCollector{}).SetupWebhookWithManager(mgr, config.FeatureGates.Warnings); err != nil {
 		setupLog.Error(err, "unable to create v1beta2 webhook", "webhook", "FlowCollector")
 		os.Exit(1)
 	}
+	if err = (&metricsv1beta1.FlowMetric{}).SetupWebhookWithManager(mgr, loki.RecentFlowsReader(mgr.GetConfig())); err != nil {
+		setupLog.Error(err, "unable to create v1beta1 webhook", "webhook", "FlowMetric")
+		os.Exit(1)
+	}
+	if err = (&endpointsv1alpha1.ExternalEndpoint{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create v1alpha1 webhook", "webhook", "ExternalEndpoint")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -186,3 +242,32 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// bindReportFlags defines the flags of the report mode, which are passed by the FlowReport jobs (see report.JobArgs)
+func bindReportFlags(fs *flag.FlagSet) *bool {
+	return fs.Bool(report.Flag, false, "generate and send the FlowReport configured in the environment, then exit")
+}
+
+// defaultReporterImage reads the operator pod, without cache as the manager isn't started yet
+func defaultReporterImage(cfg *rest.Config, config *manager.Config) error {
+	cl, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("unable to create client: %w", err)
+	}
+	return config.DefaultReporterImage(context.Background(), cl)
+}
+
+// runReport runs the operator binary as a FlowReport job: it generates the report, sends it, and exits
+func runReport() {
+	cfg := ctrl.GetConfigOrDie()
+	cl, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+	if err := report.Run(context.Background(), cfg, cl); err != nil {
+		setupLog.Error(err, "report failed")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netobserv/network-observability-operator/pkg/report"
+)
+
+func TestReportJobArgs(t *testing.T) {
+	// the FlowReport jobs run this binary: their arguments must be flags that it defines
+	fs := flag.NewFlagSet("manager", flag.ContinueOnError)
+	reportFlag := bindReportFlags(fs)
+	assert.NoError(t, fs.Parse(report.JobArgs()))
+	assert.True(t, *reportFlag)
+	assert.Empty(t, fs.Args())
+}
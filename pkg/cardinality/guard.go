@@ -0,0 +1,101 @@
+// Package cardinality checks the number of Prometheus series generated by NetObserv metrics against the budget
+// configured in FlowCollector, and disables the FlowMetric resources responsible for exceeding it.
+package cardinality
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+)
+
+const ReasonBudgetExceeded = "CardinalityBudgetExceeded"
+
+var histogramSuffixes = []string{"_bucket", "_sum", "_count"}
+
+// Usage is the number of series of the NetObserv metrics, per metric name
+type Usage struct {
+	Prefix    string
+	PerMetric map[string]int
+}
+
+func (u *Usage) Total() int {
+	total := 0
+	for _, n := range u.PerMetric {
+		total += n
+	}
+	return total
+}
+
+// FlowMetricSeries returns the number of series generated by a FlowMetric
+func (u *Usage) FlowMetricSeries(fm *metricslatest.FlowMetric) int {
+	name := u.Prefix + fm.Spec.MetricName
+	if fm.Spec.Type == metricslatest.HistogramMetric {
+		n := 0
+		for _, suffix := range histogramSuffixes {
+			n += u.PerMetric[name+suffix]
+		}
+		return n
+	}
+	return u.PerMetric[name]
+}
+
+// WorstFlowMetric returns the enabled FlowMetric generating the most series, and its number of series, or nil if none generates any
+func (u *Usage) WorstFlowMetric(items []metricslatest.FlowMetric) (*metricslatest.FlowMetric, int) {
+	var worst *metricslatest.FlowMetric
+	worstSeries := 0
+	for i := range items {
+		if IsDisabled(&items[i]) {
+			continue
+		}
+		if n := u.FlowMetricSeries(&items[i]); n > worstSeries {
+			worst = &items[i]
+			worstSeries = n
+		}
+	}
+	return worst, worstSeries
+}
+
+// IsDisabled returns true if the FlowMetric has been disabled by the operator, and not modified since then
+func IsDisabled(fm *metricslatest.FlowMetric) bool {
+	cond := meta.FindStatusCondition(fm.Status.Conditions, metricslatest.ConditionDisabled)
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == fm.Generation
+}
+
+// Disable sets the `Disabled` condition on the FlowMetric, for its current generation
+func Disable(fm *metricslatest.FlowMetric, series int, usage *Usage, budget int32) {
+	// removed first, so that the transition time records this disable even if a previous one is still set
+	meta.RemoveStatusCondition(&fm.Status.Conditions, metricslatest.ConditionDisabled)
+	meta.SetStatusCondition(&fm.Status.Conditions, metav1.Condition{
+		Type:               metricslatest.ConditionDisabled,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonBudgetExceeded,
+		Message:            fmt.Sprintf("Disabled as the metric with the most series (%d), while NetObserv metrics have %d series, exceeding the budget of %d. Modify this FlowMetric to enable it again.", series, usage.Total(), budget),
+		ObservedGeneration: fm.Generation,
+	})
+}
+
+// LastDisable returns the last time a FlowMetric was disabled by the operator, as recorded in its status, or nil if none was
+func LastDisable(items []metricslatest.FlowMetric) *metav1.Time {
+	var last *metav1.Time
+	for i := range items {
+		cond := meta.FindStatusCondition(items[i].Status.Conditions, metricslatest.ConditionDisabled)
+		if cond != nil && cond.Status == metav1.ConditionTrue && (last == nil || last.Before(&cond.LastTransitionTime)) {
+			last = &cond.LastTransitionTime
+		}
+	}
+	return last
+}
+
+// FilterEnabled returns the FlowMetrics that are not disabled
+func FilterEnabled(items []metricslatest.FlowMetric) []metricslatest.FlowMetric {
+	var enabled []metricslatest.FlowMetric
+	for i := range items {
+		if !IsDisabled(&items[i]) {
+			enabled = append(enabled, items[i])
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,122 @@
+package cardinality
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
+)
+
+func flowMetric(name, metricName string, t metricslatest.MetricType) metricslatest.FlowMetric {
+	return metricslatest.FlowMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "netobserv", Generation: 1},
+		Spec:       metricslatest.FlowMetricSpec{MetricName: metricName, Type: t},
+	}
+}
+
+func TestWorstFlowMetric(t *testing.T) {
+	assert := assert.New(t)
+
+	usage := Usage{
+		Prefix: "netobserv_",
+		PerMetric: map[string]int{
+			"netobserv_namespace_flows_total": 100,
+			"netobserv_custom_bytes_total":    300,
+			"netobserv_custom_rtt_bucket":     400,
+			"netobserv_custom_rtt_sum":        40,
+			"netobserv_custom_rtt_count":      40,
+		},
+	}
+	assert.Equal(880, usage.Total())
+
+	items := []metricslatest.FlowMetric{
+		flowMetric("bytes", "custom_bytes_total", metricslatest.CounterMetric),
+		flowMetric("rtt", "custom_rtt", metricslatest.HistogramMetric),
+		flowMetric("unused", "custom_unused_total", metricslatest.CounterMetric),
+	}
+	worst, series := usage.WorstFlowMetric(items)
+	assert.NotNil(worst)
+	assert.Equal("rtt", worst.Name)
+	assert.Equal(480, series)
+
+	// Disabled metrics are skipped, and filtered out of the pipeline
+	Disable(worst, series, &usage, 500)
+	assert.True(IsDisabled(worst))
+	assert.Contains(worst.Status.Conditions[0].Message, "NetObserv metrics have 880 series, exceeding the budget of 500")
+	worst, series = usage.WorstFlowMetric(items)
+	assert.NotNil(worst)
+	assert.Equal("bytes", worst.Name)
+	assert.Equal(300, series)
+	enabled := FilterEnabled(items)
+	assert.Len(enabled, 2)
+	assert.Equal("bytes", enabled[0].Name)
+
+	// Modifying the FlowMetric enables it again
+	items[1].Generation = 2
+	assert.False(IsDisabled(&items[1]))
+	assert.Len(FilterEnabled(items), 3)
+}
+
+func TestLastDisable(t *testing.T) {
+	assert := assert.New(t)
+	usage := Usage{Prefix: "netobserv_", PerMetric: map[string]int{"netobserv_custom_bytes_total": 300}}
+	items := []metricslatest.FlowMetric{
+		flowMetric("bytes", "custom_bytes_total", metricslatest.CounterMetric),
+		flowMetric("rtt", "custom_rtt", metricslatest.HistogramMetric),
+	}
+	assert.Nil(LastDisable(items))
+
+	old := metav1.NewTime(time.Now().Add(-time.Hour))
+	items[1].Status.Conditions = []metav1.Condition{{Type: metricslatest.ConditionDisabled, Status: metav1.ConditionTrue, LastTransitionTime: old}}
+	assert.Equal(&old, LastDisable(items))
+
+	// disabling again records the new time, even when the condition was already set for a previous generation
+	items[1].Generation = 2
+	Disable(&items[1], 0, &usage, 100)
+	last := LastDisable(items)
+	assert.NotNil(last)
+	assert.True(last.After(old.Time))
+}
+
+func TestSeriesCount(t *testing.T) {
+	assert := assert.New(t)
+
+	var query, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"__name__":"netobserv_namespace_flows_total"},"value":[1700000000,"120"]},
+			{"metric":{"__name__":"netobserv_custom_bytes_total"},"value":[1700000000,"3000"]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	q := prometheus.NewQuerier(server.URL+"/", false, &rest.Config{BearerToken: "token"})
+	defer q.Close()
+	counts, err := SeriesCount(context.Background(), q, "netobserv_")
+	assert.NoError(err)
+	assert.Equal(`count by (__name__) ({__name__=~"netobserv_.+"})`, query)
+	assert.Equal("Bearer token", auth)
+	assert.Equal(map[string]int{"netobserv_namespace_flows_total": 120, "netobserv_custom_bytes_total": 3000}, counts)
+}
+
+func TestSeriesCountError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	q := prometheus.NewQuerier(server.URL, false, nil)
+	defer q.Close()
+	_, err := SeriesCount(context.Background(), q, "netobserv_")
+	assert.ErrorContains(t, err, "query to Prometheus failed with status 403 Forbidden: forbidden")
+}
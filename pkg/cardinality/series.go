@@ -0,0 +1,21 @@
+package cardinality
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
+)
+
+// SeriesCount returns the number of series per metric name, for all the metrics starting with prefix
+func SeriesCount(ctx context.Context, q *prometheus.Querier, prefix string) (map[string]int, error) {
+	samples, err := q.Query(ctx, fmt.Sprintf(`count by (__name__) ({__name__=~"%s.+"})`, prefix))
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, s := range samples {
+		counts[s.Labels["__name__"]] = int(s.Value)
+	}
+	return counts, nil
+}
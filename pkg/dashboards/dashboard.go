@@ -18,6 +18,7 @@ const (
 	metricTagEgress  = "egress"
 	metricTagBytes   = "bytes"
 	metricTagPackets = "packets"
+	defaultPrefix    = "netobserv_"
 )
 
 var allRows []*Row
@@ -229,7 +230,7 @@ func valueTypeToText(t string) string {
 	return ""
 }
 
-func CreateFlowMetricsDashboard(netobsNs string, metrics []string) (string, error) {
+func CreateFlowMetricsDashboard(netobsNs, prefix string, metrics []string) (string, error) {
 	var rows []*Row
 	for _, ri := range allRows {
 		if slices.Contains(metrics, ri.Metric) {
@@ -244,6 +245,11 @@ func CreateFlowMetricsDashboard(netobsNs string, metrics []string) (string, erro
 			}
 		}
 	}
+	if prefix != defaultPrefix {
+		for i := range rows {
+			rows[i] = rows[i].replaceInTargets(defaultPrefix, prefix)
+		}
+	}
 	d := Dashboard{Rows: rows, Title: "NetObserv"}
 	return d.ToGrafanaJSON(netobsNs), nil
 }
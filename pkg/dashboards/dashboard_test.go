@@ -3,6 +3,7 @@ package dashboards
 import (
 	"testing"
 
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
 	"github.com/netobserv/network-observability-operator/pkg/metrics"
 	"github.com/stretchr/testify/assert"
 )
@@ -10,7 +11,7 @@ import (
 func TestCreateFlowMetricsDashboard_All(t *testing.T) {
 	assert := assert.New(t)
 
-	js, err := CreateFlowMetricsDashboard("netobserv", metrics.GetAllNames())
+	js, err := CreateFlowMetricsDashboard("netobserv", "netobserv_", metrics.GetAllNames())
 	assert.NoError(err)
 
 	d, err := FromBytes([]byte(js))
@@ -76,7 +77,7 @@ func TestCreateFlowMetricsDashboard_All(t *testing.T) {
 func TestCreateFlowMetricsDashboard_OnlyNodeIngressBytes(t *testing.T) {
 	assert := assert.New(t)
 
-	js, err := CreateFlowMetricsDashboard("netobserv", []string{"node_ingress_bytes_total"})
+	js, err := CreateFlowMetricsDashboard("netobserv", "netobserv_", []string{"node_ingress_bytes_total"})
 	assert.NoError(err)
 
 	d, err := FromBytes([]byte(js))
@@ -96,7 +97,7 @@ func TestCreateFlowMetricsDashboard_OnlyNodeIngressBytes(t *testing.T) {
 func TestCreateFlowMetricsDashboard_DefaultList(t *testing.T) {
 	assert := assert.New(t)
 
-	js, err := CreateFlowMetricsDashboard("netobserv", metrics.DefaultIncludeList)
+	js, err := CreateFlowMetricsDashboard("netobserv", "netobserv_", metrics.DefaultIncludeList)
 	assert.NoError(err)
 
 	d, err := FromBytes([]byte(js))
@@ -140,10 +141,25 @@ func TestCreateFlowMetricsDashboard_DefaultList(t *testing.T) {
 	)
 }
 
+func TestCreateFlowMetricsDashboard_CustomPrefix(t *testing.T) {
+	assert := assert.New(t)
+
+	js, err := CreateFlowMetricsDashboard("netobserv", "prod_", []string{"node_ingress_bytes_total"})
+	assert.NoError(err)
+
+	d, err := FromBytes([]byte(js))
+	assert.NoError(err)
+
+	row := d.FindRow("Byte rate received per node")
+	assert.NotNil(row)
+	assert.Contains(row.Panels[0].Targets[0].Expr, "rate(prod_node_ingress_bytes_total[2m])")
+	assert.NotContains(row.Panels[0].Targets[0].Expr, "netobserv_")
+}
+
 func TestCreateHealthDashboard_Default(t *testing.T) {
 	assert := assert.New(t)
 
-	js, err := CreateHealthDashboard("netobserv")
+	js, err := CreateHealthDashboard("netobserv", "netobserv_")
 	assert.NoError(err)
 
 	d, err := FromBytes([]byte(js))
@@ -159,3 +175,182 @@ func TestCreateHealthDashboard_Default(t *testing.T) {
 	assert.Len(d.Rows[row].Panels[0].Targets, 1)
 	assert.Contains(d.Rows[row].Panels[0].Targets[0].Expr, "netobserv_ingest_flows_processed")
 }
+
+func TestCreatePipelineHealthDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	js, err := CreatePipelineHealthDashboard("netobserv")
+	assert.NoError(err)
+
+	d, err := FromBytes([]byte(js))
+	assert.NoError(err)
+
+	assert.Equal("NetObserv / Pipeline health", d.Title)
+	assert.Equal([]string{"", "Stages", "Outputs"}, d.Titles())
+
+	row := d.FindRow("Stages")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 6)
+	assert.Equal("Stage duration P99 (ms)", row.Panels[1].Title)
+	assert.Contains(row.Panels[1].Targets[0].Expr, "netobserv_stage_duration_ms_bucket")
+}
+
+func TestCreatePluginDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	js, err := CreatePluginDashboard("netobserv")
+	assert.NoError(err)
+
+	d, err := FromBytes([]byte(js))
+	assert.NoError(err)
+
+	assert.Equal("NetObserv / Console plugin", d.Title)
+	assert.Equal([]string{"", "Endpoints", "Upstream"}, d.Titles())
+
+	row := d.FindRow("Upstream")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 5)
+	assert.Equal("Average time split", row.Panels[0].Title)
+	assert.Len(row.Panels[0].Targets, 3)
+	assert.Contains(row.Panels[2].Targets[0].Expr, "netobserv_plugin_loki_requests_total")
+}
+
+func TestCreateLoadTestDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	js, err := CreateLoadTestDashboard("netobserv", 5000)
+	assert.NoError(err)
+
+	d, err := FromBytes([]byte(js))
+	assert.NoError(err)
+
+	assert.Equal("NetObserv / Load test", d.Title)
+	assert.Equal([]string{"", "Pipeline", "Resources"}, d.Titles())
+
+	row := d.FindRow("")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 4)
+	assert.Equal("vector(5000)", row.Panels[0].Targets[0].Expr)
+	assert.Equal(`sum(rate(netobserv_ingest_flows_processed{job="flowlogs-pipeline-loadgen-prom"}[1m]))`, row.Panels[1].Targets[0].Expr)
+	assert.Contains(row.Panels[2].Targets[0].Expr, `netobserv_loki_dropped_entries_total{job="flowlogs-pipeline-loadgen-prom"}`)
+}
+
+func TestCreateServiceMeshDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	js, err := CreateServiceMeshDashboard("netobserv", "netobserv_", []string{"mesh_service_ingress_bytes_total", "mesh_sidecar_ingress_bytes_total"})
+	assert.NoError(err)
+
+	d, err := FromBytes([]byte(js))
+	assert.NoError(err)
+
+	assert.Equal("NetObserv / Service mesh", d.Title)
+	assert.Equal([]string{"", "mTLS hops", "Traffic split", "Services"}, d.Titles())
+
+	row := d.FindRow("")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 3)
+	assert.Contains(row.Panels[0].Targets[0].Expr, `netobserv_mesh_sidecar_ingress_bytes_total{SrcK8S_MeshTLSMode="istio",DstK8S_MeshTLSMode="istio"}`)
+
+	row = d.FindRow("Traffic split")
+	assert.NotNil(row)
+	assert.Contains(row.Panels[1].Targets[0].Expr, `/ on(DstK8S_Namespace, DstK8S_MeshService) group_left()`)
+
+	// Only the service metric
+	js, err = CreateServiceMeshDashboard("netobserv", "netobserv_", []string{"mesh_service_ingress_bytes_total"})
+	assert.NoError(err)
+	d, err = FromBytes([]byte(js))
+	assert.NoError(err)
+	assert.Equal([]string{"Traffic split", "Services"}, d.Titles())
+}
+
+func TestCreateEgressCostDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	js, err := CreateEgressCostDashboard("netobserv", "netobserv_", []string{"namespace_egress_class_bytes_total", "workload_egress_class_bytes_total"}, "0.05", 50)
+	assert.NoError(err)
+
+	d, err := FromBytes([]byte(js))
+	assert.NoError(err)
+
+	assert.Equal("NetObserv / Egress cost", d.Title)
+	assert.Equal([]string{"", "Destination classes", "Cost"}, d.Titles())
+
+	row := d.FindRow("")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 3)
+	assert.Equal(`sum(increase(netobserv_namespace_egress_class_bytes_total{DstEgressClass="Internet"}[24h])) * 50 * 0.05 / 1e9`, row.Panels[1].Targets[0].Expr)
+
+	row = d.FindRow("Cost")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 2)
+	assert.Contains(row.Panels[1].Targets[0].Expr, `by (SrcK8S_Namespace, SrcK8S_OwnerName) * 50 * 0.05 / 1e9`)
+
+	// Without egress class metric
+	js, err = CreateEgressCostDashboard("netobserv", "netobserv_", []string{"namespace_flows_total"}, "0.05", 50)
+	assert.NoError(err)
+	assert.Empty(js)
+}
+
+func TestCreateFlowMetricsChartsDashboards(t *testing.T) {
+	assert := assert.New(t)
+
+	items := []metricslatest.FlowMetric{
+		{Spec: metricslatest.FlowMetricSpec{
+			MetricName: "pod_outgoing_bytes_total",
+			Charts: []metricslatest.Chart{
+				{
+					DashboardName: "Main",
+					SectionName:   "Pods",
+					Title:         "Top outgoing traffic",
+					Unit:          metricslatest.UnitBPS,
+					Type:          metricslatest.ChartTypeStackArea,
+					Queries:       []metricslatest.Query{{PromQL: `sum(rate($METRIC{SrcK8S_Namespace!=""}[2m])) by (SrcK8S_Name)`, Legend: "{{SrcK8S_Name}}", Top: 5}},
+				},
+				{
+					DashboardName: "Main",
+					Title:         "Total outgoing traffic",
+					Unit:          metricslatest.UnitBPS,
+					Type:          metricslatest.ChartTypeSingleStat,
+					Queries:       []metricslatest.Query{{PromQL: "sum(rate($METRIC[2m]))", Top: 7}},
+				},
+			},
+		}},
+		{Spec: metricslatest.FlowMetricSpec{
+			MetricName: "team_a_flows_total",
+			Charts: []metricslatest.Chart{{
+				DashboardName: "Team A",
+				Title:         "Flows",
+				Type:          metricslatest.ChartTypeLine,
+				Queries:       []metricslatest.Query{{PromQL: "sum(rate($METRIC[2m]))", Legend: "flows"}},
+			}},
+		}},
+	}
+
+	dashboards := CreateFlowMetricsChartsDashboards("netobserv", "netobserv_", items)
+	assert.Len(dashboards, 2)
+
+	d, err := FromBytes([]byte(dashboards["Main"]))
+	assert.NoError(err)
+	assert.Equal("NetObserv / Main", d.Title)
+	assert.Equal([]string{"", "Pods"}, d.Titles())
+
+	row := d.FindRow("")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 1)
+	assert.Equal(PanelTypeSingleStat, row.Panels[0].Type)
+	assert.Equal("sum(rate(netobserv_pod_outgoing_bytes_total[2m]))", row.Panels[0].Targets[0].Expr)
+
+	row = d.FindRow("Pods")
+	assert.NotNil(row)
+	assert.Len(row.Panels, 1)
+	assert.Equal("Top outgoing traffic", row.Panels[0].Title)
+	assert.Equal(`topk(5, sum(rate(netobserv_pod_outgoing_bytes_total{SrcK8S_Namespace!=""}[2m])) by (SrcK8S_Name))`, row.Panels[0].Targets[0].Expr)
+	assert.Contains(dashboards["Main"], `"legendFormat": "{{SrcK8S_Name}}"`)
+
+	d, err = FromBytes([]byte(dashboards["Team A"]))
+	assert.NoError(err)
+	assert.Equal("NetObserv / Team A", d.Title)
+	assert.Equal(PanelTypeGraph, d.Rows[0].Panels[0].Type)
+	assert.Equal("sum(rate(netobserv_team_a_flows_total[2m]))", d.Rows[0].Panels[0].Targets[0].Expr)
+}
@@ -0,0 +1,74 @@
+package dashboards
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	egressClassNamespaceMetric = "namespace_egress_class_bytes_total"
+	egressClassWorkloadMetric  = "workload_egress_class_bytes_total"
+	egressClassNodeMetric      = "node_egress_class_bytes_total"
+)
+
+// CreateEgressCostDashboard builds a view of the egress traffic per destination class, and an estimation of the cost of
+// the internet egress from the price per gigabyte. Since metrics are generated from sampled flows, bytes are scaled by
+// the sampling ratio. Rows depend on the `*_egress_class_bytes_total` metrics given in parameter.
+func CreateEgressCostDashboard(netobsNs, prefix string, metrics []string, costPerGB string, sampling int) (string, error) {
+	d := Dashboard{Title: "NetObserv / Egress cost"}
+
+	var total string
+	for _, candidate := range []string{egressClassNamespaceMetric, egressClassWorkloadMetric, egressClassNodeMetric} {
+		if slices.Contains(metrics, candidate) {
+			total = prefix + candidate
+			break
+		}
+	}
+	if total == "" {
+		return "", nil
+	}
+	scale := max(sampling, 1)
+	// cost of the bytes sent to the internet over the last 24 hours, with the given aggregation
+	cost := func(metric, by string) string {
+		return fmt.Sprintf(`sum(increase(%s{DstEgressClass="Internet"}[24h]))%s * %d * %s / 1e9`, metric, by, scale, costPerGB)
+	}
+
+	// Global stats
+	d.Rows = append(d.Rows, NewRow("", false, "100px", []Panel{
+		NewSingleStatPanel("Internet egress rate", PanelUnitBPS, 4, NewTarget(
+			fmt.Sprintf(`sum(rate(%s{DstEgressClass="Internet"}[2m])) * %d`, total, scale), "")),
+		NewSingleStatPanel("Internet egress cost, last 24h", PanelUnitUSD, 4, NewTarget(cost(total, ""), "")),
+		NewSingleStatPanel("Internet egress cost, 30 days projection", PanelUnitUSD, 4, NewTarget(
+			fmt.Sprintf(`30 * %s`, cost(total, "")), "")),
+	}))
+
+	// Traffic per destination class
+	d.Rows = append(d.Rows, NewRow("Destination classes", false, "250px", []Panel{
+		NewGraphPanel("Egress traffic rate per destination class", PanelUnitBPS, 12, true, []Target{
+			NewTarget(fmt.Sprintf(`sum(rate(%s[2m])) by (DstEgressClass) * %d`, total, scale), "{{DstEgressClass}}"),
+		}),
+	}))
+
+	var panels []Panel
+	if slices.Contains(metrics, egressClassNamespaceMetric) {
+		m := prefix + egressClassNamespaceMetric
+		panels = append(panels, NewGraphPanel("Top internet egress cost per namespace, last 24h", PanelUnitUSD, 6, true, []Target{
+			NewTarget(fmt.Sprintf(`topk(10, %s)`, cost(m, " by (SrcK8S_Namespace)")), "{{SrcK8S_Namespace}}"),
+		}))
+	}
+	if slices.Contains(metrics, egressClassWorkloadMetric) {
+		m := prefix + egressClassWorkloadMetric
+		panels = append(panels, NewGraphPanel("Top internet egress cost per workload, last 24h", PanelUnitUSD, 6, true, []Target{
+			NewTarget(fmt.Sprintf(`topk(10, %s)`, cost(m, " by (SrcK8S_Namespace, SrcK8S_OwnerName)")), "{{SrcK8S_Namespace}}/{{SrcK8S_OwnerName}}"),
+		}))
+	}
+	if slices.Contains(metrics, egressClassNodeMetric) {
+		m := prefix + egressClassNodeMetric
+		panels = append(panels, NewGraphPanel("Top internet egress cost per node, last 24h", PanelUnitUSD, 6, true, []Target{
+			NewTarget(fmt.Sprintf(`topk(10, %s)`, cost(m, " by (SrcK8S_HostName)")), "{{SrcK8S_HostName}}"),
+		}))
+	}
+	d.Rows = append(d.Rows, NewRow("Cost", false, "250px", panels))
+
+	return d.ToGrafanaJSON(netobsNs), nil
+}
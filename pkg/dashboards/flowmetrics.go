@@ -0,0 +1,93 @@
+package dashboards
+
+import (
+	"fmt"
+	"strings"
+
+	metricslatest "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+)
+
+// CreateFlowMetricsChartsDashboards builds the dashboards configured in the `charts` of FlowMetric resources, keyed by
+// dashboard name. Charts are grouped per section, in the order of the FlowMetrics and of their charts.
+func CreateFlowMetricsChartsDashboards(netobsNs, prefix string, items []metricslatest.FlowMetric) map[string]string {
+	type section struct {
+		title  string
+		panels []Panel
+	}
+	var names []string
+	sections := map[string][]*section{}
+	for i := range items {
+		fm := &items[i].Spec
+		for j := range fm.Charts {
+			chart := &fm.Charts[j]
+			if _, ok := sections[chart.DashboardName]; !ok {
+				names = append(names, chart.DashboardName)
+			}
+			var s *section
+			for _, existing := range sections[chart.DashboardName] {
+				if existing.title == chart.SectionName {
+					s = existing
+				}
+			}
+			if s == nil {
+				s = &section{title: chart.SectionName}
+				sections[chart.DashboardName] = append(sections[chart.DashboardName], s)
+			}
+			s.panels = append(s.panels, chartPanels(prefix+fm.MetricName, chart)...)
+		}
+	}
+
+	dashboards := map[string]string{}
+	for _, name := range names {
+		d := Dashboard{Title: formatCleaner.Replace("NetObserv / " + name)}
+		for _, s := range sections[name] {
+			// charts without section come first, as a top section
+			row := NewRow(formatCleaner.Replace(s.title), false, "250px", s.panels)
+			if s.title == "" {
+				d.Rows = append([]*Row{row}, d.Rows...)
+			} else {
+				d.Rows = append(d.Rows, row)
+			}
+		}
+		dashboards[name] = d.ToGrafanaJSON(netobsNs)
+	}
+	return dashboards
+}
+
+// chartPanels returns the panels of a chart: a `SingleStat` chart has a panel per query
+func chartPanels(metric string, chart *metricslatest.Chart) []Panel {
+	title := formatCleaner.Replace(chart.Title)
+	unit := chartUnit(chart.Unit)
+	var targets []Target
+	for _, q := range chart.Queries {
+		expr := strings.ReplaceAll(q.PromQL, "$METRIC", metric)
+		if chart.Type != metricslatest.ChartTypeSingleStat && q.Top > 0 {
+			expr = fmt.Sprintf("topk(%d, %s)", q.Top, expr)
+		}
+		targets = append(targets, NewTarget(expr, formatCleaner.Replace(q.Legend)))
+	}
+	if chart.Type == metricslatest.ChartTypeSingleStat {
+		var panels []Panel
+		for _, t := range targets {
+			panels = append(panels, NewSingleStatPanel(title, unit, 3, t))
+		}
+		return panels
+	}
+	return []Panel{NewGraphPanel(title, unit, 6, chart.Type == metricslatest.ChartTypeStackArea, targets)}
+}
+
+func chartUnit(unit metricslatest.Unit) PanelUnit {
+	switch unit {
+	case metricslatest.UnitBytes:
+		return PanelUnitBytes
+	case metricslatest.UnitSeconds:
+		return PanelUnitSeconds
+	case metricslatest.UnitBPS:
+		return PanelUnitBPS
+	case metricslatest.UnitPPS:
+		return PanelUnitPPS
+	case metricslatest.UnitPercent:
+		return PanelUnitPercent
+	}
+	return PanelUnitShort
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 )
 
-func CreateHealthDashboard(netobsNs string) (string, error) {
+func CreateHealthDashboard(netobsNs, prefix string) (string, error) {
 	d := Dashboard{Title: "NetObserv / Health"}
 
 	// Global stats
@@ -28,7 +28,7 @@ func CreateHealthDashboard(netobsNs string) (string, error) {
 	}))
 
 	// FLP stats
-	overheadQuery := fmt.Sprintf("100 * sum(rate(netobserv_namespace_flows_total{SrcK8S_Namespace='%s'}[1m]) or rate(netobserv_namespace_flows_total{SrcK8S_Namespace!='%s',DstK8S_Namespace='%s'}[1m])) / sum(rate(netobserv_namespace_flows_total[1m]))", netobsNs, netobsNs, netobsNs)
+	overheadQuery := fmt.Sprintf("100 * sum(rate(%[1]snamespace_flows_total{SrcK8S_Namespace='%[2]s'}[1m]) or rate(%[1]snamespace_flows_total{SrcK8S_Namespace!='%[2]s',DstK8S_Namespace='%[2]s'}[1m])) / sum(rate(%[1]snamespace_flows_total[1m]))", prefix, netobsNs)
 	// TODO: add FLP error
 	d.Rows = append(d.Rows,
 		NewRow("Flowlogs-pipeline statistics", false, "250px", []Panel{
@@ -46,12 +46,12 @@ func CreateHealthDashboard(netobsNs string) (string, error) {
 				NewTarget(`sum(increase(netobserv_loki_batch_retries_total[1m]))`, "loki retries"),
 			}),
 			NewGraphPanel("By namespace", PanelUnitShort, 6, false, []Target{
-				NewTarget(`topk(10,sum(rate(netobserv_namespace_flows_total{SrcK8S_Namespace!=""}[1m])) by (SrcK8S_Namespace))`, "From {{SrcK8S_Namespace}}"),
-				NewTarget(`topk(10,sum(rate(netobserv_namespace_flows_total{DstK8S_Namespace!=""}[1m])) by (DstK8S_Namespace))`, "To {{DstK8S_Namespace}}"),
+				NewTarget(fmt.Sprintf(`topk(10,sum(rate(%snamespace_flows_total{SrcK8S_Namespace!=""}[1m])) by (SrcK8S_Namespace))`, prefix), "From {{SrcK8S_Namespace}}"),
+				NewTarget(fmt.Sprintf(`topk(10,sum(rate(%snamespace_flows_total{DstK8S_Namespace!=""}[1m])) by (DstK8S_Namespace))`, prefix), "To {{DstK8S_Namespace}}"),
 			}),
 			NewGraphPanel("By node", PanelUnitShort, 6, false, []Target{
-				NewTarget(`topk(10,sum(rate(netobserv_node_flows_total{SrcK8S_HostName!=""}[1m])) by (SrcK8S_HostName))`, "From {{SrcK8S_HostName}}"),
-				NewTarget(`topk(10,sum(rate(netobserv_node_flows_total{DstK8S_HostName!=""}[1m])) by (DstK8S_HostName))`, "To {{DstK8S_HostName}}"),
+				NewTarget(fmt.Sprintf(`topk(10,sum(rate(%snode_flows_total{SrcK8S_HostName!=""}[1m])) by (SrcK8S_HostName))`, prefix), "From {{SrcK8S_HostName}}"),
+				NewTarget(fmt.Sprintf(`topk(10,sum(rate(%snode_flows_total{DstK8S_HostName!=""}[1m])) by (DstK8S_HostName))`, prefix), "To {{DstK8S_HostName}}"),
 			}),
 		}),
 	)
@@ -0,0 +1,57 @@
+package dashboards
+
+import (
+	"fmt"
+)
+
+// loadGenJob is the Prometheus job of the flow generator, named after its metrics service
+const loadGenJob = "flowlogs-pipeline-loadgen-prom"
+
+// CreateLoadTestDashboard builds the report of a performance test run with the flow generator: the achieved throughput
+// compared with the configured rate, the losses and the latency through the pipeline, and the resources it took.
+func CreateLoadTestDashboard(netobsNs string, flowsPerSecond int32) (string, error) {
+	d := Dashboard{Title: "NetObserv / Load test"}
+	sel := fmt.Sprintf(`{job="%s"}`, loadGenJob)
+	generated := fmt.Sprintf(`sum(rate(netobserv_ingest_flows_processed%s[1m]))`, sel)
+	lost := fmt.Sprintf(`((sum(rate(netobserv_ingest_errors%s[5m])) OR on() vector(0))
+		+ (sum(rate(netobserv_loki_dropped_entries_total%s[5m])) OR on() vector(0)))`, sel, sel)
+	// the end-to-end latency is the sum of the average durations of each stage
+	latency := fmt.Sprintf(`sum(sum by(stage) (rate(netobserv_stage_duration_ms_sum%s[5m])) / sum by(stage) (rate(netobserv_stage_duration_ms_count%s[5m])))`, sel, sel)
+
+	// Global stats
+	d.Rows = append(d.Rows, NewRow("", false, "100px", []Panel{
+		NewSingleStatPanel("Target flows per second", PanelUnitShort, 3, NewTarget(
+			fmt.Sprintf(`vector(%d)`, flowsPerSecond), "")),
+		NewSingleStatPanel("Achieved flows per second", PanelUnitShort, 3, NewTarget(generated, "")),
+		NewSingleStatPanel("Loss ratio", PanelUnitShort, 3, NewTarget(
+			fmt.Sprintf(`%s / sum(rate(netobserv_ingest_flows_processed%s[5m]))`, lost, sel), "")),
+		NewSingleStatPanel("Average pipeline latency (ms)", PanelUnitShort, 3, NewTarget(latency, "")),
+	}))
+
+	// Throughput, loss and latency over time
+	d.Rows = append(d.Rows, NewRow("Pipeline", false, "250px", []Panel{
+		NewGraphPanel("Flows per second", PanelUnitShort, 4, false, []Target{
+			NewTarget(fmt.Sprintf(`vector(%d)`, flowsPerSecond), "target"),
+			NewTarget(generated, "achieved"),
+			NewTarget(lost, "lost"),
+		}),
+		NewGraphPanel("Stage duration P99 (ms)", PanelUnitShort, 4, false, []Target{
+			NewTarget(fmt.Sprintf(`histogram_quantile(0.99, sum by(stage, le) (rate(netobserv_stage_duration_ms_bucket%s[1m])))`, sel), "{{stage}}"),
+		}),
+		NewGraphPanel("Input queue size", PanelUnitShort, 4, false, []Target{
+			NewTarget(fmt.Sprintf(`sum(netobserv_stage_in_queue_size%s) by (stage)`, sel), "{{stage}}"),
+		}),
+	}))
+
+	// Resources used by the pipeline under test
+	d.Rows = append(d.Rows, NewRow("Resources", false, "250px", []Panel{
+		NewGraphPanel("CPU usage", PanelUnitShort, 6, false, []Target{
+			NewTarget(`sum(node_namespace_pod_container:container_cpu_usage_seconds_total:sum_irate{container!="",pod=~"flowlogs-pipeline-loadgen.*"}) by (pod)`, "{{pod}}"),
+		}),
+		NewGraphPanel("Memory usage", PanelUnitBytes, 6, false, []Target{
+			NewTarget(`sum(container_memory_rss{container!="",pod=~"flowlogs-pipeline-loadgen.*"}) by (pod)`, "{{pod}}"),
+		}),
+	}))
+
+	return d.ToGrafanaJSON(netobsNs), nil
+}
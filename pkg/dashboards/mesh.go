@@ -0,0 +1,71 @@
+package dashboards
+
+import (
+	"fmt"
+	"slices"
+)
+
+const (
+	meshServiceMetric = "mesh_service_ingress_bytes_total"
+	meshSidecarMetric = "mesh_sidecar_ingress_bytes_total"
+)
+
+// CreateServiceMeshDashboard builds a view of the Istio service mesh from the flows, independently of the mesh
+// telemetry: the traffic between sidecars, eligible to mutual TLS, the traffic entering or leaving the mesh in
+// plaintext, and how the traffic of services is split between their revisions. Rows depend on the `mesh_*`
+// metrics given in parameter.
+func CreateServiceMeshDashboard(netobsNs, prefix string, metrics []string) (string, error) {
+	d := Dashboard{Title: "NetObserv / Service mesh"}
+
+	if slices.Contains(metrics, meshSidecarMetric) {
+		m := prefix + meshSidecarMetric
+		// tlsMode is "istio" for pods having a sidecar, "disabled" otherwise
+		mtls := fmt.Sprintf(`sum(rate(%s{SrcK8S_MeshTLSMode="istio",DstK8S_MeshTLSMode="istio"}[2m]))`, m)
+		in := fmt.Sprintf(`sum(rate(%s{SrcK8S_MeshTLSMode!="istio",DstK8S_MeshTLSMode="istio"}[2m]))`, m)
+		out := fmt.Sprintf(`sum(rate(%s{SrcK8S_MeshTLSMode="istio",DstK8S_MeshTLSMode!="istio"}[2m]))`, m)
+		mesh := fmt.Sprintf(`sum(rate(%s{SrcK8S_MeshTLSMode="istio"}[2m]) OR rate(%s{DstK8S_MeshTLSMode="istio"}[2m]))`, m, m)
+
+		// Global stats
+		d.Rows = append(d.Rows, NewRow("", false, "100px", []Panel{
+			NewSingleStatPanel("Sidecar-to-sidecar traffic (%)", PanelUnitShort, 4, NewTarget(
+				fmt.Sprintf(`100 * (%s OR on() vector(0)) / %s`, mtls, mesh), "")),
+			NewSingleStatPanel("Plaintext traffic entering the mesh", PanelUnitBPS, 4, NewTarget(in, "")),
+			NewSingleStatPanel("Plaintext traffic leaving the mesh", PanelUnitBPS, 4, NewTarget(out, "")),
+		}))
+
+		// Hops per namespaces
+		d.Rows = append(d.Rows, NewRow("mTLS hops", false, "250px", []Panel{
+			NewGraphPanel("Top sidecar-to-sidecar traffic rate per namespaces", PanelUnitBPS, 6, true, []Target{
+				NewTarget(fmt.Sprintf(`topk(10, %s by (SrcK8S_Namespace, DstK8S_Namespace))`, mtls), "{{SrcK8S_Namespace}} -> {{DstK8S_Namespace}}"),
+			}),
+			NewGraphPanel("Top plaintext traffic rate to or from the mesh per namespaces", PanelUnitBPS, 6, true, []Target{
+				NewTarget(fmt.Sprintf(`topk(10, %s by (SrcK8S_Namespace, DstK8S_Namespace))`, in), "{{SrcK8S_Namespace}} -> {{DstK8S_Namespace}} (in)"),
+				NewTarget(fmt.Sprintf(`topk(10, %s by (SrcK8S_Namespace, DstK8S_Namespace))`, out), "{{SrcK8S_Namespace}} -> {{DstK8S_Namespace}} (out)"),
+			}),
+		}))
+	}
+
+	if slices.Contains(metrics, meshServiceMetric) {
+		m := prefix + meshServiceMetric
+		byRevision := fmt.Sprintf(`sum(rate(%s{DstK8S_MeshService!="unknown"}[2m])) by (DstK8S_Namespace, DstK8S_MeshService, DstK8S_MeshRevision)`, m)
+		byService := fmt.Sprintf(`sum(rate(%s{DstK8S_MeshService!="unknown"}[2m])) by (DstK8S_Namespace, DstK8S_MeshService)`, m)
+
+		// Traffic split between the revisions of services, such as during canary rollouts
+		d.Rows = append(d.Rows, NewRow("Traffic split", false, "250px", []Panel{
+			NewGraphPanel("Top traffic rate per service revision", PanelUnitBPS, 6, true, []Target{
+				NewTarget(fmt.Sprintf(`topk(10, %s)`, byRevision), "{{DstK8S_Namespace}}/{{DstK8S_MeshService}} {{DstK8S_MeshRevision}}"),
+			}),
+			NewGraphPanel("Traffic share per service revision (%)", PanelUnitShort, 6, false, []Target{
+				NewTarget(fmt.Sprintf(`100 * %s / on(DstK8S_Namespace, DstK8S_MeshService) group_left() %s`, byRevision, byService), "{{DstK8S_Namespace}}/{{DstK8S_MeshService}} {{DstK8S_MeshRevision}}"),
+			}),
+		}))
+		d.Rows = append(d.Rows, NewRow("Services", false, "250px", []Panel{
+			NewGraphPanel("Top traffic rate between services", PanelUnitBPS, 12, true, []Target{
+				NewTarget(fmt.Sprintf(`topk(10, sum(rate(%s[2m])) by (SrcK8S_Namespace, SrcK8S_MeshService, DstK8S_Namespace, DstK8S_MeshService))`, m),
+					"{{SrcK8S_Namespace}}/{{SrcK8S_MeshService}} -> {{DstK8S_Namespace}}/{{DstK8S_MeshService}}"),
+			}),
+		}))
+	}
+
+	return d.ToGrafanaJSON(netobsNs), nil
+}
@@ -39,6 +39,8 @@ const (
 	PanelUnitSeconds    PanelUnit = "seconds"
 	PanelUnitBPS        PanelUnit = "Bps"
 	PanelUnitPPS        PanelUnit = "pps"
+	PanelUnitPercent    PanelUnit = "percent"
+	PanelUnitUSD        PanelUnit = "currencyUSD"
 )
 
 type Panel struct {
@@ -203,10 +205,14 @@ func (r *Row) ToGrafanaJSON(netobsNs string) string {
 }
 
 func (r *Row) replaceMetric(newName string) *Row {
+	return r.replaceInTargets(r.Metric, newName)
+}
+
+func (r *Row) replaceInTargets(oldStr, newStr string) *Row {
 	clone := NewRow(r.Title, r.Collapse, r.Height, nil)
 	clone.Metric = r.Metric
 	for _, p := range r.Panels {
-		clone.Panels = append(clone.Panels, p.replaceMetric(r.Metric, newName))
+		clone.Panels = append(clone.Panels, p.replaceMetric(oldStr, newStr))
 	}
 	return clone
 }
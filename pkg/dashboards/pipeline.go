@@ -0,0 +1,53 @@
+package dashboards
+
+// CreatePipelineHealthDashboard builds a dashboard breaking down flowlogs-pipeline operational metrics by stage,
+// stage names being those of the pipeline generated from the FlowCollector (e.g. "enrich", "loki", "prometheus").
+func CreatePipelineHealthDashboard(netobsNs string) (string, error) {
+	d := Dashboard{Title: "NetObserv / Pipeline health"}
+
+	// Global stats
+	d.Rows = append(d.Rows, NewRow("", false, "100px", []Panel{
+		NewSingleStatPanel("Flows per second", PanelUnitShort, 4, NewTarget(
+			`sum(rate(netobserv_ingest_flows_processed[1m]))`, "")),
+		NewSingleStatPanel("Error ratio", PanelUnitShort, 4, NewTarget(
+			`(sum(rate(netobserv_ingest_errors[5m])) OR on() vector(0)) / sum(rate(netobserv_ingest_flows_processed[5m]))`, "")),
+		NewSingleStatPanel("P99 stage duration (ms)", PanelUnitShort, 4, NewTarget(
+			`max(histogram_quantile(0.99, sum by(stage, le) (rate(netobserv_stage_duration_ms_bucket[5m]))))`, "")),
+	}))
+
+	// Per-stage stats
+	d.Rows = append(d.Rows, NewRow("Stages", false, "250px", []Panel{
+		NewGraphPanel("Stage duration P50 (ms)", PanelUnitShort, 6, false, []Target{
+			NewTarget(`histogram_quantile(0.5, sum by(stage, le) (rate(netobserv_stage_duration_ms_bucket[1m])))`, "{{stage}}"),
+		}),
+		NewGraphPanel("Stage duration P99 (ms)", PanelUnitShort, 6, false, []Target{
+			NewTarget(`histogram_quantile(0.99, sum by(stage, le) (rate(netobserv_stage_duration_ms_bucket[1m])))`, "{{stage}}"),
+		}),
+		NewGraphPanel("Input queue size", PanelUnitShort, 6, false, []Target{
+			NewTarget(`sum(netobserv_stage_in_queue_size) by (stage)`, "{{stage}}"),
+		}),
+		NewGraphPanel("Output queue size", PanelUnitShort, 6, false, []Target{
+			NewTarget(`sum(netobserv_stage_out_queue_size) by (stage)`, "{{stage}}"),
+		}),
+		NewGraphPanel("Errors per minute", PanelUnitShort, 6, true, []Target{
+			NewTarget(`sum(increase(netobserv_ingest_errors[1m])) by (stage,code)`, "{{stage}} {{code}}"),
+			NewTarget(`sum(increase(netobserv_encode_prom_errors[1m])) by (error)`, "prometheus {{error}}"),
+		}),
+		NewGraphPanel("Error ratio by stage", PanelUnitShort, 6, false, []Target{
+			NewTarget(`sum by (stage) (rate(netobserv_ingest_errors[5m])) / ignoring(stage) group_left sum(rate(netobserv_ingest_flows_processed[5m]))`, "{{stage}}"),
+		}),
+	}))
+
+	// Outputs
+	d.Rows = append(d.Rows, NewRow("Outputs", true, "250px", []Panel{
+		NewGraphPanel("Loki throughput", PanelUnitShort, 6, false, []Target{
+			NewTarget("sum(rate(netobserv_loki_sent_entries_total[1m]))", "sent"),
+			NewTarget("sum(rate(netobserv_loki_dropped_entries_total[1m]))", "dropped"),
+		}),
+		NewGraphPanel("Loki retries per minute", PanelUnitShort, 6, false, []Target{
+			NewTarget("sum(increase(netobserv_loki_batch_retries_total[1m]))", "retries"),
+		}),
+	}))
+
+	return d.ToGrafanaJSON(netobsNs), nil
+}
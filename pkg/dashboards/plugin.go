@@ -0,0 +1,59 @@
+package dashboards
+
+// CreatePluginDashboard builds a dashboard for the console plugin backend, splitting the time spent serving
+// each endpoint between the plugin itself and its upstream (Loki or Prometheus) queries.
+func CreatePluginDashboard(netobsNs string) (string, error) {
+	d := Dashboard{Title: "NetObserv / Console plugin"}
+
+	// Global stats
+	d.Rows = append(d.Rows, NewRow("", false, "100px", []Panel{
+		NewSingleStatPanel("Requests per second", PanelUnitShort, 3, NewTarget(
+			`sum(rate(netobserv_plugin_http_request_duration_seconds_count[1m]))`, "")),
+		NewSingleStatPanel("P99 request duration", PanelUnitSeconds, 3, NewTarget(
+			`histogram_quantile(0.99, sum by(le) (rate(netobserv_plugin_http_request_duration_seconds_bucket[5m])))`, "")),
+		NewSingleStatPanel("Loki error ratio", PanelUnitShort, 3, NewTarget(
+			`(sum(rate(netobserv_plugin_loki_requests_total{code!~"2.."}[5m])) OR on() vector(0)) / sum(rate(netobserv_plugin_loki_requests_total[5m]))`, "")),
+		NewSingleStatPanel("Cache hit ratio", PanelUnitShort, 3, NewTarget(
+			`sum(rate(netobserv_plugin_cache_hits_total[5m])) / (sum(rate(netobserv_plugin_cache_hits_total[5m])) + sum(rate(netobserv_plugin_cache_misses_total[5m])))`, "")),
+	}))
+
+	// Per-endpoint stats
+	d.Rows = append(d.Rows, NewRow("Endpoints", false, "250px", []Panel{
+		NewGraphPanel("Requests per second", PanelUnitShort, 4, true, []Target{
+			NewTarget(`sum(rate(netobserv_plugin_http_request_duration_seconds_count[1m])) by (handler,code)`, "{{handler}} {{code}}"),
+		}),
+		NewGraphPanel("Request duration P50", PanelUnitSeconds, 4, false, []Target{
+			NewTarget(`histogram_quantile(0.5, sum by(handler, le) (rate(netobserv_plugin_http_request_duration_seconds_bucket[1m])))`, "{{handler}}"),
+		}),
+		NewGraphPanel("Request duration P99", PanelUnitSeconds, 4, false, []Target{
+			NewTarget(`histogram_quantile(0.99, sum by(handler, le) (rate(netobserv_plugin_http_request_duration_seconds_bucket[1m])))`, "{{handler}}"),
+		}),
+	}))
+
+	// Upstream stats: time spent waiting for Loki / Prometheus vs overall time, the difference being spent in the plugin
+	d.Rows = append(d.Rows, NewRow("Upstream", false, "250px", []Panel{
+		NewGraphPanel("Average time split", PanelUnitSeconds, 4, true, []Target{
+			NewTarget(`sum(rate(netobserv_plugin_loki_request_duration_seconds_sum[1m])) / sum(rate(netobserv_plugin_http_request_duration_seconds_count[1m]))`, "Loki"),
+			NewTarget(`sum(rate(netobserv_plugin_prometheus_request_duration_seconds_sum[1m])) / sum(rate(netobserv_plugin_http_request_duration_seconds_count[1m]))`, "Prometheus"),
+			NewTarget(`(sum(rate(netobserv_plugin_http_request_duration_seconds_sum[1m]))
+			- (sum(rate(netobserv_plugin_loki_request_duration_seconds_sum[1m])) OR on() vector(0))
+			- (sum(rate(netobserv_plugin_prometheus_request_duration_seconds_sum[1m])) OR on() vector(0)))
+			/ sum(rate(netobserv_plugin_http_request_duration_seconds_count[1m]))`, "Plugin"),
+		}),
+		NewGraphPanel("Loki request duration P99", PanelUnitSeconds, 4, false, []Target{
+			NewTarget(`histogram_quantile(0.99, sum by(route, le) (rate(netobserv_plugin_loki_request_duration_seconds_bucket[1m])))`, "{{route}}"),
+		}),
+		NewGraphPanel("Loki responses per minute", PanelUnitShort, 4, true, []Target{
+			NewTarget(`sum(increase(netobserv_plugin_loki_requests_total[1m])) by (code)`, "{{code}}"),
+		}),
+		NewGraphPanel("Prometheus request duration P99", PanelUnitSeconds, 4, false, []Target{
+			NewTarget(`histogram_quantile(0.99, sum by(le) (rate(netobserv_plugin_prometheus_request_duration_seconds_bucket[1m])))`, "p99"),
+		}),
+		NewGraphPanel("Cache hits and misses per minute", PanelUnitShort, 4, true, []Target{
+			NewTarget(`sum(increase(netobserv_plugin_cache_hits_total[1m])) by (cache)`, "{{cache}} hits"),
+			NewTarget(`sum(increase(netobserv_plugin_cache_misses_total[1m])) by (cache)`, "{{cache}} misses"),
+		}),
+	}))
+
+	return d.ToGrafanaJSON(netobsNs), nil
+}
@@ -1,7 +1,9 @@
 package discover
 
 import (
+	"sort"
 	"strings"
+	"sync"
 
 	configv1 "github.com/openshift/api/config/v1"
 	osv1alpha1 "github.com/openshift/api/console/v1alpha1"
@@ -16,22 +18,41 @@ var (
 	cno           = "networks." + operatorv1.GroupName
 	svcMonitor    = "servicemonitors." + monitoring.GroupName
 	promRule      = "prometheusrules." + monitoring.GroupName
+	lokiStack     = "lokistacks.loki.grafana.com"
+	kubeVirt      = "virtualmachineinstances.kubevirt.io"
+	istio         = "peerauthentications.security.istio.io"
+	logForwarder  = "clusterlogforwarders.observability.openshift.io"
 )
 
 // AvailableAPIs discovers the available APIs in the running cluster
 type AvailableAPIs struct {
+	client  discovery.DiscoveryInterface
+	mutex   sync.RWMutex
 	apisMap map[string]bool
 }
 
-func NewAvailableAPIs(client *discovery.DiscoveryClient) (*AvailableAPIs, error) {
+func NewAvailableAPIs(client discovery.DiscoveryInterface) (*AvailableAPIs, error) {
+	c := AvailableAPIs{client: client}
+	if _, err := c.Refresh(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Refresh runs the discovery again, and returns the names of the APIs that were installed or removed since the previous discovery
+func (c *AvailableAPIs) Refresh() ([]string, error) {
 	apiMap := map[string]bool{
 		consolePlugin: false,
 		consoleConfig: false,
 		cno:           false,
 		svcMonitor:    false,
 		promRule:      false,
+		lokiStack:     false,
+		kubeVirt:      false,
+		istio:         false,
+		logForwarder:  false,
 	}
-	_, resources, err := client.ServerGroupsAndResources()
+	_, resources, err := c.client.ServerGroupsAndResources()
 	if err != nil {
 		return nil, err
 	}
@@ -47,30 +68,69 @@ func NewAvailableAPIs(client *discovery.DiscoveryClient) (*AvailableAPIs, error)
 			}
 		}
 	}
-	return &AvailableAPIs{apisMap: apiMap}, nil
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var changed []string
+	if c.apisMap != nil {
+		for apiName, found := range apiMap {
+			if c.apisMap[apiName] != found {
+				changed = append(changed, apiName)
+			}
+		}
+		sort.Strings(changed)
+	}
+	c.apisMap = apiMap
+	return changed, nil
+}
+
+func (c *AvailableAPIs) has(apiName string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.apisMap[apiName]
 }
 
 // HasConsolePlugin returns true if "consoleplugins.console.openshift.io" API was found
 func (c *AvailableAPIs) HasConsolePlugin() bool {
-	return c.apisMap[consolePlugin]
+	return c.has(consolePlugin)
 }
 
 // HasConsoleConfig returns true if "consoles.config.openshift.io" API was found
 func (c *AvailableAPIs) HasConsoleConfig() bool {
-	return c.apisMap[consoleConfig]
+	return c.has(consoleConfig)
 }
 
 // HasCNO returns true if "networks.operator.openshift.io" API was found
 func (c *AvailableAPIs) HasCNO() bool {
-	return c.apisMap[cno]
+	return c.has(cno)
 }
 
 // HasSvcMonitor returns true if "servicemonitors.monitoring.coreos.com" API was found
 func (c *AvailableAPIs) HasSvcMonitor() bool {
-	return c.apisMap[svcMonitor]
+	return c.has(svcMonitor)
 }
 
 // HasPromRule returns true if "prometheusrules.monitoring.coreos.com" API was found
 func (c *AvailableAPIs) HasPromRule() bool {
-	return c.apisMap[promRule]
+	return c.has(promRule)
+}
+
+// HasLokiStack returns true if "lokistacks.loki.grafana.com" API was found
+func (c *AvailableAPIs) HasLokiStack() bool {
+	return c.has(lokiStack)
+}
+
+// HasKubeVirt returns true if "virtualmachineinstances.kubevirt.io" API was found
+func (c *AvailableAPIs) HasKubeVirt() bool {
+	return c.has(kubeVirt)
+}
+
+// HasIstio returns true if "peerauthentications.security.istio.io" API was found
+func (c *AvailableAPIs) HasIstio() bool {
+	return c.has(istio)
+}
+
+// HasClusterLogForwarder returns true if "clusterlogforwarders.observability.openshift.io" API was found
+func (c *AvailableAPIs) HasClusterLogForwarder() bool {
+	return c.has(logForwarder)
 }
@@ -0,0 +1,64 @@
+package discover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestRefreshAPIs(t *testing.T) {
+	fake := &clienttesting.Fake{
+		Resources: []*metav1.APIResourceList{{
+			GroupVersion: "console.openshift.io/v1alpha1",
+			APIResources: []metav1.APIResource{{Name: "consoleplugins"}},
+		}},
+	}
+	apis, err := NewAvailableAPIs(&fakediscovery.FakeDiscovery{Fake: fake})
+	assert.NoError(t, err)
+	assert.True(t, apis.HasConsolePlugin())
+	assert.False(t, apis.HasSvcMonitor())
+	assert.False(t, apis.HasPromRule())
+
+	// Nothing changed
+	changed, err := apis.Refresh()
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+
+	// Prometheus Operator installed later
+	fake.Resources = append(fake.Resources, &metav1.APIResourceList{
+		GroupVersion: "monitoring.coreos.com/v1",
+		APIResources: []metav1.APIResource{{Name: "servicemonitors"}, {Name: "prometheusrules"}},
+	})
+	changed, err = apis.Refresh()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{promRule, svcMonitor}, changed)
+	assert.True(t, apis.HasSvcMonitor())
+	assert.True(t, apis.HasPromRule())
+	assert.True(t, apis.HasConsolePlugin())
+	assert.False(t, apis.HasKubeVirt())
+
+	// KubeVirt installed later
+	fake.Resources = append(fake.Resources, &metav1.APIResourceList{
+		GroupVersion: "kubevirt.io/v1",
+		APIResources: []metav1.APIResource{{Name: "virtualmachineinstances"}, {Name: "virtualmachines"}},
+	})
+	changed, err = apis.Refresh()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{kubeVirt}, changed)
+	assert.True(t, apis.HasKubeVirt())
+	assert.False(t, apis.HasIstio())
+	assert.False(t, apis.HasClusterLogForwarder())
+
+	// Logging Operator installed later
+	fake.Resources = append(fake.Resources, &metav1.APIResourceList{
+		GroupVersion: "observability.openshift.io/v1",
+		APIResources: []metav1.APIResource{{Name: "clusterlogforwarders"}},
+	})
+	changed, err = apis.Refresh()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{logForwarder}, changed)
+	assert.True(t, apis.HasClusterLogForwarder())
+}
@@ -0,0 +1,160 @@
+// Package featuregates lets experimental capabilities ship disabled, to be enabled per cluster, and newer ones be disabled
+// when they misbehave. Gates are set for the whole operator, with the `--feature-gates` flag or the `FEATURE_GATES`
+// environment variable, such as `BatchAutoTuning=true,AnomalyDetection=false`.
+package featuregates
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+type Feature string
+
+const (
+	AnomalyDetection Feature = "AnomalyDetection"
+	BatchAutoTuning  Feature = "BatchAutoTuning"
+)
+
+type maturity string
+
+const (
+	// alpha features are disabled by default
+	alpha maturity = "Alpha"
+	// beta features are enabled by default
+	beta maturity = "Beta"
+)
+
+type gatedField struct {
+	path    string
+	enabled func(*flowslatest.FlowCollectorSpec) bool
+	disable func(*flowslatest.FlowCollectorSpec)
+}
+
+type gate struct {
+	maturity maturity
+	fields   []gatedField
+}
+
+var gates = map[Feature]gate{
+	AnomalyDetection: {
+		maturity: beta,
+		fields: []gatedField{{
+			path: "spec.analytics.anomalyDetection.enable",
+			enabled: func(spec *flowslatest.FlowCollectorSpec) bool {
+				return helper.IsAnomalyDetectionEnabled(&spec.Analytics)
+			},
+			disable: func(spec *flowslatest.FlowCollectorSpec) { spec.Analytics.AnomalyDetection.Enable = ptr.To(false) },
+		}},
+	},
+	BatchAutoTuning: {
+		maturity: alpha,
+		fields: []gatedField{{
+			path: "spec.processor.batchAutoTuning.enable",
+			enabled: func(spec *flowslatest.FlowCollectorSpec) bool {
+				return helper.IsBatchAutoTuningEnabled(&spec.Processor)
+			},
+			disable: func(spec *flowslatest.FlowCollectorSpec) { spec.Processor.BatchAutoTuning.Enable = ptr.To(false) },
+		}},
+	},
+}
+
+// Gates holds the features enabled or disabled in the operator. A nil Gates uses the defaults.
+type Gates struct {
+	overrides map[Feature]bool
+}
+
+// Parse reads a comma-separated list of `Feature=bool`, where unlisted features keep their default
+func Parse(s string) (*Gates, error) {
+	g := Gates{overrides: map[Feature]bool{}}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid feature gate %q: expecting Feature=true|false", item)
+		}
+		feature := Feature(strings.TrimSpace(name))
+		if _, found := gates[feature]; !found {
+			return nil, fmt.Errorf("unknown feature gate %q, known gates are: %s", feature, strings.Join(known(), ", "))
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature gate %q: %w", item, err)
+		}
+		g.overrides[feature] = enabled
+	}
+	return &g, nil
+}
+
+func known() []string {
+	var names []string
+	for f := range gates {
+		names = append(names, string(f))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enabled returns true if the feature is enabled, either explicitly or by default
+func (g *Gates) Enabled(f Feature) bool {
+	if g != nil {
+		if enabled, set := g.overrides[f]; set {
+			return enabled
+		}
+	}
+	return gates[f].maturity == beta
+}
+
+// String lists all the gates with their state, such as `AnomalyDetection=true,BatchAutoTuning=false`
+func (g *Gates) String() string {
+	var items []string
+	for _, f := range known() {
+		items = append(items, fmt.Sprintf("%s=%t", f, g.Enabled(Feature(f))))
+	}
+	return strings.Join(items, ",")
+}
+
+// Warnings returns a warning for each field of the spec enabling a feature which gate is disabled
+func (g *Gates) Warnings(spec *flowslatest.FlowCollectorSpec) []string {
+	var warnings []string
+	for _, f := range known() {
+		feature := Feature(f)
+		if g.Enabled(feature) {
+			continue
+		}
+		for _, field := range gates[feature].fields {
+			if field.enabled(spec) {
+				warnings = append(warnings, fmt.Sprintf("%s is ignored, as the %s feature gate (%s) is disabled in the operator", field.path, feature, gates[feature].maturity))
+			}
+		}
+	}
+	return warnings
+}
+
+// Apply returns the FlowCollector with the features of disabled gates turned off, along with the corresponding warnings.
+// The FlowCollector is copied when modified.
+func (g *Gates) Apply(fc *flowslatest.FlowCollector) (*flowslatest.FlowCollector, []string) {
+	warnings := g.Warnings(&fc.Spec)
+	if len(warnings) == 0 {
+		return fc, nil
+	}
+	fc = fc.DeepCopy()
+	for feature, gate := range gates {
+		if g.Enabled(feature) {
+			continue
+		}
+		for _, field := range gate.fields {
+			field.disable(&fc.Spec)
+		}
+	}
+	return fc, warnings
+}
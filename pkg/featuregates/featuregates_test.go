@@ -0,0 +1,61 @@
+package featuregates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	// defaults
+	g, err := Parse("")
+	assert.NoError(err)
+	assert.True(g.Enabled(AnomalyDetection))
+	assert.False(g.Enabled(BatchAutoTuning))
+	assert.Equal("AnomalyDetection=true,BatchAutoTuning=false", g.String())
+
+	var nilGates *Gates
+	assert.Equal(g.String(), nilGates.String())
+
+	g, err = Parse(" BatchAutoTuning=true, AnomalyDetection=false ")
+	assert.NoError(err)
+	assert.False(g.Enabled(AnomalyDetection))
+	assert.True(g.Enabled(BatchAutoTuning))
+
+	_, err = Parse("PacketCapture=true")
+	assert.EqualError(err, `unknown feature gate "PacketCapture", known gates are: AnomalyDetection, BatchAutoTuning`)
+	_, err = Parse("BatchAutoTuning")
+	assert.EqualError(err, `invalid feature gate "BatchAutoTuning": expecting Feature=true|false`)
+	_, err = Parse("BatchAutoTuning=yes")
+	assert.ErrorContains(err, `invalid feature gate "BatchAutoTuning=yes"`)
+}
+
+func TestApply(t *testing.T) {
+	assert := assert.New(t)
+
+	fc := &flowslatest.FlowCollector{
+		Spec: flowslatest.FlowCollectorSpec{
+			Processor: flowslatest.FlowCollectorFLP{
+				BatchAutoTuning: flowslatest.FLPBatchAutoTuning{Enable: ptr.To(true)},
+			},
+		},
+	}
+
+	// gate disabled by default: the field is turned off in a copy
+	applied, warnings := (*Gates)(nil).Apply(fc)
+	assert.Equal([]string{"spec.processor.batchAutoTuning.enable is ignored, as the BatchAutoTuning feature gate (Alpha) is disabled in the operator"}, warnings)
+	assert.False(*applied.Spec.Processor.BatchAutoTuning.Enable)
+	assert.True(*fc.Spec.Processor.BatchAutoTuning.Enable)
+
+	// gate enabled: unchanged
+	g, err := Parse("BatchAutoTuning=true")
+	assert.NoError(err)
+	applied, warnings = g.Apply(fc)
+	assert.Empty(warnings)
+	assert.Same(fc, applied)
+}
@@ -0,0 +1,156 @@
+// Package flowschema builds the JSON schema of the flow records written by flowlogs-pipeline to Loki and to the
+// exporters, depending on the features enabled in the FlowCollector.
+package flowschema
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/consoleplugin/config"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// fieldFeature is a field written only when a feature is enabled, identified by name or by name prefix
+type fieldFeature struct {
+	match   func(string) bool
+	enabled func(*flowslatest.FlowCollectorSpec) bool
+}
+
+func is(names ...string) func(string) bool {
+	return func(name string) bool {
+		for _, n := range names {
+			if name == n {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func hasPrefix(prefixes ...string) func(string) bool {
+	return func(name string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(name, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func hasSuffix(suffix string) func(string) bool {
+	return func(name string) bool {
+		return strings.HasSuffix(name, suffix)
+	}
+}
+
+var fieldFeatures = []fieldFeature{
+	{match: hasPrefix("PktDrop"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsPktDropEnabled(&s.Agent.EBPF) }},
+	{match: hasPrefix("Dns"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsDNSTrackingEnabled(&s.Agent.EBPF) }},
+	{match: is("TimeFlowRttNs"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsFlowRTTEnabled(&s.Agent.EBPF) }},
+	{match: is("NetworkEvents"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsOVNObservabilityEnabled(&s.Agent) }},
+	{match: is("K8S_ClusterName"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsMultiClusterEnabled(&s.Processor) }},
+	{match: hasSuffix("_Zone"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsZoneEnabled(&s.Processor) }},
+	{match: hasSuffix("SubnetLabel"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsSubnetLabelsEnabled(&s.Processor) }},
+	{match: hasSuffix("_VirtualMachine"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsVirtualizationEnabled(&s.Processor) }},
+	{match: hasPrefix("SrcK8S_Mesh", "DstK8S_Mesh"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsServiceMeshEnabled(&s.Processor) }},
+	{match: is("DstEgressClass"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsEgressClassificationEnabled(&s.Processor) }},
+	{match: is("DscpClass"), enabled: func(s *flowslatest.FlowCollectorSpec) bool { return helper.IsDSCPClassificationEnabled(&s.Processor) }},
+}
+
+// Schema is a JSON schema of the flow records
+type Schema struct {
+	Schema      string              `json:"$schema"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Version     string              `json:"version"`
+	Type        string              `json:"type"`
+	Properties  map[string]Property `json:"properties"`
+	// Fields may be added by future versions, or may not be documented
+	AdditionalProperties bool `json:"additionalProperties"`
+}
+
+// Property is a field of the flow records
+type Property struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// Build returns the schema of the flow records written with the FlowCollector configuration. The version is a digest of
+// the schema content, which changes only when the set of fields or their definition changes.
+func Build(spec *flowslatest.FlowCollectorSpec) (*Schema, error) {
+	frontend, err := readFrontendConfig()
+	if err != nil {
+		return nil, err
+	}
+	schema := Schema{
+		Schema:      draft,
+		Title:       "NetObserv flow record",
+		Description: "Flow record written by flowlogs-pipeline to Loki and to the exporters, with the fields of the enabled features",
+		Type:        "object",
+		Properties:  map[string]Property{},
+	}
+	for _, field := range frontend.Fields {
+		if !isFieldEnabled(spec, field.Name) {
+			continue
+		}
+		schema.Properties[field.Name] = Property{Type: field.Type, Description: field.Description}
+	}
+	schema.AdditionalProperties = true
+	version, err := digest(&schema)
+	if err != nil {
+		return nil, err
+	}
+	schema.Version = version
+	return &schema, nil
+}
+
+// FieldNames returns the names of all the flow fields, whatever the enabled features
+func FieldNames() ([]string, error) {
+	frontend, err := readFrontendConfig()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(frontend.Fields))
+	for _, field := range frontend.Fields {
+		names = append(names, field.Name)
+	}
+	return names, nil
+}
+
+func readFrontendConfig() (*config.FrontendConfig, error) {
+	var frontend config.FrontendConfig
+	if err := yaml.Unmarshal(config.StaticFrontendConfig, &frontend); err != nil {
+		return nil, err
+	}
+	return &frontend, nil
+}
+
+func isFieldEnabled(spec *flowslatest.FlowCollectorSpec, name string) bool {
+	for _, ff := range fieldFeatures {
+		if ff.match(name) {
+			return ff.enabled(spec)
+		}
+	}
+	return true
+}
+
+func digest(schema *Schema) (string, error) {
+	// properties are marshalled with sorted keys, which keeps the digest stable
+	bs, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New64a()
+	if _, err := hasher.Write(bs); err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(hasher.Sum64(), 36), nil
+}
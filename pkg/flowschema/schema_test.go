@@ -0,0 +1,50 @@
+package flowschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+func TestBuildSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{}
+	schema, err := Build(&spec)
+	assert.NoError(err)
+	assert.Equal("object", schema.Type)
+	assert.True(schema.AdditionalProperties)
+	assert.Equal("number", schema.Properties["Bytes"].Type)
+	assert.Equal("string", schema.Properties["SrcK8S_Name"].Type)
+	assert.NotEmpty(schema.Properties["Bytes"].Description)
+	assert.NotContains(schema.Properties, "PktDropBytes")
+	assert.NotContains(schema.Properties, "DnsLatencyMs")
+	assert.NotContains(schema.Properties, "TimeFlowRttNs")
+	assert.NotContains(schema.Properties, "SrcK8S_Zone")
+	assert.NotContains(schema.Properties, "K8S_ClusterName")
+	baseVersion := schema.Version
+	assert.NotEmpty(baseVersion)
+
+	// Same configuration, same version
+	schema, err = Build(&spec)
+	assert.NoError(err)
+	assert.Equal(baseVersion, schema.Version)
+
+	// Enabling features adds their fields, and changes the version
+	spec.Agent.EBPF.Privileged = true
+	spec.Agent.EBPF.Features = []flowslatest.AgentFeature{flowslatest.PacketDrop, flowslatest.DNSTracking, flowslatest.FlowRTT}
+	spec.Processor.AddZone = ptr.To(true)
+	spec.Processor.MultiClusterDeployment = ptr.To(true)
+	schema, err = Build(&spec)
+	assert.NoError(err)
+	assert.Contains(schema.Properties, "PktDropBytes")
+	assert.Contains(schema.Properties, "DnsLatencyMs")
+	assert.Contains(schema.Properties, "TimeFlowRttNs")
+	assert.Contains(schema.Properties, "SrcK8S_Zone")
+	assert.Contains(schema.Properties, "DstK8S_Zone")
+	assert.Contains(schema.Properties, "K8S_ClusterName")
+	assert.NotEqual(baseVersion, schema.Version)
+}
@@ -17,12 +17,21 @@ import (
 type Client struct {
 	client.Client
 	SetControllerReference func(client.Object) error
+	metadata               commonMetadata
+	// manualRBACNamespace is the namespace where cluster-scoped RBAC manifests are written, when not managed by the operator
+	manualRBACNamespace string
+	// pendingRBAC lists the cluster-scoped RBAC objects that are not applied yet, in manual mode
+	pendingRBAC *[]string
+	// onWrite and onDelete, when set, are called with the objects written as returned by the API server, and with the deleted objects
+	onWrite  func(client.Object)
+	onDelete func(client.Object)
 }
 
 func UnmanagedClient(cl client.Client) Client {
 	return Client{
 		Client:                 cl,
 		SetControllerReference: func(_ client.Object) error { return nil },
+		pendingRBAC:            &[]string{},
 	}
 }
 
@@ -31,17 +40,70 @@ func NewFlowCollectorClientHelper(ctx context.Context, c client.Client) (*Client
 	if err != nil || fc == nil {
 		return nil, fc, err
 	}
+	manualRBACNamespace := ""
+	if IsManualClusterRBAC(&fc.Spec) {
+		manualRBACNamespace = GetNamespace(&fc.Spec)
+	}
 	return &Client{
 		Client: c,
 		SetControllerReference: func(obj client.Object) error {
 			return controllerutil.SetControllerReference(fc, obj, c.Scheme())
 		},
+		metadata:            newCommonMetadata(fc),
+		manualRBACNamespace: manualRBACNamespace,
+		pendingRBAC:         &[]string{},
 	}, fc, nil
 }
 
+// WithObjectsTracking returns a copy of the client that reports the objects it creates, updates or deletes
+func (c Client) WithObjectsTracking(onWrite, onDelete func(client.Object)) Client {
+	c.onWrite = onWrite
+	c.onDelete = onDelete
+	return c
+}
+
+// Delete deletes an object, and reports it as deleted when tracking is enabled, including when it was already gone
+func (c Client) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	if c.onDelete != nil && client.IgnoreNotFound(err) == nil {
+		c.onDelete(obj)
+	}
+	return err
+}
+
+// CommonMetadataChanged returns true when the labels and annotations that are set on every owned object, such as the
+// Kubernetes recommended labels, differ between the actual and the desired objects
+func (c *Client) CommonMetadataChanged(actual, desired client.Object) bool {
+	return c.metadata.changed(actual, desired)
+}
+
+// ManualClusterRBAC returns the namespace where the manifests of cluster-scoped RBAC objects must be written, when these
+// objects are not managed by the operator
+func (c *Client) ManualClusterRBAC() (string, bool) {
+	return c.manualRBACNamespace, c.manualRBACNamespace != ""
+}
+
+// WithOwnPendingClusterRBAC returns a copy of the client recording its own pending cluster-scoped RBAC objects, so that
+// components reconciled concurrently don't report each other's
+func (c Client) WithOwnPendingClusterRBAC() Client {
+	c.pendingRBAC = &[]string{}
+	return c
+}
+
+// AddPendingClusterRBAC records a cluster-scoped RBAC object that an administrator must apply
+func (c *Client) AddPendingClusterRBAC(name string) {
+	*c.pendingRBAC = append(*c.pendingRBAC, name)
+}
+
+// PendingClusterRBAC returns the cluster-scoped RBAC objects recorded since the client creation
+func (c *Client) PendingClusterRBAC() []string {
+	return *c.pendingRBAC
+}
+
 // CreateOwned is an helper function that creates an object, sets owner reference and writes info & errors logs
 func (c *Client) CreateOwned(ctx context.Context, obj client.Object) error {
 	log := log.FromContext(ctx)
+	c.metadata.apply(obj)
 	err := c.SetControllerReference(obj)
 	if err != nil {
 		log.Error(err, "Failed to set controller reference")
@@ -54,6 +116,9 @@ func (c *Client) CreateOwned(ctx context.Context, obj client.Object) error {
 		log.Error(err, "Failed to create new "+kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName())
 		return err
 	}
+	if c.onWrite != nil {
+		c.onWrite(obj)
+	}
 	return nil
 }
 
@@ -63,6 +128,7 @@ func (c *Client) UpdateOwned(ctx context.Context, old, obj client.Object) error
 	if old != nil {
 		obj.SetResourceVersion(old.GetResourceVersion())
 	}
+	c.metadata.apply(obj)
 	err := c.SetControllerReference(obj)
 	if err != nil {
 		log.Error(err, "Failed to set controller reference")
@@ -75,6 +141,10 @@ func (c *Client) UpdateOwned(ctx context.Context, old, obj client.Object) error
 		log.Error(err, "Failed to update "+kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName())
 		return err
 	}
+	// reported from the API server response, as the cache might not be synced yet
+	if c.onWrite != nil {
+		c.onWrite(obj)
+	}
 	err = c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
 	if err != nil {
 		log.Error(err, "Failed to get updated resource "+kind, "Namespace", obj.GetNamespace(), "Name", obj.GetName())
@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// staleCacheClient bumps the generation on writes, but its reads return the object as first created, like a cache not synced yet
+type staleCacheClient struct {
+	client.Client
+	created map[string]client.Object
+}
+
+func (c *staleCacheClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	stored, ok := c.created[key.Name]
+	if !ok {
+		return kerr.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	obj.SetGeneration(stored.GetGeneration())
+	return nil
+}
+
+func (c *staleCacheClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	obj.SetGeneration(1)
+	c.created[obj.GetName()] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
+
+func (c *staleCacheClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	obj.SetGeneration(obj.GetGeneration() + 1)
+	return nil
+}
+
+func (c *staleCacheClient) Delete(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	if _, ok := c.created[obj.GetName()]; !ok {
+		return kerr.NewNotFound(schema.GroupResource{}, obj.GetName())
+	}
+	delete(c.created, obj.GetName())
+	return nil
+}
+
+func TestObjectsTracking(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	written := map[string]int64{}
+	var deleted []string
+	cl := UnmanagedClient(&staleCacheClient{created: map[string]client.Object{}}).WithObjectsTracking(
+		func(obj client.Object) { written[obj.GetName()] = obj.GetGeneration() },
+		func(obj client.Object) { deleted = append(deleted, obj.GetName()) },
+	)
+
+	// cluster-scoped objects are tracked as well
+	crb := rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "netobserv-reader"}}
+	assert.NoError(cl.CreateOwned(ctx, &crb))
+	assert.Equal(map[string]int64{"netobserv-reader": 1}, written)
+
+	// the update is reported from the API server response, not from the stale cache
+	updated := crb.DeepCopy()
+	updated.Subjects = []rbacv1.Subject{{Kind: "ServiceAccount", Name: "flowlogs-pipeline"}}
+	assert.NoError(cl.UpdateOwned(ctx, &crb, updated))
+	assert.Equal(map[string]int64{"netobserv-reader": 2}, written)
+
+	// deleted objects are reported, including when they were already gone
+	assert.NoError(cl.Delete(ctx, &crb))
+	assert.True(kerr.IsNotFound(cl.Delete(ctx, &crb)))
+	assert.Equal([]string{"netobserv-reader", "netobserv-reader"}, deleted)
+}
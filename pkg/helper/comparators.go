@@ -9,6 +9,8 @@ import (
 	ascv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
@@ -38,6 +40,7 @@ func DaemonSetChanged(current, desired *appsv1.DaemonSet) ReconcileAction {
 	if !IsSubSet(current.ObjectMeta.Labels, desired.ObjectMeta.Labels) ||
 		!deepDerivative(dSpec.Selector, cSpec.Selector) ||
 		!deepDerivative(dSpec.Template, cSpec.Template) ||
+		daemonSetUpdateStrategyChanged(&cSpec.UpdateStrategy, &dSpec.UpdateStrategy) ||
 		assignationChanged(&cSpec.Template, &dSpec.Template, nil) {
 		return ActionUpdate
 	}
@@ -54,7 +57,57 @@ func DaemonSetChanged(current, desired *appsv1.DaemonSet) ReconcileAction {
 
 func DeploymentChanged(old, new *appsv1.Deployment, contName string, checkReplicas bool, desiredReplicas int32, report *ChangeReport) bool {
 	return report.Check("Pod changed", PodChanged(&old.Spec.Template, &new.Spec.Template, contName, report)) ||
-		report.Check("Replicas changed", (checkReplicas && *old.Spec.Replicas != desiredReplicas))
+		report.Check("Replicas changed", (checkReplicas && *old.Spec.Replicas != desiredReplicas)) ||
+		report.Check("Strategy changed", !deepEqual(defaultedDeploymentStrategy(&new.Spec.Strategy), defaultedDeploymentStrategy(&old.Spec.Strategy)))
+}
+
+// DaemonSetUpdateStrategyChanged compares the strategies defaulted as by the cluster, so that removing a custom strategy restores the default one
+func DaemonSetUpdateStrategyChanged(old, new *appsv1.DaemonSet, report *ChangeReport) bool {
+	return report.Check("Update strategy changed", daemonSetUpdateStrategyChanged(&old.Spec.UpdateStrategy, &new.Spec.UpdateStrategy))
+}
+
+func daemonSetUpdateStrategyChanged(old, new *appsv1.DaemonSetUpdateStrategy) bool {
+	return !deepEqual(defaultedDaemonSetUpdateStrategy(new), defaultedDaemonSetUpdateStrategy(old))
+}
+
+// defaultedDeploymentStrategy fills the unset fields with the Kubernetes defaults: RollingUpdate, with 25% max unavailable and 25% max surge
+func defaultedDeploymentStrategy(in *appsv1.DeploymentStrategy) *appsv1.DeploymentStrategy {
+	out := in.DeepCopy()
+	if out.Type == "" {
+		out.Type = appsv1.RollingUpdateDeploymentStrategyType
+	}
+	if out.Type == appsv1.RollingUpdateDeploymentStrategyType {
+		if out.RollingUpdate == nil {
+			out.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+		}
+		if out.RollingUpdate.MaxUnavailable == nil {
+			out.RollingUpdate.MaxUnavailable = ptr.To(intstr.FromString("25%"))
+		}
+		if out.RollingUpdate.MaxSurge == nil {
+			out.RollingUpdate.MaxSurge = ptr.To(intstr.FromString("25%"))
+		}
+	}
+	return out
+}
+
+// defaultedDaemonSetUpdateStrategy fills the unset fields with the Kubernetes defaults: RollingUpdate, with 1 max unavailable and no surge
+func defaultedDaemonSetUpdateStrategy(in *appsv1.DaemonSetUpdateStrategy) *appsv1.DaemonSetUpdateStrategy {
+	out := in.DeepCopy()
+	if out.Type == "" {
+		out.Type = appsv1.RollingUpdateDaemonSetStrategyType
+	}
+	if out.Type == appsv1.RollingUpdateDaemonSetStrategyType {
+		if out.RollingUpdate == nil {
+			out.RollingUpdate = &appsv1.RollingUpdateDaemonSet{}
+		}
+		if out.RollingUpdate.MaxUnavailable == nil {
+			out.RollingUpdate.MaxUnavailable = ptr.To(intstr.FromInt32(1))
+		}
+		if out.RollingUpdate.MaxSurge == nil {
+			out.RollingUpdate.MaxSurge = ptr.To(intstr.FromInt32(0))
+		}
+	}
+	return out
 }
 
 func PodChanged(old, new *corev1.PodTemplateSpec, containerName string, report *ChangeReport) bool {
@@ -125,13 +178,23 @@ func containerChanged(old, new *corev1.Container, report *ChangeReport) bool {
 	return report.Check("Image changed", new.Image != old.Image) ||
 		report.Check("Pull policy changed", new.ImagePullPolicy != old.ImagePullPolicy) ||
 		report.Check("Args changed", !deepDerivative(new.Args, old.Args)) ||
+		report.Check("Env changed", !deepDerivative(new.Env, old.Env)) ||
 		report.Check("Resources req/limit changed", !deepDerivative(new.Resources, old.Resources)) ||
 		report.Check("Liveness probe changed", probeChanged(new.LivenessProbe, old.LivenessProbe)) ||
+		report.Check("Readiness probe changed", probeChanged(new.ReadinessProbe, old.ReadinessProbe)) ||
 		report.Check("Startup probe changed", probeChanged(new.StartupProbe, old.StartupProbe))
 }
 
-func probeChanged(old, new *corev1.Probe) bool {
-	return (old == nil && new != nil) || (old != nil && new == nil)
+// probeChanged compares the presence and timing of probes. Handlers aren't compared, as the API server sets their defaults,
+// and timing values must be fully set in desired probes.
+func probeChanged(new, old *corev1.Probe) bool {
+	if new == nil || old == nil {
+		return new != old
+	}
+	return new.InitialDelaySeconds != old.InitialDelaySeconds ||
+		new.PeriodSeconds != old.PeriodSeconds ||
+		new.TimeoutSeconds != old.TimeoutSeconds ||
+		new.FailureThreshold != old.FailureThreshold
 }
 
 func ServiceChanged(old, new *corev1.Service, report *ChangeReport) bool {
@@ -172,5 +235,9 @@ func AutoScalerChanged(asc *ascv2.HorizontalPodAutoscaler, desired flowslatest.F
 	if report.Check("Metrics changed", !deepDerivative(desired.Metrics, asc.Spec.Metrics)) {
 		return true
 	}
+	// scaling rules omitted in the desired behavior are defaulted by the API server
+	if report.Check("Behavior changed", !deepDerivative(desired.Behavior, asc.Spec.Behavior)) {
+		return true
+	}
 	return false
 }
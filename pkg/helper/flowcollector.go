@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/netobserv/flowlogs-pipeline/pkg/api"
@@ -22,6 +23,11 @@ func UseKafka(spec *flowslatest.FlowCollectorSpec) bool {
 	return spec.DeploymentModel == flowslatest.DeploymentModelKafka
 }
 
+// UseKafkaSource returns true when flows are consumed from an external Kafka topic, in addition to the agents direct flows
+func UseKafkaSource(spec *flowslatest.FlowCollectorSpec) bool {
+	return !UseKafka(spec) && spec.Processor.KafkaSource != nil
+}
+
 func HasKafkaExporter(spec *flowslatest.FlowCollectorSpec) bool {
 	for _, ex := range spec.Exporters {
 		if ex.Type == flowslatest.KafkaExporter {
@@ -31,6 +37,16 @@ func HasKafkaExporter(spec *flowslatest.FlowCollectorSpec) bool {
 	return false
 }
 
+// GetClusterLogForwarderExporter returns the ClusterLogForwarder that flows are handed to, if any
+func GetClusterLogForwarderExporter(spec *flowslatest.FlowCollectorSpec) *flowslatest.FlowCollectorLogForwarderExporter {
+	for _, ex := range spec.Exporters {
+		if ex.Type == flowslatest.ClusterLogForwarderExporter {
+			return &ex.ClusterLogForwarder
+		}
+	}
+	return nil
+}
+
 func HPAEnabled(spec *flowslatest.FlowCollectorHPA) bool {
 	return spec != nil && spec.Status == flowslatest.HPAStatusEnabled
 }
@@ -60,6 +76,11 @@ func GetRecordTypes(processor *flowslatest.FlowCollectorFLP) []api.ConnTrackOutp
 	return []api.ConnTrackOutputRecordTypeEnum{api.ConnTrackFlowLog}
 }
 
+// HasEndedConversations returns true when flowlogs-pipeline emits a record for each ended conversation
+func HasEndedConversations(processor *flowslatest.FlowCollectorFLP) bool {
+	return slices.Contains(GetRecordTypes(processor), api.ConnTrackEndConnection)
+}
+
 func UseSASL(cfg *flowslatest.SASLConfig) bool {
 	return cfg.Type == flowslatest.SASLPlain || cfg.Type == flowslatest.SASLScramSHA512
 }
@@ -69,12 +90,55 @@ func UseLoki(spec *flowslatest.FlowCollectorSpec) bool {
 	return spec.Loki.Enable == nil || *spec.Loki.Enable
 }
 
+func IsLokiAggregationEnabled(spec *flowslatest.FlowCollectorLoki) bool {
+	return spec.Aggregation != nil && spec.Aggregation.Enable != nil && *spec.Aggregation.Enable
+}
+
+// GetLokiRecordTypes returns the types of the records written to Loki, which are the aggregated records when the Loki aggregation is enabled
+func GetLokiRecordTypes(spec *flowslatest.FlowCollectorSpec) []api.ConnTrackOutputRecordTypeEnum {
+	if IsLokiAggregationEnabled(&spec.Loki) {
+		return []api.ConnTrackOutputRecordTypeEnum{api.ConnTrackHeartbeat, api.ConnTrackEndConnection}
+	}
+	return GetRecordTypes(&spec.Processor)
+}
+
+func IsAnomalyDetectionEnabled(spec *flowslatest.FlowCollectorAnalytics) bool {
+	return spec.AnomalyDetection.Enable != nil && *spec.AnomalyDetection.Enable
+}
+
+func IsSecurityAnalyticsEnabled(spec *flowslatest.FlowCollectorAnalytics) bool {
+	return spec.Security.Enable != nil && *spec.Security.Enable
+}
+
+func IsLongTermAggregatesEnabled(spec *flowslatest.FlowCollectorRetentionPolicy) bool {
+	return spec.LongTermAggregates != nil && *spec.LongTermAggregates
+}
+
+func IsMetricsRBACProxyEnabled(spec *flowslatest.FLPMetrics) bool {
+	return spec.RBACProxy.Enable != nil && *spec.RBACProxy.Enable
+}
+
+func IsDeveloperPerspectiveEnabled(spec *flowslatest.FlowCollectorConsolePlugin) bool {
+	return spec.DeveloperPerspective.Enable != nil && *spec.DeveloperPerspective.Enable
+}
+
+func UseHostedProfile(spec *flowslatest.FlowCollectorSpec) bool {
+	return spec.Hosted.Profile != "" && spec.Hosted.Profile != flowslatest.HostedProfileDisabled
+}
+
 func UseConsolePlugin(spec *flowslatest.FlowCollectorSpec) bool {
 	return UseLoki(spec) &&
+		// hosted services come with their own UI
+		!UseHostedProfile(spec) &&
 		// nil should fallback to default value, which is "true"
 		(spec.ConsolePlugin.Enable == nil || *spec.ConsolePlugin.Enable)
 }
 
+// UseQueryService returns true when the flow query service must be deployed: it reads flows from Loki, like the console plugin
+func UseQueryService(spec *flowslatest.FlowCollectorSpec) bool {
+	return UseLoki(spec) && spec.QueryService.Enable != nil && *spec.QueryService.Enable
+}
+
 func IsAgentFeatureEnabled(spec *flowslatest.FlowCollectorEBPF, feature flowslatest.AgentFeature) bool {
 	for _, f := range spec.Features {
 		if f == feature {
@@ -103,10 +167,63 @@ func IsFlowRTTEnabled(spec *flowslatest.FlowCollectorEBPF) bool {
 	return IsAgentFeatureEnabled(spec, flowslatest.FlowRTT)
 }
 
+func IsOVNObservabilityEnabled(spec *flowslatest.FlowCollectorAgent) bool {
+	return IsPrivileged(&spec.EBPF) && spec.OVN.Enable != nil && *spec.OVN.Enable
+}
+
+func IsLoadGeneratorEnabled(spec *flowslatest.FlowCollectorLoadGenerator) bool {
+	return spec.Enable != nil && *spec.Enable
+}
+
+func IsExternalIngestEnabled(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.ExternalIngest.Enable != nil && *spec.ExternalIngest.Enable
+}
+
+func IsVirtualizationEnabled(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.Virtualization.Enable != nil && *spec.Virtualization.Enable
+}
+
+func IsServiceMeshEnabled(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.ServiceMesh.Enable != nil && *spec.ServiceMesh.Enable
+}
+
+func IsDSCPClassificationEnabled(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.DSCPClassification.Enable != nil && *spec.DSCPClassification.Enable
+}
+
+func IsMultiHomingEnabled(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.MultiHoming.Enable != nil && *spec.MultiHoming.Enable
+}
+
+func IsEgressClassificationEnabled(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.EgressClassification.Enable != nil && *spec.EgressClassification.Enable
+}
+
+// GetEgressCostPerGB returns the price of the internet egress per gigabyte, as a PromQL number
+func GetEgressCostPerGB(spec *flowslatest.FlowCollectorFLP) string {
+	if spec.EgressClassification.CostPerGB == "" {
+		return "0.09"
+	}
+	return spec.EgressClassification.CostPerGB
+}
+
 func IsMultiClusterEnabled(spec *flowslatest.FlowCollectorFLP) bool {
 	return spec.MultiClusterDeployment != nil && *spec.MultiClusterDeployment
 }
 
+// GetFlowMetricsTier returns which Prometheus scrapes the flow metrics
+func GetFlowMetricsTier(spec *flowslatest.FLPMetrics) flowslatest.FlowMetricsTier {
+	if spec.Routing.FlowMetrics == "" {
+		return flowslatest.FlowMetricsPlatform
+	}
+	return spec.Routing.FlowMetrics
+}
+
+// IsFlowMetricsTiered returns true when flow metrics and health metrics are scraped by different Prometheus instances
+func IsFlowMetricsTiered(spec *flowslatest.FLPMetrics) bool {
+	return GetFlowMetricsTier(spec) != flowslatest.FlowMetricsPlatform
+}
+
 func IsZoneEnabled(spec *flowslatest.FlowCollectorFLP) bool {
 	return spec.AddZone != nil && *spec.AddZone
 }
@@ -123,6 +240,18 @@ func IsEBFPFlowFilterEnabled(spec *flowslatest.FlowCollectorEBPF) bool {
 	return spec.FlowFilter != nil && spec.FlowFilter.Enable != nil && *spec.FlowFilter.Enable
 }
 
+func IsAnonymizationEnabled(spec *flowslatest.Anonymization) bool {
+	return spec != nil &&
+		(spec.IPs == flowslatest.AnonymizationIPTruncate || spec.IPs == flowslatest.AnonymizationIPDrop || spec.DropMACs || spec.DropPodNames)
+}
+
+func GetAnonymizationPrefixLength(spec *flowslatest.Anonymization) int32 {
+	if spec.TruncatePrefixLength == nil {
+		return 24
+	}
+	return *spec.TruncatePrefixLength
+}
+
 func PtrBool(b *bool) bool {
 	if b == nil {
 		return false
@@ -149,6 +278,10 @@ func GetNamespace(spec *flowslatest.FlowCollectorSpec) string {
 	return constants.DefaultOperatorNamespace
 }
 
+func IsManualClusterRBAC(spec *flowslatest.FlowCollectorSpec) bool {
+	return spec.ClusterRBAC.Mode == flowslatest.ClusterRBACManual
+}
+
 func GetAdvancedAgentConfig(specConfig *flowslatest.AdvancedAgentConfig) flowslatest.AdvancedAgentConfig {
 	cfg := flowslatest.AdvancedAgentConfig{
 		Env: map[string]string{},
@@ -178,6 +311,12 @@ func GetAdvancedAgentConfig(specConfig *flowslatest.AdvancedAgentConfig) flowsla
 				cfg.Scheduling.PriorityClassName = specConfig.Scheduling.PriorityClassName
 			}
 		}
+		if specConfig.UpdateStrategy != nil {
+			cfg.UpdateStrategy = specConfig.UpdateStrategy
+		}
+		if specConfig.GoRuntime != nil {
+			cfg.GoRuntime = specConfig.GoRuntime
+		}
 	}
 
 	return cfg
@@ -244,6 +383,15 @@ func GetAdvancedProcessorConfig(specConfig *flowslatest.AdvancedProcessorConfig)
 				cfg.Scheduling.PriorityClassName = specConfig.Scheduling.PriorityClassName
 			}
 		}
+		if specConfig.UpdateStrategy != nil {
+			cfg.UpdateStrategy = specConfig.UpdateStrategy
+		}
+		if specConfig.DeploymentStrategy != nil {
+			cfg.DeploymentStrategy = specConfig.DeploymentStrategy
+		}
+		cfg.Probes = specConfig.Probes
+		cfg.GoRuntime = specConfig.GoRuntime
+		cfg.OverridesConfigMap = specConfig.OverridesConfigMap
 	}
 
 	return cfg
@@ -302,6 +450,10 @@ func GetAdvancedPluginConfig(specConfig *flowslatest.AdvancedPluginConfig) flows
 		if specConfig.Port != nil && *specConfig.Port > 0 {
 			cfg.Port = specConfig.Port
 		}
+		if len(specConfig.ServiceAnnotations) > 0 {
+			cfg.ServiceAnnotations = specConfig.ServiceAnnotations
+		}
+		cfg.ServingCert = specConfig.ServingCert
 		if specConfig.Scheduling != nil {
 			if len(specConfig.Scheduling.NodeSelector) > 0 {
 				cfg.Scheduling.NodeSelector = specConfig.Scheduling.NodeSelector
@@ -316,6 +468,11 @@ func GetAdvancedPluginConfig(specConfig *flowslatest.AdvancedPluginConfig) flows
 				cfg.Scheduling.PriorityClassName = specConfig.Scheduling.PriorityClassName
 			}
 		}
+		if specConfig.DeploymentStrategy != nil {
+			cfg.DeploymentStrategy = specConfig.DeploymentStrategy
+		}
+		cfg.Probes = specConfig.Probes
+		cfg.OverridesConfigMap = specConfig.OverridesConfigMap
 	}
 
 	return cfg
@@ -324,3 +481,15 @@ func GetAdvancedPluginConfig(specConfig *flowslatest.AdvancedPluginConfig) flows
 func AutoDetectOpenShiftNetworks(spec *flowslatest.FlowCollectorFLP) bool {
 	return spec.SubnetLabels.OpenShiftAutoDetect == nil || *spec.SubnetLabels.OpenShiftAutoDetect
 }
+
+func IsClusterNetworkOperatorManaged(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.ClusterNetworkOperatorManaged == nil || *spec.ClusterNetworkOperatorManaged
+}
+
+func IsBatchAutoTuningEnabled(spec *flowslatest.FlowCollectorFLP) bool {
+	return spec.BatchAutoTuning.Enable != nil && *spec.BatchAutoTuning.Enable
+}
+
+func IsPipelineHealthCheckEnabled(spec *flowslatest.FLPMetrics) bool {
+	return spec.PipelineHealthCheck.Enable != nil && *spec.PipelineHealthCheck.Enable
+}
@@ -0,0 +1,37 @@
+package helper
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+const (
+	envGoMemLimit             = "GOMEMLIMIT"
+	envGoGC                   = "GOGC"
+	defaultMemoryLimitPercent = 90
+)
+
+// GoRuntimeEnv returns the GOMEMLIMIT and GOGC env vars for a container, from the Go runtime configuration and the container memory limit.
+// Variables already set in env are skipped, so that they take precedence.
+func GoRuntimeEnv(cfg *flowslatest.GoRuntimeConfig, resources *corev1.ResourceRequirements, env map[string]string) []corev1.EnvVar {
+	var vars []corev1.EnvVar
+	percent := int32(defaultMemoryLimitPercent)
+	if cfg != nil && cfg.MemoryLimitPercent != nil {
+		percent = *cfg.MemoryLimitPercent
+	}
+	if _, set := env[envGoMemLimit]; !set && percent > 0 {
+		// GOMEMLIMIT is a soft memory cap, forcing GC when the memory limit is close, to prevent OOM kills.
+		// The remaining headroom accounts for memory sources the Go runtime is unaware of.
+		if memLimit, ok := resources.Limits.Memory().AsInt64(); ok && memLimit > 0 {
+			memLimit -= int64(float64(memLimit) * float64(100-percent) / 100)
+			vars = append(vars, corev1.EnvVar{Name: envGoMemLimit, Value: fmt.Sprint(memLimit)})
+		}
+	}
+	if _, set := env[envGoGC]; !set && cfg != nil && cfg.GCPercent != nil {
+		vars = append(vars, corev1.EnvVar{Name: envGoGC, Value: fmt.Sprint(*cfg.GCPercent)})
+	}
+	return vars
+}
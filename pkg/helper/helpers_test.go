@@ -4,9 +4,14 @@ import (
 	"testing"
 	"time"
 
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
 )
 
@@ -156,3 +161,110 @@ func TestCRDDefault(t *testing.T) {
 	assert.Equal(t, "app: netobserv-flowcollector\n", GetFieldDefaultString([]string{"spec", "processor", "debug"}, "lokiStaticLabels"))
 
 }
+
+func TestProxyConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	cluster := configv1.Proxy{
+		Status: configv1.ProxyStatus{
+			HTTPProxy:  "http://cluster-proxy:3128",
+			HTTPSProxy: "http://cluster-proxy:3128",
+			NoProxy:    ".cluster.local,.svc,10.0.0.0/16",
+		},
+	}
+
+	// Auto mode, no cluster-wide proxy
+	cfg := NewProxyConfig(&flowslatest.FlowCollectorProxy{}, nil)
+	assert.False(cfg.IsEnabled())
+	assert.Empty(cfg.EnvVars())
+
+	// Auto mode, cluster-wide proxy with override
+	cfg = NewProxyConfig(&flowslatest.FlowCollectorProxy{HTTPSProxy: "http://my-proxy:3128"}, &cluster)
+	assert.Equal([]corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: "http://cluster-proxy:3128"},
+		{Name: "HTTPS_PROXY", Value: "http://my-proxy:3128"},
+		{Name: "NO_PROXY", Value: ".cluster.local,.svc,10.0.0.0/16"},
+	}, cfg.EnvVars())
+
+	// Manual mode ignores cluster-wide proxy
+	cfg = NewProxyConfig(&flowslatest.FlowCollectorProxy{Mode: flowslatest.ProxyModeManual, HTTPSProxy: "http://my-proxy:3128", NoProxy: ".svc"}, &cluster)
+	assert.Equal([]corev1.EnvVar{
+		{Name: "HTTPS_PROXY", Value: "http://my-proxy:3128"},
+		{Name: "NO_PROXY", Value: ".svc"},
+	}, cfg.EnvVars())
+
+	// Disabled
+	cfg = NewProxyConfig(&flowslatest.FlowCollectorProxy{Mode: flowslatest.ProxyModeDisabled, HTTPSProxy: "http://my-proxy:3128"}, &cluster)
+	assert.False(cfg.IsEnabled())
+
+	// Trusted CA bundle injection defaults to the cluster-wide proxy trusted CA
+	assert.False(IsTrustedCABundleEnabled(&flowslatest.FlowCollectorProxy{}, nil))
+	assert.False(IsTrustedCABundleEnabled(&flowslatest.FlowCollectorProxy{}, &cluster))
+	assert.True(IsTrustedCABundleEnabled(&flowslatest.FlowCollectorProxy{InjectTrustedCABundle: ptr.To(true)}, &cluster))
+	cluster.Spec.TrustedCA.Name = "user-ca-bundle"
+	assert.True(IsTrustedCABundleEnabled(&flowslatest.FlowCollectorProxy{}, &cluster))
+	assert.False(IsTrustedCABundleEnabled(&flowslatest.FlowCollectorProxy{InjectTrustedCABundle: ptr.To(false)}, &cluster))
+}
+
+func TestHostedProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{
+		Loki: flowslatest.FlowCollectorLoki{
+			Mode:       flowslatest.LokiModeMonolithic,
+			Monolithic: flowslatest.LokiMonolithParams{URL: "http://loki:3100/", TenantID: "netobserv"},
+		},
+	}
+	assert.True(UseConsolePlugin(&spec))
+	assert.Equal("http://loki:3100/", GetLokiConfig(&spec, "netobserv").IngesterURL)
+
+	spec.Hosted = flowslatest.FlowCollectorHosted{
+		Profile: flowslatest.HostedProfileGrafanaCloud,
+		GrafanaCloud: flowslatest.HostedGrafanaCloud{
+			LogsURL:  "https://logs-prod-eu-west-0.grafana.net",
+			LogsUser: "123456",
+			APIKey:   flowslatest.FileReference{Type: flowslatest.RefTypeSecret, Name: "grafana-cloud", File: "token"},
+		},
+	}
+	assert.False(UseConsolePlugin(&spec))
+	loki := GetLokiConfig(&spec, "netobserv")
+	assert.Equal("https://logs-prod-eu-west-0.grafana.net", loki.IngesterURL)
+	assert.Empty(loki.TenantID)
+	assert.Equal("123456", loki.BasicAuth.Username)
+	assert.Equal([]flowslatest.FileReference{spec.Hosted.GrafanaCloud.APIKey}, loki.CredentialsFiles())
+}
+
+func TestAdvancedConfigOverridesConfigMap(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(GetAdvancedProcessorConfig(nil).OverridesConfigMap)
+	assert.Equal("flp-overrides", GetAdvancedProcessorConfig(&flowslatest.AdvancedProcessorConfig{OverridesConfigMap: "flp-overrides"}).OverridesConfigMap)
+	assert.Equal("plugin-overrides", GetAdvancedPluginConfig(&flowslatest.AdvancedPluginConfig{OverridesConfigMap: "plugin-overrides"}).OverridesConfigMap)
+}
+
+func TestGoRuntimeEnv(t *testing.T) {
+	assert := assert.New(t)
+
+	resources := corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("800Mi")}}
+
+	// by default, GOMEMLIMIT is set to 90% of the memory limit
+	env := GoRuntimeEnv(nil, &resources, nil)
+	assert.Equal([]corev1.EnvVar{{Name: "GOMEMLIMIT", Value: "754974720"}}, env)
+
+	// no memory limit
+	env = GoRuntimeEnv(nil, &corev1.ResourceRequirements{}, nil)
+	assert.Empty(env)
+
+	cfg := flowslatest.GoRuntimeConfig{MemoryLimitPercent: ptr.To(int32(80)), GCPercent: ptr.To(int32(50))}
+	env = GoRuntimeEnv(&cfg, &resources, nil)
+	assert.Equal([]corev1.EnvVar{{Name: "GOMEMLIMIT", Value: "671088640"}, {Name: "GOGC", Value: "50"}}, env)
+
+	// disabled GOMEMLIMIT
+	cfg.MemoryLimitPercent = ptr.To(int32(0))
+	env = GoRuntimeEnv(&cfg, &resources, nil)
+	assert.Equal([]corev1.EnvVar{{Name: "GOGC", Value: "50"}}, env)
+
+	// explicit env vars take precedence
+	env = GoRuntimeEnv(nil, &resources, map[string]string{"GOMEMLIMIT": "1GiB"})
+	assert.Empty(env)
+}
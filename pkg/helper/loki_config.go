@@ -76,6 +76,56 @@ func NewLokiConfig(spec *flowslatest.FlowCollectorLoki, namespace string) LokiCo
 	return loki
 }
 
+// GetLokiConfig returns the Loki connection parameters, derived from the hosted profile when one is set
+func GetLokiConfig(spec *flowslatest.FlowCollectorSpec, namespace string) LokiConfig {
+	if spec.Hosted.Profile == flowslatest.HostedProfileGrafanaCloud {
+		gc := &spec.Hosted.GrafanaCloud
+		return LokiConfig{
+			LokiManualParams: flowslatest.LokiManualParams{
+				QuerierURL:  gc.LogsURL,
+				IngesterURL: gc.LogsURL,
+				StatusURL:   gc.LogsURL,
+				AuthToken:   flowslatest.LokiAuthDisabled,
+				BasicAuth: &flowslatest.LokiBasicAuth{
+					Username: gc.LogsUser,
+					Password: gc.APIKey,
+				},
+			},
+		}
+	}
+	return NewLokiConfig(&spec.Loki, namespace)
+}
+
+// NewLokiExporterConfig returns the connection parameters of a Loki exporter
+func NewLokiExporterConfig(exp *flowslatest.FlowCollectorLokiExporter) LokiConfig {
+	return LokiConfig{
+		LokiManualParams: flowslatest.LokiManualParams{
+			IngesterURL:     exp.URL,
+			TenantID:        exp.TenantID,
+			AuthToken:       exp.AuthToken,
+			AuthTokenSecret: exp.AuthTokenSecret,
+			BasicAuth:       exp.BasicAuth,
+			TLS:             exp.TLS,
+		},
+	}
+}
+
+// NewLokiMigrationTargetConfig returns the connection parameters of the target Loki of a migration, or nil when no migration is configured
+func NewLokiMigrationTargetConfig(spec *flowslatest.FlowCollectorSpec) *LokiConfig {
+	if spec.Loki.Migration == nil {
+		return nil
+	}
+	return &LokiConfig{LokiManualParams: spec.Loki.Migration.Target}
+}
+
+// GetLokiReadConfig returns the connection parameters of the Loki queried by the console plugin: the target Loki when a migration reads from it, else the current Loki
+func GetLokiReadConfig(spec *flowslatest.FlowCollectorSpec, current *LokiConfig) *LokiConfig {
+	if target := NewLokiMigrationTargetConfig(spec); target != nil && spec.Loki.Migration.ReadFrom == flowslatest.LokiMigrationReadFromTarget {
+		return target
+	}
+	return current
+}
+
 func (l *LokiConfig) UseForwardToken() bool {
 	return l.LokiManualParams.AuthToken == flowslatest.LokiAuthForwardUserToken
 }
@@ -83,3 +133,19 @@ func (l *LokiConfig) UseForwardToken() bool {
 func (l *LokiConfig) UseHostToken() bool {
 	return l.LokiManualParams.AuthToken == flowslatest.LokiAuthUseHostToken
 }
+
+func (l *LokiConfig) UseSecretToken() bool {
+	return l.LokiManualParams.AuthToken == flowslatest.LokiAuthSecretToken && l.AuthTokenSecret != nil
+}
+
+// CredentialsFiles returns the references to the files holding the Loki credentials, such as tokens or passwords
+func (l *LokiConfig) CredentialsFiles() []flowslatest.FileReference {
+	var files []flowslatest.FileReference
+	if l.UseSecretToken() {
+		files = append(files, *l.AuthTokenSecret)
+	}
+	if l.BasicAuth != nil {
+		files = append(files, l.BasicAuth.Password)
+	}
+	return files
+}
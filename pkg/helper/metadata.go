@@ -0,0 +1,136 @@
+package helper
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+)
+
+// Kubernetes recommended labels, see https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/
+const (
+	LabelName      = "app.kubernetes.io/name"
+	LabelInstance  = "app.kubernetes.io/instance"
+	LabelVersion   = "app.kubernetes.io/version"
+	LabelComponent = "app.kubernetes.io/component"
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+
+	applicationName = "netobserv"
+	// componentLabel is the label that the operator sets on the objects of each component
+	componentLabel = "app"
+)
+
+var operatorVersion string
+
+// SetOperatorVersion sets the version reported in the `app.kubernetes.io/version` label
+func SetOperatorVersion(version string) {
+	if len(validation.IsValidLabelValue(version)) == 0 {
+		operatorVersion = version
+	}
+}
+
+// commonMetadata holds the labels and annotations set on every object managed by the operator
+type commonMetadata struct {
+	labels      map[string]string
+	annotations map[string]string
+}
+
+func newCommonMetadata(fc *flowslatest.FlowCollector) commonMetadata {
+	labels := map[string]string{}
+	for k, v := range fc.Spec.CommonLabels {
+		labels[k] = v
+	}
+	labels[LabelName] = applicationName
+	labels[LabelInstance] = fc.Name
+	labels[LabelManagedBy] = constants.OperatorName
+	if operatorVersion != "" {
+		labels[LabelVersion] = operatorVersion
+	}
+	return commonMetadata{labels: labels, annotations: fc.Spec.CommonAnnotations}
+}
+
+// expected returns the labels and annotations expected on an object: the component comes from its `app` label
+func (m *commonMetadata) expected(meta *metav1.ObjectMeta) (map[string]string, map[string]string) {
+	if len(m.labels) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(m.labels)+1)
+	for k, v := range m.labels {
+		labels[k] = v
+	}
+	if component := meta.Labels[componentLabel]; component != "" {
+		labels[LabelComponent] = component
+	}
+	return labels, m.annotations
+}
+
+// apply adds the missing common labels and annotations to an object, and to its pod template if any.
+// Existing labels and annotations are kept.
+func (m *commonMetadata) apply(obj client.Object) {
+	for _, meta := range objectMetas(obj) {
+		labels, annotations := m.expected(meta)
+		meta.Labels = mergeMissing(meta.Labels, labels)
+		meta.Annotations = mergeMissing(meta.Annotations, annotations)
+	}
+}
+
+// changed returns true when the common labels and annotations of an object differ from the desired ones. They are
+// compared to the desired object after merge, so that the labels set by the operator take precedence.
+func (m *commonMetadata) changed(actual, desired client.Object) bool {
+	m.apply(desired)
+	actualMetas, desiredMetas := objectMetas(actual), objectMetas(desired)
+	if len(actualMetas) != len(desiredMetas) {
+		return true
+	}
+	for i, meta := range desiredMetas {
+		labels, annotations := m.expected(meta)
+		if !sameValues(actualMetas[i].Labels, meta.Labels, labels) ||
+			!sameValues(actualMetas[i].Annotations, meta.Annotations, annotations) {
+			return true
+		}
+	}
+	return false
+}
+
+func objectMetas(obj client.Object) []*metav1.ObjectMeta {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return []*metav1.ObjectMeta{&o.ObjectMeta, &o.Spec.Template.ObjectMeta}
+	case *appsv1.DaemonSet:
+		return []*metav1.ObjectMeta{&o.ObjectMeta, &o.Spec.Template.ObjectMeta}
+	}
+	if meta, ok := obj.(metav1.ObjectMetaAccessor); ok {
+		if om, ok := meta.GetObjectMeta().(*metav1.ObjectMeta); ok {
+			return []*metav1.ObjectMeta{om}
+		}
+	}
+	return nil
+}
+
+func mergeMissing(current, added map[string]string) map[string]string {
+	if len(added) == 0 {
+		return current
+	}
+	if current == nil {
+		current = make(map[string]string, len(added))
+	}
+	for k, v := range added {
+		if _, ok := current[k]; !ok {
+			current[k] = v
+		}
+	}
+	return current
+}
+
+// sameValues returns true when both maps have the same values for the given keys
+func sameValues(actual, desired, keys map[string]string) bool {
+	for k := range keys {
+		if actual[k] != desired[k] {
+			return false
+		}
+	}
+	return true
+}
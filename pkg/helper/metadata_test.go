@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"testing"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommonMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	SetOperatorVersion("1.8.0")
+	defer SetOperatorVersion("")
+	m := newCommonMetadata(&flowslatest.FlowCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: flowslatest.FlowCollectorSpec{
+			CommonLabels:      map[string]string{"team": "netops", LabelName: "other"},
+			CommonAnnotations: map[string]string{"owner": "netops@example.com"},
+		},
+	})
+
+	ds := appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "netobserv-ebpf-agent", "team": "agents"}},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "netobserv-ebpf-agent"}},
+		}},
+	}
+	actual := ds.DeepCopy()
+	assert.True(m.changed(actual, &ds))
+	m.apply(actual)
+	assert.False(m.changed(actual, &ds))
+	assert.Equal(map[string]string{
+		"app":          "netobserv-ebpf-agent",
+		"team":         "agents",
+		LabelName:      "netobserv",
+		LabelInstance:  "cluster",
+		LabelVersion:   "1.8.0",
+		LabelComponent: "netobserv-ebpf-agent",
+		LabelManagedBy: "netobserv-operator",
+	}, ds.Labels)
+	assert.Equal("netobserv-ebpf-agent", ds.Spec.Template.Labels[LabelComponent])
+	assert.Equal("netops@example.com", ds.Spec.Template.Annotations["owner"])
+
+	cm := corev1.ConfigMap{}
+	m.apply(&cm)
+	assert.Equal(map[string]string{
+		"team":         "netops",
+		LabelName:      "netobserv",
+		LabelInstance:  "cluster",
+		LabelVersion:   "1.8.0",
+		LabelManagedBy: "netobserv-operator",
+	}, cm.Labels)
+	assert.Equal(map[string]string{"owner": "netops@example.com"}, cm.Annotations)
+
+	// a change of common labels is detected
+	m2 := newCommonMetadata(&flowslatest.FlowCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       flowslatest.FlowCollectorSpec{CommonLabels: map[string]string{"team": "sre"}},
+	})
+	assert.True(m2.changed(&cm, &corev1.ConfigMap{}))
+
+	// invalid label values are ignored
+	SetOperatorVersion("main (dirty)")
+	assert.Equal("1.8.0", operatorVersion)
+}
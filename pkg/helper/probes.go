@@ -0,0 +1,35 @@
+package helper
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+// WithProbeConfig returns the probe with the timing values set in the configuration
+func WithProbeConfig(probe *corev1.Probe, cfg *flowslatest.ProbeConfig) *corev1.Probe {
+	if cfg == nil {
+		return probe
+	}
+	if cfg.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *cfg.InitialDelaySeconds
+	}
+	if cfg.PeriodSeconds != nil {
+		probe.PeriodSeconds = *cfg.PeriodSeconds
+	}
+	if cfg.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *cfg.TimeoutSeconds
+	}
+	if cfg.FailureThreshold != nil {
+		probe.FailureThreshold = *cfg.FailureThreshold
+	}
+	return probe
+}
+
+// GetProbesConfig returns the probes configuration, never nil
+func GetProbesConfig(cfg *flowslatest.ProbesConfig) *flowslatest.ProbesConfig {
+	if cfg == nil {
+		return &flowslatest.ProbesConfig{}
+	}
+	return cfg
+}
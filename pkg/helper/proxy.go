@@ -0,0 +1,132 @@
+package helper
+
+import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+)
+
+const (
+	envHTTPProxy  = "HTTP_PROXY"
+	envHTTPSProxy = "HTTPS_PROXY"
+	envNoProxy    = "NO_PROXY"
+)
+
+// ProxyConfig holds the resolved HTTP(S) proxy settings to inject in the containers
+type ProxyConfig struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+	// TrustedCABundle is true when the cluster trusted CA bundle must be mounted in the containers
+	TrustedCABundle bool
+}
+
+// NewProxyConfig merges the FlowCollector proxy settings with the cluster-wide proxy, which can be nil
+func NewProxyConfig(spec *flowslatest.FlowCollectorProxy, cluster *configv1.Proxy) ProxyConfig {
+	cfg := ProxyConfig{}
+	switch spec.Mode {
+	case flowslatest.ProxyModeDisabled:
+		return cfg
+	case flowslatest.ProxyModeManual:
+	default:
+		// Auto (default) mode: the cluster-wide status contains the effective values, including the generated noProxy
+		if cluster != nil {
+			cfg.HTTPProxy = cluster.Status.HTTPProxy
+			cfg.HTTPSProxy = cluster.Status.HTTPSProxy
+			cfg.NoProxy = cluster.Status.NoProxy
+		}
+	}
+	if spec.HTTPProxy != "" {
+		cfg.HTTPProxy = spec.HTTPProxy
+	}
+	if spec.HTTPSProxy != "" {
+		cfg.HTTPSProxy = spec.HTTPSProxy
+	}
+	if spec.NoProxy != "" {
+		cfg.NoProxy = spec.NoProxy
+	}
+	return cfg
+}
+
+// FetchClusterProxy returns the OpenShift cluster-wide proxy, or nil when not found
+func FetchClusterProxy(ctx context.Context, cl client.Client) (*configv1.Proxy, error) {
+	proxy := configv1.Proxy{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: "cluster"}, &proxy); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &proxy, nil
+}
+
+// GetProxyConfig resolves the proxy configuration of the FlowCollector, reading the cluster-wide proxy on OpenShift in Auto mode
+func GetProxyConfig(ctx context.Context, cl client.Client, isOpenShift bool, spec *flowslatest.FlowCollectorProxy) ProxyConfig {
+	var clusterProxy *configv1.Proxy
+	// cluster-wide proxy api is specific to openshift; it is also read in other modes for its trusted CA
+	if isOpenShift {
+		var err error
+		clusterProxy, err = FetchClusterProxy(ctx, cl)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "unable to read the cluster-wide proxy")
+		}
+	}
+	cfg := NewProxyConfig(spec, clusterProxy)
+	// trusted CA bundle injection is specific to openshift
+	cfg.TrustedCABundle = isOpenShift && IsTrustedCABundleEnabled(spec, clusterProxy)
+	return cfg
+}
+
+func IsTrustedCABundleEnabled(spec *flowslatest.FlowCollectorProxy, cluster *configv1.Proxy) bool {
+	if spec.InjectTrustedCABundle == nil {
+		// nil should fallback to default value, which is enabled when the cluster-wide proxy defines a trusted CA
+		return cluster != nil && cluster.Spec.TrustedCA.Name != ""
+	}
+	return *spec.InjectTrustedCABundle
+}
+
+// WatchClusterProxy reconciles the FlowCollector again when the OpenShift cluster-wide proxy changes
+func WatchClusterProxy(b *builder.Builder) {
+	b.Watches(
+		&configv1.Proxy{},
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}),
+	)
+}
+
+func (p *ProxyConfig) IsEnabled() bool {
+	return p.HTTPProxy != "" || p.HTTPSProxy != ""
+}
+
+func (p *ProxyConfig) UseTrustedCABundle() bool {
+	return p != nil && p.TrustedCABundle
+}
+
+// EnvVars returns the proxy environment variables, or nil if no proxy is configured
+func (p *ProxyConfig) EnvVars() []corev1.EnvVar {
+	if p == nil || !p.IsEnabled() {
+		return nil
+	}
+	var envs []corev1.EnvVar
+	if p.HTTPProxy != "" {
+		envs = append(envs, corev1.EnvVar{Name: envHTTPProxy, Value: p.HTTPProxy})
+	}
+	if p.HTTPSProxy != "" {
+		envs = append(envs, corev1.EnvVar{Name: envHTTPSProxy, Value: p.HTTPSProxy})
+	}
+	if p.NoProxy != "" {
+		envs = append(envs, corev1.EnvVar{Name: envNoProxy, Value: p.NoProxy})
+	}
+	return envs
+}
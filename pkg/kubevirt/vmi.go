@@ -0,0 +1,108 @@
+package kubevirt
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+)
+
+//+kubebuilder:rbac:groups=kubevirt.io,resources=virtualmachineinstances,verbs=get;list;watch
+
+// VMIGVK is the kind of the running virtual machines managed by KubeVirt. It is read as unstructured,
+// to not depend on the KubeVirt API.
+var VMIGVK = schema.GroupVersionKind{Group: "kubevirt.io", Version: "v1", Kind: "VirtualMachineInstance"}
+
+// VirtualMachine is a running virtual machine, with the IP addresses of all its network interfaces.
+// A VirtualMachineInstance has the same name as the VirtualMachine it is created from.
+type VirtualMachine struct {
+	Name      string
+	Namespace string
+	IPs       []string
+}
+
+// WatchVirtualMachineInstances triggers a FlowCollector reconcile when the IP addresses of a virtual machine change
+func WatchVirtualMachineInstances(b *builder.Builder) {
+	vmi := unstructured.Unstructured{}
+	vmi.SetGroupVersionKind(VMIGVK)
+	b.Watches(
+		&vmi,
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}),
+		// the status of VirtualMachineInstances is often updated: ignore everything but interfaces changes
+		builder.WithPredicates(predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return !slices.Equal(interfaceIPs(e.ObjectOld), interfaceIPs(e.ObjectNew))
+			},
+		}),
+	)
+}
+
+// ListVirtualMachines returns the running virtual machines having at least one IP address, sorted to keep
+// the generated configuration stable
+func ListVirtualMachines(ctx context.Context, cl client.Reader) ([]VirtualMachine, error) {
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(VMIGVK.GroupVersion().WithKind(VMIGVK.Kind + "List"))
+	if err := cl.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	var vms []VirtualMachine
+	for i := range list.Items {
+		if ips := interfaceIPs(&list.Items[i]); len(ips) > 0 {
+			vms = append(vms, VirtualMachine{
+				Name:      list.Items[i].GetName(),
+				Namespace: list.Items[i].GetNamespace(),
+				IPs:       ips,
+			})
+		}
+	}
+	slices.SortFunc(vms, func(a, b VirtualMachine) int {
+		if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return vms, nil
+}
+
+// interfaceIPs reads the sorted IP addresses reported in the status of a VirtualMachineInstance. Addresses of the
+// pod network are the ones of the virt-launcher pod; others come from secondary networks, such as Multus ones.
+func interfaceIPs(o client.Object) []string {
+	vmi, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	interfaces, _, _ := unstructured.NestedSlice(vmi.Object, "status", "interfaces")
+	var ips []string
+	for _, i := range interfaces {
+		iface, ok := i.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		addresses, _, _ := unstructured.NestedStringSlice(iface, "ipAddresses")
+		if len(addresses) == 0 {
+			// older KubeVirt versions only report the first address
+			if ip, _, _ := unstructured.NestedString(iface, "ipAddress"); ip != "" {
+				addresses = []string{ip}
+			}
+		}
+		for _, ip := range addresses {
+			if !slices.Contains(ips, ip) {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	slices.Sort(ips)
+	return ips
+}
@@ -0,0 +1,56 @@
+package kubevirt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func vmi(ns, name string, interfaces ...interface{}) *unstructured.Unstructured {
+	o := unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{"interfaces": interfaces},
+	}}
+	o.SetGroupVersionKind(VMIGVK)
+	o.SetNamespace(ns)
+	o.SetName(name)
+	return &o
+}
+
+// vmiReader lists the given VirtualMachineInstances
+type vmiReader []*unstructured.Unstructured
+
+func (r vmiReader) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return nil
+}
+
+func (r vmiReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	for _, o := range r {
+		list.(*unstructured.UnstructuredList).Items = append(list.(*unstructured.UnstructuredList).Items, *o)
+	}
+	return nil
+}
+
+func TestListVirtualMachines(t *testing.T) {
+	assert := assert.New(t)
+
+	cl := vmiReader{
+		vmi("vms", "web",
+			map[string]interface{}{"name": "default", "ipAddress": "10.129.0.7", "ipAddresses": []interface{}{"10.129.0.7", "fd02::7"}},
+			map[string]interface{}{"name": "secondary", "ipAddresses": []interface{}{"192.168.100.5"}},
+		),
+		// older KubeVirt versions only report ipAddress
+		vmi("vms", "db", map[string]interface{}{"name": "default", "ipAddress": "10.128.0.12"}),
+		// not started yet
+		vmi("apps", "starting"),
+	}
+
+	vms, err := ListVirtualMachines(context.Background(), cl)
+	assert.NoError(err)
+	assert.Equal([]VirtualMachine{
+		{Name: "db", Namespace: "vms", IPs: []string{"10.128.0.12"}},
+		{Name: "web", Namespace: "vms", IPs: []string{"10.129.0.7", "192.168.100.5", "fd02::7"}},
+	}, vms)
+}
@@ -0,0 +1,231 @@
+package logforwarder
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+//+kubebuilder:rbac:groups=observability.openshift.io,resources=clusterlogforwarders,verbs=get;list;watch;update
+
+// ClusterLogForwarderGVK is the kind of the resources managed by the Red Hat OpenShift Logging Operator. It is read as
+// unstructured, to not depend on the Logging Operator API.
+var ClusterLogForwarderGVK = schema.GroupVersionKind{Group: "observability.openshift.io", Version: "v1", Kind: "ClusterLogForwarder"}
+
+const (
+	// ManagedLabel flags the ClusterLogForwarders that flows are handed to, so that they are cleaned up once no longer referenced
+	ManagedLabel     = "netobserv.io/flows-exporter"
+	defaultNamespace = "openshift-logging"
+	entryName        = "netobserv-flows"
+	dropFilterName   = entryName + "-drop"
+	parseFilterName  = entryName + "-parse"
+)
+
+// Client reads and updates ClusterLogForwarders
+type Client interface {
+	client.Reader
+	Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error
+}
+
+// WatchClusterLogForwarders triggers a FlowCollector reconcile when the spec of a ClusterLogForwarder changes, to restore
+// the flows pipeline if it was removed
+func WatchClusterLogForwarders(b *builder.Builder) {
+	clf := unstructured.Unstructured{}
+	clf.SetGroupVersionKind(ClusterLogForwarderGVK)
+	b.Watches(
+		&clf,
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}),
+		builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+	)
+}
+
+// Reconcile adds to the ClusterLogForwarder of the exporter, if any, an input collecting the flows written by
+// flowlogs-pipeline in the given namespace, and a pipeline to the exporter outputs. These entries are removed from the
+// ClusterLogForwarders that are no longer referenced.
+func Reconcile(ctx context.Context, cl Client, ns string, spec *flowslatest.FlowCollectorSpec) error {
+	exporter := helper.GetClusterLogForwarderExporter(spec)
+	var desired *types.NamespacedName
+	if exporter != nil {
+		desired = &types.NamespacedName{Name: exporter.Name, Namespace: exporter.Namespace}
+		if desired.Namespace == "" {
+			desired.Namespace = defaultNamespace
+		}
+	}
+
+	list := unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(ClusterLogForwarderGVK.GroupVersion().WithKind(ClusterLogForwarderGVK.Kind + "List"))
+	if err := cl.List(ctx, &list, client.HasLabels{ManagedLabel}); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		clf := &list.Items[i]
+		if desired != nil && clf.GetName() == desired.Name && clf.GetNamespace() == desired.Namespace {
+			continue
+		}
+		if err := update(ctx, cl, clf, removeEntries); err != nil {
+			return fmt.Errorf("cleaning up ClusterLogForwarder %s/%s: %w", clf.GetNamespace(), clf.GetName(), err)
+		}
+	}
+
+	if desired == nil {
+		return nil
+	}
+	clf := unstructured.Unstructured{}
+	clf.SetGroupVersionKind(ClusterLogForwarderGVK)
+	if err := cl.Get(ctx, *desired, &clf); err != nil {
+		if kerr.IsNotFound(err) {
+			return fmt.Errorf("ClusterLogForwarder %s not found", desired.String())
+		}
+		return err
+	}
+	return update(ctx, cl, &clf, func(clf *unstructured.Unstructured) error {
+		return setEntries(clf, ns, exporter.Outputs)
+	})
+}
+
+// update applies the changes to the ClusterLogForwarder, and updates it only when something changed
+func update(ctx context.Context, cl Client, clf *unstructured.Unstructured, change func(*unstructured.Unstructured) error) error {
+	before := clf.DeepCopy()
+	if err := change(clf); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(before.Object, clf.Object) {
+		return nil
+	}
+	return cl.Update(ctx, clf)
+}
+
+func setEntries(clf *unstructured.Unstructured, ns string, outputs []string) error {
+	// flowlogs-pipeline writes its own logs along with the flows: only the JSON lines are kept, and parsed as
+	// structured logs
+	entries := []struct {
+		list  string
+		entry map[string]interface{}
+	}{
+		{list: "inputs", entry: map[string]interface{}{
+			"name": entryName,
+			"type": "application",
+			"application": map[string]interface{}{
+				"includes": []interface{}{
+					map[string]interface{}{"namespace": ns, "container": constants.FLPName},
+				},
+			},
+		}},
+		{list: "filters", entry: map[string]interface{}{
+			"name": dropFilterName,
+			"type": "drop",
+			"drop": []interface{}{
+				map[string]interface{}{
+					"test": []interface{}{
+						map[string]interface{}{"field": ".message", "notMatches": `^\{`},
+					},
+				},
+			},
+		}},
+		{list: "filters", entry: map[string]interface{}{
+			"name": parseFilterName,
+			"type": "parse",
+		}},
+		{list: "pipelines", entry: map[string]interface{}{
+			"name":       entryName,
+			"inputRefs":  []interface{}{entryName},
+			"filterRefs": []interface{}{dropFilterName, parseFilterName},
+			"outputRefs": toInterfaces(outputs),
+		}},
+	}
+	for _, e := range entries {
+		if err := upsert(clf, e.list, e.entry); err != nil {
+			return err
+		}
+	}
+	labels := clf.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedLabel] = "true"
+	clf.SetLabels(labels)
+	return nil
+}
+
+func removeEntries(clf *unstructured.Unstructured) error {
+	for list, names := range map[string][]string{
+		"inputs":    {entryName},
+		"filters":   {dropFilterName, parseFilterName},
+		"pipelines": {entryName},
+	} {
+		if err := remove(clf, list, names); err != nil {
+			return err
+		}
+	}
+	labels := clf.GetLabels()
+	delete(labels, ManagedLabel)
+	clf.SetLabels(labels)
+	return nil
+}
+
+// upsert replaces the entry of the spec list having the same name, or appends it
+func upsert(clf *unstructured.Unstructured, list string, entry map[string]interface{}) error {
+	items, _, err := unstructured.NestedSlice(clf.Object, "spec", list)
+	if err != nil {
+		return err
+	}
+	found := false
+	for i := range items {
+		if item, ok := items[i].(map[string]interface{}); ok && item["name"] == entry["name"] {
+			items[i] = entry
+			found = true
+			break
+		}
+	}
+	if !found {
+		items = append(items, entry)
+	}
+	return unstructured.SetNestedSlice(clf.Object, items, "spec", list)
+}
+
+// remove deletes the entries of the spec list having one of the names
+func remove(clf *unstructured.Unstructured, list string, names []string) error {
+	items, found, err := unstructured.NestedSlice(clf.Object, "spec", list)
+	if err != nil || !found {
+		return err
+	}
+	var kept []interface{}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name, _ := m["name"].(string); slices.Contains(names, name) {
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	if len(kept) == 0 {
+		unstructured.RemoveNestedField(clf.Object, "spec", list)
+		return nil
+	}
+	return unstructured.SetNestedSlice(clf.Object, kept, "spec", list)
+}
+
+func toInterfaces(values []string) []interface{} {
+	res := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		res = append(res, v)
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+package logforwarder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+// forwarders stores ClusterLogForwarders by namespace and name
+type forwarders map[client.ObjectKey]*unstructured.Unstructured
+
+func (f forwarders) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	clf, ok := f[key]
+	if !ok {
+		return kerr.NewNotFound(schema.GroupResource{Group: ClusterLogForwarderGVK.Group, Resource: "clusterlogforwarders"}, key.Name)
+	}
+	clf.DeepCopyInto(obj.(*unstructured.Unstructured))
+	return nil
+}
+
+func (f forwarders) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	for _, clf := range f {
+		if _, ok := clf.GetLabels()[ManagedLabel]; ok {
+			list.(*unstructured.UnstructuredList).Items = append(list.(*unstructured.UnstructuredList).Items, *clf.DeepCopy())
+		}
+	}
+	return nil
+}
+
+func (f forwarders) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	f[client.ObjectKeyFromObject(obj)] = obj.(*unstructured.Unstructured).DeepCopy()
+	return nil
+}
+
+func forwarder(ns, name string) *unstructured.Unstructured {
+	clf := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"outputs": []interface{}{
+				map[string]interface{}{"name": "splunk", "type": "splunk"},
+			},
+			"pipelines": []interface{}{
+				map[string]interface{}{"name": "audit", "inputRefs": []interface{}{"audit"}, "outputRefs": []interface{}{"splunk"}},
+			},
+		},
+	}}
+	clf.SetGroupVersionKind(ClusterLogForwarderGVK)
+	clf.SetNamespace(ns)
+	clf.SetName(name)
+	return &clf
+}
+
+func specWithForwarder(name string, outputs ...string) *flowslatest.FlowCollectorSpec {
+	return &flowslatest.FlowCollectorSpec{
+		Exporters: []*flowslatest.FlowCollectorExporter{{
+			Type: flowslatest.ClusterLogForwarderExporter,
+			ClusterLogForwarder: flowslatest.FlowCollectorLogForwarderExporter{
+				Name:    name,
+				Outputs: outputs,
+			},
+		}},
+	}
+}
+
+func names(clf *unstructured.Unstructured, list string) []string {
+	items, _, _ := unstructured.NestedSlice(clf.Object, "spec", list)
+	var res []string
+	for _, item := range items {
+		res = append(res, item.(map[string]interface{})["name"].(string))
+	}
+	return res
+}
+
+func TestReconcileClusterLogForwarder(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	keyA := client.ObjectKey{Namespace: "openshift-logging", Name: "a"}
+	keyB := client.ObjectKey{Namespace: "openshift-logging", Name: "b"}
+	cl := forwarders{keyA: forwarder(keyA.Namespace, keyA.Name), keyB: forwarder(keyB.Namespace, keyB.Name)}
+
+	// Hand flows to "a"
+	err := Reconcile(ctx, cl, "netobserv", specWithForwarder("a", "splunk"))
+	assert.NoError(err)
+	a := cl[keyA]
+	assert.Equal("true", a.GetLabels()[ManagedLabel])
+	assert.Equal([]string{"netobserv-flows"}, names(a, "inputs"))
+	assert.Equal([]string{"netobserv-flows-drop", "netobserv-flows-parse"}, names(a, "filters"))
+	assert.Equal([]string{"audit", "netobserv-flows"}, names(a, "pipelines"))
+	includes, _, _ := unstructured.NestedSlice(a.Object["spec"].(map[string]interface{})["inputs"].([]interface{})[0].(map[string]interface{}), "application", "includes")
+	assert.Equal([]interface{}{map[string]interface{}{"namespace": "netobserv", "container": "flowlogs-pipeline"}}, includes)
+	pipeline := a.Object["spec"].(map[string]interface{})["pipelines"].([]interface{})[1].(map[string]interface{})
+	assert.Equal([]interface{}{"splunk"}, pipeline["outputRefs"])
+	assert.Empty(cl[keyB].GetLabels())
+
+	// Idempotent, and updating the outputs
+	err = Reconcile(ctx, cl, "netobserv", specWithForwarder("a", "splunk", "cloudwatch"))
+	assert.NoError(err)
+	a = cl[keyA]
+	assert.Equal([]string{"audit", "netobserv-flows"}, names(a, "pipelines"))
+	pipeline = a.Object["spec"].(map[string]interface{})["pipelines"].([]interface{})[1].(map[string]interface{})
+	assert.Equal([]interface{}{"splunk", "cloudwatch"}, pipeline["outputRefs"])
+
+	// Moving to "b" cleans up "a"
+	err = Reconcile(ctx, cl, "netobserv", specWithForwarder("b", "splunk"))
+	assert.NoError(err)
+	a = cl[keyA]
+	assert.Empty(a.GetLabels())
+	assert.Empty(names(a, "inputs"))
+	assert.Empty(names(a, "filters"))
+	assert.Equal([]string{"audit"}, names(a, "pipelines"))
+	assert.Equal([]string{"netobserv-flows"}, names(cl[keyB], "inputs"))
+
+	// Removing the exporter cleans up "b"
+	err = Reconcile(ctx, cl, "netobserv", &flowslatest.FlowCollectorSpec{})
+	assert.NoError(err)
+	assert.Empty(cl[keyB].GetLabels())
+	assert.Equal([]string{"audit"}, names(cl[keyB], "pipelines"))
+
+	// Missing forwarder
+	err = Reconcile(ctx, cl, "netobserv", specWithForwarder("c", "splunk"))
+	assert.EqualError(err, "ClusterLogForwarder openshift-logging/c not found")
+}
@@ -9,7 +9,7 @@ import (
 func GetLokiLabels(desired *flowslatest.FlowCollectorSpec) []string {
 	indexFields := constants.LokiIndexFields
 
-	if desired.Processor.LogTypes != nil && *desired.Processor.LogTypes != flowslatest.LogTypeFlows {
+	if (desired.Processor.LogTypes != nil && *desired.Processor.LogTypes != flowslatest.LogTypeFlows) || helper.IsLokiAggregationEnabled(&desired.Loki) {
 		indexFields = append(indexFields, constants.LokiConnectionIndexFields...)
 	}
 
@@ -0,0 +1,188 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+//+kubebuilder:rbac:groups=loki.grafana.com,resources=lokistacks,verbs=get;list;watch
+
+// LokiStackGVK is the kind of the resources managed by the Loki Operator. It is read as unstructured,
+// to not depend on the Loki Operator API.
+var LokiStackGVK = schema.GroupVersionKind{Group: "loki.grafana.com", Version: "v1", Kind: "LokiStack"}
+
+const (
+	tenantsModeNetwork = "openshift-network"
+	tenantsModeLogging = "openshift-logging"
+	tenantsModeStatic  = "static"
+	tenantsModeDynamic = "dynamic"
+	networkTenant      = "network"
+)
+
+// WatchLokiStacks triggers a FlowCollector reconcile when the spec of a LokiStack changes
+func WatchLokiStacks(b *builder.Builder) {
+	ls := unstructured.Unstructured{}
+	ls.SetGroupVersionKind(LokiStackGVK)
+	b.Watches(
+		&ls,
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}),
+		builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+	)
+}
+
+// ApplyLokiStack refines the Loki configuration derived from the LokiStack name, with the tenancy read from the LokiStack resource.
+// The name-based configuration is kept when the LokiStack can't be found.
+func ApplyLokiStack(ctx context.Context, cl client.Client, spec *flowslatest.FlowCollectorSpec, cfg *helper.LokiConfig) error {
+	if spec.Loki.Mode != flowslatest.LokiModeLokiStack || helper.UseHostedProfile(spec) {
+		return nil
+	}
+	ref := spec.Loki.LokiStack
+	ns := ref.Namespace
+	if ns == "" {
+		ns = helper.GetNamespace(spec)
+	}
+	ls, err := getLokiStack(ctx, cl, ref.Name, ns)
+	if ls == nil || err != nil {
+		return err
+	}
+	if !isReady(ls) {
+		log.FromContext(ctx).Info("LokiStack is not ready", "name", ref.Name, "namespace", ns)
+	}
+	return applyTenancy(ls, ns, cfg)
+}
+
+// CheckQueryLimits compares the console plugin read timeout with the query timeout of the LokiStack, and returns a warning when it exceeds it
+func CheckQueryLimits(ctx context.Context, cl client.Client, spec *flowslatest.FlowCollectorSpec) ([]string, error) {
+	if spec.Loki.Mode != flowslatest.LokiModeLokiStack || helper.UseHostedProfile(spec) {
+		return nil, nil
+	}
+	ns := spec.Loki.LokiStack.Namespace
+	if ns == "" {
+		ns = helper.GetNamespace(spec)
+	}
+	ls, err := getLokiStack(ctx, cl, spec.Loki.LokiStack.Name, ns)
+	if ls == nil || err != nil {
+		return nil, err
+	}
+	return queryLimitsWarnings(ls, &spec.Loki), nil
+}
+
+// getLokiStack returns the LokiStack, or nil when it doesn't exist
+func getLokiStack(ctx context.Context, cl client.Client, name, ns string) (*unstructured.Unstructured, error) {
+	ls := unstructured.Unstructured{}
+	ls.SetGroupVersionKind(LokiStackGVK)
+	if err := cl.Get(ctx, client.ObjectKey{Name: name, Namespace: ns}, &ls); err != nil {
+		if kerr.IsNotFound(err) {
+			log.FromContext(ctx).Info("LokiStack not found, assuming default settings", "name", name, "namespace", ns)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("can't read LokiStack %s/%s: %w", ns, name, err)
+	}
+	return &ls, nil
+}
+
+// queryLimitsWarnings checks the limits that are set both in the FlowCollector and in the LokiStack, tenant limits overriding global limits
+func queryLimitsWarnings(ls *unstructured.Unstructured, spec *flowslatest.FlowCollectorLoki) []string {
+	tenant := networkTenant
+	if mode, _, _ := unstructured.NestedString(ls.Object, "spec", "tenants", "mode"); mode == tenantsModeStatic || mode == tenantsModeDynamic {
+		tenant, _ = pickTenant(ls)
+	}
+	limit := func(field string) (interface{}, bool) {
+		if v, found, _ := unstructured.NestedFieldNoCopy(ls.Object, "spec", "limits", "tenants", tenant, "queries", field); found {
+			return v, true
+		}
+		v, found, _ := unstructured.NestedFieldNoCopy(ls.Object, "spec", "limits", "global", "queries", field)
+		return v, found
+	}
+	var warnings []string
+	if spec.ReadTimeout != nil && spec.ReadTimeout.Duration > 0 {
+		if v, found := limit("queryTimeout"); found {
+			if str, ok := v.(string); ok {
+				if max, err := time.ParseDuration(str); err == nil && spec.ReadTimeout.Duration > max {
+					warnings = append(warnings, fmt.Sprintf("readTimeout %s exceeds the LokiStack queryTimeout %s", spec.ReadTimeout.Duration, str))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+func applyTenancy(ls *unstructured.Unstructured, ns string, cfg *helper.LokiConfig) error {
+	mode, _, _ := unstructured.NestedString(ls.Object, "spec", "tenants", "mode")
+	switch mode {
+	case tenantsModeNetwork:
+		// name-based defaults already match this mode
+		return nil
+	case tenantsModeStatic, tenantsModeDynamic:
+		tenant, err := pickTenant(ls)
+		if err != nil {
+			return err
+		}
+		gatewayURL := fmt.Sprintf("https://%s-gateway-http.%s.svc:8080/api/logs/v1/%s/", ls.GetName(), ns, tenant)
+		cfg.QuerierURL = gatewayURL
+		cfg.IngesterURL = gatewayURL
+		cfg.TenantID = tenant
+		return nil
+	case "":
+		return status.InvalidSpecError("LokiStack %s/%s has no tenants configured, hence no gateway: set its `spec.tenants.mode` to %s", ns, ls.GetName(), tenantsModeNetwork)
+	case tenantsModeLogging:
+		return status.InvalidSpecError("LokiStack %s/%s tenants mode %s has no %s tenant: use a dedicated LokiStack with mode %s", ns, ls.GetName(), mode, networkTenant, tenantsModeNetwork)
+	default:
+		return status.InvalidSpecError("LokiStack %s/%s has an unsupported tenants mode: %s", ns, ls.GetName(), mode)
+	}
+}
+
+// pickTenant returns the "network" tenant if declared, otherwise the first declared tenant
+func pickTenant(ls *unstructured.Unstructured) (string, error) {
+	auths, _, _ := unstructured.NestedSlice(ls.Object, "spec", "tenants", "authentication")
+	var first string
+	for _, a := range auths {
+		m, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(m, "tenantName")
+		if name == networkTenant {
+			return name, nil
+		}
+		if first == "" {
+			first = name
+		}
+	}
+	if first == "" {
+		return "", status.InvalidSpecError("LokiStack %s/%s doesn't declare any tenant", ls.GetNamespace(), ls.GetName())
+	}
+	return first, nil
+}
+
+func isReady(ls *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(ls.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if m["type"] == "Ready" {
+			return m["status"] == "True"
+		}
+	}
+	return false
+}
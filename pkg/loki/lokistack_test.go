@@ -0,0 +1,83 @@
+package loki
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+func lokiStack(tenants map[string]interface{}) *unstructured.Unstructured {
+	ls := unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+	ls.SetGroupVersionKind(LokiStackGVK)
+	ls.SetName("lokistack")
+	ls.SetNamespace("loki")
+	if tenants != nil {
+		ls.Object["spec"].(map[string]interface{})["tenants"] = tenants
+	}
+	return &ls
+}
+
+func TestLokiStackTenancy(t *testing.T) {
+	assert := assert.New(t)
+	spec := flowslatest.FlowCollectorLoki{
+		Mode:      flowslatest.LokiModeLokiStack,
+		LokiStack: flowslatest.LokiStackRef{Name: "lokistack", Namespace: "loki"},
+	}
+
+	// openshift-network: defaults are kept
+	cfg := helper.NewLokiConfig(&spec, "netobserv")
+	err := applyTenancy(lokiStack(map[string]interface{}{"mode": "openshift-network"}), "loki", &cfg)
+	assert.NoError(err)
+	assert.Equal(helper.NewLokiConfig(&spec, "netobserv"), cfg)
+
+	// static: tenant is read from the LokiStack
+	cfg = helper.NewLokiConfig(&spec, "netobserv")
+	err = applyTenancy(lokiStack(map[string]interface{}{
+		"mode": "static",
+		"authentication": []interface{}{
+			map[string]interface{}{"tenantName": "flows"},
+			map[string]interface{}{"tenantName": "other"},
+		},
+	}), "loki", &cfg)
+	assert.NoError(err)
+	assert.Equal("flows", cfg.TenantID)
+	assert.Equal("https://lokistack-gateway-http.loki.svc:8080/api/logs/v1/flows/", cfg.IngesterURL)
+	assert.Equal("https://lokistack-gateway-http.loki.svc:8080/api/logs/v1/flows/", cfg.QuerierURL)
+
+	// openshift-logging and no tenants are rejected
+	cfg = helper.NewLokiConfig(&spec, "netobserv")
+	err = applyTenancy(lokiStack(map[string]interface{}{"mode": "openshift-logging"}), "loki", &cfg)
+	assert.ErrorContains(err, "has no network tenant")
+	err = applyTenancy(lokiStack(nil), "loki", &cfg)
+	assert.ErrorContains(err, "has no tenants configured")
+}
+
+func TestQueryLimitsWarnings(t *testing.T) {
+	assert := assert.New(t)
+	ls := lokiStack(map[string]interface{}{"mode": "openshift-network"})
+	ls.Object["spec"].(map[string]interface{})["limits"] = map[string]interface{}{
+		"global": map[string]interface{}{
+			"queries": map[string]interface{}{"queryTimeout": "1m"},
+		},
+	}
+
+	spec := flowslatest.FlowCollectorLoki{ReadTimeout: &metav1.Duration{Duration: 30 * time.Second}}
+	assert.Empty(queryLimitsWarnings(ls, &spec))
+
+	spec.ReadTimeout.Duration = 2 * time.Minute
+	assert.Equal([]string{"readTimeout 2m0s exceeds the LokiStack queryTimeout 1m"}, queryLimitsWarnings(ls, &spec))
+
+	// tenant limit overrides the global limit
+	ls.Object["spec"].(map[string]interface{})["limits"].(map[string]interface{})["tenants"] = map[string]interface{}{
+		"network": map[string]interface{}{
+			"queries": map[string]interface{}{"queryTimeout": "3m"},
+		},
+	}
+	assert.Empty(queryLimitsWarnings(ls, &spec))
+}
@@ -0,0 +1,77 @@
+package loki
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const (
+	MigrationStepDualWrite      = "DualWrite"
+	MigrationStepHistoryOverlap = "HistoryOverlap"
+	MigrationStepReadFromTarget = "ReadFromTarget"
+	MigrationStepFinalize       = "Finalize"
+)
+
+// MigrationStatus returns the checklist of the Loki migration, or nil when no migration is configured, and the delay after which it should be refreshed.
+// DualWriting is true when flowlogs-pipeline is rolled out with the target Loki: the overlap period starts from then.
+// The start time of the previous status is kept, so that the overlap period isn't reset on every reconcile, unless the target changed.
+func MigrationStatus(spec *flowslatest.FlowCollectorSpec, previous *flowslatest.LokiMigrationStatus, dualWriting bool, now time.Time) (*flowslatest.LokiMigrationStatus, time.Duration) {
+	migration := spec.Loki.Migration
+	if migration == nil || !helper.UseLoki(spec) {
+		return nil, 0
+	}
+	target := migration.Target.IngesterURL
+	var start *metav1.Time
+	if previous != nil && previous.Target == target && previous.StartTime != nil {
+		start = previous.StartTime
+	} else if dualWriting {
+		start = &metav1.Time{Time: now}
+	}
+	var overlap time.Duration
+	if migration.OverlapPeriod != nil {
+		overlap = migration.OverlapPeriod.Duration
+	}
+	readFromTarget := migration.ReadFrom == flowslatest.LokiMigrationReadFromTarget
+
+	steps := []flowslatest.LokiMigrationStep{
+		{
+			Name: MigrationStepDualWrite,
+			Done: start != nil,
+		},
+		{
+			Name: MigrationStepHistoryOverlap,
+		},
+		{
+			Name: MigrationStepReadFromTarget,
+			Done: readFromTarget,
+		},
+		{
+			Name:    MigrationStepFinalize,
+			Message: "Move `spec.loki.migration.target` to `spec.loki.manual`, set `spec.loki.mode` to `Manual` (or the mode matching the target) and remove `spec.loki.migration`",
+		},
+	}
+	var requeueAfter time.Duration
+	if start != nil {
+		overlapEnd := start.Add(overlap)
+		if now.Before(overlapEnd) {
+			requeueAfter = overlapEnd.Sub(now)
+		}
+		steps[0].Message = fmt.Sprintf("Flows are written to both the current Loki and the target Loki %s", target)
+		steps[1].Done = requeueAfter == 0
+		steps[1].Message = fmt.Sprintf("The target Loki holds flows since %s; the overlap period ends at %s", start.UTC().Format(time.RFC3339), overlapEnd.UTC().Format(time.RFC3339))
+	} else {
+		steps[0].Message = fmt.Sprintf("Waiting for flowlogs-pipeline to be rolled out with the target Loki %s", target)
+		steps[1].Message = "The overlap period starts once flows are written to the target Loki"
+	}
+	if readFromTarget {
+		steps[2].Message = "The console plugin reads from the target Loki"
+	} else {
+		steps[2].Message = "Set `spec.loki.migration.readFrom` to `Target` to read from the target Loki"
+	}
+	return &flowslatest.LokiMigrationStatus{Target: target, StartTime: start, Steps: steps}, requeueAfter
+}
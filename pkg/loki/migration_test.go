@@ -0,0 +1,69 @@
+package loki
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	assert := assert.New(t)
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	spec := flowslatest.FlowCollectorSpec{}
+
+	// no migration
+	status, requeue := MigrationStatus(&spec, nil, true, start)
+	assert.Nil(status)
+	assert.Zero(requeue)
+
+	spec.Loki.Migration = &flowslatest.LokiMigration{
+		Target:        flowslatest.LokiManualParams{IngesterURL: "http://new-loki:3100/"},
+		ReadFrom:      flowslatest.LokiMigrationReadFromCurrent,
+		OverlapPeriod: &metav1.Duration{Duration: 24 * time.Hour},
+	}
+
+	// flowlogs-pipeline not rolled out yet
+	status, requeue = MigrationStatus(&spec, nil, false, start.Add(-time.Hour))
+	assert.Nil(status.StartTime)
+	assert.Zero(requeue)
+	assert.Equal([]bool{false, false, false, false}, stepsDone(status))
+	assert.Equal("Waiting for flowlogs-pipeline to be rolled out with the target Loki http://new-loki:3100/", status.Steps[0].Message)
+
+	status, requeue = MigrationStatus(&spec, status, true, start)
+	assert.Equal(start, status.StartTime.Time)
+	assert.Equal("http://new-loki:3100/", status.Target)
+	assert.Equal(24*time.Hour, requeue)
+	assert.Equal([]bool{true, false, false, false}, stepsDone(status))
+
+	// start time is kept, including during a later rollout, overlap is over
+	status, requeue = MigrationStatus(&spec, status, false, start.Add(25*time.Hour))
+	assert.Equal(start, status.StartTime.Time)
+	assert.Zero(requeue)
+	assert.Equal([]bool{true, true, false, false}, stepsDone(status))
+
+	spec.Loki.Migration.ReadFrom = flowslatest.LokiMigrationReadFromTarget
+	status, _ = MigrationStatus(&spec, status, true, start.Add(26*time.Hour))
+	assert.Equal([]bool{true, true, true, false}, stepsDone(status))
+	assert.Equal("The console plugin reads from the target Loki", status.Steps[2].Message)
+
+	// a new target restarts the migration
+	spec.Loki.Migration.Target.IngesterURL = "http://other-loki:3100/"
+	status, _ = MigrationStatus(&spec, status, false, start.Add(27*time.Hour))
+	assert.Nil(status.StartTime)
+	assert.Equal([]bool{false, false, true, false}, stepsDone(status))
+	status, requeue = MigrationStatus(&spec, status, true, start.Add(28*time.Hour))
+	assert.Equal(start.Add(28*time.Hour), status.StartTime.Time)
+	assert.Equal(24*time.Hour, requeue)
+}
+
+func stepsDone(status *flowslatest.LokiMigrationStatus) []bool {
+	var done []bool
+	for _, s := range status.Steps {
+		done = append(done, s.Done)
+	}
+	return done
+}
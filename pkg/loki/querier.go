@@ -0,0 +1,171 @@
+package loki
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+)
+
+const serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+// Querier reads flows through the Loki HTTP API. Requests are authenticated with the operator service account token
+// when Loki expects a token: the operator must be allowed to read the flows tenant.
+type Querier struct {
+	url       string
+	tenantID  string
+	selector  string
+	client    *http.Client
+	token     string
+	tokenFile string
+}
+
+// NewQuerier creates a Querier for the Loki read configuration of this FlowCollector
+func NewQuerier(spec *flowslatest.FlowCollectorSpec, namespace string, cfg *rest.Config) *Querier {
+	current := helper.GetLokiConfig(spec, namespace)
+	lc := helper.GetLokiReadConfig(spec, &current)
+	tlsConfig := &tls.Config{InsecureSkipVerify: lc.TLS.InsecureSkipVerify} //nolint:gosec // user-defined
+	if lc.TLS.Enable && !lc.TLS.InsecureSkipVerify {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if ca, err := os.ReadFile(serviceCAFile); err == nil {
+			pool.AppendCertsFromPEM(ca)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	q := Querier{
+		url:      strings.TrimSuffix(lc.QuerierURL, "/"),
+		tenantID: lc.TenantID,
+		selector: streamSelector(helper.GetAdvancedLokiConfig(spec.Loki.Advanced).StaticLabels),
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   30 * time.Second,
+		},
+	}
+	if cfg != nil && (lc.UseHostToken() || lc.UseForwardToken()) {
+		q.token = cfg.BearerToken
+		q.tokenFile = cfg.BearerTokenFile
+	}
+	return &q
+}
+
+// RecentFlowsReader returns a function reading the recent flows of a FlowCollector, used to preview FlowMetrics
+func RecentFlowsReader(cfg *rest.Config) func(context.Context, *flowslatest.FlowCollector, time.Duration, int) ([]map[string]any, error) {
+	return func(ctx context.Context, fc *flowslatest.FlowCollector, period time.Duration, limit int) ([]map[string]any, error) {
+		q := NewQuerier(&fc.Spec, helper.GetNamespace(&fc.Spec), cfg)
+		defer q.Close()
+		return q.RecentFlows(ctx, period, limit)
+	}
+}
+
+// streamSelector returns the LogQL selector of the flow streams, from the static labels written by flowlogs-pipeline
+func streamSelector(staticLabels map[string]string) string {
+	matchers := make([]string, 0, len(staticLabels))
+	for k, v := range staticLabels {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", k, v))
+	}
+	sort.Strings(matchers)
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// Close releases the idle connections of the querier
+func (q *Querier) Close() {
+	q.client.CloseIdleConnections()
+}
+
+type queryRangeResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Result []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// RecentFlows returns up to limit of the flows written during the last period, newest first. The labels of the
+// Loki streams are set as flow fields, as they are not repeated in the log lines.
+func (q *Querier) RecentFlows(ctx context.Context, period time.Duration, limit int) ([]map[string]any, error) {
+	now := time.Now()
+	params := url.Values{}
+	params.Set("query", q.selector)
+	params.Set("start", strconv.FormatInt(now.Add(-period).UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(now.UnixNano(), 10))
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("direction", "backward")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, q.url+"/loki/api/v1/query_range?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if q.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", q.tenantID)
+	}
+	token := q.token
+	if q.tokenFile != "" {
+		// Read on every query, as the projected token is rotated
+		b, err := os.ReadFile(q.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read service account token: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not query Loki: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read Loki response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query to Loki failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return parseStreams(body)
+}
+
+func parseStreams(body []byte) ([]map[string]any, error) {
+	var qr queryRangeResponse
+	if err := json.Unmarshal(body, &qr); err != nil {
+		return nil, fmt.Errorf("could not parse Loki response: %w", err)
+	}
+	if qr.Status != "success" {
+		return nil, fmt.Errorf("query to Loki failed with status %q", qr.Status)
+	}
+	var flows []map[string]any
+	for _, r := range qr.Data.Result {
+		for _, v := range r.Values {
+			if len(v) != 2 {
+				return nil, fmt.Errorf("unexpected Loki value: %v", v)
+			}
+			flow := map[string]any{}
+			if err := json.Unmarshal([]byte(v[1]), &flow); err != nil {
+				return nil, fmt.Errorf("could not parse flow: %w", err)
+			}
+			for k, l := range r.Stream {
+				flow[k] = l
+			}
+			flows = append(flows, flow)
+		}
+	}
+	return flows, nil
+}
@@ -1,9 +1,25 @@
 package manager
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+	"github.com/netobserv/network-observability-operator/pkg/featuregates"
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// operatorContainer is the name of the operator container, in the operator pod
+const operatorContainer = "manager"
+
 // Config of the operator.
 type Config struct {
 	// EBPFAgentImage is the image of the eBPF agent that is managed by the operator
@@ -12,8 +28,72 @@ type Config struct {
 	FlowlogsPipelineImage string
 	// ConsolePluginImage is the image of the Console Plugin that is managed by the operator
 	ConsolePluginImage string
+	// KubeRBACProxyImage is the image of kube-rbac-proxy, which can run as a sidecar of the components exposing metrics
+	KubeRBACProxyImage string
+	// ReporterImage is the image of the jobs generating FlowReports, which runs the operator binary in report mode.
+	// It defaults to the image of the operator itself, see DefaultReporterImage.
+	ReporterImage string
 	// Release kind is either upstream or downstream
 	DownstreamDeployment bool
+	// WatchNamespaces restricts the namespaces where the operator watches and manages objects, for installations where
+	// cluster-wide operators are forbidden. When empty, all namespaces are watched.
+	WatchNamespaces []string
+	// APIDiscoveryInterval is the period at which the available APIs are discovered again, so that APIs installed after
+	// the operator (such as the Prometheus Operator or the Console) are picked up without restart. Zero disables it.
+	APIDiscoveryInterval time.Duration
+	// FeatureGates enables or disables experimental features
+	FeatureGates *featuregates.Gates
+}
+
+// IsNamespaced returns true when the operator only manages objects in some namespaces
+func (cfg *Config) IsNamespaced() bool {
+	return len(cfg.WatchNamespaces) > 0
+}
+
+// IsWatched returns true when the operator watches objects in this namespace
+func (cfg *Config) IsWatched(ns string) bool {
+	return !cfg.IsNamespaced() || slices.Contains(cfg.WatchNamespaces, ns)
+}
+
+// CheckFlowCollector returns an error when a FlowCollector can't be managed in namespaced mode: its namespaces must be
+// watched, and its cluster-scoped RBAC must be applied by an administrator
+func (cfg *Config) CheckFlowCollector(spec *flowslatest.FlowCollectorSpec) error {
+	if !cfg.IsNamespaced() {
+		return nil
+	}
+	ns := helper.GetNamespace(spec)
+	for _, n := range []string{ns, ns + constants.EBPFPrivilegedNSSuffix} {
+		if !slices.Contains(cfg.WatchNamespaces, n) {
+			return status.InvalidSpecError("namespace %s is not watched by the operator, which only watches %v", n, cfg.WatchNamespaces)
+		}
+	}
+	if !helper.IsManualClusterRBAC(spec) {
+		return status.InvalidSpecError("the operator only watches some namespaces: spec.clusterRBAC.mode must be %s", flowslatest.ClusterRBACManual)
+	}
+	return nil
+}
+
+// DefaultReporterImage sets the reporter image to the image of the operator container when it isn't set, so that the report
+// jobs run the same binary as the operator. The operator pod is read from the POD_NAME and POD_NAMESPACE environment variables.
+func (cfg *Config) DefaultReporterImage(ctx context.Context, cl client.Reader) error {
+	if cfg.ReporterImage != "" {
+		return nil
+	}
+	name, ns := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if name == "" || ns == "" {
+		return errors.New("reporter image argument is empty, and the operator pod is unknown: POD_NAME and POD_NAMESPACE must be set")
+	}
+	pod := corev1.Pod{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: name, Namespace: ns}, &pod); err != nil {
+		return fmt.Errorf("can't read the operator pod to pick the reporter image: %w", err)
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == operatorContainer {
+			cfg.ReporterImage = pod.Spec.Containers[i].Image
+			return nil
+		}
+	}
+	return fmt.Errorf("container %s not found in the operator pod %s/%s", operatorContainer, ns, name)
 }
 
 func (cfg *Config) Validate() error {
@@ -26,5 +106,14 @@ func (cfg *Config) Validate() error {
 	if cfg.ConsolePluginImage == "" {
 		return errors.New("console plugin image argument can't be empty")
 	}
+	if cfg.KubeRBACProxyImage == "" {
+		return errors.New("kube-rbac-proxy image argument can't be empty")
+	}
+	if cfg.ReporterImage == "" {
+		return errors.New("reporter image argument can't be empty")
+	}
+	if cfg.APIDiscoveryInterval < 0 {
+		return errors.New("API discovery interval can't be negative")
+	}
 	return nil
 }
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+	"github.com/netobserv/network-observability-operator/pkg/manager/status"
+)
+
+func TestCheckFlowCollector(t *testing.T) {
+	assert := assert.New(t)
+	spec := flowslatest.FlowCollectorSpec{Namespace: "netobserv"}
+
+	cfg := Config{}
+	assert.False(cfg.IsNamespaced())
+	assert.True(cfg.IsWatched("openshift-config-managed"))
+	assert.NoError(cfg.CheckFlowCollector(&spec))
+
+	cfg.WatchNamespaces = []string{"netobserv"}
+	err := cfg.CheckFlowCollector(&spec)
+	assert.EqualError(err, "namespace netobserv-privileged is not watched by the operator, which only watches [netobserv]")
+	assert.Equal(status.ErrorInvalidSpec, status.KindOf(err))
+
+	cfg.WatchNamespaces = append(cfg.WatchNamespaces, "netobserv-privileged")
+	assert.EqualError(cfg.CheckFlowCollector(&spec), "the operator only watches some namespaces: spec.clusterRBAC.mode must be Manual")
+
+	spec.ClusterRBAC.Mode = flowslatest.ClusterRBACManual
+	assert.NoError(cfg.CheckFlowCollector(&spec))
+	assert.True(cfg.IsWatched("netobserv"))
+	assert.False(cfg.IsWatched("openshift-config-managed"))
+}
+
+// podReader is a minimal reader returning a single pod
+type podReader struct {
+	client.Reader
+	pod corev1.Pod
+}
+
+func (r *podReader) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	if key.Name != r.pod.Name || key.Namespace != r.pod.Namespace {
+		return kerr.NewNotFound(schema.GroupResource{Resource: "pods"}, key.Name)
+	}
+	r.pod.DeepCopyInto(obj.(*corev1.Pod))
+	return nil
+}
+
+func TestDefaultReporterImage(t *testing.T) {
+	assert := assert.New(t)
+	cl := &podReader{}
+	cl.pod.Name = "netobserv-controller-manager-abc"
+	cl.pod.Namespace = "openshift-netobserv-operator"
+	cl.pod.Spec.Containers = []corev1.Container{
+		{Name: "kube-rbac-proxy", Image: "quay.io/brancz/kube-rbac-proxy:v0.15.0"},
+		{Name: "manager", Image: "quay.io/netobserv/network-observability-operator:main"},
+	}
+
+	// explicit image is kept
+	cfg := Config{ReporterImage: "quay.io/me/operator:dev"}
+	assert.NoError(cfg.DefaultReporterImage(context.Background(), cl))
+	assert.Equal("quay.io/me/operator:dev", cfg.ReporterImage)
+
+	cfg.ReporterImage = ""
+	t.Setenv("POD_NAME", "")
+	assert.ErrorContains(cfg.DefaultReporterImage(context.Background(), cl), "POD_NAME and POD_NAMESPACE must be set")
+
+	t.Setenv("POD_NAME", "netobserv-controller-manager-abc")
+	t.Setenv("POD_NAMESPACE", "openshift-netobserv-operator")
+	assert.NoError(cfg.DefaultReporterImage(context.Background(), cl))
+	assert.Equal("quay.io/netobserv/network-observability-operator:main", cfg.ReporterImage)
+}
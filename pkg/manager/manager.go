@@ -3,33 +3,47 @@ package manager
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/netobserv/network-observability-operator/controllers/constants"
 	"github.com/netobserv/network-observability-operator/pkg/discover"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
 	"github.com/netobserv/network-observability-operator/pkg/narrowcache"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 //+kubebuilder:rbac:groups=apps,resources=deployments;daemonsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups=core,resources=namespaces;services;serviceaccounts;configmaps;secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=endpoints,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings;clusterroles;rolebindings;roles,verbs=get;list;create;delete;update;watch
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=get;create;delete;update;patch;list;watch
 //+kubebuilder:rbac:groups=operator.openshift.io,resources=consoles,verbs=get;list;update;watch
+//+kubebuilder:rbac:groups=flows.netobserv.io,resources=externalendpoints,verbs=get;list;watch
 //+kubebuilder:rbac:groups=flows.netobserv.io,resources=flowcollectors,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=flows.netobserv.io,resources=flowcollectors/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=flows.netobserv.io,resources=flowcollectors/finalizers,verbs=update
 //+kubebuilder:rbac:groups=flows.netobserv.io,resources=flowmetrics,verbs=get;list;watch
+//+kubebuilder:rbac:groups=flows.netobserv.io,resources=flowmetrics/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=flows.netobserv.io,resources=flowreports,verbs=get;list;watch
+//+kubebuilder:rbac:groups=flows.netobserv.io,resources=flowreports/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=hostnetwork,verbs=use
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=list;create;update;watch
 //+kubebuilder:rbac:groups=apiregistration.k8s.io,resources=apiservices,verbs=list;get;watch
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;create;delete;update;patch;list;watch
-//+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;networks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions;networks;proxies,verbs=get;list;watch
 //+kubebuilder:rbac:groups=loki.grafana.com,resources=network,resourceNames=logs,verbs=get;create
 //+kubebuilder:rbac:urls="/metrics",verbs=get
 
@@ -37,11 +51,14 @@ type Registerer func(context.Context, *Manager) error
 
 type Manager struct {
 	manager.Manager
-	discover.AvailableAPIs
+	*discover.AvailableAPIs
 	Client client.Client
 	Status *status.Manager
 	Config *Config
-	vendor discover.Vendor
+	// AgentPods reads the eBPF agent pods from a cache restricted to them
+	AgentPods    client.Reader
+	vendor       discover.Vendor
+	apiListeners []chan event.GenericEvent
 }
 
 func NewManager(
@@ -57,11 +74,35 @@ func NewManager(
 
 	narrowCache := narrowcache.NewConfig(kcfg, narrowcache.ConfigMaps, narrowcache.Secrets)
 	opts.Client = client.Options{Cache: narrowCache.ControllerRuntimeClientCacheOptions()}
+	if opcfg.IsNamespaced() {
+		// only namespaced objects are restricted, cluster-scoped objects such as the FlowCollector are still cached
+		log.Info("Restricting the watched namespaces", "namespaces", opcfg.WatchNamespaces)
+		opts.Cache.DefaultNamespaces = map[string]cache.Config{}
+		for _, ns := range opcfg.WatchNamespaces {
+			opts.Cache.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
 
 	internalManager, err := ctrl.NewManager(kcfg, *opts)
 	if err != nil {
 		return nil, err
 	}
+	// agent pods are read from a dedicated cache selecting them by label, as caching all the pods of the cluster isn't worth it
+	agentPods, err := cache.New(kcfg, cache.Options{
+		Scheme:            internalManager.GetScheme(),
+		Mapper:            internalManager.GetRESTMapper(),
+		DefaultNamespaces: opts.Cache.DefaultNamespaces,
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: labels.SelectorFromSet(labels.Set{"app": constants.EBPFAgentName})},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create agent pods cache: %w", err)
+	}
+	if err := internalManager.Add(agentPods); err != nil {
+		return nil, fmt.Errorf("unable to add agent pods cache: %w", err)
+	}
+
 	client, err := narrowCache.CreateClient(internalManager.GetClient())
 	if err != nil {
 		return nil, fmt.Errorf("unable to create narrow cache client: %w", err)
@@ -81,12 +122,14 @@ func NewManager(
 
 	this := &Manager{
 		Manager:       internalManager,
-		AvailableAPIs: *apis,
+		AvailableAPIs: apis,
 		Status:        status.NewManager(),
 		Client:        client,
 		Config:        opcfg,
+		AgentPods:     agentPods,
 		vendor:        vendor,
 	}
+	this.Status.SetEventRecorder(internalManager.GetEventRecorderFor("netobserv-operator"))
 
 	log.Info("Building controllers")
 	for _, f := range ctrls {
@@ -95,6 +138,12 @@ func NewManager(
 		}
 	}
 
+	if opcfg.APIDiscoveryInterval > 0 {
+		if err := internalManager.Add(manager.RunnableFunc(this.watchAPIs)); err != nil {
+			return nil, fmt.Errorf("unable to add API discovery runnable: %w", err)
+		}
+	}
+
 	return this, nil
 }
 
@@ -105,3 +154,47 @@ func (m *Manager) GetClient() client.Client {
 func (m *Manager) IsOpenShift() bool {
 	return m.vendor == discover.VendorOpenShift
 }
+
+// APIChangesSource returns a source that emits an event whenever an API gets installed or removed in the cluster.
+// Controllers watch it to reconcile again when, for instance, ServiceMonitors become available.
+// It must be called before the manager is started.
+func (m *Manager) APIChangesSource() source.Source {
+	// a single pending event is enough, as they all trigger the same reconcile
+	ch := make(chan event.GenericEvent, 1)
+	m.apiListeners = append(m.apiListeners, ch)
+	return &source.Channel{Source: ch}
+}
+
+func (m *Manager) watchAPIs(ctx context.Context) error {
+	rlog := log.FromContext(ctx).WithName("api-discovery")
+	ticker := time.NewTicker(m.Config.APIDiscoveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			changed, err := m.AvailableAPIs.Refresh()
+			if err != nil {
+				rlog.Error(err, "can't discover available APIs")
+				continue
+			}
+			if len(changed) == 0 {
+				continue
+			}
+			rlog.Info("Available APIs changed, triggering reconcile", "apis", changed)
+			m.notifyAPIChanges()
+		}
+	}
+}
+
+// notifyAPIChanges never blocks: when a listener already has a pending event, its controller hasn't consumed it yet and
+// will reconcile anyway, so the new event is dropped
+func (m *Manager) notifyAPIChanges() {
+	for _, ch := range m.apiListeners {
+		select {
+		case ch <- event.GenericEvent{Object: &metav1.PartialObjectMetadata{}}:
+		default:
+		}
+	}
+}
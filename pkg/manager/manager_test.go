@@ -0,0 +1,27 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestNotifyAPIChanges(t *testing.T) {
+	m := Manager{}
+	m.APIChangesSource()
+	m.APIChangesSource()
+
+	// listeners that don't consume events don't block the discovery: pending events are coalesced
+	m.notifyAPIChanges()
+	m.notifyAPIChanges()
+	for _, ch := range m.apiListeners {
+		assert.Len(t, ch, 1)
+		<-ch
+	}
+
+	m.notifyAPIChanges()
+	for _, ch := range m.apiListeners {
+		assert.IsType(t, event.GenericEvent{}, <-ch)
+	}
+}
@@ -0,0 +1,86 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrorKind classifies reconcile errors, so that they are reported with distinct condition reasons and events
+type ErrorKind string
+
+const (
+	// ErrorPermission is a missing RBAC permission of the operator
+	ErrorPermission ErrorKind = "Permission"
+	// ErrorDependency is a missing or unreachable dependency, such as a referenced secret or a LokiStack
+	ErrorDependency ErrorKind = "Dependency"
+	// ErrorInvalidSpec is a configuration that can't be applied, and won't be until it is fixed
+	ErrorInvalidSpec ErrorKind = "InvalidSpec"
+	// ErrorTransient is any other error, expected to be solved by retrying
+	ErrorTransient ErrorKind = "Transient"
+)
+
+// Reason returns the condition reason used for this kind of error
+func (k ErrorKind) Reason() string {
+	switch k {
+	case ErrorPermission:
+		return "MissingPermission"
+	case ErrorDependency:
+		return "DependencyUnavailable"
+	case ErrorInvalidSpec:
+		return "InvalidSpec"
+	}
+	return "TransientError"
+}
+
+// ReconcileError is an error with a known kind
+type ReconcileError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *ReconcileError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ReconcileError) Unwrap() error {
+	return e.Err
+}
+
+func PermissionError(err error) error {
+	return &ReconcileError{Kind: ErrorPermission, Err: err}
+}
+
+func DependencyError(err error) error {
+	return &ReconcileError{Kind: ErrorDependency, Err: err}
+}
+
+func InvalidSpecError(format string, a ...any) error {
+	return &ReconcileError{Kind: ErrorInvalidSpec, Err: fmt.Errorf(format, a...)}
+}
+
+func TransientError(err error) error {
+	return &ReconcileError{Kind: ErrorTransient, Err: err}
+}
+
+// KindOf returns the kind of an error: missing permissions take precedence, then the kind of the wrapped ReconcileError if any,
+// else it is guessed from API and network errors
+func KindOf(err error) ErrorKind {
+	if kerr.IsForbidden(err) || kerr.IsUnauthorized(err) {
+		return ErrorPermission
+	}
+	var re *ReconcileError
+	if errors.As(err, &re) {
+		return re.Kind
+	}
+	var netErr net.Error
+	switch {
+	case kerr.IsNotFound(err), errors.As(err, &netErr):
+		return ErrorDependency
+	case kerr.IsInvalid(err), kerr.IsBadRequest(err):
+		return ErrorInvalidSpec
+	}
+	return ErrorTransient
+}
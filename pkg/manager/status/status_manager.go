@@ -3,7 +3,9 @@ package status
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 	"github.com/netobserv/network-observability-operator/controllers/constants"
@@ -12,7 +14,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -21,16 +25,49 @@ type ComponentName string
 
 const (
 	FlowCollectorLegacy ComponentName = "FlowCollectorLegacy"
+	EBPFAgent           ComponentName = "EBPFAgent"
+	ConsolePlugin       ComponentName = "ConsolePlugin"
+	QueryService        ComponentName = "QueryService"
 	FLPParent           ComponentName = "FLPParent"
 	FLPMonolith         ComponentName = "FLPMonolith"
 	FLPTransformOnly    ComponentName = "FLPTransformOnly"
+	FLPLoadGenerator    ComponentName = "FLPLoadGenerator"
 	Monitoring          ComponentName = "Monitoring"
+	Snapshots           ComponentName = "Snapshots"
 )
 
 var allNames = []ComponentName{FlowCollectorLegacy, Monitoring}
 
 type Manager struct {
-	statuses sync.Map
+	statuses   sync.Map
+	warnings   sync.Map
+	objects    sync.Map
+	agentNodes atomic.Pointer[flowslatest.AgentNodesStatus]
+	// conflicting eBPF users, listed in `status.agent.conflicts`
+	agentConflicts atomic.Pointer[[]flowslatest.AgentConflict]
+	// nodes per eBPF attach mode, listed in `status.agent.attachModes`
+	agentAttachModes atomic.Pointer[[]flowslatest.AgentAttachModeCount]
+	// nil until the migration status is known, so that the status stored in the FlowCollector isn't erased meanwhile
+	lokiMigration atomic.Pointer[lokiMigrationState]
+	// nil until the cluster network is read, listed in `status.clusterNetwork`
+	clusterNetwork atomic.Pointer[clusterNetworkState]
+	// nil until the flow rate is checked, listed in `status.batchTuning`
+	batchTuning atomic.Pointer[batchTuningState]
+	// generation last reconciled by each controller, the lowest one being listed in `status.observedGeneration`
+	generations sync.Map
+	recorder    record.EventRecorder
+}
+
+type lokiMigrationState struct {
+	status *flowslatest.LokiMigrationStatus
+}
+
+type clusterNetworkState struct {
+	status *flowslatest.ClusterNetworkStatus
+}
+
+type batchTuningState struct {
+	status *flowslatest.BatchTuningStatus
 }
 
 func NewManager() *Manager {
@@ -44,6 +81,11 @@ func NewManager() *Manager {
 	return &s
 }
 
+// SetEventRecorder enables events on the FlowCollector, emitted when a component fails with a classified error
+func (s *Manager) SetEventRecorder(recorder record.EventRecorder) {
+	s.recorder = recorder
+}
+
 func (s *Manager) setInProgress(cpnt ComponentName, reason, message string) {
 	s.statuses.Store(cpnt, ComponentStatus{
 		name:    cpnt,
@@ -67,6 +109,11 @@ func (s *Manager) hasFailure(cpnt ComponentName) bool {
 	return v != nil && v.(ComponentStatus).status == StatusFailure
 }
 
+func (s *Manager) isReady(cpnt ComponentName) bool {
+	v, _ := s.statuses.Load(cpnt)
+	return v != nil && v.(ComponentStatus).status == StatusReady
+}
+
 func (s *Manager) setReady(cpnt ComponentName) {
 	s.statuses.Store(cpnt, ComponentStatus{
 		name:   cpnt,
@@ -90,6 +137,21 @@ func (s *Manager) setUnused(cpnt ComponentName, message string) {
 	})
 }
 
+func (s *Manager) setWarning(cpnt ComponentName, reason, message string) {
+	s.warnings.Store(cpnt, ComponentStatus{
+		name:    cpnt,
+		reason:  reason,
+		message: message,
+	})
+}
+
+func (s *Manager) clearWarning(cpnt ComponentName) {
+	// keep the entry, so that the warning condition is set back to false
+	if _, ok := s.warnings.Load(cpnt); ok {
+		s.warnings.Store(cpnt, ComponentStatus{name: cpnt})
+	}
+}
+
 func (s *Manager) getConditions() []metav1.Condition {
 	global := metav1.Condition{
 		Type:   "Ready",
@@ -112,19 +174,79 @@ func (s *Manager) getConditions() []metav1.Condition {
 		global.Status = metav1.ConditionFalse
 		global.Reason = "Pending"
 	}
+	s.warnings.Range(func(_, v any) bool {
+		warning := v.(ComponentStatus)
+		conds = append(conds, warning.toWarningCondition())
+		return true
+	})
 	return append([]metav1.Condition{global}, conds...)
 }
 
+func objectKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func (s *Manager) setObject(obj flowslatest.FlowCollectorComponentObject) {
+	s.objects.Store(objectKey(obj.Kind, obj.Namespace, obj.Name), obj)
+}
+
+func (s *Manager) removeObject(kind, namespace, name string) {
+	s.objects.Delete(objectKey(kind, namespace, name))
+}
+
+func (s *Manager) getComponents() []flowslatest.FlowCollectorComponentObject {
+	components := []flowslatest.FlowCollectorComponentObject{}
+	s.objects.Range(func(_, v any) bool {
+		components = append(components, v.(flowslatest.FlowCollectorComponentObject))
+		return true
+	})
+	sort.Slice(components, func(i, j int) bool {
+		return objectKey(components[i].Kind, components[i].Namespace, components[i].Name) <
+			objectKey(components[j].Kind, components[j].Namespace, components[j].Name)
+	})
+	return components
+}
+
+// getObservedGeneration returns the lowest generation reconciled by the controllers, 0 when none reconciled yet
+func (s *Manager) getObservedGeneration() int64 {
+	var observed int64
+	s.generations.Range(func(_, v any) bool {
+		if gen := v.(int64); observed == 0 || gen < observed {
+			observed = gen
+		}
+		return true
+	})
+	return observed
+}
+
+func (s *Manager) getAgentStatus() *flowslatest.FlowCollectorAgentStatus {
+	nodes := s.agentNodes.Load()
+	if nodes == nil {
+		return nil
+	}
+	agent := flowslatest.FlowCollectorAgentStatus{Nodes: *nodes}
+	if conflicts := s.agentConflicts.Load(); conflicts != nil {
+		agent.Conflicts = *conflicts
+	}
+	if modes := s.agentAttachModes.Load(); modes != nil {
+		agent.AttachModes = *modes
+	}
+	return &agent
+}
+
 func (s *Manager) Sync(ctx context.Context, c client.Client) {
-	updateStatus(ctx, c, s.getConditions()...)
+	updateStatus(ctx, c, s.recorder, s.getComponents(), s.getAgentStatus(), s.lokiMigration.Load(), s.clusterNetwork.Load(), s.batchTuning.Load(), s.getObservedGeneration(), s.getConditions()...)
 }
 
-func updateStatus(ctx context.Context, c client.Client, conditions ...metav1.Condition) {
+func updateStatus(ctx context.Context, c client.Client, recorder record.EventRecorder, components []flowslatest.FlowCollectorComponentObject, agent *flowslatest.FlowCollectorAgentStatus, migration *lokiMigrationState, network *clusterNetworkState, tuning *batchTuningState, observedGeneration int64, conditions ...metav1.Condition) {
 	log := log.FromContext(ctx)
 	log.Info("Updating FlowCollector status")
 
+	var fc flowslatest.FlowCollector
+	var newErrors []metav1.Condition
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		fc := flowslatest.FlowCollector{}
+		fc = flowslatest.FlowCollector{}
+		newErrors = nil
 		if err := c.Get(ctx, constants.FlowCollectorName, &fc); err != nil {
 			if errors.IsNotFound(err) {
 				// ignore: when it's being deleted, there's no point trying to update its status
@@ -133,14 +255,55 @@ func updateStatus(ctx context.Context, c client.Client, conditions ...metav1.Con
 			return err
 		}
 		for _, c := range conditions {
+			if isNewError(fc.Status.Conditions, &c) {
+				newErrors = append(newErrors, c)
+			}
 			meta.SetStatusCondition(&fc.Status.Conditions, c)
 		}
+		fc.Status.Components = components
+		fc.Status.Agent = agent
+		if migration != nil {
+			fc.Status.LokiMigration = migration.status
+		}
+		if network != nil {
+			fc.Status.ClusterNetwork = network.status
+		}
+		if tuning != nil {
+			fc.Status.BatchTuning = tuning.status
+		}
+		if observedGeneration > 0 {
+			fc.Status.ObservedGeneration = observedGeneration
+		}
 		return c.Status().Update(ctx, &fc)
 	})
 
 	if err != nil {
 		log.Error(err, "failed to update FlowCollector status")
+		return
+	}
+	if recorder != nil {
+		for _, c := range newErrors {
+			recorder.Event(&fc, v1.EventTypeWarning, c.Reason, fmt.Sprintf("%s: %s", c.Type, c.Message))
+		}
+	}
+}
+
+// isNewError tells whether the condition reports a classified error that isn't already in the status
+func isNewError(current []metav1.Condition, c *metav1.Condition) bool {
+	if c.Status != metav1.ConditionFalse || !isErrorReason(c.Reason) {
+		return false
 	}
+	prev := meta.FindStatusCondition(current, c.Type)
+	return prev == nil || prev.Reason != c.Reason || prev.Message != c.Message
+}
+
+func isErrorReason(reason string) bool {
+	for _, kind := range []ErrorKind{ErrorPermission, ErrorDependency, ErrorInvalidSpec, ErrorTransient} {
+		if reason == kind.Reason() {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Manager) ForComponent(cpnt ComponentName) Instance {
@@ -152,6 +315,10 @@ type Instance struct {
 	s    *Manager
 }
 
+func (i *Instance) Name() ComponentName {
+	return i.cpnt
+}
+
 func (i *Instance) SetReady() {
 	i.s.setReady(i.cpnt)
 }
@@ -164,11 +331,18 @@ func (i *Instance) SetUnused(message string) {
 	i.s.setUnused(i.cpnt, message)
 }
 
+func (i *Instance) SetInProgress(reason, message string) {
+	i.s.setInProgress(i.cpnt, reason, message)
+}
+
 func (i *Instance) CheckDeploymentProgress(d *appsv1.Deployment) {
 	// TODO (when legacy controller is broken down into individual controllers)
 	// this should set the status as Ready when replicas match
 	if d == nil {
 		i.s.setInProgress(i.cpnt, "DeploymentNotCreated", "Deployment not created")
+	} else if d.Status.ObservedGeneration < d.Generation || d.Status.UpdatedReplicas < d.Status.Replicas {
+		// still available during a rolling update, but some pods run the previous revision
+		i.s.setInProgress(i.cpnt, "DeploymentNotReady", fmt.Sprintf("Deployment %s rolling out: %d/%d", d.Name, d.Status.UpdatedReplicas, d.Status.Replicas))
 	} else {
 		for _, c := range d.Status.Conditions {
 			if c.Type == appsv1.DeploymentAvailable {
@@ -186,7 +360,7 @@ func (i *Instance) CheckDaemonSetProgress(ds *appsv1.DaemonSet) {
 	// this should set the status as Ready when replicas match
 	if ds == nil {
 		i.s.setInProgress(i.cpnt, "DaemonSetNotCreated", "DaemonSet not created")
-	} else if ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+	} else if ds.Status.ObservedGeneration < ds.Generation || ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
 		i.s.setInProgress(i.cpnt, "DaemonSetNotReady", fmt.Sprintf("DaemonSet %s not ready: %d/%d", ds.Name, ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled))
 	}
 }
@@ -199,12 +373,93 @@ func (i *Instance) SetCreatingDaemonSet(ds *appsv1.DaemonSet) {
 	i.s.setInProgress(i.cpnt, "CreatingDaemonSet", fmt.Sprintf("Creating daemon set %s", ds.Name))
 }
 
+func (i *Instance) SetUpdatingDeployment(d *appsv1.Deployment) {
+	i.s.setInProgress(i.cpnt, "UpdatingDeployment", fmt.Sprintf("Updating deployment %s", d.Name))
+}
+
+func (i *Instance) SetUpdatingDaemonSet(ds *appsv1.DaemonSet) {
+	i.s.setInProgress(i.cpnt, "UpdatingDaemonSet", fmt.Sprintf("Updating daemon set %s", ds.Name))
+}
+
+// SetObject records an object deployed by the operator, listed in `status.components`
+func (i *Instance) SetObject(kind string, obj client.Object) {
+	i.s.setObject(flowslatest.FlowCollectorComponentObject{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Revision:  obj.GetGeneration(),
+		Ready:     isObjectReady(obj),
+	})
+}
+
+// RemoveObject removes an object that is not deployed anymore from `status.components`
+func (i *Instance) RemoveObject(kind, namespace, name string) {
+	i.s.removeObject(kind, namespace, name)
+}
+
+// SetAgentNodes records the per-node summary of the eBPF agent, listed in `status.agent.nodes`; nil removes it
+func (i *Instance) SetAgentNodes(nodes *flowslatest.AgentNodesStatus) {
+	i.s.agentNodes.Store(nodes)
+}
+
+// SetAgentConflicts records the eBPF users that might conflict with the agent, listed in `status.agent.conflicts`
+func (i *Instance) SetAgentConflicts(conflicts []flowslatest.AgentConflict) {
+	i.s.agentConflicts.Store(&conflicts)
+}
+
+// SetAgentAttachModes records the number of nodes per eBPF attach mode, listed in `status.agent.attachModes`
+func (i *Instance) SetAgentAttachModes(modes []flowslatest.AgentAttachModeCount) {
+	i.s.agentAttachModes.Store(&modes)
+}
+
+// SetLokiMigration records the checklist of the Loki migration, listed in `status.lokiMigration`; nil removes it
+func (i *Instance) SetLokiMigration(migration *flowslatest.LokiMigrationStatus) {
+	i.s.lokiMigration.Store(&lokiMigrationState{status: migration})
+}
+
+// SetClusterNetwork records the cluster network configuration, listed in `status.clusterNetwork`; nil removes it
+func (i *Instance) SetClusterNetwork(network *flowslatest.ClusterNetworkStatus) {
+	i.s.clusterNetwork.Store(&clusterNetworkState{status: network})
+}
+
+// SetBatchTuning records the batch sizes picked from the flow rate, listed in `status.batchTuning`; nil removes it
+func (i *Instance) SetBatchTuning(tuning *flowslatest.BatchTuningStatus) {
+	i.s.batchTuning.Store(&batchTuningState{status: tuning})
+}
+
+// SetObservedGeneration records that the controller reconciled this FlowCollector generation
+func (i *Instance) SetObservedGeneration(generation int64) {
+	i.s.generations.Store(i.cpnt, generation)
+}
+
+func isObjectReady(obj client.Object) bool {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		desired := ptr.Deref(o.Spec.Replicas, 1)
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.UpdatedReplicas >= desired && o.Status.AvailableReplicas >= desired
+	case *appsv1.DaemonSet:
+		desired := o.Status.DesiredNumberScheduled
+		return o.Status.ObservedGeneration >= o.Generation && o.Status.UpdatedNumberScheduled >= desired && o.Status.NumberAvailable >= desired
+	}
+	return true
+}
+
+// SetWarning reports an issue that doesn't prevent the component from working, as a `<component>Warning` condition
+func (i *Instance) SetWarning(reason, message string) {
+	i.s.setWarning(i.cpnt, reason, message)
+}
+
+func (i *Instance) ClearWarning() {
+	i.s.clearWarning(i.cpnt)
+}
+
 func (i *Instance) SetFailure(reason, message string) {
 	i.s.setFailure(i.cpnt, reason, message)
 }
 
-func (i *Instance) Error(reason string, err error) error {
-	i.SetFailure(reason, err.Error())
+// Error sets the component in failure, with a condition reason depending on the kind of the error, and the failing step in the message
+func (i *Instance) Error(step string, err error) error {
+	i.SetFailure(KindOf(err).Reason(), fmt.Sprintf("%s: %s", step, err.Error()))
 	return err
 }
 
@@ -212,6 +467,11 @@ func (i *Instance) HasFailure() bool {
 	return i.s.hasFailure(i.cpnt)
 }
 
+// IsReady returns true when the component is ready, in particular when its pods are all updated
+func (i *Instance) IsReady() bool {
+	return i.s.isReady(i.cpnt)
+}
+
 func (i *Instance) Commit(ctx context.Context, c client.Client) {
 	i.s.Sync(ctx, c)
 }
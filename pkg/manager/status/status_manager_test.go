@@ -1,11 +1,18 @@
 package status
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 )
 
 func TestStatusWorkflow(t *testing.T) {
@@ -63,6 +70,69 @@ func TestStatusWorkflow(t *testing.T) {
 	assertHasCondition(t, conds, "MonitoringReady", "Ready", metav1.ConditionTrue)
 }
 
+func TestRolloutProgress(t *testing.T) {
+	s := NewManager()
+	sl := s.ForComponent(FlowCollectorLegacy)
+
+	// the controller hasn't observed the new revision yet
+	sl.SetReady()
+	sl.CheckDaemonSetProgress(&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2}, Status: appsv1.DaemonSetStatus{
+		ObservedGeneration:     1,
+		DesiredNumberScheduled: 3,
+		UpdatedNumberScheduled: 3,
+	}})
+	assert.False(t, sl.IsReady())
+	assertHasCondition(t, s.getConditions(), "FlowCollectorLegacyReady", "DaemonSetNotReady", metav1.ConditionFalse)
+
+	// still available, but rolling out
+	sl.SetReady()
+	sl.CheckDeploymentProgress(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2}, Status: appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           3,
+		UpdatedReplicas:    1,
+		Conditions:         []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+	}})
+	assert.False(t, sl.IsReady())
+	assertHasCondition(t, s.getConditions(), "FlowCollectorLegacyReady", "DeploymentNotReady", metav1.ConditionFalse)
+
+	sl.SetReady()
+	sl.CheckDeploymentProgress(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2}, Status: appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           3,
+		UpdatedReplicas:    3,
+		Conditions:         []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+	}})
+	assert.True(t, sl.IsReady())
+}
+
+func TestStatusComponents(t *testing.T) {
+	s := NewManager()
+	sl := s.ForComponent(FlowCollectorLegacy)
+	sm := s.ForComponent(Monitoring)
+
+	sl.SetObject("DaemonSet", &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "ns-privileged", Generation: 2},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     2,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 1,
+			NumberAvailable:        3,
+		},
+	})
+	sm.SetObject("ConfigMap", &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Namespace: "ns"}})
+
+	components := s.getComponents()
+	assert.Equal(t, []flowslatest.FlowCollectorComponentObject{
+		{Kind: "ConfigMap", Namespace: "ns", Name: "dashboard", Ready: true},
+		{Kind: "DaemonSet", Namespace: "ns-privileged", Name: "agent", Revision: 2, Ready: false},
+	}, components)
+
+	sm.RemoveObject("ConfigMap", "ns", "dashboard")
+	components = s.getComponents()
+	assert.Len(t, components, 1)
+	assert.Equal(t, "agent", components[0].Name)
+}
+
 func assertHasCondition(t *testing.T, conditions []metav1.Condition, searchType, reason string, value metav1.ConditionStatus) {
 	for _, c := range conditions {
 		if c.Type == searchType {
@@ -73,3 +143,71 @@ func assertHasCondition(t *testing.T, conditions []metav1.Condition, searchType,
 	}
 	assert.Fail(t, "Condition type not found", searchType, conditions)
 }
+
+func TestStatusWarning(t *testing.T) {
+	s := NewManager()
+	sl := s.ForComponent(FlowCollectorLegacy)
+	sm := s.ForComponent(Monitoring)
+	sl.SetReady()
+	sm.SetReady()
+
+	// no warning condition until one is set
+	sl.ClearWarning()
+	assert.Len(t, s.getConditions(), 3)
+
+	sl.SetWarning("LokiQueryLimitsExceeded", "too many")
+	conds := s.getConditions()
+	assert.Len(t, conds, 4)
+	assertHasCondition(t, conds, "Ready", "Ready", metav1.ConditionTrue)
+	assertHasCondition(t, conds, "FlowCollectorLegacyWarning", "LokiQueryLimitsExceeded", metav1.ConditionTrue)
+
+	sl.ClearWarning()
+	conds = s.getConditions()
+	assert.Len(t, conds, 4)
+	assertHasCondition(t, conds, "FlowCollectorLegacyWarning", "NoWarning", metav1.ConditionFalse)
+}
+
+func TestObservedGeneration(t *testing.T) {
+	s := NewManager()
+	sl := s.ForComponent(FlowCollectorLegacy)
+	sf := s.ForComponent(FLPParent)
+
+	// nothing reconciled yet
+	assert.Equal(t, int64(0), s.getObservedGeneration())
+
+	sl.SetObservedGeneration(3)
+	sf.SetObservedGeneration(2)
+	assert.Equal(t, int64(2), s.getObservedGeneration())
+
+	// all controllers caught up
+	sf.SetObservedGeneration(3)
+	assert.Equal(t, int64(3), s.getObservedGeneration())
+}
+
+func TestStatusErrorKinds(t *testing.T) {
+	s := NewManager()
+	sl := s.ForComponent(FlowCollectorLegacy)
+	sm := s.ForComponent(Monitoring)
+	gr := schema.GroupResource{Group: "console.openshift.io", Resource: "consoleplugins"}
+
+	_ = sl.Error("ReconcileConsolePluginFailed", fmt.Errorf("reconciling ConsolePlugin: %w", kerr.NewForbidden(gr, "netobserv-plugin", errors.New("denied"))))
+	_ = sm.Error("LokiStackError", InvalidSpecError("LokiStack %s has no tenants configured", "loki"))
+	conds := s.getConditions()
+	assertHasCondition(t, conds, "FlowCollectorLegacyReady", "MissingPermission", metav1.ConditionFalse)
+	assertHasCondition(t, conds, "MonitoringReady", "InvalidSpec", metav1.ConditionFalse)
+
+	_ = sl.Error("ReconcileConsolePluginFailed", kerr.NewNotFound(schema.GroupResource{Resource: "secrets"}, "loki-token"))
+	_ = sm.Error("MonitoringError", errors.New("boom"))
+	conds = s.getConditions()
+	assertHasCondition(t, conds, "FlowCollectorLegacyReady", "DependencyUnavailable", metav1.ConditionFalse)
+	assertHasCondition(t, conds, "MonitoringReady", "TransientError", metav1.ConditionFalse)
+
+	// permission errors take precedence over an explicit kind
+	assert.Equal(t, ErrorPermission, KindOf(DependencyError(kerr.NewUnauthorized("no token"))))
+
+	// events are only emitted for new errors
+	c := metav1.Condition{Type: "MonitoringReady", Status: metav1.ConditionFalse, Reason: "TransientError", Message: "MonitoringError: boom"}
+	assert.True(t, isNewError(nil, &c))
+	assert.False(t, isNewError([]metav1.Condition{c}, &c))
+	assert.False(t, isNewError(nil, &metav1.Condition{Type: "MonitoringReady", Status: metav1.ConditionFalse, Reason: "DeploymentNotReady"}))
+}
@@ -39,3 +39,17 @@ func (s *ComponentStatus) toCondition() metav1.Condition {
 	}
 	return c
 }
+
+func (s *ComponentStatus) toWarningCondition() metav1.Condition {
+	c := metav1.Condition{
+		Type:   string(s.name) + "Warning",
+		Status: metav1.ConditionFalse,
+		Reason: "NoWarning",
+	}
+	if s.reason != "" {
+		c.Status = metav1.ConditionTrue
+		c.Reason = s.reason
+		c.Message = s.message
+	}
+	return c
+}
@@ -3,6 +3,7 @@ package metrics
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
 	flpapi "github.com/netobserv/flowlogs-pipeline/pkg/api"
@@ -11,13 +12,23 @@ import (
 )
 
 const (
-	tagNamespaces = "namespaces"
-	tagNodes      = "nodes"
-	tagWorkloads  = "workloads"
-	tagIngress    = "ingress"
-	tagEgress     = "egress"
-	tagBytes      = "bytes"
-	tagPackets    = "packets"
+	tagNamespaces    = "namespaces"
+	tagNodes         = "nodes"
+	tagWorkloads     = "workloads"
+	tagIngress       = "ingress"
+	tagEgress        = "egress"
+	tagBytes         = "bytes"
+	tagPackets       = "packets"
+	tagConversations = "conversations"
+	tagVMs           = "vms"
+	tagMesh          = "mesh"
+	tagDSCP          = "dscp"
+	tagICMP          = "icmp"
+	tagSCTP          = "sctp"
+	tagEgressClass   = "egress-class"
+
+	// DefaultPrefix is the prefix of the flow metrics names, unless overridden in FlowCollector
+	DefaultPrefix = "netobserv_"
 )
 
 var (
@@ -25,6 +36,7 @@ var (
 		tagNodes:      {"SrcK8S_HostName", "DstK8S_HostName"},
 		tagNamespaces: {"SrcK8S_Namespace", "DstK8S_Namespace"},
 		tagWorkloads:  {"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_OwnerName", "DstK8S_OwnerName", "SrcK8S_OwnerType", "DstK8S_OwnerType"},
+		tagVMs:        {"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_VirtualMachine", "DstK8S_VirtualMachine"},
 	}
 	mapValueFields = map[string]string{
 		tagBytes:   "Bytes",
@@ -44,6 +56,8 @@ var (
 		"namespace_rtt_seconds",
 		"namespace_dns_latency_seconds",
 	}
+	// Conversation sizes span from a few bytes to gigabytes
+	conversationBytesBuckets = []float64{100, 1_000, 10_000, 100_000, 1_000_000, 10_000_000, 100_000_000, 1_000_000_000}
 	// Pre-deprecation default IgnoreTags list (1.4) - used before switching to whitelist approach,
 	// to make sure there is no unintended new metrics being collected
 	// Don't add anything here: this is not meant to evolve
@@ -78,6 +92,58 @@ func init() {
 				})
 			}
 		}
+		// Bytes / packets metrics per DSCP service class
+		dscpLabels := append(labels, "DscpClass")
+		for _, vt := range []string{tagBytes, tagPackets} {
+			for _, dir := range []string{tagEgress, tagIngress} {
+				predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+					MetricsItem: flpapi.MetricsItem{
+						Name:     fmt.Sprintf("%s_dscp_%s_%s_total", groupTrimmed, dir, vt),
+						Type:     "counter",
+						ValueKey: mapValueFields[vt],
+						Filters: []flpapi.MetricsFilter{
+							{Key: "Duplicate", Value: "true", Type: flpapi.MetricFilterNotEqual},
+							{Key: "FlowDirection", Value: mapDirection[dir], Type: flpapi.MetricFilterRegex},
+						},
+						Labels: dscpLabels,
+					},
+					tags: []string{group, tagDSCP, vt, dir},
+				})
+			}
+		}
+		// Egress bytes metrics per destination class, such as to estimate the cost of the internet egress
+		predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+			MetricsItem: flpapi.MetricsItem{
+				Name:     fmt.Sprintf("%s_egress_class_bytes_total", groupTrimmed),
+				Type:     "counter",
+				ValueKey: "Bytes",
+				Filters: []flpapi.MetricsFilter{
+					{Key: "Duplicate", Value: "true", Type: flpapi.MetricFilterNotEqual},
+					{Key: "FlowDirection", Value: mapDirection[tagEgress], Type: flpapi.MetricFilterRegex},
+				},
+				Labels: append(labels, "DstEgressClass"),
+			},
+			tags: []string{group, tagEgressClass, tagBytes, tagEgress},
+		})
+		// SCTP bytes / packets metrics, such as for the signaling interfaces of 5G core networks
+		for _, vt := range []string{tagBytes, tagPackets} {
+			for _, dir := range []string{tagEgress, tagIngress} {
+				predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+					MetricsItem: flpapi.MetricsItem{
+						Name:     fmt.Sprintf("%s_sctp_%s_%s_total", groupTrimmed, dir, vt),
+						Type:     "counter",
+						ValueKey: mapValueFields[vt],
+						Filters: []flpapi.MetricsFilter{
+							{Key: "Duplicate", Value: "true", Type: flpapi.MetricFilterNotEqual},
+							{Key: "FlowDirection", Value: mapDirection[dir], Type: flpapi.MetricFilterRegex},
+							{Key: "Proto", Value: "132", Type: flpapi.MetricFilterEqual},
+						},
+						Labels: labels,
+					},
+					tags: []string{group, tagSCTP, vt, dir},
+				})
+			}
+		}
 		// Flows metrics
 		predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
 			MetricsItem: flpapi.MetricsItem{
@@ -128,6 +194,21 @@ func init() {
 			},
 			tags: []string{group, tagBytes, "drop"},
 		})
+		// ICMP errors: destination unreachable and time exceeded, which are types 3 and 11 in ICMP, 1 and 3 in ICMPv6
+		predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+			MetricsItem: flpapi.MetricsItem{
+				Name:     fmt.Sprintf("%s_icmp_error_packets_total", groupTrimmed),
+				Type:     "counter",
+				ValueKey: "Packets",
+				Filters: []flpapi.MetricsFilter{
+					{Key: "Duplicate", Value: "true", Type: flpapi.MetricFilterNotEqual},
+					{Key: "Proto", Value: "^(1|58)$", Type: flpapi.MetricFilterRegex},
+					{Key: "IcmpType", Value: "^(1|3|11)$", Type: flpapi.MetricFilterRegex},
+				},
+				Labels: append(labels, "Proto", "IcmpType", "IcmpCode"),
+			},
+			tags: []string{group, tagPackets, tagICMP},
+		})
 		// DNS metrics
 		dnsLabels := append(labels, "DnsFlagsResponseCode")
 		predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
@@ -143,7 +224,81 @@ func init() {
 			},
 			tags: []string{group, "dns"},
 		})
+		// Conversation metrics, computed on the records emitted when a conversation ends
+		endConnectionFilter := flpapi.MetricsFilter{Key: flpapi.RecordTypeFieldName, Value: string(flpapi.ConnTrackEndConnection), Type: flpapi.MetricFilterEqual}
+		predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+			MetricsItem: flpapi.MetricsItem{
+				Name:    fmt.Sprintf("%s_conversations_total", groupTrimmed),
+				Type:    "counter",
+				Filters: []flpapi.MetricsFilter{endConnectionFilter},
+				Labels:  labels,
+			},
+			tags: []string{group, tagConversations},
+		})
+		predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+			MetricsItem: flpapi.MetricsItem{
+				Name:     fmt.Sprintf("%s_conversation_bytes", groupTrimmed),
+				Type:     "histogram",
+				ValueKey: "Bytes",
+				Filters:  []flpapi.MetricsFilter{endConnectionFilter},
+				Labels:   labels,
+				Buckets:  conversationBytesBuckets,
+			},
+			tags: []string{group, tagBytes, tagConversations},
+		})
+	}
+	// Virtual machines metrics, only for bytes, packets and flows
+	vmLabels := mapLabels[tagVMs]
+	for _, vt := range []string{tagBytes, tagPackets} {
+		for _, dir := range []string{tagEgress, tagIngress} {
+			predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+				MetricsItem: flpapi.MetricsItem{
+					Name:     fmt.Sprintf("vm_%s_%s_total", dir, vt),
+					Type:     "counter",
+					ValueKey: mapValueFields[vt],
+					Filters: []flpapi.MetricsFilter{
+						{Key: "Duplicate", Value: "true", Type: flpapi.MetricFilterNotEqual},
+						{Key: "FlowDirection", Value: mapDirection[dir], Type: flpapi.MetricFilterRegex},
+					},
+					Labels: vmLabels,
+				},
+				tags: []string{tagVMs, vt, dir},
+			})
+		}
+	}
+	predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+		MetricsItem: flpapi.MetricsItem{
+			Name:   "vm_flows_total",
+			Type:   "counter",
+			Labels: vmLabels,
+		},
+		tags: []string{tagVMs, "flows"},
+	})
+	// Service mesh metrics, on the fields read from the Istio labels of pods
+	meshFilters := []flpapi.MetricsFilter{
+		{Key: "Duplicate", Value: "true", Type: flpapi.MetricFilterNotEqual},
+		{Key: "FlowDirection", Value: mapDirection[tagIngress], Type: flpapi.MetricFilterRegex},
 	}
+	predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+		MetricsItem: flpapi.MetricsItem{
+			Name:     "mesh_service_ingress_bytes_total",
+			Type:     "counter",
+			ValueKey: "Bytes",
+			Filters:  meshFilters,
+			Labels:   []string{"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_MeshService", "DstK8S_MeshService", "DstK8S_MeshRevision"},
+		},
+		tags: []string{tagMesh, tagBytes, tagIngress},
+	})
+	predefinedMetrics = append(predefinedMetrics, taggedMetricDefinition{
+		MetricsItem: flpapi.MetricsItem{
+			Name:     "mesh_sidecar_ingress_bytes_total",
+			Type:     "counter",
+			ValueKey: "Bytes",
+			Filters:  meshFilters,
+			Labels:   []string{"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_MeshTLSMode", "DstK8S_MeshTLSMode"},
+		},
+		tags: []string{tagMesh, tagBytes, tagIngress},
+	})
 }
 
 func isIgnored(def *taggedMetricDefinition, ignoreTags []string) bool {
@@ -158,6 +313,8 @@ func isIgnored(def *taggedMetricDefinition, ignoreTags []string) bool {
 }
 
 func convertIgnoreTagsToIncludeList(ignoreTags []string) []flowslatest.FLPMetric {
+	// Conversation, virtual machines, mesh, DSCP, ICMP, SCTP and egress class metrics were introduced after ignoreTags deprecation: they are never converted
+	ignoreTags = append(slices.Clone(ignoreTags), tagConversations, tagVMs, tagMesh, tagDSCP, tagICMP, tagSCTP, tagEgressClass)
 	ret := []flowslatest.FLPMetric{}
 	for i := range predefinedMetrics {
 		if !isIgnored(&predefinedMetrics[i], ignoreTags) {
@@ -216,9 +373,31 @@ func GetIncludeList(spec *flowslatest.FlowCollectorSpec) []string {
 	if !helper.IsDNSTrackingEnabled(&spec.Agent.EBPF) {
 		list = removeMetricsByPattern(list, "_dns_")
 	}
+	if !helper.HasEndedConversations(&spec.Processor) {
+		list = removeMetricsByPattern(list, "_conversation")
+	}
+	if !helper.IsVirtualizationEnabled(&spec.Processor) {
+		list = removeMetricsByPattern(list, "vm_")
+	}
+	if !helper.IsServiceMeshEnabled(&spec.Processor) {
+		list = removeMetricsByPattern(list, "mesh_")
+	}
+	if !helper.IsDSCPClassificationEnabled(&spec.Processor) {
+		list = removeMetricsByPattern(list, "_dscp_")
+	}
+	if !helper.IsEgressClassificationEnabled(&spec.Processor) {
+		list = removeMetricsByPattern(list, "_egress_class_")
+	}
 	return list
 }
 
+func GetPrefix(spec *flowslatest.FlowCollectorSpec) string {
+	if spec.Processor.Metrics.Prefix == nil {
+		return DefaultPrefix
+	}
+	return *spec.Processor.Metrics.Prefix
+}
+
 func removeMetricsByPattern(list []string, search string) []string {
 	var filtered []string
 	for _, m := range list {
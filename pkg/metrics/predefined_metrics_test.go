@@ -3,8 +3,10 @@ package metrics
 import (
 	"testing"
 
+	flpapi "github.com/netobserv/flowlogs-pipeline/pkg/api"
 	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
 )
 
 func TestIncludeExclude(t *testing.T) {
@@ -55,3 +57,166 @@ func TestGetDefinitions(t *testing.T) {
 	assert.Equal("Packets", res[2].ValueKey)
 	assert.Equal([]string{"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_OwnerName", "DstK8S_OwnerName", "SrcK8S_OwnerType", "DstK8S_OwnerType"}, res[2].Labels)
 }
+
+func TestConversationMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{
+		Processor: flowslatest.FlowCollectorFLP{
+			Metrics: flowslatest.FLPMetrics{
+				IncludeList: &[]flowslatest.FLPMetric{"namespace_flows_total", "namespace_conversations_total", "workload_conversation_bytes"},
+			},
+		},
+	}
+
+	// Conversation tracking disabled => conversation metrics are removed
+	assert.Equal([]string{"namespace_flows_total"}, GetIncludeList(&spec))
+
+	logTypes := flowslatest.LogTypeEndedConversations
+	spec.Processor.LogTypes = &logTypes
+	names := GetIncludeList(&spec)
+	assert.Equal([]string{"namespace_flows_total", "namespace_conversations_total", "workload_conversation_bytes"}, names)
+
+	res := GetDefinitions(names)
+	assert.Len(res, 3)
+	assert.Equal("namespace_conversations_total", res[1].Name)
+	assert.Equal([]flpapi.MetricsFilter{{Key: "_RecordType", Value: "endConnection", Type: flpapi.MetricFilterEqual}}, res[1].Filters)
+	assert.Equal("workload_conversation_bytes", res[2].Name)
+	assert.Equal("histogram", string(res[2].Type))
+	assert.Equal("Bytes", res[2].ValueKey)
+	assert.NotEmpty(res[2].Buckets)
+}
+
+func TestVirtualMachineMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{
+		Processor: flowslatest.FlowCollectorFLP{
+			Metrics: flowslatest.FLPMetrics{
+				IncludeList: &[]flowslatest.FLPMetric{"namespace_flows_total", "vm_ingress_bytes_total", "vm_flows_total"},
+			},
+		},
+	}
+
+	// Virtualization disabled => VM metrics are removed
+	assert.Equal([]string{"namespace_flows_total"}, GetIncludeList(&spec))
+
+	spec.Processor.Virtualization.Enable = ptr.To(true)
+	names := GetIncludeList(&spec)
+	assert.Equal([]string{"namespace_flows_total", "vm_ingress_bytes_total", "vm_flows_total"}, names)
+
+	res := GetDefinitions(names)
+	assert.Len(res, 3)
+	assert.Equal("vm_ingress_bytes_total", res[1].Name)
+	assert.Equal("Bytes", res[1].ValueKey)
+	assert.Equal([]string{"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_VirtualMachine", "DstK8S_VirtualMachine"}, res[1].Labels)
+	assert.Equal("vm_flows_total", res[2].Name)
+
+	// VM metrics are never derived from ignoreTags
+	assert.NotContains(*GetAsIncludeList([]string{"egress"}, nil), flowslatest.FLPMetric("vm_ingress_bytes_total"))
+}
+
+func TestServiceMeshMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{
+		Processor: flowslatest.FlowCollectorFLP{
+			Metrics: flowslatest.FLPMetrics{
+				IncludeList: &[]flowslatest.FLPMetric{"namespace_flows_total", "mesh_service_ingress_bytes_total", "mesh_sidecar_ingress_bytes_total"},
+			},
+		},
+	}
+
+	// Service mesh disabled => mesh metrics are removed
+	assert.Equal([]string{"namespace_flows_total"}, GetIncludeList(&spec))
+
+	spec.Processor.ServiceMesh.Enable = ptr.To(true)
+	names := GetIncludeList(&spec)
+	assert.Equal([]string{"namespace_flows_total", "mesh_service_ingress_bytes_total", "mesh_sidecar_ingress_bytes_total"}, names)
+
+	res := GetDefinitions(names)
+	assert.Len(res, 3)
+	assert.Equal("mesh_service_ingress_bytes_total", res[1].Name)
+	assert.Contains(res[1].Labels, "DstK8S_MeshRevision")
+	assert.Equal("mesh_sidecar_ingress_bytes_total", res[2].Name)
+	assert.Equal([]string{"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_MeshTLSMode", "DstK8S_MeshTLSMode"}, res[2].Labels)
+}
+
+func TestDSCPMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{
+		Processor: flowslatest.FlowCollectorFLP{
+			Metrics: flowslatest.FLPMetrics{
+				IncludeList: &[]flowslatest.FLPMetric{"namespace_flows_total", "namespace_dscp_ingress_bytes_total", "node_dscp_egress_packets_total"},
+			},
+		},
+	}
+
+	// DSCP classification disabled => DSCP metrics are removed
+	assert.Equal([]string{"namespace_flows_total"}, GetIncludeList(&spec))
+
+	spec.Processor.DSCPClassification.Enable = ptr.To(true)
+	names := GetIncludeList(&spec)
+	assert.Equal([]string{"namespace_flows_total", "namespace_dscp_ingress_bytes_total", "node_dscp_egress_packets_total"}, names)
+
+	res := GetDefinitions(names)
+	assert.Len(res, 3)
+	assert.Equal("node_dscp_egress_packets_total", res[0].Name)
+	assert.Equal("Packets", res[0].ValueKey)
+	assert.Equal([]string{"SrcK8S_HostName", "DstK8S_HostName", "DscpClass"}, res[0].Labels)
+	assert.Equal("namespace_dscp_ingress_bytes_total", res[1].Name)
+	assert.Equal([]string{"SrcK8S_Namespace", "DstK8S_Namespace", "DscpClass"}, res[1].Labels)
+	assert.Equal("0|2", res[1].Filters[1].Value)
+}
+
+func TestICMPMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	res := GetDefinitions([]string{"workload_icmp_error_packets_total"})
+	assert.Len(res, 1)
+	assert.Equal("Packets", res[0].ValueKey)
+	assert.Equal([]string{"SrcK8S_Namespace", "DstK8S_Namespace", "SrcK8S_OwnerName", "DstK8S_OwnerName", "SrcK8S_OwnerType", "DstK8S_OwnerType", "Proto", "IcmpType", "IcmpCode"}, res[0].Labels)
+	assert.Equal(flpapi.MetricsFilter{Key: "IcmpType", Value: "^(1|3|11)$", Type: flpapi.MetricFilterRegex}, res[0].Filters[2])
+
+	// Not converted from ignore tags
+	assert.NotContains(*GetAsIncludeList([]string{"egress"}, nil), flowslatest.FLPMetric("namespace_icmp_error_packets_total"))
+}
+
+func TestSCTPMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	res := GetDefinitions([]string{"namespace_sctp_ingress_bytes_total", "workload_sctp_egress_packets_total"})
+	assert.Len(res, 2)
+	assert.Equal("namespace_sctp_ingress_bytes_total", res[0].Name)
+	assert.Equal("Bytes", res[0].ValueKey)
+	assert.Equal(flpapi.MetricsFilter{Key: "Proto", Value: "132", Type: flpapi.MetricFilterEqual}, res[0].Filters[2])
+	assert.Equal([]string{"SrcK8S_Namespace", "DstK8S_Namespace"}, res[0].Labels)
+	assert.Equal("workload_sctp_egress_packets_total", res[1].Name)
+	assert.Equal("1|2", res[1].Filters[1].Value)
+}
+
+func TestEgressClassMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := flowslatest.FlowCollectorSpec{
+		Processor: flowslatest.FlowCollectorFLP{
+			Metrics: flowslatest.FLPMetrics{
+				IncludeList: &[]flowslatest.FLPMetric{"namespace_flows_total", "namespace_egress_class_bytes_total", "workload_egress_class_bytes_total"},
+			},
+		},
+	}
+
+	// Egress classification disabled => egress class metrics are removed
+	assert.Equal([]string{"namespace_flows_total"}, GetIncludeList(&spec))
+
+	spec.Processor.EgressClassification.Enable = ptr.To(true)
+	names := GetIncludeList(&spec)
+	assert.Equal([]string{"namespace_flows_total", "namespace_egress_class_bytes_total", "workload_egress_class_bytes_total"}, names)
+
+	res := GetDefinitions([]string{"namespace_egress_class_bytes_total"})
+	assert.Len(res, 1)
+	assert.Equal("Bytes", res[0].ValueKey)
+	assert.Equal("1|2", res[0].Filters[1].Value)
+	assert.Equal([]string{"SrcK8S_Namespace", "DstK8S_Namespace", "DstEgressClass"}, res[0].Labels)
+}
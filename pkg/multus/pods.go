@@ -0,0 +1,138 @@
+package multus
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/netobserv/network-observability-operator/controllers/constants"
+)
+
+// NetworkStatusAnnotation is set by Multus on pods, with the interfaces and addresses of all their networks
+const NetworkStatusAnnotation = "k8s.v1.cni.cncf.io/network-status"
+
+// Pod is a multi-homed pod, with the IP addresses of its secondary network interfaces. The owner is the controller
+// of the pod, a ReplicaSet being replaced by its Deployment, like the Kubernetes enrichment of flowlogs-pipeline does.
+type Pod struct {
+	Name      string
+	Namespace string
+	OwnerName string
+	OwnerType string
+	IPs       []string
+}
+
+type networkStatus struct {
+	Name    string   `json:"name"`
+	IPs     []string `json:"ips"`
+	Default bool     `json:"default"`
+}
+
+// WatchPods triggers a FlowCollector reconcile when the secondary addresses of a pod change. Only the metadata of pods
+// is cached, as it contains the network status annotation.
+func WatchPods(b *builder.Builder) {
+	b.WatchesMetadata(
+		&corev1.Pod{},
+		handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: constants.FlowCollectorName}}
+		}),
+		builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return len(secondaryIPs(e.Object)) > 0
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return !slices.Equal(secondaryIPs(e.ObjectOld), secondaryIPs(e.ObjectNew))
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return len(secondaryIPs(e.Object)) > 0
+			},
+			GenericFunc: func(_ event.GenericEvent) bool {
+				return false
+			},
+		}),
+	)
+}
+
+// ListPods returns the pods having at least one secondary IP address, sorted to keep the generated configuration stable
+func ListPods(ctx context.Context, cl client.Reader) ([]Pod, error) {
+	list := metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+	if err := cl.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	var pods []Pod
+	for i := range list.Items {
+		meta := &list.Items[i]
+		ips := secondaryIPs(meta)
+		if len(ips) == 0 {
+			continue
+		}
+		ownerName, ownerType := owner(meta)
+		pods = append(pods, Pod{
+			Name:      meta.GetName(),
+			Namespace: meta.GetNamespace(),
+			OwnerName: ownerName,
+			OwnerType: ownerType,
+			IPs:       ips,
+		})
+	}
+	slices.SortFunc(pods, func(a, b Pod) int {
+		if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return pods, nil
+}
+
+// secondaryIPs reads the sorted IP addresses of the non-default networks of a pod. Addresses of the default network are
+// already known to the Kubernetes enrichment.
+func secondaryIPs(o client.Object) []string {
+	annotation := o.GetAnnotations()[NetworkStatusAnnotation]
+	if annotation == "" {
+		return nil
+	}
+	var statuses []networkStatus
+	if err := json.Unmarshal([]byte(annotation), &statuses); err != nil {
+		return nil
+	}
+	var ips []string
+	for _, s := range statuses {
+		if s.Default {
+			continue
+		}
+		for _, ip := range s.IPs {
+			if !slices.Contains(ips, ip) {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	slices.Sort(ips)
+	return ips
+}
+
+func owner(o client.Object) (string, string) {
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := o.GetLabels()["pod-template-hash"]; hash != "" {
+				if deployment, ok := strings.CutSuffix(ref.Name, "-"+hash); ok {
+					return deployment, "Deployment"
+				}
+			}
+		}
+		return ref.Name, ref.Kind
+	}
+	return o.GetName(), "Pod"
+}
@@ -0,0 +1,65 @@
+package multus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func pod(ns, name, networkStatus string, owners ...metav1.OwnerReference) metav1.PartialObjectMetadata {
+	o := metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       ns,
+		Name:            name,
+		Labels:          map[string]string{"pod-template-hash": "5f9c7d6b8"},
+		OwnerReferences: owners,
+	}}
+	if networkStatus != "" {
+		o.Annotations = map[string]string{NetworkStatusAnnotation: networkStatus}
+	}
+	return o
+}
+
+// podReader lists the given pods metadata
+type podReader []metav1.PartialObjectMetadata
+
+func (r podReader) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return nil
+}
+
+func (r podReader) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	list.(*metav1.PartialObjectMetadataList).Items = append(list.(*metav1.PartialObjectMetadataList).Items, r...)
+	return nil
+}
+
+func TestListPods(t *testing.T) {
+	assert := assert.New(t)
+
+	cl := podReader{
+		pod("5gc", "amf-5f9c7d6b8-x7k2p",
+			`[{"name":"ovn-kubernetes","interface":"eth0","ips":["10.128.2.15"],"default":true},`+
+				`{"name":"5gc/n2-a","interface":"net1","ips":["192.168.10.5"]},{"name":"5gc/n2-b","interface":"net2","ips":["192.168.20.5","fd00:20::5"]}]`,
+			metav1.OwnerReference{Kind: "ReplicaSet", Name: "amf-5f9c7d6b8", Controller: ptr.To(true)},
+		),
+		pod("5gc", "smf-0",
+			`[{"name":"5gc/n4","interface":"net1","ips":["192.168.30.7"]}]`,
+			metav1.OwnerReference{Kind: "StatefulSet", Name: "smf", Controller: ptr.To(true)},
+		),
+		pod("5gc", "tools", `[{"name":"5gc/n2-a","interface":"net1","ips":["192.168.10.9"]}]`),
+		// only the default network
+		pod("apps", "web", `[{"name":"ovn-kubernetes","interface":"eth0","ips":["10.128.2.16"],"default":true}]`),
+		// not multi-homed
+		pod("apps", "db", ""),
+	}
+
+	pods, err := ListPods(context.Background(), cl)
+	assert.NoError(err)
+	assert.Equal([]Pod{
+		{Name: "amf-5f9c7d6b8-x7k2p", Namespace: "5gc", OwnerName: "amf", OwnerType: "Deployment", IPs: []string{"192.168.10.5", "192.168.20.5", "fd00:20::5"}},
+		{Name: "smf-0", Namespace: "5gc", OwnerName: "smf", OwnerType: "StatefulSet", IPs: []string{"192.168.30.7"}},
+		{Name: "tools", Namespace: "5gc", OwnerName: "tools", OwnerType: "Pod", IPs: []string{"192.168.10.9"}},
+	}, pods)
+}
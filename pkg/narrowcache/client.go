@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -21,6 +22,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+var (
+	// watchRestartDelay is the pause before restarting a terminated watch, doubled on each failure
+	watchRestartDelay    = time.Second
+	maxWatchRestartDelay = time.Minute
+)
+
 type Client struct {
 	client.Client
 	liveClient     kubernetes.Interface
@@ -31,6 +38,7 @@ type Client struct {
 
 type watchedObject struct {
 	cached   client.Object
+	stale    bool
 	handlers []handlerOnQueue
 }
 
@@ -67,13 +75,32 @@ func (c *Client) getAndCreateWatchIfNeeded(ctx context.Context, info GVKInfo, gv
 
 	c.wmut.RLock()
 	ca := c.watchedObjects[objKey]
-	c.wmut.RUnlock()
+	var cached client.Object
+	var stale bool
 	if ca != nil {
-		if ca.cached == nil {
+		cached, stale = ca.cached, ca.stale
+	}
+	c.wmut.RUnlock()
+	if ca != nil && !stale {
+		if cached == nil {
 			return nil, objKey, errors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, key.Name)
 		}
 		// Return from cache
-		return ca.cached, objKey, nil
+		return cached, objKey, nil
+	}
+	if ca != nil {
+		// Invalidated entry: refresh it, the watch is still running
+		fetched, err := info.Getter(ctx, c.liveClient, key)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				c.removeFromCache(objKey)
+			}
+			return nil, objKey, err
+		}
+		if err := c.setToCache(objKey, fetched); err != nil {
+			return nil, objKey, err
+		}
+		return fetched.(client.Object), objKey, nil
 	}
 
 	// Live query
@@ -97,7 +124,9 @@ func (c *Client) getAndCreateWatchIfNeeded(ctx context.Context, info GVKInfo, gv
 	}
 
 	// Start updating goroutine
-	go c.updateCache(ctx, objKey, w)
+	go c.updateCache(ctx, objKey, w, func() (watch.Interface, error) {
+		return c.refreshWatch(ctx, info, objKey, key)
+	})
 
 	return fetched.(client.Object), objKey, nil
 }
@@ -118,22 +147,58 @@ func copyInto(obj runtime.Object, out client.Object) error {
 	return nil
 }
 
-func (c *Client) updateCache(ctx context.Context, key string, watcher watch.Interface) {
-	rlog := log.FromContext(ctx).WithName("narrowcache")
-	for watchEvent := range watcher.ResultChan() {
-		rlog.WithValues("key", key, "event type", watchEvent.Type).Info("Event received")
-		if watchEvent.Type == watch.Added || watchEvent.Type == watch.Modified {
-			err := c.setToCache(key, watchEvent.Object)
-			if err != nil {
-				rlog.WithValues("key", key).Error(err, "Error while updating cache")
+func (c *Client) updateCache(ctx context.Context, key string, watcher watch.Interface, restart func() (watch.Interface, error)) {
+	rlog := log.FromContext(ctx).WithName("narrowcache").WithValues("key", key)
+	for {
+		for watchEvent := range watcher.ResultChan() {
+			rlog.WithValues("event type", watchEvent.Type).Info("Event received")
+			if watchEvent.Type == watch.Added || watchEvent.Type == watch.Modified {
+				err := c.setToCache(key, watchEvent.Object)
+				if err != nil {
+					rlog.Error(err, "Error while updating cache")
+				}
+			} else if watchEvent.Type == watch.Deleted {
+				c.removeFromCache(key)
+			}
+			c.callHandlers(ctx, key, watchEvent)
+		}
+		// The API server regularly closes watches: restart it rather than dropping the cache entry, which would also
+		// drop the registered handlers, so that further changes are still notified
+		delay := watchRestartDelay
+		for {
+			select {
+			case <-ctx.Done():
+				rlog.Info("Watch terminated. Clearing cache entry.")
+				c.clearEntryByKey(key)
+				return
+			case <-time.After(delay):
 			}
-		} else if watchEvent.Type == watch.Deleted {
-			c.removeFromCache(key)
+			w, err := restart()
+			if err == nil {
+				rlog.Info("Watch restarted")
+				watcher = w
+				break
+			}
+			delay = min(delay*2, maxWatchRestartDelay)
+			rlog.Error(err, "Could not restart watch", "retryAfter", delay)
+		}
+	}
+}
+
+// refreshWatch reads the object again, as events may have been missed while the watch was down, and starts a new watch
+func (c *Client) refreshWatch(ctx context.Context, info GVKInfo, key string, objKey client.ObjectKey) (watch.Interface, error) {
+	fetched, err := info.Getter(ctx, c.liveClient, objKey)
+	switch {
+	case errors.IsNotFound(err):
+		c.removeFromCache(key)
+	case err != nil:
+		return nil, err
+	default:
+		if err := c.setToCache(key, fetched); err != nil {
+			return nil, err
 		}
-		c.callHandlers(ctx, key, watchEvent)
 	}
-	rlog.WithValues("key", key).Info("Watch terminated. Clearing cache entry.")
-	c.clearEntryByKey(key)
+	return info.Watcher(ctx, c.liveClient, objKey)
 }
 
 func (c *Client) setToCache(key string, obj runtime.Object) error {
@@ -145,6 +210,7 @@ func (c *Client) setToCache(key string, obj runtime.Object) error {
 	defer c.wmut.Unlock()
 	if ca := c.watchedObjects[key]; ca != nil {
 		ca.cached = cObj
+		ca.stale = false
 	} else {
 		c.watchedObjects[key] = &watchedObject{cached: cObj}
 	}
@@ -156,6 +222,7 @@ func (c *Client) removeFromCache(key string) {
 	defer c.wmut.Unlock()
 	if ca := c.watchedObjects[key]; ca != nil {
 		ca.cached = nil
+		ca.stale = false
 	}
 }
 
@@ -240,13 +307,16 @@ func (c *Client) clearEntry(ctx context.Context, obj client.Object) {
 			Info("Invalidating cache entry")
 		strGVK := gvk.String()
 		objKey := strGVK + "|" + key.String()
-		c.clearEntryByKey(objKey)
+		// Handlers are kept, as the watch is still running: the object is read again on the next Get call
+		c.wmut.Lock()
+		defer c.wmut.Unlock()
+		if ca := c.watchedObjects[objKey]; ca != nil {
+			ca.stale = true
+		}
 	}
 }
 
 func (c *Client) clearEntryByKey(key string) {
-	// Note that this doesn't remove the watch, which lives in a goroutine
-	// Watch would recreate cache object on received event, or it can be recreated on subsequent Get call
 	c.wmut.Lock()
 	defer c.wmut.Unlock()
 	delete(c.watchedObjects, key)
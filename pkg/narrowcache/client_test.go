@@ -0,0 +1,137 @@
+package narrowcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+var caCert = corev1.ConfigMap{
+	ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "ns"},
+	Data:       map[string]string{"ca.crt": " -- CA --"},
+}
+
+// underlying only resolves kinds, as managed kinds are read from the live client
+type underlying struct {
+	client.Client
+}
+
+func (underlying) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return apiutil.GVKForObject(obj, scheme.Scheme)
+}
+
+// setupClient returns a narrowcache client on a fake clientset, which watches are served from the returned channel
+func setupClient(t *testing.T) (*Client, *fake.Clientset, chan *watch.FakeWatcher) {
+	goclient := fake.NewSimpleClientset(&caCert)
+	watchers := make(chan *watch.FakeWatcher, 10)
+	goclient.PrependWatchReactor("configmaps", func(clienttesting.Action) (bool, watch.Interface, error) {
+		w := watch.NewFake()
+		watchers <- w
+		return true, w, nil
+	})
+	NewLiveClient = func(*rest.Config) (kubernetes.Interface, error) {
+		return goclient, nil
+	}
+	cl, err := NewConfig(&rest.Config{}, ConfigMaps).CreateClient(underlying{})
+	assert.NoError(t, err)
+	return cl, goclient, watchers
+}
+
+// startSource registers a handler that forwards the updated objects
+func startSource(t *testing.T, cl *Client, obj client.Object) chan client.Object {
+	src, err := cl.GetSource(context.Background(), obj)
+	assert.NoError(t, err)
+	updates := make(chan client.Object, 10)
+	err = src.Start(context.Background(), handler.Funcs{
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+			updates <- e.ObjectNew
+		},
+	}, workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()))
+	assert.NoError(t, err)
+	return updates
+}
+
+func modified(data string) runtime.Object {
+	cm := caCert.DeepCopy()
+	cm.Data["ca.crt"] = data
+	return cm
+}
+
+func TestWatchRestart(t *testing.T) {
+	assert := assert.New(t)
+	watchRestartDelay = 10 * time.Millisecond
+	cl, goclient, watchers := setupClient(t)
+
+	cm := corev1.ConfigMap{}
+	err := cl.Get(context.Background(), client.ObjectKeyFromObject(&caCert), &cm)
+	assert.NoError(err)
+	assert.Equal(" -- CA --", cm.Data["ca.crt"])
+	updates := startSource(t, cl, &caCert)
+	first := <-watchers
+
+	// The API server closes the watch, then the object is changed while the watch is down
+	_, err = goclient.CoreV1().ConfigMaps("ns").Update(context.Background(), modified(" -- ROTATED CA --").(*corev1.ConfigMap), metav1.UpdateOptions{})
+	assert.NoError(err)
+	first.Stop()
+	second := <-watchers
+
+	// The missed change is read when restarting the watch
+	err = cl.Get(context.Background(), client.ObjectKeyFromObject(&caCert), &cm)
+	assert.NoError(err)
+	assert.Equal(" -- ROTATED CA --", cm.Data["ca.crt"])
+
+	// Handlers are still called on changes
+	second.Modify(modified(" -- ROTATED AGAIN --"))
+	select {
+	case obj := <-updates:
+		assert.Equal(" -- ROTATED AGAIN --", obj.(*corev1.ConfigMap).Data["ca.crt"])
+	case <-time.After(time.Second):
+		assert.Fail("handler not called after the watch restart")
+	}
+}
+
+func TestInvalidateKeepsHandlers(t *testing.T) {
+	assert := assert.New(t)
+	cl, goclient, watchers := setupClient(t)
+
+	cm := corev1.ConfigMap{}
+	err := cl.Get(context.Background(), client.ObjectKeyFromObject(&caCert), &cm)
+	assert.NoError(err)
+	updates := startSource(t, cl, &caCert)
+	w := <-watchers
+
+	// Invalidated entry is read again on the next Get
+	_, err = goclient.CoreV1().ConfigMaps("ns").Update(context.Background(), modified(" -- ROTATED CA --").(*corev1.ConfigMap), metav1.UpdateOptions{})
+	assert.NoError(err)
+	cl.clearEntry(context.Background(), &caCert)
+	err = cl.Get(context.Background(), client.ObjectKeyFromObject(&caCert), &cm)
+	assert.NoError(err)
+	assert.Equal(" -- ROTATED CA --", cm.Data["ca.crt"])
+	assert.Empty(watchers, "no new watch expected")
+
+	// Handlers are still called on changes
+	w.Modify(modified(" -- ROTATED AGAIN --"))
+	select {
+	case obj := <-updates:
+		assert.Equal(" -- ROTATED AGAIN --", obj.(*corev1.ConfigMap).Data["ca.crt"])
+	case <-time.After(time.Second):
+		assert.Fail("handler not called after invalidation")
+	}
+}
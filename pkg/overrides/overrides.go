@@ -0,0 +1,144 @@
+// Package overrides applies the break-glass overrides of a ConfigMap to the configuration generated by the operator,
+// such as the flowlogs-pipeline and console plugin configuration files. They are meant to work around an issue until
+// a fix is released: they bypass the FlowCollector validation, and aren't supported.
+package overrides
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/netobserv/network-observability-operator/pkg/helper"
+	"github.com/netobserv/network-observability-operator/pkg/watchers"
+)
+
+const ReasonConfigOverridden = "ConfigOverridden"
+
+// Read returns the overrides held in a ConfigMap, which is watched for changes
+func Read(ctx context.Context, cl helper.Client, w *watchers.Watcher, name, namespace string) (map[string]string, error) {
+	cm, err := w.ProcessConfigMap(ctx, cl, name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("could not read overrides ConfigMap %s: %w", name, err)
+	}
+	return cm.Data, nil
+}
+
+// Warning returns the message reported in status while overrides are applied
+func Warning(name string, overrides map[string]string) string {
+	return fmt.Sprintf(
+		"configuration overridden from ConfigMap %s (%s): overrides aren't supported, remove them once the issue is fixed",
+		name, strings.Join(keys(overrides), ", "),
+	)
+}
+
+func keys(overrides map[string]string) []string {
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplyJSON overrides values of a JSON document. Each key is a dot-separated path: object fields are selected by name,
+// and array elements by their `name` or `id` field, or by index. Missing object fields are created.
+// Values are parsed as YAML, so that `1`, `true` or `{a: b}` keep their type, and strings can be unquoted.
+func ApplyJSON(doc []byte, overrides map[string]string) ([]byte, error) {
+	if len(overrides) == 0 {
+		return doc, nil
+	}
+	var root any
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	// keep numbers as they are, such as large integers
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, key := range keys(overrides) {
+		var value any
+		if err := yaml.Unmarshal([]byte(overrides[key]), &value); err != nil {
+			errs = append(errs, fmt.Errorf("override %s: invalid value: %w", key, err))
+			continue
+		}
+		updated, err := set(root, strings.Split(key, "."), value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("override %s: %w", key, err))
+			continue
+		}
+		root = updated
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return json.Marshal(root)
+}
+
+// ApplyYAML overrides values of a YAML document, as ApplyJSON does
+func ApplyYAML(doc []byte, overrides map[string]string) ([]byte, error) {
+	if len(overrides) == 0 {
+		return doc, nil
+	}
+	js, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	js, err = ApplyJSON(js, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(js)
+}
+
+func set(node any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	switch n := node.(type) {
+	case nil:
+		child, err := set(nil, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{path[0]: child}, nil
+	case map[string]any:
+		child, err := set(n[path[0]], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = child
+		return n, nil
+	case []any:
+		i := findElement(n, path[0])
+		if i < 0 {
+			return nil, fmt.Errorf("no element %q", path[0])
+		}
+		child, err := set(n[i], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = child
+		return n, nil
+	default:
+		return nil, fmt.Errorf("can't select %q in a value that is neither an object nor an array", path[0])
+	}
+}
+
+func findElement(items []any, selector string) int {
+	for i, item := range items {
+		if obj, ok := item.(map[string]any); ok && (obj["name"] == selector || obj["id"] == selector) {
+			return i
+		}
+	}
+	if i, err := strconv.Atoi(selector); err == nil && i >= 0 && i < len(items) {
+		return i
+	}
+	return -1
+}
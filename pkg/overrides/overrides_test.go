@@ -0,0 +1,70 @@
+package overrides
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := `{"log-level":"info","parameters":[{"name":"grpc","ingest":{"grpc":{"port":2055}}},{"name":"loki","write":{"loki":{"batchSize":102400,"maxRetries":18446744073709551615}}}]}`
+	res, err := ApplyJSON([]byte(doc), map[string]string{
+		"log-level":                            "debug",
+		"parameters.loki.write.loki.batchSize": "1048576",
+		"parameters.0.ingest.grpc.buffer":      "{size: 10}",
+	})
+	assert.NoError(err)
+	assert.JSONEq(
+		`{"log-level":"debug","parameters":[{"name":"grpc","ingest":{"grpc":{"port":2055,"buffer":{"size":10}}}},{"name":"loki","write":{"loki":{"batchSize":1048576,"maxRetries":18446744073709551615}}}]}`,
+		string(res),
+	)
+
+	// no override
+	res, err = ApplyJSON([]byte(doc), nil)
+	assert.NoError(err)
+	assert.Equal(doc, string(res))
+
+	// invalid paths are all reported
+	_, err = ApplyJSON([]byte(doc), map[string]string{
+		"parameters.kafka.ingest":   "{}",
+		"log-level.value":           "debug",
+		"parameters.loki.write.url": "http://loki",
+	})
+	assert.EqualError(err, `override log-level.value: can't select "value" in a value that is neither an object nor an array
+override parameters.kafka.ingest: no element "kafka"`)
+}
+
+func TestApplyYAML(t *testing.T) {
+	assert := assert.New(t)
+
+	doc := `loki:
+  timeout: 30s
+frontend:
+  columns:
+  - id: SrcK8S_Name
+    name: Name
+    default: true
+`
+	res, err := ApplyYAML([]byte(doc), map[string]string{
+		"loki.timeout":                         "1m",
+		"frontend.columns.SrcK8S_Name.default": "false",
+	})
+	assert.NoError(err)
+	assert.Equal(`frontend:
+  columns:
+  - default: false
+    id: SrcK8S_Name
+    name: Name
+loki:
+  timeout: 1m
+`, string(res))
+}
+
+func TestWarning(t *testing.T) {
+	assert.Equal(t,
+		"configuration overridden from ConfigMap my-overrides (a.b, c): overrides aren't supported, remove them once the issue is fixed",
+		Warning("my-overrides", map[string]string{"c": "1", "a.b": "2"}),
+	)
+}
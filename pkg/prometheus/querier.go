@@ -0,0 +1,144 @@
+// Package prometheus runs instant queries against the Prometheus HTTP API, authenticated with a service account token.
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+const serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+// Querier queries Prometheus through its HTTP API
+type Querier struct {
+	url       string
+	insecure  bool
+	client    *http.Client
+	token     string
+	tokenFile string
+}
+
+// Sample is an element of an instant vector
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// NewQuerier creates a Querier for the Prometheus API at this URL, authenticated with the token of the rest config
+func NewQuerier(apiURL string, insecureSkipVerify bool, cfg *rest.Config) *Querier {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify} //nolint:gosec // user-defined
+	if !insecureSkipVerify {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if ca, err := os.ReadFile(serviceCAFile); err == nil {
+			pool.AppendCertsFromPEM(ca)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	q := Querier{
+		url:      strings.TrimSuffix(apiURL, "/"),
+		insecure: insecureSkipVerify,
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   30 * time.Second,
+		},
+	}
+	if cfg != nil {
+		q.token = cfg.BearerToken
+		q.tokenFile = cfg.BearerTokenFile
+	}
+	return &q
+}
+
+// Matches returns true if the querier has been created for this configuration
+func (q *Querier) Matches(apiURL string, insecureSkipVerify bool) bool {
+	return q.url == strings.TrimSuffix(apiURL, "/") && q.insecure == insecureSkipVerify
+}
+
+// Close releases the idle connections of the querier
+func (q *Querier) Close() {
+	q.client.CloseIdleConnections()
+}
+
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []any             `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// Query runs an instant query, which must return a vector
+func (q *Querier) Query(ctx context.Context, query string) ([]Sample, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, q.url+"/api/v1/query?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	token := q.token
+	if q.tokenFile != "" {
+		// Read on every query, as the projected token is rotated
+		b, err := os.ReadFile(q.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read service account token: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not query Prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read Prometheus response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("query to Prometheus failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return parseVector(body)
+}
+
+func parseVector(body []byte) ([]Sample, error) {
+	var qr queryResponse
+	if err := json.Unmarshal(body, &qr); err != nil {
+		return nil, fmt.Errorf("could not parse Prometheus response: %w", err)
+	}
+	if qr.Status != "success" {
+		return nil, fmt.Errorf("query to Prometheus failed: %s", qr.Error)
+	}
+	samples := make([]Sample, 0, len(qr.Data.Result))
+	for _, r := range qr.Data.Result {
+		if len(r.Value) != 2 {
+			return nil, fmt.Errorf("unexpected Prometheus value: %v", r.Value)
+		}
+		str, ok := r.Value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected Prometheus value: %v", r.Value)
+		}
+		v, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected Prometheus value: %w", err)
+		}
+		samples = append(samples, Sample{Labels: r.Metric, Value: v})
+	}
+	return samples, nil
+}
@@ -0,0 +1,89 @@
+// Package report generates the traffic reports defined by FlowReport resources, from the NetObserv metrics stored in Prometheus.
+// It runs in the jobs created by the operator for each FlowReport.
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	reportslatest "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
+)
+
+const (
+	// Flag runs the operator binary in report mode
+	Flag = "report"
+	// EnvConfig is the environment variable holding the configuration of the report job, as JSON
+	EnvConfig = "NETOBSERV_REPORT"
+	// EnvS3AccessKey and EnvS3SecretKey hold the S3 credentials, read from the credentials Secret
+	EnvS3AccessKey = "AWS_ACCESS_KEY_ID"
+	EnvS3SecretKey = "AWS_SECRET_ACCESS_KEY"
+	// EnvSMTPUsername and EnvSMTPPassword hold the mail server credentials, read from the credentials Secret
+	EnvSMTPUsername = "SMTP_USERNAME"
+	EnvSMTPPassword = "SMTP_PASSWORD"
+
+	defaultLimit     = 10
+	defaultTimeRange = 24 * time.Hour
+)
+
+// JobArgs returns the arguments of the report jobs, which run the operator binary in report mode
+func JobArgs() []string {
+	return []string{"--" + Flag}
+}
+
+// Config of a report job, written by the operator
+type Config struct {
+	Name      string                       `json:"name"`
+	Namespace string                       `json:"namespace"`
+	Prefix    string                       `json:"prefix"`
+	Spec      reportslatest.FlowReportSpec `json:"spec"`
+}
+
+// LoadConfig reads the report configuration from the environment
+func LoadConfig() (*Config, error) {
+	str := os.Getenv(EnvConfig)
+	if str == "" {
+		return nil, fmt.Errorf("missing %s environment variable", EnvConfig)
+	}
+	var cfg Config
+	if err := json.Unmarshal([]byte(str), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid report configuration: %w", err)
+	}
+	return &cfg, nil
+}
+
+func (c *Config) limit() int {
+	if c.Spec.Limit > 0 {
+		return int(c.Spec.Limit)
+	}
+	return defaultLimit
+}
+
+func (c *Config) timeRange() time.Duration {
+	if c.Spec.TimeRange != nil && c.Spec.TimeRange.Duration > 0 {
+		return c.Spec.TimeRange.Duration
+	}
+	return defaultTimeRange
+}
+
+// Validate checks the parts of the spec that the CRD schema can't check
+func Validate(spec *reportslatest.FlowReportSpec) error {
+	for key := range spec.Filters {
+		if !labelName.MatchString(key) {
+			return fmt.Errorf("invalid filter %q: keys must be metric label names", key)
+		}
+	}
+	switch spec.Output.Type {
+	case reportslatest.OutputS3:
+		if spec.Output.S3 == nil {
+			return errors.New("spec.output.s3 is required when the output type is S3")
+		}
+	case reportslatest.OutputEmail:
+		if spec.Output.Email == nil {
+			return errors.New("spec.output.email is required when the output type is Email")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	reportslatest "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
+)
+
+const (
+	// LabelReport is set on the objects created for a FlowReport, with the FlowReport name as value
+	LabelReport = "netobserv.io/report"
+	csvKey      = "report.csv"
+	textKey     = "report.txt"
+)
+
+// s3Client bounds the upload time, so that an unresponsive endpoint doesn't block the report job
+var s3Client = &http.Client{Timeout: time.Minute}
+
+// Send delivers the report to the output configured in the FlowReport
+func Send(ctx context.Context, cl client.Client, cfg *Config, r *Report) error {
+	out := &cfg.Spec.Output
+	switch out.Type {
+	case reportslatest.OutputS3:
+		return sendS3(ctx, s3Client, out.S3, cfg.Name, r)
+	case reportslatest.OutputEmail:
+		return sendEmail(out.Email, r)
+	default:
+		return writeConfigMap(ctx, cl, cfg, r)
+	}
+}
+
+func writeConfigMap(ctx context.Context, cl client.Client, cfg *Config, r *Report) error {
+	content, err := r.CSV()
+	if err != nil {
+		return err
+	}
+	name := cfg.Spec.Output.ConfigMap.Name
+	if name == "" {
+		name = cfg.Name
+	}
+	cm := corev1.ConfigMap{}
+	err = cl.Get(ctx, client.ObjectKey{Name: name, Namespace: cfg.Namespace}, &cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("could not get ConfigMap %s: %w", name, err)
+	}
+	exists := err == nil
+	if !exists {
+		cm.ObjectMeta = metav1.ObjectMeta{Name: name, Namespace: cfg.Namespace}
+	} else if cm.Labels[LabelReport] != cfg.Name {
+		return fmt.Errorf("ConfigMap %s already exists and doesn't belong to this report", name)
+	}
+	if cm.Labels == nil {
+		cm.Labels = map[string]string{}
+	}
+	cm.Labels[LabelReport] = cfg.Name
+	cm.Data = map[string]string{
+		csvKey:  string(content),
+		textKey: r.Text(),
+	}
+	if exists {
+		return cl.Update(ctx, &cm)
+	}
+	return cl.Create(ctx, &cm)
+}
+
+func objectKey(prefix, name string, t time.Time) string {
+	return fmt.Sprintf("%s%s-%s.csv", prefix, name, t.UTC().Format("20060102T150405Z"))
+}
+
+func sendS3(ctx context.Context, httpClient *http.Client, conf *reportslatest.FlowReportS3, name string, r *Report) error {
+	content, err := r.CSV()
+	if err != nil {
+		return err
+	}
+	accessKey, secretKey := os.Getenv(EnvS3AccessKey), os.Getenv(EnvS3SecretKey)
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("missing S3 credentials: %s and %s must be set in the credentials Secret", EnvS3AccessKey, EnvS3SecretKey)
+	}
+	u, err := url.Parse(strings.TrimSuffix(conf.Endpoint, "/"))
+	if err != nil {
+		return fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	u = u.JoinPath(conf.Bucket, objectKey(conf.Prefix, name, r.GeneratedAt))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	payloadHash := sha256Hex(content)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	region := conf.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	signV4(req, payloadHash, "s3", accessKey, secretKey, region, time.Now())
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not upload report to S3: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("upload to S3 failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// signV4 signs a request with AWS Signature Version 4. The signature covers the host and all the headers set on the request.
+func signV4(req *http.Request, payloadHash, service, accessKey, secretKey, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query parameters sorted by name then value, and URI-encoded as expected by AWS
+func canonicalQuery(query url.Values) string {
+	escape := func(s string) string {
+		return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+	}
+	var params []string
+	for name, values := range query {
+		for _, v := range values {
+			params = append(params, escape(name)+"="+escape(v))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+func buildEmail(conf *reportslatest.FlowReportEmail, r *Report) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", conf.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(conf.To, ", "))
+	fmt.Fprintf(&buf, "Subject: NetObserv report %s\r\n", r.Name)
+	fmt.Fprintf(&buf, "Date: %s\r\n", r.GeneratedAt.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(r.Text(), "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+func sendEmail(conf *reportslatest.FlowReportEmail, r *Report) error {
+	var auth smtp.Auth
+	if user := os.Getenv(EnvSMTPUsername); user != "" {
+		host, _, err := net.SplitHostPort(conf.SMTPAddress)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address: %w", err)
+		}
+		auth = smtp.PlainAuth("", user, os.Getenv(EnvSMTPPassword), host)
+	}
+	if err := smtp.SendMail(conf.SMTPAddress, auth, conf.From, conf.To, buildEmail(conf, r)); err != nil {
+		return fmt.Errorf("could not send report by email: %w", err)
+	}
+	return nil
+}
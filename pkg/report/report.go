@@ -0,0 +1,162 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	reportslatest "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
+)
+
+const metricName = "workload_ingress_bytes_total"
+
+var labelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+type aggregation struct {
+	labels  []string
+	columns []string
+	// matcher excludes the series without the aggregation labels
+	matcher string
+}
+
+var aggregations = map[reportslatest.FlowReportAggregation]aggregation{
+	reportslatest.AggregationTopTalkers: {
+		labels:  []string{"SrcK8S_Namespace", "SrcK8S_OwnerName", "DstK8S_Namespace", "DstK8S_OwnerName"},
+		columns: []string{"Source namespace", "Source owner", "Destination namespace", "Destination owner", "Bytes"},
+	},
+	reportslatest.AggregationNamespaceEgress: {
+		labels:  []string{"SrcK8S_Namespace"},
+		columns: []string{"Namespace", "Bytes"},
+		matcher: `SrcK8S_Namespace!=""`,
+	},
+}
+
+// Report is the result of a report run
+type Report struct {
+	Name        string
+	Aggregation reportslatest.FlowReportAggregation
+	TimeRange   time.Duration
+	GeneratedAt time.Time
+	Columns     []string
+	Rows        [][]string
+}
+
+func getAggregation(cfg *Config) (*aggregation, reportslatest.FlowReportAggregation, error) {
+	kind := cfg.Spec.Aggregation
+	if kind == "" {
+		kind = reportslatest.AggregationTopTalkers
+	}
+	agg, ok := aggregations[kind]
+	if !ok {
+		return nil, kind, fmt.Errorf("unknown aggregation %q", kind)
+	}
+	return &agg, kind, nil
+}
+
+// BuildQuery returns the PromQL query of the report
+func BuildQuery(cfg *Config) (string, error) {
+	if err := Validate(&cfg.Spec); err != nil {
+		return "", err
+	}
+	agg, _, err := getAggregation(cfg)
+	if err != nil {
+		return "", err
+	}
+	var matchers []string
+	if agg.matcher != "" {
+		matchers = append(matchers, agg.matcher)
+	}
+	keys := make([]string, 0, len(cfg.Spec.Filters))
+	for key := range cfg.Spec.Filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		matchers = append(matchers, fmt.Sprintf("%s=~%s", key, strconv.Quote(cfg.Spec.Filters[key])))
+	}
+	return fmt.Sprintf(
+		"topk(%d, sum by (%s) (increase(%s%s{%s}[%ds])))",
+		cfg.limit(),
+		strings.Join(agg.labels, ","),
+		cfg.Prefix,
+		metricName,
+		strings.Join(matchers, ","),
+		int64(cfg.timeRange().Seconds()),
+	), nil
+}
+
+// Generate queries Prometheus and returns the report, with rows sorted by decreasing bytes
+func Generate(ctx context.Context, q *prometheus.Querier, cfg *Config, now time.Time) (*Report, error) {
+	agg, kind, err := getAggregation(cfg)
+	if err != nil {
+		return nil, err
+	}
+	query, err := BuildQuery(cfg)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Value > samples[j].Value })
+	r := Report{
+		Name:        cfg.Name,
+		Aggregation: kind,
+		TimeRange:   cfg.timeRange(),
+		GeneratedAt: now.UTC(),
+		Columns:     agg.columns,
+	}
+	for _, s := range samples {
+		row := make([]string, 0, len(agg.labels)+1)
+		for _, l := range agg.labels {
+			row = append(row, s.Labels[l])
+		}
+		row = append(row, strconv.FormatInt(int64(s.Value), 10))
+		r.Rows = append(r.Rows, row)
+	}
+	return &r, nil
+}
+
+// Title is a one-line description of the report
+func (r *Report) Title() string {
+	return fmt.Sprintf("NetObserv report %s: %s over the last %s, generated at %s", r.Name, r.Aggregation, r.TimeRange, r.GeneratedAt.Format(time.RFC3339))
+}
+
+// CSV returns the report as CSV, with a header row
+func (r *Report) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(r.Columns); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(r.Rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Text returns the report as a plain text table
+func (r *Report) Text() string {
+	var buf bytes.Buffer
+	buf.WriteString(r.Title() + "\n\n")
+	if len(r.Rows) == 0 {
+		buf.WriteString("No traffic found.\n")
+		return buf.String()
+	}
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(r.Columns, "\t"))
+	for _, row := range r.Rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+	return buf.String()
+}
@@ -0,0 +1,31 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/netobserv/network-observability-operator/pkg/prometheus"
+)
+
+// Run generates the report configured in the environment, and sends it to its output
+func Run(ctx context.Context, restConfig *rest.Config, cl client.Client) error {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	q := prometheus.NewQuerier(cfg.Spec.Prometheus.URL, cfg.Spec.Prometheus.InsecureSkipVerify, restConfig)
+	defer q.Close()
+	r, err := Generate(ctx, q, cfg, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := Send(ctx, cl, cfg, r); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Report sent", "name", cfg.Name, "rows", len(r.Rows), "output", cfg.Spec.Output.Type)
+	return nil
+}
@@ -26,11 +26,15 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	endpointsv1alpha1 "github.com/netobserv/network-observability-operator/apis/externalendpoints/v1alpha1"
 	// nolint:staticcheck
 	flowsv1beta1 "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta1"
 	flowsv1beta2 "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
 	metricsv1alpha1 "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1alpha1"
+	metricsv1beta1 "github.com/netobserv/network-observability-operator/apis/flowmetrics/v1beta1"
+	reportsv1alpha1 "github.com/netobserv/network-observability-operator/apis/flowreports/v1alpha1"
 	"github.com/netobserv/network-observability-operator/pkg/helper"
 	"github.com/netobserv/network-observability-operator/pkg/manager"
 	"github.com/netobserv/network-observability-operator/pkg/manager/status"
@@ -82,6 +86,15 @@ func PrepareEnvTest(controllers []manager.Registerer, namespaces []string, baseP
 	err = metricsv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = metricsv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = endpointsv1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = reportsv1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = corev1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -122,6 +135,8 @@ func PrepareEnvTest(controllers []manager.Registerer, namespaces []string, baseP
 			EBPFAgentImage:        "registry-proxy.engineering.redhat.com/rh-osbs/network-observability-ebpf-agent@sha256:6481481ba23375107233f8d0a4f839436e34e50c2ec550ead0a16c361ae6654e",
 			FlowlogsPipelineImage: "registry-proxy.engineering.redhat.com/rh-osbs/network-observability-flowlogs-pipeline@sha256:6481481ba23375107233f8d0a4f839436e34e50c2ec550ead0a16c361ae6654e",
 			ConsolePluginImage:    "registry-proxy.engineering.redhat.com/rh-osbs/network-observability-console-plugin@sha256:6481481ba23375107233f8d0a4f839436e34e50c2ec550ead0a16c361ae6654e",
+			KubeRBACProxyImage:    "gcr.io/kubebuilder/kube-rbac-proxy:v0.15.0",
+			ReporterImage:         "quay.io/netobserv/network-observability-operator:main",
 			DownstreamDeployment:  false,
 		},
 		&ctrl.Options{
@@ -129,6 +144,11 @@ func PrepareEnvTest(controllers []manager.Registerer, namespaces []string, baseP
 			Metrics: server.Options{
 				BindAddress: "0", // disable
 			},
+			WebhookServer: webhook.NewServer(webhook.Options{
+				Host:    testEnv.WebhookInstallOptions.LocalServingHost,
+				Port:    testEnv.WebhookInstallOptions.LocalServingPort,
+				CertDir: testEnv.WebhookInstallOptions.LocalServingCertDir,
+			}),
 		},
 		controllers,
 	)
@@ -136,6 +156,9 @@ func PrepareEnvTest(controllers []manager.Registerer, namespaces []string, baseP
 	Expect(err).ToNot(HaveOccurred())
 	Expect(k8sManager).NotTo(BeNil())
 
+	err = (&metricsv1beta1.FlowMetric{}).SetupWebhookWithManager(k8sManager, nil)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = helper.SetCRDForTests(filepath.Join(basePath, ".."))
 	Expect(err).NotTo(HaveOccurred())
 
@@ -49,7 +49,7 @@ func (b *Builder) AddCertificate(ref *flowslatest.CertificateReference, volumeNa
 func (b *Builder) AddVolume(config *flowslatest.FileReference, volumeName string) string {
 	vol, vm := buildVolumeAndMount(config.Type, config.Name, volumeName)
 	b.insertOrReplace(&VolumeInfo{Volume: vol, Mount: vm})
-	return path.Join("var", volumeName, config.File)
+	return path.Join("/var", volumeName, config.File)
 }
 
 // AddToken will add a volume + volume mount for a service account token if defined
@@ -76,6 +76,30 @@ func (b *Builder) AddToken(name string) string {
 	return constants.TokensPath + name
 }
 
+// AddTrustedCABundle will add a volume + volume mount for the cluster trusted CA bundle, replacing the default system bundle
+func (b *Builder) AddTrustedCABundle() {
+	vol := corev1.Volume{
+		Name: constants.TrustedCABundleName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: constants.TrustedCABundleName,
+				},
+				Items: []corev1.KeyToPath{{
+					Key:  constants.TrustedCABundleKey,
+					Path: constants.TrustedCABundleMountedKey,
+				}},
+			},
+		},
+	}
+	vm := corev1.VolumeMount{
+		Name:      constants.TrustedCABundleName,
+		ReadOnly:  true,
+		MountPath: constants.TrustedCABundleMountPath,
+	}
+	b.insertOrReplace(&VolumeInfo{Volume: vol, Mount: vm})
+}
+
 func (b *Builder) GetVolumes() []corev1.Volume {
 	var vols []corev1.Volume
 	for i := range b.info {
@@ -0,0 +1,27 @@
+package volumes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	flowslatest "github.com/netobserv/network-observability-operator/apis/flowcollector/v1beta2"
+)
+
+func TestAddVolume(t *testing.T) {
+	assert := assert.New(t)
+
+	var b Builder
+	file := b.AddVolume(&flowslatest.FileReference{Type: flowslatest.RefTypeSecret, Name: "kafka-creds", File: "id"}, "kafka-sasl-id")
+	assert.Equal("/var/kafka-sasl-id/id", file)
+	mounts := b.GetMounts()
+	assert.Len(mounts, 1)
+	// the returned path is under the mount path, whatever the working directory of the container
+	assert.Equal("/var/kafka-sasl-id", mounts[0].MountPath)
+	assert.Equal("kafka-creds", b.GetVolumes()[0].Secret.SecretName)
+
+	certPath, keyPath := b.AddCertificate(&flowslatest.CertificateReference{Type: flowslatest.RefTypeConfigMap, Name: "ca", CertFile: "ca.crt"}, "kafka-ca")
+	assert.Equal("/var/kafka-ca/ca.crt", certPath)
+	assert.Equal("/var/kafka-ca/", keyPath)
+	assert.Len(b.GetMounts(), 2)
+}
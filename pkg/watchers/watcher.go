@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -156,6 +157,18 @@ func (w *Watcher) ProcessFileReference(ctx context.Context, cl helper.Client, fi
 	return fileDigest, nil
 }
 
+// ProcessConfigMap returns a ConfigMap which content is used by the operator, such as configuration overrides, and watches it for changes
+func (w *Watcher) ProcessConfigMap(ctx context.Context, cl helper.Client, name, namespace string) (*corev1.ConfigMap, error) {
+	cm := corev1.ConfigMap{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &cm); err != nil {
+		return nil, err
+	}
+	if err := w.watch(ctx, cl.Client.(*narrowcache.Client), flowslatest.RefTypeConfigMap, &cm); err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
 func (w *Watcher) ProcessSASL(ctx context.Context, cl helper.Client, sasl *flowslatest.SASLConfig, targetNamespace string) (idDigest string, secretDigest string, err error) {
 	idDigest, err = w.reconcile(ctx, cl, w.refFromFile(&sasl.ClientIDReference), targetNamespace)
 	if err != nil {
@@ -208,6 +221,10 @@ func (w *Watcher) reconcile(ctx context.Context, cl helper.Client, ref objectRef
 				return "", err
 			}
 		} else {
+			// Watch the copy too, to restore it when it's deleted or modified
+			if err := w.watch(ctx, cl.Client.(*narrowcache.Client), ref.kind, target); err != nil {
+				return "", err
+			}
 			// Check for update
 			targetDigest, err := watchable.GetDigest(target, ref.keys)
 			if err != nil {
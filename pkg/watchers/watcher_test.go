@@ -305,4 +305,7 @@ func TestNoUpdateCertificate(t *testing.T) {
 	assert.NoError(err)
 	clientMock.AssertCreateNotCalled(t)
 	clientMock.AssertUpdateNotCalled(t)
+	// Both the source and the copy are watched
+	assert.True(watcher.watches[key(flowslatest.RefTypeConfigMap, otherLokiCA.Name, otherNamespace)])
+	assert.True(watcher.watches[key(flowslatest.RefTypeConfigMap, copied.Name, baseNamespace)])
 }